@@ -18,6 +18,7 @@ func main() {
 		hivevalidatingwebhooks.NewClusterDeploymentValidatingAdmissionHook(),
 		&hivevalidatingwebhooks.ClusterImageSetValidatingAdmissionHook{},
 		&hivevalidatingwebhooks.ClusterProvisionValidatingAdmissionHook{},
+		&hivevalidatingwebhooks.ClusterResourceCollectionValidatingAdmissionHook{},
 		&hivevalidatingwebhooks.MachinePoolValidatingAdmissionHook{},
 		&hivevalidatingwebhooks.SyncSetValidatingAdmissionHook{},
 		&hivevalidatingwebhooks.SelectorSyncSetValidatingAdmissionHook{},
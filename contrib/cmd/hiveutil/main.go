@@ -12,6 +12,7 @@ import (
 	"github.com/openshift/hive/contrib/pkg/createcluster"
 	"github.com/openshift/hive/contrib/pkg/deprovision"
 	"github.com/openshift/hive/contrib/pkg/report"
+	"github.com/openshift/hive/contrib/pkg/syncset"
 	"github.com/openshift/hive/contrib/pkg/testresource"
 	"github.com/openshift/hive/contrib/pkg/verification"
 	"github.com/openshift/hive/pkg/imageset"
@@ -50,6 +51,7 @@ func newHiveutilCommand() *cobra.Command {
 	cmd.AddCommand(report.NewClusterReportCommand())
 	cmd.AddCommand(certificate.NewCertificateCommand())
 	cmd.AddCommand(adm.NewAdmCommand())
+	cmd.AddCommand(syncset.NewSyncSetCommand())
 
 	return cmd
 }
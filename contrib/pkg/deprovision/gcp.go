@@ -1,29 +1,41 @@
 package deprovision
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"os"
+	"time"
 
+	gcpconfig "github.com/openshift/installer/pkg/asset/installconfig/gcp"
 	"github.com/openshift/installer/pkg/destroy/gcp"
 	"github.com/openshift/installer/pkg/types"
 	typesgcp "github.com/openshift/installer/pkg/types/gcp"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	compute "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 )
 
+// gcpNetworkProjectTimeout is how long the cleanup of the host project of a shared VPC network may take
+const gcpNetworkProjectTimeout = 5 * time.Minute
+
 // gcpOptions is the set of options to deprovision a GCP cluster
 type gcpOptions struct {
-	logLevel  string
-	infraID   string
-	region    string
-	projectID string
+	logLevel         string
+	infraID          string
+	region           string
+	projectID        string
+	networkProjectID string
 }
 
 // NewDeprovisionGCPCommand is the entrypoint to create the GCP deprovision subcommand
 func NewDeprovisionGCPCommand() *cobra.Command {
 	opt := &gcpOptions{}
 	cmd := &cobra.Command{
-		Use:   "gcp INFRAID --region=REGION --gcp-project-id=GCP_PROJECT_ID",
+		Use:   "gcp INFRAID --region=REGION --gcp-project-id=GCP_PROJECT_ID [--gcp-network-project-id=GCP_NETWORK_PROJECT_ID]",
 		Short: "Deprovision GCP assets (as created by openshift-installer)",
 		Args:  cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
@@ -42,6 +54,7 @@ func NewDeprovisionGCPCommand() *cobra.Command {
 	flags.StringVar(&opt.logLevel, "loglevel", "info", "log level, one of: debug, info, warn, error, fatal, panic")
 	flags.StringVar(&opt.region, "region", "", "GCP region where the cluster is installed")
 	flags.StringVar(&opt.projectID, "gcp-project-id", "", "ID of the GCP project in which the cluster is installed")
+	flags.StringVar(&opt.networkProjectID, "gcp-network-project-id", "", "ID of the host project of the shared VPC network of the cluster, if any")
 	return cmd
 }
 
@@ -99,5 +112,62 @@ func (o *gcpOptions) Run() error {
 		return err
 	}
 
-	return destroyer.Run()
+	if err := destroyer.Run(); err != nil {
+		return err
+	}
+
+	if o.networkProjectID == "" || o.networkProjectID == o.projectID {
+		return nil
+	}
+	return o.destroyNetworkProjectFirewalls(logger)
+}
+
+// destroyNetworkProjectFirewalls deletes the firewall rules of the cluster in the host project of its shared VPC
+// network. The destroyer of the installer only deletes resources in the project of the cluster.
+func (o *gcpOptions) destroyNetworkProjectFirewalls(logger log.FieldLogger) error {
+	logger = logger.WithField("networkProject", o.networkProjectID)
+	ctx, cancel := context.WithTimeout(context.Background(), gcpNetworkProjectTimeout)
+	defer cancel()
+
+	ssn, err := gcpconfig.GetSession(ctx)
+	if err != nil {
+		logger.WithError(err).Error("failed to get session")
+		return err
+	}
+	computeSvc, err := compute.NewService(ctx, option.WithCredentials(ssn.Credentials))
+	if err != nil {
+		logger.WithError(err).Error("failed to create compute service")
+		return err
+	}
+
+	// Firewall rules of the cluster are named after its infra ID, and the health check rules of its cloud controller
+	// after the cloud controller UID.
+	filter := fmt.Sprintf("name eq \"(%s|k8s-%s)-.*\"", o.infraID, typesgcp.CloudControllerUID(o.infraID))
+	firewalls := []string{}
+	err = computeSvc.Firewalls.List(o.networkProjectID).Fields("items(name),nextPageToken").Filter(filter).Pages(ctx, func(list *compute.FirewallList) error {
+		for _, firewall := range list.Items {
+			firewalls = append(firewalls, firewall.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to list firewall rules")
+		return err
+	}
+
+	errs := []error{}
+	for _, name := range firewalls {
+		firewallLog := logger.WithField("firewall", name)
+		_, err := computeSvc.Firewalls.Delete(o.networkProjectID, name).Context(ctx).Do()
+		if ae, ok := err.(*googleapi.Error); ok && ae.Code == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			firewallLog.WithError(err).Error("failed to delete firewall rule")
+			errs = append(errs, err)
+			continue
+		}
+		firewallLog.Info("deleted firewall rule")
+	}
+	return utilerrors.NewAggregate(errs)
 }
@@ -0,0 +1,568 @@
+package syncset
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/pmezard/go-difflib/difflib"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	contributils "github.com/openshift/hive/contrib/pkg/utils"
+	"github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	hiveresource "github.com/openshift/hive/pkg/resource"
+	"github.com/openshift/hive/pkg/syncsetmatch"
+	"github.com/openshift/hive/pkg/syncsettemplate"
+)
+
+const (
+	deletedApplyResult hiveresource.ApplyResult = "deleted"
+
+	secretKind          = "Secret"
+	configMapKind       = "ConfigMap"
+	referenceAPIVersion = "v1"
+)
+
+var (
+	patchTypes = map[string]types.PatchType{
+		"json":      types.JSONPatchType,
+		"merge":     types.MergePatchType,
+		"strategic": types.StrategicMergePatchType,
+	}
+)
+
+// DiffOptions is the set of options for the syncset diff command.
+type DiffOptions struct {
+	// File is the file containing the SyncSet or SelectorSyncSet to preview.
+	File string
+	// Namespace is the namespace of a SyncSet that does not specify one.
+	Namespace string
+	// Cluster restricts the preview to the ClusterDeployment with this name.
+	Cluster string
+
+	out io.Writer
+}
+
+// syncSetSource is the SyncSet or SelectorSyncSet being previewed.
+type syncSetSource struct {
+	name            string
+	spec            *hivev1.SyncSetCommonSpec
+	syncSet         *hivev1.SyncSet
+	selectorSyncSet *hivev1.SelectorSyncSet
+}
+
+// NewDiffCommand creates a command that previews the changes a SyncSet or SelectorSyncSet would make to the
+// clusters it targets.
+func NewDiffCommand() *cobra.Command {
+	opt := &DiffOptions{out: os.Stdout}
+	cmd := &cobra.Command{
+		Use:   "diff -f FILENAME",
+		Short: "Preview the changes a SyncSet or SelectorSyncSet would make to its target clusters",
+		Long: `Resolves the ClusterDeployments targeted by the SyncSet or SelectorSyncSet in the given file and performs a
+server-side dry run of its resources, patches, secret references and configmap references against each
+cluster. For every object the result of the apply (created, configured, unchanged) is printed together with
+a diff against the live object. The values of secrets are redacted. In Sync mode, objects previously applied
+to a cluster that would be deleted are also listed. Nothing is changed on the clusters.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			log.SetLevel(log.InfoLevel)
+			if err := opt.Complete(cmd, args); err != nil {
+				log.WithError(err).Fatal("Error")
+			}
+
+			if err := opt.Validate(cmd); err != nil {
+				log.WithError(err).Fatal("Error")
+			}
+
+			kubeClient, err := contributils.GetClient()
+			if err != nil {
+				log.WithError(err).Fatal("error creating kube clients")
+			}
+
+			if err := opt.Run(kubeClient); err != nil {
+				log.WithError(err).Fatal("Error")
+			}
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVarP(&opt.File, "filename", "f", "", "File containing the SyncSet or SelectorSyncSet to preview")
+	flags.StringVarP(&opt.Namespace, "namespace", "n", "default", "Namespace of the SyncSet if not set in the file")
+	flags.StringVar(&opt.Cluster, "cluster", "", "Only preview the ClusterDeployment with this name")
+	return cmd
+}
+
+// Complete finishes parsing arguments for the command
+func (o *DiffOptions) Complete(cmd *cobra.Command, args []string) error {
+	return nil
+}
+
+// Validate ensures that option values make sense
+func (o *DiffOptions) Validate(cmd *cobra.Command) error {
+	if o.File == "" {
+		cmd.Usage()
+		return fmt.Errorf("a file containing a SyncSet or SelectorSyncSet must be specified")
+	}
+	return nil
+}
+
+// Run executes the command
+func (o *DiffOptions) Run(c client.Client) error {
+	if err := apis.AddToScheme(scheme.Scheme); err != nil {
+		return err
+	}
+	source, err := o.readSource()
+	if err != nil {
+		return err
+	}
+	cds, err := o.targetClusterDeployments(c, source)
+	if err != nil {
+		return err
+	}
+	if len(cds) == 0 {
+		fmt.Fprintf(o.out, "%s does not target any cluster deployments\n", source.name)
+		return nil
+	}
+	for i := range cds {
+		cd := &cds[i]
+		fmt.Fprintf(o.out, "=== %s/%s\n", cd.Namespace, cd.Name)
+		if err := o.diffClusterDeployment(c, source, cd); err != nil {
+			fmt.Fprintf(o.out, "  error: %v\n", err)
+		}
+	}
+	return nil
+}
+
+func (o *DiffOptions) readSource() (*syncSetSource, error) {
+	data, err := ioutil.ReadFile(o.File)
+	if err != nil {
+		return nil, err
+	}
+	obj, _, err := scheme.Codecs.UniversalDeserializer().Decode(data, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode %s: %v", o.File, err)
+	}
+	switch ss := obj.(type) {
+	case *hivev1.SyncSet:
+		if ss.Namespace == "" {
+			ss.Namespace = o.Namespace
+		}
+		return &syncSetSource{
+			name:    fmt.Sprintf("syncset %s/%s", ss.Namespace, ss.Name),
+			spec:    &ss.Spec.SyncSetCommonSpec,
+			syncSet: ss,
+		}, nil
+	case *hivev1.SelectorSyncSet:
+		return &syncSetSource{
+			name:            fmt.Sprintf("selectorsyncset %s", ss.Name),
+			spec:            &ss.Spec.SyncSetCommonSpec,
+			selectorSyncSet: ss,
+		}, nil
+	}
+	return nil, fmt.Errorf("%s does not contain a SyncSet or SelectorSyncSet", o.File)
+}
+
+// targetClusterDeployments returns the cluster deployments referenced by a SyncSet or matching the selector of a
+// SelectorSyncSet
+func (o *DiffOptions) targetClusterDeployments(c client.Client, source *syncSetSource) ([]hivev1.ClusterDeployment, error) {
+	cds := []hivev1.ClusterDeployment{}
+	if source.syncSet != nil {
+		for _, ref := range source.syncSet.Spec.ClusterDeploymentRefs {
+			if o.Cluster != "" && ref.Name != o.Cluster {
+				continue
+			}
+			cd := &hivev1.ClusterDeployment{}
+			err := c.Get(context.Background(), types.NamespacedName{Namespace: source.syncSet.Namespace, Name: ref.Name}, cd)
+			if errors.IsNotFound(err) {
+				log.Warnf("cluster deployment %s/%s not found", source.syncSet.Namespace, ref.Name)
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			cds = append(cds, *cd)
+		}
+		return cds, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&source.selectorSyncSet.Spec.ClusterDeploymentSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cluster deployment selector: %v", err)
+	}
+	cdList := &hivev1.ClusterDeploymentList{}
+	if err := c.List(context.Background(), cdList, client.UseListOptions(&client.ListOptions{LabelSelector: selector})); err != nil {
+		return nil, err
+	}
+	for i, cd := range cdList.Items {
+		if o.Cluster != "" && cd.Name != o.Cluster {
+			continue
+		}
+		matches, err := syncsetmatch.MatchesCluster(source.selectorSyncSet.Spec.ClusterMatch, &cdList.Items[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cluster match: %v", err)
+		}
+		if matches {
+			cds = append(cds, cd)
+		}
+	}
+	return cds, nil
+}
+
+func (o *DiffOptions) diffClusterDeployment(c client.Client, source *syncSetSource, cd *hivev1.ClusterDeployment) error {
+	if !cd.Spec.Installed || cd.Spec.ClusterMetadata == nil {
+		fmt.Fprintln(o.out, "  skipped: cluster installation is not complete")
+		return nil
+	}
+	if controllerutils.HasUnreachableCondition(cd) {
+		fmt.Fprintln(o.out, "  skipped: cluster is unreachable")
+		return nil
+	}
+	spec, err := syncsettemplate.Expand(source.spec, cd)
+	if err != nil {
+		return fmt.Errorf("cannot expand templates: %v", err)
+	}
+
+	secret := &corev1.Secret{}
+	secretName := types.NamespacedName{Namespace: cd.Namespace, Name: cd.Spec.ClusterMetadata.AdminKubeconfigSecretRef.Name}
+	if err := c.Get(context.Background(), secretName, secret); err != nil {
+		return fmt.Errorf("cannot get admin kubeconfig secret: %v", err)
+	}
+	kubeConfig, err := controllerutils.FixupKubeconfigSecretData(secret.Data)
+	if err != nil {
+		return err
+	}
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeConfig)
+	if err != nil {
+		return err
+	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	helper := hiveresource.NewHelper(kubeConfig, log.WithField("cluster", cd.Name))
+
+	applied := o.diffSpec(c, dynamicClient, helper, spec)
+
+	if spec.ResourceApplyMode != hivev1.SyncResourceApplyMode {
+		return nil
+	}
+	ssi, err := findSyncSetInstance(c, source, cd)
+	if err != nil || ssi == nil {
+		return err
+	}
+	o.printDeleted(ssi.Status.Resources, applied.resources)
+	o.printDeleted(ssi.Status.SecretReferences, applied.secretReferences)
+	o.printDeleted(ssi.Status.ConfigMapReferences, applied.configMapReferences)
+	return nil
+}
+
+// dryRunApplier performs server-side dry runs against a cluster. It is implemented by hiveresource.Helper.
+type dryRunApplier interface {
+	Info(obj []byte) (*hiveresource.Info, error)
+	DryRunApply(obj []byte) (hiveresource.ApplyResult, runtime.Object, error)
+	Serialize(obj runtime.Object, scheme *runtime.Scheme) ([]byte, error)
+}
+
+// appliedObjects are the objects of an expanded SyncSet spec, as they are recorded in the SyncSetInstance status
+type appliedObjects struct {
+	resources           []hivev1.SyncStatus
+	secretReferences    []hivev1.SyncStatus
+	configMapReferences []hivev1.SyncStatus
+}
+
+// diffSpec previews the resources, patches, secret references and configmap references of an expanded SyncSet spec
+// in the order the syncsetinstance controller applies them. Errors are printed per object.
+func (o *DiffOptions) diffSpec(c client.Client, dynamicClient dynamic.Interface, applier dryRunApplier, spec *hivev1.SyncSetCommonSpec) *appliedObjects {
+	applied := &appliedObjects{}
+	for i, resource := range spec.Resources {
+		info, err := applier.Info(resource.Raw)
+		if err != nil {
+			fmt.Fprintf(o.out, "  resource at index %d: error: cannot determine type: %v\n", i, err)
+			continue
+		}
+		applied.resources = append(applied.resources, hivev1.SyncStatus{APIVersion: info.APIVersion, Kind: info.Kind, Name: info.Name, Namespace: info.Namespace})
+		if err := o.diffResource(dynamicClient, applier, resource.Raw, info); err != nil {
+			fmt.Fprintf(o.out, "  %s: error: %v\n", resourceName(info.Kind, info.Namespace, info.Name), err)
+		}
+	}
+
+	for _, patch := range spec.Patches {
+		if err := o.diffPatch(dynamicClient, applier, patch); err != nil {
+			fmt.Fprintf(o.out, "  patch %s: error: %v\n", resourceName(patch.Kind, patch.Namespace, patch.Name), err)
+		}
+	}
+
+	for _, ref := range spec.SecretReferences {
+		applied.secretReferences = append(applied.secretReferences, referenceSyncStatus(ref.Target, secretKind))
+		if err := o.diffReference(c, dynamicClient, applier, &corev1.Secret{}, ref.Source, ref.Target); err != nil {
+			fmt.Fprintf(o.out, "  %s: error: %v\n", resourceName(secretKind, ref.Target.Namespace, ref.Target.Name), err)
+		}
+	}
+
+	for _, ref := range spec.ConfigMapReferences {
+		applied.configMapReferences = append(applied.configMapReferences, referenceSyncStatus(ref.Target, configMapKind))
+		if err := o.diffReference(c, dynamicClient, applier, &corev1.ConfigMap{}, ref.Source, ref.Target); err != nil {
+			fmt.Fprintf(o.out, "  %s: error: %v\n", resourceName(configMapKind, ref.Target.Namespace, ref.Target.Name), err)
+		}
+	}
+	return applied
+}
+
+// printDeleted lists the objects recorded in a SyncSetInstance status that are no longer applied
+func (o *DiffOptions) printDeleted(statusList, applied []hivev1.SyncStatus) {
+	for _, status := range statusList {
+		if !containsSyncStatus(applied, status) {
+			fmt.Fprintf(o.out, "  %s: %s\n", resourceName(status.Kind, status.Namespace, status.Name), deletedApplyResult)
+		}
+	}
+}
+
+// diffResource performs a server-side dry run of a resource and prints its result with a diff against the live object
+func (o *DiffOptions) diffResource(dynamicClient dynamic.Interface, applier dryRunApplier, raw []byte, info *hiveresource.Info) error {
+	gv, err := schema.ParseGroupVersion(info.APIVersion)
+	if err != nil {
+		return err
+	}
+	var live interface{}
+	liveObj, err := dynamicClient.Resource(gv.WithResource(info.Resource)).Namespace(info.Namespace).Get(info.Name, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+	case err != nil:
+		return err
+	default:
+		live = liveObj
+	}
+
+	result, dryRunObj, err := applier.DryRunApply(raw)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(o.out, "  %s: %s\n", resourceName(info.Kind, info.Namespace, info.Name), result)
+	if result == hiveresource.UnchangedApplyResult || dryRunObj == nil {
+		return nil
+	}
+	before, err := comparableYAML(live)
+	if err != nil {
+		return err
+	}
+	after, err := comparableYAML(dryRunObj)
+	if err != nil {
+		return err
+	}
+	return o.printDiff(before, after)
+}
+
+// diffPatch performs a server-side dry run of a patch and prints its result with a diff against the live object
+func (o *DiffOptions) diffPatch(dynamicClient dynamic.Interface, applier dryRunApplier, patch hivev1.SyncObjectPatch) error {
+	patchTypeName := patch.PatchType
+	if patchTypeName == "" {
+		patchTypeName = "strategic"
+	}
+	patchType, ok := patchTypes[patchTypeName]
+	if !ok {
+		return fmt.Errorf("invalid patch type: %s", patch.PatchType)
+	}
+	data, err := yaml.YAMLToJSON([]byte(patch.Patch))
+	if err != nil {
+		return fmt.Errorf("cannot parse patch: %v", err)
+	}
+
+	// The resource of the patched kind is resolved from a reference to the patched object
+	ref, err := json.Marshal(map[string]interface{}{
+		"apiVersion": patch.APIVersion,
+		"kind":       patch.Kind,
+		"metadata": map[string]interface{}{
+			"name":      patch.Name,
+			"namespace": patch.Namespace,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	info, err := applier.Info(ref)
+	if err != nil {
+		return err
+	}
+	gv, err := schema.ParseGroupVersion(info.APIVersion)
+	if err != nil {
+		return err
+	}
+	resourceClient := dynamicClient.Resource(gv.WithResource(info.Resource)).Namespace(patch.Namespace)
+	live, err := resourceClient.Get(patch.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	patched, err := resourceClient.Patch(patch.Name, patchType, data, metav1.PatchOptions{DryRun: []string{metav1.DryRunAll}})
+	if err != nil {
+		return err
+	}
+
+	before, err := comparableYAML(live)
+	if err != nil {
+		return err
+	}
+	after, err := comparableYAML(patched)
+	if err != nil {
+		return err
+	}
+	if before == after {
+		fmt.Fprintf(o.out, "  patch %s: %s\n", resourceName(patch.Kind, patch.Namespace, patch.Name), hiveresource.UnchangedApplyResult)
+		return nil
+	}
+	fmt.Fprintf(o.out, "  patch %s: %s\n", resourceName(patch.Kind, patch.Namespace, patch.Name), hiveresource.ConfiguredApplyResult)
+	return o.printDiff(before, after)
+}
+
+// diffReference reads the source secret or configmap of a reference from the hub, renames it to its target the way
+// the syncsetinstance controller does, and previews it like a resource
+func (o *DiffOptions) diffReference(c client.Client, dynamicClient dynamic.Interface, applier dryRunApplier, obj runtime.Object, source, target corev1.ObjectReference) error {
+	if err := c.Get(context.Background(), types.NamespacedName{Namespace: source.Namespace, Name: source.Name}, obj); err != nil {
+		return fmt.Errorf("cannot read %s/%s: %v", source.Namespace, source.Name, err)
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	accessor.SetName(target.Name)
+	accessor.SetNamespace(target.Namespace)
+	accessor.SetGeneration(0)
+	accessor.SetResourceVersion("")
+	accessor.SetUID("")
+	accessor.SetOwnerReferences(nil)
+
+	raw, err := applier.Serialize(obj, scheme.Scheme)
+	if err != nil {
+		return err
+	}
+	info, err := applier.Info(raw)
+	if err != nil {
+		return err
+	}
+	return o.diffResource(dynamicClient, applier, raw, info)
+}
+
+// printDiff prints a unified diff between the live object and the object as it would be persisted
+func (o *DiffOptions) printDiff(before, after string) error {
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(before),
+		B:        difflib.SplitLines(after),
+		FromFile: "live",
+		ToFile:   "syncset",
+		Context:  3,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(o.out, diff)
+	return nil
+}
+
+// comparableYAML serializes an object to YAML without the status and the metadata that is maintained by the server
+func comparableYAML(obj interface{}) (string, error) {
+	if obj == nil {
+		return "", nil
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+	content := map[string]interface{}{}
+	if err := json.Unmarshal(data, &content); err != nil {
+		return "", err
+	}
+	delete(content, "status")
+	if content["apiVersion"] == "v1" && content["kind"] == secretKind {
+		for _, field := range []string{"data", "stringData"} {
+			if values, ok := content[field].(map[string]interface{}); ok {
+				for key, value := range values {
+					values[key] = redact(value)
+				}
+			}
+		}
+	}
+	if metadata, ok := content["metadata"].(map[string]interface{}); ok {
+		for _, field := range []string{"creationTimestamp", "generation", "managedFields", "resourceVersion", "selfLink", "uid"} {
+			delete(metadata, field)
+		}
+		if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+			delete(annotations, corev1.LastAppliedConfigAnnotation)
+			if len(annotations) == 0 {
+				delete(metadata, "annotations")
+			}
+		}
+	}
+	out, err := yaml.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// redact replaces a secret value with a short checksum, so that a diff shows which keys change without their values
+func redact(value interface{}) string {
+	sum := sha256.Sum256([]byte(fmt.Sprint(value)))
+	return fmt.Sprintf("<redacted sha256:%x>", sum[:8])
+}
+
+// referenceSyncStatus returns the sync status the syncsetinstance controller records for the target of a reference
+func referenceSyncStatus(target corev1.ObjectReference, kind string) hivev1.SyncStatus {
+	apiVersion := referenceAPIVersion
+	if target.APIVersion != "" {
+		apiVersion = target.APIVersion
+	}
+	return hivev1.SyncStatus{APIVersion: apiVersion, Kind: kind, Name: target.Name, Namespace: target.Namespace}
+}
+
+// findSyncSetInstance returns the SyncSetInstance of the source for a cluster deployment, if it has been applied before
+func findSyncSetInstance(c client.Client, source *syncSetSource, cd *hivev1.ClusterDeployment) (*hivev1.SyncSetInstance, error) {
+	ssiList := &hivev1.SyncSetInstanceList{}
+	if err := c.List(context.Background(), ssiList, client.InNamespace(cd.Namespace)); err != nil {
+		return nil, err
+	}
+	for i, ssi := range ssiList.Items {
+		if ssi.Spec.ClusterDeploymentRef.Name != cd.Name {
+			continue
+		}
+		if source.syncSet != nil && ssi.Spec.SyncSetRef != nil && ssi.Spec.SyncSetRef.Name == source.syncSet.Name {
+			return &ssiList.Items[i], nil
+		}
+		if source.selectorSyncSet != nil && ssi.Spec.SelectorSyncSetRef != nil && ssi.Spec.SelectorSyncSetRef.Name == source.selectorSyncSet.Name {
+			return &ssiList.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func containsSyncStatus(statusList []hivev1.SyncStatus, status hivev1.SyncStatus) bool {
+	for _, s := range statusList {
+		if s.APIVersion == status.APIVersion && s.Kind == status.Kind && s.Namespace == status.Namespace && s.Name == status.Name {
+			return true
+		}
+	}
+	return false
+}
+
+func resourceName(kind, namespace, name string) string {
+	if namespace == "" {
+		return fmt.Sprintf("%s %s", kind, name)
+	}
+	return fmt.Sprintf("%s %s/%s", kind, namespace, name)
+}
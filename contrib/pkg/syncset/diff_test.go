@@ -0,0 +1,478 @@
+package syncset
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	hiveresource "github.com/openshift/hive/pkg/resource"
+)
+
+func TestComparableYAML(t *testing.T) {
+	cases := []struct {
+		name     string
+		obj      interface{}
+		expected string
+	}{
+		{
+			name: "nil object",
+		},
+		{
+			name: "server maintained fields removed",
+			obj: &corev1.ConfigMap{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "foo",
+					Namespace:       "bar",
+					ResourceVersion: "10",
+					UID:             "abc",
+					Generation:      2,
+					SelfLink:        "/api/v1/namespaces/bar/configmaps/foo",
+					Annotations: map[string]string{
+						corev1.LastAppliedConfigAnnotation: "{}",
+					},
+				},
+				Data: map[string]string{"key": "value"},
+			},
+			expected: `apiVersion: v1
+data:
+  key: value
+kind: ConfigMap
+metadata:
+  name: foo
+  namespace: bar
+`,
+		},
+		{
+			name: "other annotations kept",
+			obj: &corev1.ConfigMap{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "foo",
+					Annotations: map[string]string{
+						corev1.LastAppliedConfigAnnotation: "{}",
+						"owner":                            "hive",
+					},
+				},
+			},
+			expected: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  annotations:
+    owner: hive
+  name: foo
+`,
+		},
+		{
+			name: "status removed",
+			obj: &corev1.Namespace{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceActive},
+			},
+			expected: `apiVersion: v1
+kind: Namespace
+metadata:
+  name: foo
+spec: {}
+`,
+		},
+		{
+			name: "secret values redacted",
+			obj: &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Secret",
+				"metadata":   map[string]interface{}{"name": "foo"},
+				"data":       map[string]interface{}{"password": "c2VjcmV0"},
+				"stringData": map[string]interface{}{"token": "secret"},
+			}},
+			expected: fmt.Sprintf(`apiVersion: v1
+data:
+  password: %s
+kind: Secret
+metadata:
+  name: foo
+stringData:
+  token: %s
+`, redact("c2VjcmV0"), redact("secret")),
+		},
+		{
+			name: "secret kind in another group not redacted",
+			obj: &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "example.com/v1",
+				"kind":       "Secret",
+				"metadata":   map[string]interface{}{"name": "foo"},
+				"data":       map[string]interface{}{"password": "c2VjcmV0"},
+			}},
+			expected: `apiVersion: example.com/v1
+data:
+  password: c2VjcmV0
+kind: Secret
+metadata:
+  name: foo
+`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := comparableYAML(tc.obj)
+			require.NoError(t, err, "unexpected error")
+			assert.Equal(t, tc.expected, actual, "unexpected YAML")
+		})
+	}
+}
+
+func TestRedact(t *testing.T) {
+	assert.Equal(t, redact("secret"), redact("secret"), "expected the same value to be redacted the same way")
+	assert.NotEqual(t, redact("secret"), redact("other"), "expected different values to be redacted differently")
+	assert.NotContains(t, redact("secret"), "secret", "expected value not to be shown")
+}
+
+func TestDiffSpec(t *testing.T) {
+	existingConfigMap := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "existing", "namespace": "bar"},
+		"data":       map[string]interface{}{"key": "value"},
+	}}
+	cases := []struct {
+		name                  string
+		spec                  hivev1.SyncSetCommonSpec
+		hubObjects            []runtime.Object
+		liveObjects           []*unstructured.Unstructured
+		dryRunResults         map[string]hiveresource.ApplyResult
+		expectedOutput        []string
+		unexpectedOutput      []string
+		expectedApplied       appliedObjects
+		expectedDryRunPatches int
+	}{
+		{
+			name: "resource created",
+			spec: hivev1.SyncSetCommonSpec{
+				Resources: []runtime.RawExtension{{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"foo","namespace":"bar"},"data":{"key":"value"}}`)}},
+			},
+			dryRunResults: map[string]hiveresource.ApplyResult{"foo": hiveresource.CreatedApplyResult},
+			expectedOutput: []string{
+				"  ConfigMap bar/foo: created\n",
+				"+++ syncset\n",
+				"+  key: value\n",
+			},
+			expectedApplied: appliedObjects{
+				resources: []hivev1.SyncStatus{{APIVersion: "v1", Kind: "ConfigMap", Namespace: "bar", Name: "foo"}},
+			},
+		},
+		{
+			name: "resource unchanged",
+			spec: hivev1.SyncSetCommonSpec{
+				Resources: []runtime.RawExtension{{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"existing","namespace":"bar"},"data":{"key":"value"}}`)}},
+			},
+			liveObjects:      []*unstructured.Unstructured{existingConfigMap},
+			dryRunResults:    map[string]hiveresource.ApplyResult{"existing": hiveresource.UnchangedApplyResult},
+			expectedOutput:   []string{"  ConfigMap bar/existing: unchanged\n"},
+			unexpectedOutput: []string{"+++ syncset"},
+			expectedApplied: appliedObjects{
+				resources: []hivev1.SyncStatus{{APIVersion: "v1", Kind: "ConfigMap", Namespace: "bar", Name: "existing"}},
+			},
+		},
+		{
+			name: "patch configured",
+			spec: hivev1.SyncSetCommonSpec{
+				Patches: []hivev1.SyncObjectPatch{{
+					APIVersion: "v1",
+					Kind:       "ConfigMap",
+					Name:       "existing",
+					Namespace:  "bar",
+					Patch:      "data:\n  key: other\n",
+					PatchType:  "merge",
+				}},
+			},
+			liveObjects: []*unstructured.Unstructured{existingConfigMap},
+			expectedOutput: []string{
+				"  patch ConfigMap bar/existing: configured\n",
+				"-  key: value\n",
+				"+  key: other\n",
+			},
+			expectedDryRunPatches: 1,
+		},
+		{
+			name: "patch unchanged",
+			spec: hivev1.SyncSetCommonSpec{
+				Patches: []hivev1.SyncObjectPatch{{
+					APIVersion: "v1",
+					Kind:       "ConfigMap",
+					Name:       "existing",
+					Namespace:  "bar",
+					Patch:      `{"data":{"key":"value"}}`,
+					PatchType:  "merge",
+				}},
+			},
+			liveObjects:           []*unstructured.Unstructured{existingConfigMap},
+			expectedOutput:        []string{"  patch ConfigMap bar/existing: unchanged\n"},
+			unexpectedOutput:      []string{"+++ syncset"},
+			expectedDryRunPatches: 1,
+		},
+		{
+			name: "patch of missing object",
+			spec: hivev1.SyncSetCommonSpec{
+				Patches: []hivev1.SyncObjectPatch{{
+					APIVersion: "v1",
+					Kind:       "ConfigMap",
+					Name:       "missing",
+					Namespace:  "bar",
+					Patch:      `{"data":{"key":"value"}}`,
+					PatchType:  "merge",
+				}},
+			},
+			expectedOutput: []string{"  patch ConfigMap bar/missing: error: "},
+		},
+		{
+			name: "invalid patch type",
+			spec: hivev1.SyncSetCommonSpec{
+				Patches: []hivev1.SyncObjectPatch{{
+					APIVersion: "v1",
+					Kind:       "ConfigMap",
+					Name:       "existing",
+					Namespace:  "bar",
+					Patch:      `{"data":{"key":"value"}}`,
+					PatchType:  "unknown",
+				}},
+			},
+			liveObjects:    []*unstructured.Unstructured{existingConfigMap},
+			expectedOutput: []string{"  patch ConfigMap bar/existing: error: invalid patch type: unknown\n"},
+		},
+		{
+			name: "secret reference created with redacted values",
+			spec: hivev1.SyncSetCommonSpec{
+				SecretReferences: []hivev1.SecretReference{{
+					Source: corev1.ObjectReference{Name: "source", Namespace: "hub"},
+					Target: corev1.ObjectReference{Name: "target", Namespace: "bar"},
+				}},
+			},
+			hubObjects: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "source", Namespace: "hub"},
+				Data:       map[string][]byte{"password": []byte("secret")},
+			}},
+			dryRunResults: map[string]hiveresource.ApplyResult{"target": hiveresource.CreatedApplyResult},
+			expectedOutput: []string{
+				"  Secret bar/target: created\n",
+				"+  password: <redacted sha256:",
+				"+  namespace: bar\n",
+			},
+			unexpectedOutput: []string{"c2VjcmV0", "namespace: hub"},
+			expectedApplied: appliedObjects{
+				secretReferences: []hivev1.SyncStatus{{APIVersion: "v1", Kind: "Secret", Namespace: "bar", Name: "target"}},
+			},
+		},
+		{
+			name: "missing secret reference source",
+			spec: hivev1.SyncSetCommonSpec{
+				SecretReferences: []hivev1.SecretReference{{
+					Source: corev1.ObjectReference{Name: "source", Namespace: "hub"},
+					Target: corev1.ObjectReference{Name: "target", Namespace: "bar"},
+				}},
+			},
+			expectedOutput: []string{"  Secret bar/target: error: cannot read hub/source: "},
+			expectedApplied: appliedObjects{
+				secretReferences: []hivev1.SyncStatus{{APIVersion: "v1", Kind: "Secret", Namespace: "bar", Name: "target"}},
+			},
+		},
+		{
+			name: "configmap reference configured",
+			spec: hivev1.SyncSetCommonSpec{
+				ConfigMapReferences: []hivev1.ConfigMapReference{{
+					Source: corev1.ObjectReference{Name: "source", Namespace: "hub"},
+					Target: corev1.ObjectReference{Name: "existing", Namespace: "bar"},
+				}},
+			},
+			hubObjects: []runtime.Object{&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "source", Namespace: "hub"},
+				Data:       map[string]string{"key": "other"},
+			}},
+			liveObjects:   []*unstructured.Unstructured{existingConfigMap},
+			dryRunResults: map[string]hiveresource.ApplyResult{"existing": hiveresource.ConfiguredApplyResult},
+			expectedOutput: []string{
+				"  ConfigMap bar/existing: configured\n",
+				"-  key: value\n",
+				"+  key: other\n",
+			},
+			expectedApplied: appliedObjects{
+				configMapReferences: []hivev1.SyncStatus{{APIVersion: "v1", Kind: "ConfigMap", Namespace: "bar", Name: "existing"}},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			o := &DiffOptions{out: out}
+			c := fake.NewFakeClient(tc.hubObjects...)
+			dynamicClient := newFakeDynamicClient(tc.liveObjects...)
+			applier := &fakeDryRunApplier{results: tc.dryRunResults}
+
+			applied := o.diffSpec(c, dynamicClient, applier, &tc.spec)
+
+			for _, expected := range tc.expectedOutput {
+				assert.Contains(t, out.String(), expected, "expected output not found")
+			}
+			for _, unexpected := range tc.unexpectedOutput {
+				assert.NotContains(t, out.String(), unexpected, "unexpected output found")
+			}
+			assert.Equal(t, tc.expectedApplied, *applied, "unexpected applied objects")
+			require.Len(t, dynamicClient.patches, tc.expectedDryRunPatches, "unexpected number of patches")
+			for _, options := range dynamicClient.patches {
+				assert.Equal(t, []string{metav1.DryRunAll}, options.DryRun, "expected patch to be a dry run")
+			}
+			if live, ok := dynamicClient.objects["configmaps/bar/existing"]; ok {
+				assert.Equal(t, existingConfigMap, live, "expected live object to be left unchanged")
+			}
+		})
+	}
+}
+
+func TestPrintDeleted(t *testing.T) {
+	out := &bytes.Buffer{}
+	o := &DiffOptions{out: out}
+	o.printDeleted(
+		[]hivev1.SyncStatus{
+			{APIVersion: "v1", Kind: "ConfigMap", Namespace: "bar", Name: "kept"},
+			{APIVersion: "v1", Kind: "ConfigMap", Namespace: "bar", Name: "removed"},
+			{APIVersion: "v1", Kind: "Namespace", Name: "removed"},
+		},
+		[]hivev1.SyncStatus{
+			{APIVersion: "v1", Kind: "ConfigMap", Namespace: "bar", Name: "kept"},
+		},
+	)
+	assert.Equal(t, "  ConfigMap bar/removed: deleted\n  Namespace removed: deleted\n", out.String(), "unexpected output")
+}
+
+// fakeDryRunApplier returns the configured result for a dry run of an object by name, and the object itself as the
+// object that would be persisted
+type fakeDryRunApplier struct {
+	results map[string]hiveresource.ApplyResult
+}
+
+func (a *fakeDryRunApplier) decode(obj []byte) (*unstructured.Unstructured, error) {
+	data, err := yaml.YAMLToJSON(obj)
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{}
+	if err := json.Unmarshal(data, &u.Object); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func (a *fakeDryRunApplier) Info(obj []byte) (*hiveresource.Info, error) {
+	u, err := a.decode(obj)
+	if err != nil {
+		return nil, err
+	}
+	return &hiveresource.Info{
+		Name:       u.GetName(),
+		Namespace:  u.GetNamespace(),
+		APIVersion: u.GetAPIVersion(),
+		Kind:       u.GetKind(),
+		Resource:   strings.ToLower(u.GetKind()) + "s",
+	}, nil
+}
+
+func (a *fakeDryRunApplier) DryRunApply(obj []byte) (hiveresource.ApplyResult, runtime.Object, error) {
+	u, err := a.decode(obj)
+	if err != nil {
+		return "", nil, err
+	}
+	return a.results[u.GetName()], u, nil
+}
+
+func (a *fakeDryRunApplier) Serialize(obj runtime.Object, scheme *runtime.Scheme) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := printers.NewTypeSetter(scheme).ToPrinter(&printers.JSONPrinter{}).PrintObj(obj, buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+var _ dryRunApplier = &fakeDryRunApplier{}
+
+// fakeDynamicClient is a dynamic client that stores objects by resource, namespace and name. Patches are recorded and
+// merge patches are applied to the returned object without being persisted.
+type fakeDynamicClient struct {
+	objects map[string]*unstructured.Unstructured
+	patches []metav1.PatchOptions
+}
+
+func newFakeDynamicClient(objects ...*unstructured.Unstructured) *fakeDynamicClient {
+	c := &fakeDynamicClient{objects: map[string]*unstructured.Unstructured{}}
+	for _, obj := range objects {
+		resource := strings.ToLower(obj.GetKind()) + "s"
+		c.objects[fmt.Sprintf("%s/%s/%s", resource, obj.GetNamespace(), obj.GetName())] = obj.DeepCopy()
+	}
+	return c
+}
+
+func (c *fakeDynamicClient) Resource(resource schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return &fakeDynamicResourceClient{client: c, resource: resource}
+}
+
+// fakeDynamicResourceClient implements the operations used by the diff command, other operations panic
+type fakeDynamicResourceClient struct {
+	dynamic.ResourceInterface
+	client    *fakeDynamicClient
+	resource  schema.GroupVersionResource
+	namespace string
+}
+
+func (c *fakeDynamicResourceClient) Namespace(namespace string) dynamic.ResourceInterface {
+	return &fakeDynamicResourceClient{client: c.client, resource: c.resource, namespace: namespace}
+}
+
+func (c *fakeDynamicResourceClient) Get(name string, options metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	obj, ok := c.client.objects[fmt.Sprintf("%s/%s/%s", c.resource.Resource, c.namespace, name)]
+	if !ok {
+		return nil, errors.NewNotFound(c.resource.GroupResource(), name)
+	}
+	return obj.DeepCopy(), nil
+}
+
+func (c *fakeDynamicResourceClient) Patch(name string, pt types.PatchType, data []byte, options metav1.PatchOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	c.client.patches = append(c.client.patches, options)
+	live, err := c.Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if pt != types.MergePatchType {
+		return nil, fmt.Errorf("unsupported patch type %s", pt)
+	}
+	liveData, err := json.Marshal(live.Object)
+	if err != nil {
+		return nil, err
+	}
+	patchedData, err := jsonpatch.MergePatch(liveData, data)
+	if err != nil {
+		return nil, err
+	}
+	patched := &unstructured.Unstructured{}
+	if err := json.Unmarshal(patchedData, &patched.Object); err != nil {
+		return nil, err
+	}
+	return patched, nil
+}
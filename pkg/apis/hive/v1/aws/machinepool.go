@@ -12,6 +12,18 @@ type MachinePoolPlatform struct {
 
 	// EC2RootVolume defines the storage for ec2 instance.
 	EC2RootVolume `json:"rootVolume"`
+
+	// SpotMarketOptions allows the instances of the machine pool to be run as AWS Spot instances.
+	// +optional
+	SpotMarketOptions *SpotMarketOptions `json:"spotMarketOptions,omitempty"`
+}
+
+// SpotMarketOptions defines the options for running instances as Spot instances.
+type SpotMarketOptions struct {
+	// MaxPrice is the maximum hourly price to pay for an instance, in US dollars.
+	// Defaults to the On-Demand price of the instance type.
+	// +optional
+	MaxPrice *string `json:"maxPrice,omitempty"`
 }
 
 // EC2RootVolume defines the storage for an ec2 instance.
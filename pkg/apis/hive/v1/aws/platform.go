@@ -18,6 +18,24 @@ type Platform struct {
 	// +optional
 	UserTags map[string]string `json:"userTags,omitempty"`
 
+	// Subnets are the IDs of existing subnets of a single VPC the cluster is installed in, instead of the
+	// installer creating a VPC for the cluster. Both the public and the private subnets of the zones of the
+	// cluster must be listed, unless the cluster is internal, in which case only private subnets are needed.
+	// +optional
+	Subnets []string `json:"subnets,omitempty"`
+
+	// AdditionalSecurityGroupIDs are the IDs of existing security groups of the VPC of the cluster that are
+	// attached to the worker machines created by the installer and by MachinePools, in addition to the worker
+	// security group of the cluster.
+	// +optional
+	AdditionalSecurityGroupIDs []string `json:"additionalSecurityGroupIDs,omitempty"`
+
+	// Internal makes the endpoints of the cluster only reachable from within its network, publishing them
+	// with private DNS records and internal load balancers ("publish: Internal" in the install config).
+	// Requires Subnets.
+	// +optional
+	Internal bool `json:"internal,omitempty"`
+
 	// DefaultMachinePlatform is the default configuration used when
 	// installing on AWS for machine pools which do not define their own
 	// platform configuration.
@@ -5,6 +5,7 @@ package azure
 type MachinePool struct {
 	// Zones is list of availability zones that can be used.
 	// eg. ["1", "2", "3"]
+	// Not every Azure region has availability zones, so the zones are required for MachinePools.
 	Zones []string `json:"zones,omitempty"`
 
 	// InstanceType defines the azure instance type.
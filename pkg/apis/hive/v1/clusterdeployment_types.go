@@ -197,6 +197,96 @@ type ClusterDeploymentStatus struct {
 	// ProvisionRef is a reference to the last ClusterProvision created for the deployment
 	// +optional
 	ProvisionRef *corev1.LocalObjectReference `json:"provisionRef,omitempty"`
+
+	// ReachabilityProbes contains the results of the most recent probes of whether the cluster can be reached,
+	// most recent first. A probe run is only recorded when its outcome differs from the one before it.
+	// +optional
+	ReachabilityProbes []ReachabilityProbeRun `json:"reachabilityProbes,omitempty"`
+
+	// CloudHealth contains what the cloud provider reported about the instances and load balancers of the cluster
+	// when it was last found unreachable. It is cleared once the cluster is reachable again.
+	// +optional
+	CloudHealth *CloudHealthStatus `json:"cloudHealth,omitempty"`
+}
+
+// ReachabilityProbeRun contains the results of probing whether a cluster can be reached.
+type ReachabilityProbeRun struct {
+	// Time is when the cluster was probed.
+	Time metav1.Time `json:"time"`
+
+	// Results contains the result of every probe that was run.
+	Results []ReachabilityProbeResult `json:"results"`
+}
+
+// ReachabilityProbeResult is the result of a single reachability probe.
+type ReachabilityProbeResult struct {
+	// Probe is the probe that was run.
+	Probe ClusterReachabilityProbe `json:"probe"`
+
+	// Reachable is whether the probe succeeded.
+	Reachable bool `json:"reachable"`
+
+	// Latency is how long the probe took.
+	Latency metav1.Duration `json:"latency"`
+
+	// Error is the error of a failed probe.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// CloudHealthStatus contains what the cloud provider reports about the instances and load balancers of a cluster.
+type CloudHealthStatus struct {
+	// Time is when the cloud provider was queried.
+	Time metav1.Time `json:"time"`
+
+	// ControlPlaneInstances contains the state of the control plane instances of the cluster.
+	// +optional
+	ControlPlaneInstances []CloudInstanceState `json:"controlPlaneInstances,omitempty"`
+
+	// LoadBalancers contains the health of the targets of the load balancers of the cluster.
+	// +optional
+	LoadBalancers []CloudLoadBalancerHealth `json:"loadBalancers,omitempty"`
+
+	// RecentTerminations contains the instances of the cluster that the cloud provider reports as recently
+	// terminated or stopped.
+	// +optional
+	RecentTerminations []CloudInstanceState `json:"recentTerminations,omitempty"`
+
+	// Error is the error querying the cloud provider, if any.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// CloudInstanceState is the state of an instance of a cluster as reported by the cloud provider.
+type CloudInstanceState struct {
+	// Name is the name of the instance.
+	Name string `json:"name"`
+
+	// ID is the ID of the instance in the cloud provider.
+	ID string `json:"id"`
+
+	// Zone is the availability zone of the instance.
+	// +optional
+	Zone string `json:"zone,omitempty"`
+
+	// State is the state of the instance, such as running or terminated.
+	State string `json:"state"`
+
+	// Reason is the reason the cloud provider gives for the last change of state of the instance.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// CloudLoadBalancerHealth is the health of the targets of a load balancer of a cluster.
+type CloudLoadBalancerHealth struct {
+	// Name is the name of the load balancer, or of its target group when it has several.
+	Name string `json:"name"`
+
+	// HealthyTargets is the number of targets that pass the health checks of the load balancer.
+	HealthyTargets int `json:"healthyTargets"`
+
+	// UnhealthyTargets is the number of targets that do not pass the health checks of the load balancer.
+	UnhealthyTargets int `json:"unhealthyTargets"`
 }
 
 // ClusterDeploymentCondition contains details for the current condition of a cluster deployment
@@ -256,6 +346,13 @@ const (
 
 	// SyncSetFailedCondition indicates if any syncset for a cluster deployment failed
 	SyncSetFailedCondition ClusterDeploymentConditionType = "SyncSetFailed"
+
+	// SyncSetConflictCondition indicates if syncsets for a cluster deployment manage the same objects
+	SyncSetConflictCondition ClusterDeploymentConditionType = "SyncSetConflict"
+
+	// InvalidAWSNetworkCondition indicates that the existing subnets or security groups of an AWS cluster
+	// deployment do not exist or are not in a single available VPC, which blocks its provisioning
+	InvalidAWSNetworkCondition ClusterDeploymentConditionType = "InvalidAWSNetwork"
 )
 
 // AllClusterDeploymentConditions is a slice containing all condition types. This can be used for dealing with
@@ -270,6 +367,8 @@ var AllClusterDeploymentConditions = []ClusterDeploymentConditionType{
 	DNSNotReadyCondition,
 	ProvisionFailedCondition,
 	SyncSetFailedCondition,
+	SyncSetConflictCondition,
+	InvalidAWSNetworkCondition,
 }
 
 // +genclient
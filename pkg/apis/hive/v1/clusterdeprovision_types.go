@@ -55,6 +55,10 @@ type GCPClusterDeprovision struct {
 	Region string `json:"region"`
 	// ProjectID is the ID of the GCP project in which the cluster exists
 	ProjectID string `json:"projectID"`
+	// NetworkProjectID is the ID of the host project of the shared VPC network of the cluster, whose firewall rules
+	// for the cluster are deprovisioned as well.
+	// +optional
+	NetworkProjectID string `json:"networkProjectID,omitempty"`
 	// CredentialsSecretRef is the GCP account credentials to use for deprovisioning the cluster
 	CredentialsSecretRef *corev1.LocalObjectReference `json:"credentialsSecretRef,omitempty"`
 }
@@ -0,0 +1,117 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterResourceCollectionSpec defines the desired state of ClusterResourceCollection
+type ClusterResourceCollectionSpec struct {
+	// ClusterDeploymentSelector is a LabelSelector indicating which clusters objects are collected from.
+	ClusterDeploymentSelector metav1.LabelSelector `json:"clusterDeploymentSelector,omitempty"`
+
+	// Resources is the list of remote resources to collect from each cluster.
+	Resources []CollectedResource `json:"resources"`
+
+	// Interval is how often objects are collected from each cluster. Defaults to 1 hour.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// CountOnly indicates that only the number of objects of each resource is recorded in the status, and the
+	// objects themselves are not stored in ConfigMaps on the hub.
+	// +optional
+	CountOnly bool `json:"countOnly,omitempty"`
+}
+
+// CollectedResource identifies remote objects to collect.
+type CollectedResource struct {
+	// APIVersion is the group and version of the objects, e.g. "operators.coreos.com/v1alpha1".
+	APIVersion string `json:"apiVersion"`
+
+	// Kind is the kind of the objects, e.g. "Subscription".
+	Kind string `json:"kind"`
+
+	// Namespace limits collection to objects in a namespace. Objects in all namespaces are collected if it is empty.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// LabelSelector limits collection to objects with matching labels.
+	// +optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+}
+
+// ClusterResourceCollectionStatus defines the observed state of ClusterResourceCollection
+type ClusterResourceCollectionStatus struct {
+	// Clusters contains the result of the last collection from each selected cluster.
+	Clusters []ClusterCollectionStatus `json:"clusters,omitempty"`
+}
+
+// ClusterCollectionStatus is the result of collecting objects from a single cluster.
+type ClusterCollectionStatus struct {
+	// ClusterDeploymentNamespace is the namespace of the ClusterDeployment of the cluster.
+	ClusterDeploymentNamespace string `json:"clusterDeploymentNamespace"`
+
+	// ClusterDeploymentName is the name of the ClusterDeployment of the cluster.
+	ClusterDeploymentName string `json:"clusterDeploymentName"`
+
+	// LastCollectionTime is the last time that objects were collected from the cluster.
+	// +optional
+	LastCollectionTime *metav1.Time `json:"lastCollectionTime,omitempty"`
+
+	// ConfigMapName is the name of the ConfigMap in the namespace of the ClusterDeployment that the collected objects
+	// are stored in. It is empty if only counts are collected.
+	// +optional
+	ConfigMapName string `json:"configMapName,omitempty"`
+
+	// Resources contains the result of collecting each resource.
+	Resources []CollectedResourceStatus `json:"resources,omitempty"`
+
+	// Error is the error that prevented collection from the cluster, if any.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// CollectedResourceStatus is the result of collecting a single resource from a cluster.
+type CollectedResourceStatus struct {
+	// APIVersion is the group and version of the objects.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind is the kind of the objects.
+	Kind string `json:"kind"`
+
+	// Count is the number of objects collected.
+	Count int `json:"count"`
+
+	// Error is the error encountered collecting the objects, if any.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// +genclient:nonNamespaced
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterResourceCollection is the Schema for the clusterresourcecollections API. It periodically collects remote
+// objects from selected clusters and stores them on the hub.
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=clusterresourcecollections,shortName=crc
+// +kubebuilder:subresource:status
+type ClusterResourceCollection struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterResourceCollectionSpec   `json:"spec,omitempty"`
+	Status ClusterResourceCollectionStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterResourceCollectionList contains a list of ClusterResourceCollection
+type ClusterResourceCollectionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterResourceCollection `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterResourceCollection{}, &ClusterResourceCollectionList{})
+}
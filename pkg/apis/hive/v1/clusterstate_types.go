@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	configv1 "github.com/openshift/api/config/v1"
@@ -18,6 +19,20 @@ type ClusterStateStatus struct {
 	// ClusterOperators contains the state for every cluster operator in the
 	// target cluster
 	ClusterOperators []ClusterOperatorState `json:"clusterOperators,omitempty"`
+
+	// ResourcesLastUpdated is the last time that the node, machine and capacity
+	// summaries were updated
+	ResourcesLastUpdated *metav1.Time `json:"resourcesLastUpdated,omitempty"`
+
+	// Nodes summarizes the nodes of the target cluster
+	Nodes *ClusterNodesState `json:"nodes,omitempty"`
+
+	// Machines summarizes the machines and machine sets of the target cluster
+	Machines *ClusterMachinesState `json:"machines,omitempty"`
+
+	// Capacity summarizes the allocatable and requested compute resources of
+	// the target cluster
+	Capacity *ClusterCapacityState `json:"capacity,omitempty"`
 }
 
 // ClusterOperatorState summarizes the status of a single cluster operator
@@ -30,6 +45,101 @@ type ClusterOperatorState struct {
 	Conditions []configv1.ClusterOperatorStatusCondition `json:"conditions,omitempty"`
 }
 
+// ClusterNodesState summarizes the nodes of a cluster
+type ClusterNodesState struct {
+	// Total is the number of nodes
+	Total int `json:"total"`
+
+	// Ready is the number of nodes with a true Ready condition
+	Ready int `json:"ready"`
+
+	// NotReady is the number of nodes without a true Ready condition
+	NotReady int `json:"notReady"`
+
+	// Roles summarizes the nodes with each node role. A node with several
+	// roles is counted for each of them.
+	Roles []NodeRoleState `json:"roles,omitempty"`
+
+	// KubeletVersions is the number of nodes running each kubelet version
+	KubeletVersions []KubeletVersionState `json:"kubeletVersions,omitempty"`
+}
+
+// NodeRoleState summarizes the nodes with a node role
+type NodeRoleState struct {
+	// Role is the node role, e.g. master or worker
+	Role string `json:"role"`
+
+	// Total is the number of nodes with the role
+	Total int `json:"total"`
+
+	// Ready is the number of nodes with the role that are ready
+	Ready int `json:"ready"`
+}
+
+// KubeletVersionState is the number of nodes running a kubelet version
+type KubeletVersionState struct {
+	// Version is the kubelet version
+	Version string `json:"version"`
+
+	// Count is the number of nodes running the version
+	Count int `json:"count"`
+}
+
+// ClusterMachinesState summarizes the machines and machine sets of a cluster
+type ClusterMachinesState struct {
+	// Total is the number of machines
+	Total int `json:"total"`
+
+	// Running is the number of machines in the Running phase
+	Running int `json:"running"`
+
+	// Failed is the number of machines that have a terminal error
+	Failed int `json:"failed"`
+
+	// MachineSets contains the replica counts of every machine set
+	MachineSets []MachineSetState `json:"machineSets,omitempty"`
+}
+
+// MachineSetState summarizes the replicas of a machine set
+type MachineSetState struct {
+	// Name is the name of the machine set
+	Name string `json:"name"`
+
+	// Replicas is the desired number of replicas
+	Replicas int32 `json:"replicas"`
+
+	// ReadyReplicas is the number of replicas whose node is ready
+	ReadyReplicas int32 `json:"readyReplicas"`
+
+	// AvailableReplicas is the number of available replicas
+	AvailableReplicas int32 `json:"availableReplicas"`
+}
+
+// ClusterCapacityState summarizes the compute resources of a cluster. Requests
+// are those of the pods that have not terminated.
+type ClusterCapacityState struct {
+	// AllocatableCPU is the total allocatable CPU of the nodes
+	AllocatableCPU resource.Quantity `json:"allocatableCPU"`
+
+	// AllocatableMemory is the total allocatable memory of the nodes
+	AllocatableMemory resource.Quantity `json:"allocatableMemory"`
+
+	// AllocatablePods is the total number of pods the nodes can run
+	AllocatablePods int64 `json:"allocatablePods"`
+
+	// RequestedCPU is the total CPU requested by pods
+	RequestedCPU resource.Quantity `json:"requestedCPU"`
+
+	// RequestedMemory is the total memory requested by pods
+	RequestedMemory resource.Quantity `json:"requestedMemory"`
+
+	// Pods is the number of pods that have not terminated
+	Pods int `json:"pods"`
+
+	// PendingPods is the number of pods in the Pending phase
+	PendingPods int `json:"pendingPods"`
+}
+
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
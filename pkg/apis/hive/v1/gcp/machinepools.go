@@ -8,6 +8,10 @@ type MachinePool struct {
 	// InstanceType defines the GCP instance type.
 	// eg. n1-standard-4
 	InstanceType string `json:"type"`
+
+	// Preemptible runs the instances of the machine pool as preemptible VMs.
+	// +optional
+	Preemptible bool `json:"preemptible,omitempty"`
 }
 
 // Set sets the values from `required` to `a`.
@@ -23,4 +27,8 @@ func (a *MachinePool) Set(required *MachinePool) {
 	if required.InstanceType != "" {
 		a.InstanceType = required.InstanceType
 	}
+
+	if required.Preemptible {
+		a.Preemptible = required.Preemptible
+	}
 }
@@ -17,6 +17,25 @@ type Platform struct {
 	// Region specifies the GCP region where the cluster will be created.
 	Region string `json:"region"`
 
+	// Network is the name of an existing VPC network the cluster is installed in, instead of the installer
+	// creating a network for the cluster.
+	// +optional
+	Network string `json:"network,omitempty"`
+
+	// NetworkProjectID is the ID of the host project of a shared VPC network. Defaults to ProjectID.
+	// +optional
+	NetworkProjectID string `json:"networkProjectID,omitempty"`
+
+	// ControlPlaneSubnet is the name of the existing subnet of Network for the control plane machines.
+	// Required when Network is set.
+	// +optional
+	ControlPlaneSubnet string `json:"controlPlaneSubnet,omitempty"`
+
+	// ComputeSubnet is the name of the existing subnet of Network for the compute machines, including those
+	// of MachinePools. Required when Network is set.
+	// +optional
+	ComputeSubnet string `json:"computeSubnet,omitempty"`
+
 	// DefaultMachinePlatform is the default configuration used when
 	// installing on GCP for machine pools which do not define their own
 	// platform configuration.
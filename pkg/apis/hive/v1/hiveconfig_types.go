@@ -39,6 +39,24 @@ type HiveConfigSpec struct {
 
 	// FailedProvisionConfig is used to configure settings related to handling provision failures.
 	FailedProvisionConfig FailedProvisionConfig `json:"failedProvisionConfig"`
+
+	// SyncSetApplier configures how resources and patches from SyncSets and SelectorSyncSets are
+	// applied to target clusters.
+	// +optional
+	SyncSetApplier SyncSetApplierConfig `json:"syncSetApplier,omitempty"`
+
+	// SyncSetConflictPolicy configures how SyncSets and SelectorSyncSets that manage the same objects on a cluster
+	// are handled: "Report" (default) or "Reject".
+	// SyncSetConflictPolicy "Report" indicates that conflicts are only reported in the status of the
+	// SyncSetInstances and ClusterDeployments.
+	// SyncSetConflictPolicy "Reject" indicates that in addition, the validating webhooks reject SyncSets and
+	// SelectorSyncSets that conflict with existing ones of equal or higher priority.
+	// +optional
+	SyncSetConflictPolicy SyncSetConflictPolicy `json:"syncSetConflictPolicy,omitempty"`
+
+	// ClusterReachability configures how Hive probes whether installed clusters can be reached.
+	// +optional
+	ClusterReachability ClusterReachabilityConfig `json:"clusterReachability,omitempty"`
 }
 
 // HiveConfigStatus defines the observed state of Hive
@@ -79,6 +97,94 @@ type FailedProvisionConfig struct {
 	SkipGatherLogs bool `json:"skipGatherLogs,omitempty"`
 }
 
+// SyncSetApplierType is the method used to apply SyncSet resources and patches to target clusters.
+type SyncSetApplierType string
+
+const (
+	// KubectlSyncSetApplierType applies resources with the kubectl apply and patch libraries.
+	KubectlSyncSetApplierType SyncSetApplierType = "Kubectl"
+
+	// ServerSideSyncSetApplierType applies resources with server-side apply through a dynamic client.
+	ServerSideSyncSetApplierType SyncSetApplierType = "ServerSide"
+)
+
+// SyncSetConflictPolicy is a string representing how conflicting SyncSets are handled.
+type SyncSetConflictPolicy string
+
+const (
+	// ReportSyncSetConflictPolicy indicates that conflicting SyncSets are reported in status.
+	ReportSyncSetConflictPolicy SyncSetConflictPolicy = "Report"
+
+	// RejectSyncSetConflictPolicy indicates that conflicting SyncSets are rejected by the validating webhooks.
+	RejectSyncSetConflictPolicy SyncSetConflictPolicy = "Reject"
+)
+
+// SyncSetApplierConfig contains settings for applying SyncSets to target clusters.
+type SyncSetApplierConfig struct {
+	// Type is the method used to apply resources and patches, "Kubectl" (default) or "ServerSide".
+	// +optional
+	Type SyncSetApplierType `json:"type,omitempty"`
+
+	// FieldManager is the name of the field manager used with server-side apply.
+	// Defaults to "hive".
+	// +optional
+	FieldManager string `json:"fieldManager,omitempty"`
+
+	// ForceConflicts makes server-side apply take ownership of fields owned by other field managers
+	// instead of failing to apply the resource.
+	// +optional
+	ForceConflicts bool `json:"forceConflicts,omitempty"`
+}
+
+// ClusterReachabilityProbe is a check of whether a part of a cluster can be reached from Hive.
+type ClusterReachabilityProbe string
+
+const (
+	// APIClientReachabilityProbe builds an API client for the cluster, which discovers the API resources of the
+	// cluster. It is always run.
+	APIClientReachabilityProbe ClusterReachabilityProbe = "APIClient"
+
+	// APIReadyzReachabilityProbe checks the /readyz endpoint of the API server of the cluster.
+	APIReadyzReachabilityProbe ClusterReachabilityProbe = "APIReadyz"
+
+	// ConsoleReachabilityProbe checks the web console of the cluster, which is served through its ingress.
+	ConsoleReachabilityProbe ClusterReachabilityProbe = "Console"
+
+	// IngressCanaryReachabilityProbe checks the ingress canary route of the cluster.
+	IngressCanaryReachabilityProbe ClusterReachabilityProbe = "IngressCanary"
+)
+
+// ClusterReachabilityConfig contains settings for probing whether installed clusters can be reached.
+type ClusterReachabilityConfig struct {
+	// AdditionalProbes are probes run along with the APIClient probe: "APIReadyz", "Console" and
+	// "IngressCanary". A cluster is marked unreachable when one of its API probes fails. The results of the
+	// Console and IngressCanary probes are only recorded, to tell whether the ingress of the cluster can be
+	// reached as well.
+	// +optional
+	AdditionalProbes []ClusterReachabilityProbe `json:"additionalProbes,omitempty"`
+
+	// ReachableProbeInterval is the time between probes of a reachable cluster.
+	// Defaults to 10 minutes.
+	// +optional
+	ReachableProbeInterval *metav1.Duration `json:"reachableProbeInterval,omitempty"`
+
+	// UnreachableProbeBackoffFactor controls how often an unreachable cluster is probed: it is probed again
+	// once the time since its last probe exceeds the time it has been unreachable divided by this factor.
+	// Defaults to 4.
+	// +optional
+	UnreachableProbeBackoffFactor *int `json:"unreachableProbeBackoffFactor,omitempty"`
+
+	// MaxUnreachableProbeInterval is the longest time between probes of an unreachable cluster.
+	// Defaults to 2 hours.
+	// +optional
+	MaxUnreachableProbeInterval *metav1.Duration `json:"maxUnreachableProbeInterval,omitempty"`
+
+	// ProbeHistoryLimit is the number of probe results kept in the status of every ClusterDeployment.
+	// Defaults to 5.
+	// +optional
+	ProbeHistoryLimit *int `json:"probeHistoryLimit,omitempty"`
+}
+
 // ExternalDNSConfig contains settings for running external-dns in a Hive
 // environment.
 type ExternalDNSConfig struct {
@@ -3,6 +3,7 @@ package v1
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/openshift/hive/pkg/apis/hive/v1/aws"
 	"github.com/openshift/hive/pkg/apis/hive/v1/azure"
@@ -20,8 +21,15 @@ type MachinePoolSpec struct {
 	Name string `json:"name"`
 
 	// Replicas is the count of machines for this machine pool.
-	// Default is 1.
-	Replicas *int64 `json:"replicas"`
+	// Replicas and autoscaling cannot be used together.
+	// Default is 1, if autoscaling is not used.
+	// +optional
+	Replicas *int64 `json:"replicas,omitempty"`
+
+	// Autoscaling is the details for auto-scaling the machine pool.
+	// Replicas and autoscaling cannot be used together.
+	// +optional
+	Autoscaling *MachinePoolAutoscaling `json:"autoscaling,omitempty"`
 
 	// Platform is configuration for machine pool specific to the platform.
 	Platform MachinePoolPlatform `json:"platform"`
@@ -36,6 +44,53 @@ type MachinePoolSpec struct {
 	// This list will overwrite any modifications made to Node taints on an ongoing basis.
 	// +optional
 	Taints []corev1.Taint `json:"taints,omitempty"`
+
+	// HealthCheck is the configuration for remediating the unhealthy machines of the machine pool.
+	// +optional
+	HealthCheck *MachinePoolHealthCheck `json:"healthCheck,omitempty"`
+}
+
+// MachinePoolAutoscaling details how the machine pool is to be auto-scaled.
+type MachinePoolAutoscaling struct {
+	// MinReplicas is the minimum number of replicas for the machine pool. It is spread across the MachineSets of
+	// the zones of the machine pool.
+	MinReplicas int32 `json:"minReplicas"`
+
+	// MaxReplicas is the maximum number of replicas for the machine pool. It is spread across the MachineSets of
+	// the zones of the machine pool.
+	MaxReplicas int32 `json:"maxReplicas"`
+}
+
+// MachinePoolHealthCheck details how the unhealthy machines of the machine pool are remediated by a
+// MachineHealthCheck on the remote cluster.
+type MachinePoolHealthCheck struct {
+	// UnhealthyConditions are the node conditions that make a machine unhealthy once they last longer than
+	// their timeout. Defaults to the Ready condition being False or Unknown for 5 minutes.
+	// +optional
+	UnhealthyConditions []MachinePoolUnhealthyCondition `json:"unhealthyConditions,omitempty"`
+
+	// MaxUnhealthy is the number or percentage of the machines of the machine pool that can be unhealthy
+	// for unhealthy machines to be remediated. eg. 40%. Defaults to 100%.
+	// +optional
+	MaxUnhealthy *intstr.IntOrString `json:"maxUnhealthy,omitempty"`
+
+	// NodeStartupTimeout is how long a machine can go without a node before it is unhealthy. Defaults to
+	// 10 minutes.
+	// +optional
+	NodeStartupTimeout *metav1.Duration `json:"nodeStartupTimeout,omitempty"`
+}
+
+// MachinePoolUnhealthyCondition is a node condition that makes a machine unhealthy once it lasts longer than
+// its timeout.
+type MachinePoolUnhealthyCondition struct {
+	// Type is the type of the node condition.
+	Type corev1.NodeConditionType `json:"type"`
+
+	// Status is the status of the node condition.
+	Status corev1.ConditionStatus `json:"status"`
+
+	// Timeout is how long the node condition must last for the machine to be unhealthy. Must be positive.
+	Timeout metav1.Duration `json:"timeout"`
 }
 
 // MachinePoolPlatform is the platform-specific configuration for a machine
@@ -51,12 +106,58 @@ type MachinePoolPlatform struct {
 
 // MachinePoolStatus defines the observed state of MachinePool
 type MachinePoolStatus struct {
+	// Replicas is the current number of replicas for the machine pool.
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// ReadyReplicas is the number of replicas for the machine pool that are ready.
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// RecentInterruptions is the number of spot or preemptible instances of the machine pool that were recently
+	// interrupted by the cloud provider.
+	// +optional
+	RecentInterruptions int32 `json:"recentInterruptions,omitempty"`
+
+	// MachineSets is the status of the machine sets for the machine pool on the remote cluster.
+	// +optional
+	MachineSets []MachineSetStatus `json:"machineSets,omitempty"`
+
+	// SubnetsCheckedGeneration is the generation of the machine pool whose subnets were last found, the result of
+	// which is the InvalidSubnets condition.
+	// +optional
+	SubnetsCheckedGeneration int64 `json:"subnetsCheckedGeneration,omitempty"`
 
 	// Conditions includes more detailed status for the cluster deployment
 	// +optional
 	Conditions []MachinePoolCondition `json:"conditions,omitempty"`
 }
 
+// MachineSetStatus is the status of a machineset in the remote cluster.
+type MachineSetStatus struct {
+	// Name is the name of the machine set.
+	Name string `json:"name"`
+
+	// Zone is the availability zone of the machine set.
+	// +optional
+	Zone string `json:"zone,omitempty"`
+
+	// Replicas is the current number of replicas for the machine set.
+	Replicas int32 `json:"replicas"`
+
+	// ReadyReplicas is the number of replicas for the machine set that are ready.
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// MinReplicas is the minimum number of replicas for the machine set, when the machine pool is autoscaled.
+	// +optional
+	MinReplicas int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the maximum number of replicas for the machine set, when the machine pool is autoscaled.
+	// +optional
+	MaxReplicas int32 `json:"maxReplicas,omitempty"`
+}
+
 // MachinePoolCondition contains details for the current condition of a machine pool
 type MachinePoolCondition struct {
 	// Type is the type of the condition.
@@ -80,6 +181,20 @@ type MachinePoolCondition struct {
 // MachinePoolConditionType is a valid value for MachinePoolCondition.Type
 type MachinePoolConditionType string
 
+const (
+	// NotEnoughReplicasMachinePoolCondition is true when the replicas of an autoscaled machine pool are too few
+	// for each of its machine sets to have at least one replica.
+	NotEnoughReplicasMachinePoolCondition MachinePoolConditionType = "NotEnoughReplicas"
+
+	// InvalidSubnetsMachinePoolCondition is true when the cluster has no subnets for some of the zones of the
+	// machine pool.
+	InvalidSubnetsMachinePoolCondition MachinePoolConditionType = "InvalidSubnets"
+
+	// UnsupportedConfigurationMachinePoolCondition is true when the configuration of the machine pool is not
+	// supported for its cluster.
+	UnsupportedConfigurationMachinePoolCondition MachinePoolConditionType = "UnsupportedConfiguration"
+)
+
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
@@ -89,6 +204,8 @@ type MachinePoolConditionType string
 // +kubebuilder:printcolumn:name="PoolName",type="string",JSONPath=".spec.name"
 // +kubebuilder:printcolumn:name="ClusterDeployment",type="string",JSONPath=".spec.clusterDeploymentRef.name"
 // +kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=".spec.replicas"
+// +kubebuilder:printcolumn:name="Current",type="integer",JSONPath=".status.replicas"
+// +kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.readyReplicas"
 // +kubebuilder:resource:path=machinepools
 type MachinePool struct {
 	metav1.TypeMeta   `json:",inline"`
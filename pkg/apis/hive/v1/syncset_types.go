@@ -21,6 +21,38 @@ const (
 	SyncResourceApplyMode SyncSetResourceApplyMode = "Sync"
 )
 
+// SyncSetDriftPolicy is a string representing how changes made on the remote
+// cluster to objects created from SyncSet Resources are handled.
+type SyncSetDriftPolicy string
+
+const (
+	// IgnoreDriftPolicy indicates that remote objects are not checked for drift.
+	// They are only reapplied when changed in the SyncSet or periodically.
+	IgnoreDriftPolicy SyncSetDriftPolicy = "Ignore"
+
+	// ReportDriftPolicy indicates that remote objects are checked for drift and
+	// drifted objects are reported in status, but not reverted.
+	ReportDriftPolicy SyncSetDriftPolicy = "Report"
+
+	// RevertDriftPolicy indicates that remote objects are checked for drift and
+	// drifted objects are reapplied immediately.
+	RevertDriftPolicy SyncSetDriftPolicy = "Revert"
+)
+
+// SyncSetExistingObjectPolicy is a string representing how objects that already
+// exist on the remote cluster without being owned by the SyncSet are handled.
+type SyncSetExistingObjectPolicy string
+
+const (
+	// AdoptExistingObjectPolicy indicates that existing objects are taken over
+	// and labeled as owned by the SyncSet when it is applied.
+	AdoptExistingObjectPolicy SyncSetExistingObjectPolicy = "Adopt"
+
+	// RefuseExistingObjectPolicy indicates that existing objects which are not
+	// labeled as owned by the SyncSet are not modified and reported as failed.
+	RefuseExistingObjectPolicy SyncSetExistingObjectPolicy = "Refuse"
+)
+
 // SyncSetPatchApplyMode is a string representing the mode with which to apply
 // SyncSet Patches.
 type SyncSetPatchApplyMode string
@@ -65,12 +97,40 @@ type SyncObjectPatch struct {
 	PatchType string `json:"patchType,omitempty"`
 }
 
+// SyncObjectDeletion represents an object to be deleted from the cluster
+type SyncObjectDeletion struct {
+	// APIVersion is the Group and Version of the object to be deleted.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind is the Kind of the object to be deleted.
+	Kind string `json:"kind"`
+
+	// Name is the name of the object to be deleted.
+	Name string `json:"name"`
+
+	// Namespace is the Namespace in which the object to delete exists.
+	// Must be empty for cluster-scoped objects.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// PropagationPolicy indicates how dependents of the object are deleted: "Orphan", "Background" or
+	// "Foreground". Defaults to the default policy of the object's resource.
+	// +optional
+	PropagationPolicy *metav1.DeletionPropagation `json:"propagationPolicy,omitempty"`
+}
+
 // SecretReference represents a reference to an existing secret object to be synced
 type SecretReference struct {
 	Source corev1.ObjectReference `json:"source"`
 	Target corev1.ObjectReference `json:"target"`
 }
 
+// ConfigMapReference represents a reference to an existing configmap object to be synced
+type ConfigMapReference struct {
+	Source corev1.ObjectReference `json:"source"`
+	Target corev1.ObjectReference `json:"target"`
+}
+
 // SyncConditionType is a valid value for SyncCondition.Type
 type SyncConditionType string
 
@@ -89,6 +149,28 @@ const (
 	// UnknownObjectSyncCondition indicates that the resource type cannot be determined.
 	// It should include a reason and message for the failure.
 	UnknownObjectSyncCondition SyncConditionType = "UnknownObject"
+
+	// TemplateExpansionFailureSyncCondition indicates that the templates in a SyncSet could not be
+	// expanded for the target cluster.
+	// It should include a reason and message for the failure.
+	TemplateExpansionFailureSyncCondition SyncConditionType = "TemplateExpansionFailure"
+
+	// ReadySyncCondition indicates whether a resource applied in an apply wave is ready for
+	// the next apply wave to proceed. It is only set on resources that are followed by a later wave.
+	ReadySyncCondition SyncConditionType = "Ready"
+
+	// WaitingForReadinessSyncCondition indicates that resources in later apply waves are not
+	// being applied until the resources of an earlier wave become ready.
+	WaitingForReadinessSyncCondition SyncConditionType = "WaitingForReadiness"
+
+	// DriftedSyncCondition indicates that the remote object no longer matches the content
+	// that was applied from the SyncSet. The message contains a summary of the differences.
+	DriftedSyncCondition SyncConditionType = "Drifted"
+
+	// ResourceConflictSyncCondition indicates that objects managed by a SyncSetInstance are also managed
+	// with different content by another SyncSetInstance of the same cluster deployment.
+	// The message lists the conflicting objects.
+	ResourceConflictSyncCondition SyncConditionType = "ResourceConflict"
 )
 
 // SyncCondition is a condition in a SyncStatus
@@ -135,6 +217,14 @@ type SyncSetObjectStatus struct {
 	// +optional
 	SecretReferences []SyncStatus `json:"secretReferences,omitempty"`
 
+	// ConfigMapReferences is the list of SyncStatus for configmaps that have been synced.
+	// +optional
+	ConfigMapReferences []SyncStatus `json:"configMapReferences,omitempty"`
+
+	// Deletions is the list of SyncStatus for objects that have been deleted.
+	// +optional
+	Deletions []SyncStatus `json:"deletions,omitempty"`
+
 	// Conditions is the list of SyncConditions used to indicate UnknownObject
 	// when a resource type cannot be determined from a SyncSet resource.
 	// +optional
@@ -182,6 +272,22 @@ type SyncSetCommonSpec struct {
 	// +optional
 	ResourceApplyMode SyncSetResourceApplyMode `json:"resourceApplyMode,omitempty"`
 
+	// DriftPolicy indicates how changes made on the remote cluster to objects created from Resources are
+	// handled: "Ignore" (default), "Report" or "Revert".
+	// DriftPolicy "Ignore" indicates that objects are only reapplied when changed or periodically.
+	// DriftPolicy "Report" indicates that drifted objects are reported with a Drifted condition but not reverted.
+	// DriftPolicy "Revert" indicates that drifted objects are reported and reapplied immediately.
+	// +optional
+	DriftPolicy SyncSetDriftPolicy `json:"driftPolicy,omitempty"`
+
+	// ExistingObjectPolicy indicates how objects that already exist on the cluster without being
+	// owned by this SyncSet are handled when Resources, SecretReferences or ConfigMapReferences are
+	// applied: "Adopt" (default) or "Refuse".
+	// ExistingObjectPolicy "Adopt" indicates that existing objects are taken over by the SyncSet.
+	// ExistingObjectPolicy "Refuse" indicates that existing objects are left untouched and reported as failed.
+	// +optional
+	ExistingObjectPolicy SyncSetExistingObjectPolicy `json:"existingObjectPolicy,omitempty"`
+
 	// Patches is the list of patches to apply.
 	// +optional
 	Patches []SyncObjectPatch `json:"patches,omitempty"`
@@ -189,6 +295,23 @@ type SyncSetCommonSpec struct {
 	// SecretReferences is the list of secrets to sync from existing resources.
 	// +optional
 	SecretReferences []SecretReference `json:"secretReferences,omitempty"`
+
+	// ConfigMapReferences is the list of configmaps to sync from existing resources.
+	// +optional
+	ConfigMapReferences []ConfigMapReference `json:"configMapReferences,omitempty"`
+
+	// Deletions is the list of objects to delete from the cluster. Like patches, a deletion is submitted
+	// again when it changes, when it failed, or every 2 hours, so objects are removed again if they are recreated.
+	// +optional
+	Deletions []SyncObjectDeletion `json:"deletions,omitempty"`
+
+	// EnableResourceTemplates indicates that string values in Resources and Patches are templates
+	// that are expanded against the target ClusterDeployment before being applied. Templates use the
+	// Go text/template syntax, e.g. "{{ .BaseDomain }}" or "{{ index .Labels \"region\" }}".
+	// Available fields are ClusterDeploymentName, ClusterDeploymentNamespace, ClusterName, BaseDomain,
+	// InfraID, ClusterID, Platform, Region and Labels.
+	// +optional
+	EnableResourceTemplates bool `json:"enableResourceTemplates,omitempty"`
 }
 
 // SelectorSyncSetSpec defines the SyncSetCommonSpec resources and patches to sync along
@@ -201,6 +324,56 @@ type SelectorSyncSetSpec struct {
 	// applies to in any namespace.
 	// +optional
 	ClusterDeploymentSelector metav1.LabelSelector `json:"clusterDeploymentSelector,omitempty"`
+
+	// ClusterMatch restricts the clusters the SelectorSyncSet applies to, in addition to the
+	// ClusterDeploymentSelector, based on the version, platform and region of the clusters. Clusters
+	// are evaluated again when they change, e.g. when they are upgraded.
+	// +optional
+	ClusterMatch *SelectorSyncSetClusterMatch `json:"clusterMatch,omitempty"`
+
+	// Rollout is the strategy used to roll out changes to the SelectorSyncSet across the clusters it
+	// applies to. When not set, all clusters are updated at once.
+	// +optional
+	Rollout *SelectorSyncSetRolloutStrategy `json:"rollout,omitempty"`
+}
+
+// SelectorSyncSetClusterMatch defines conditions that clusters must meet for a SelectorSyncSet to apply to them.
+// All conditions that are set must be met.
+type SelectorSyncSetClusterMatch struct {
+	// VersionRange is a range of OpenShift versions which is matched against the desired version in the
+	// cluster version status of the ClusterDeployment. It is a space separated list of constraints which
+	// must all be met, e.g. ">=4.2.0 <4.4.0", and alternatives can be separated with "||". Supported
+	// operators are "=", "!=", ">", ">=", "<" and "<=". Clusters whose version is not known yet do not match.
+	// +optional
+	VersionRange string `json:"versionRange,omitempty"`
+
+	// Platforms is the list of platforms of the matching clusters: "aws", "azure", "gcp" or "baremetal".
+	// +optional
+	Platforms []string `json:"platforms,omitempty"`
+
+	// Regions is the list of regions of the matching clusters.
+	// +optional
+	Regions []string `json:"regions,omitempty"`
+}
+
+// SelectorSyncSetRolloutStrategy controls how changes to a SelectorSyncSet are rolled out across clusters.
+// Changing the rollout strategy does not by itself cause the SelectorSyncSet to be reapplied.
+type SelectorSyncSetRolloutStrategy struct {
+	// CanarySelector is a LabelSelector indicating the clusters that are updated first. Other clusters
+	// are only updated once the change has been applied successfully to all canary clusters.
+	// +optional
+	CanarySelector *metav1.LabelSelector `json:"canarySelector,omitempty"`
+
+	// MaxUnavailablePercentage is the maximum percentage of the targeted clusters that can be updating
+	// at the same time. Clusters that failed to apply the change count as updating until they succeed.
+	// At least one cluster is always allowed to update. Defaults to 100.
+	// +optional
+	MaxUnavailablePercentage int `json:"maxUnavailablePercentage,omitempty"`
+
+	// PauseOnFailure halts the rollout when any cluster that has been updated fails to apply the change.
+	// The rollout resumes when the failure is resolved, for example by changing the SelectorSyncSet again.
+	// +optional
+	PauseOnFailure bool `json:"pauseOnFailure,omitempty"`
 }
 
 // SyncSetSpec defines the SyncSetCommonSpec resources and patches to sync along with
@@ -215,12 +388,74 @@ type SyncSetSpec struct {
 	ClusterDeploymentRefs []corev1.LocalObjectReference `json:"clusterDeploymentRefs"`
 }
 
+// SyncSetCommonStatus summarizes the results of applying a SyncSet or SelectorSyncSet to the clusters it targets
+type SyncSetCommonStatus struct {
+	// ObservedGeneration is the generation of the SyncSet or SelectorSyncSet that this status was computed for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// TargetedClusters is the number of installed clusters the SyncSet or SelectorSyncSet applies to.
+	TargetedClusters int `json:"targetedClusters"`
+
+	// AppliedClusters is the number of targeted clusters to which the current spec has been applied successfully.
+	AppliedClusters int `json:"appliedClusters"`
+
+	// FailedClusters is the number of targeted clusters with a failure applying or deleting objects.
+	FailedClusters int `json:"failedClusters"`
+
+	// PendingClusters is the number of targeted clusters to which the current spec has not been applied yet.
+	PendingClusters int `json:"pendingClusters"`
+
+	// FailingClusters is the list of targeted clusters with a failure, along with their first error.
+	// +optional
+	FailingClusters []SyncSetClusterFailure `json:"failingClusters,omitempty"`
+}
+
+// SyncSetClusterFailure describes a cluster to which a SyncSet or SelectorSyncSet failed to apply
+type SyncSetClusterFailure struct {
+	// Namespace is the namespace of the ClusterDeployment.
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the ClusterDeployment.
+	Name string `json:"name"`
+
+	// Message is the message of the first failure found in the SyncSetInstance for the cluster.
+	Message string `json:"message"`
+}
+
 // SyncSetStatus defines the observed state of a SyncSet
 type SyncSetStatus struct {
+	SyncSetCommonStatus `json:",inline"`
 }
 
 // SelectorSyncSetStatus defines the observed state of a SelectorSyncSet
 type SelectorSyncSetStatus struct {
+	SyncSetCommonStatus `json:",inline"`
+
+	// Rollout is the progress of rolling out the current spec of a SelectorSyncSet with a rollout strategy.
+	// +optional
+	Rollout *SelectorSyncSetRolloutStatus `json:"rollout,omitempty"`
+}
+
+// SelectorSyncSetRolloutStatus is the progress of rolling out a change to a SelectorSyncSet across clusters
+type SelectorSyncSetRolloutStatus struct {
+	// SyncSetHash is the hash of the spec being rolled out.
+	SyncSetHash string `json:"syncSetHash"`
+
+	// AllowedClusters is the list of clusters that have been allowed to update to the spec being rolled out, but
+	// whose SyncSetInstance has not been found updated yet. They count as updating along with the clusters whose
+	// SyncSetInstance has been updated but has not applied the spec yet.
+	// +optional
+	AllowedClusters []SyncSetClusterReference `json:"allowedClusters,omitempty"`
+}
+
+// SyncSetClusterReference is a reference to the ClusterDeployment of a cluster a SyncSet or SelectorSyncSet applies to
+type SyncSetClusterReference struct {
+	// Namespace is the namespace of the ClusterDeployment.
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the ClusterDeployment.
+	Name string `json:"name"`
 }
 
 // +genclient:nonNamespaced
@@ -229,6 +464,11 @@ type SelectorSyncSetStatus struct {
 // SelectorSyncSet is the Schema for the SelectorSyncSet API
 // +k8s:openapi-gen=true
 // +kubebuilder:resource:path=selectorsyncsets,shortName=sss
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Targeted",type="integer",JSONPath=".status.targetedClusters"
+// +kubebuilder:printcolumn:name="Applied",type="integer",JSONPath=".status.appliedClusters"
+// +kubebuilder:printcolumn:name="Failed",type="integer",JSONPath=".status.failedClusters"
+// +kubebuilder:printcolumn:name="Pending",type="integer",JSONPath=".status.pendingClusters"
 type SelectorSyncSet struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -243,6 +483,11 @@ type SelectorSyncSet struct {
 // SyncSet is the Schema for the SyncSet API
 // +k8s:openapi-gen=true
 // +kubebuilder:resource:path=syncsets,shortName=ss
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Targeted",type="integer",JSONPath=".status.targetedClusters"
+// +kubebuilder:printcolumn:name="Applied",type="integer",JSONPath=".status.appliedClusters"
+// +kubebuilder:printcolumn:name="Failed",type="integer",JSONPath=".status.failedClusters"
+// +kubebuilder:printcolumn:name="Pending",type="integer",JSONPath=".status.pendingClusters"
 type SyncSet struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -78,6 +78,18 @@ type SyncSetInstanceStatus struct {
 	// +optional
 	SecretReferences []SyncStatus `json:"secretReferences,omitempty"`
 
+	// ConfigMapReferences is the list of SyncStatus for configmaps that have been synced.
+	// +optional
+	ConfigMapReferences []SyncStatus `json:"configMapReferences,omitempty"`
+
+	// Deletions is the list of SyncStatus for objects that have been deleted.
+	// +optional
+	Deletions []SyncStatus `json:"deletions,omitempty"`
+
+	// AppliedSyncSetHash is the SyncSetHash of the spec that was last applied completely and without errors.
+	// +optional
+	AppliedSyncSetHash string `json:"appliedSyncSetHash,omitempty"`
+
 	// Conditions is the list of SyncConditions used to indicate UnknownObject
 	// when a resource type cannot be determined from a SyncSet resource.
 	// +optional
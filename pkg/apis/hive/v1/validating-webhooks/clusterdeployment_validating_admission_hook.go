@@ -20,7 +20,15 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/rest"
 
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	hivev1aws "github.com/openshift/hive/pkg/apis/hive/v1/aws"
+	hivev1gcp "github.com/openshift/hive/pkg/apis/hive/v1/gcp"
+	"github.com/openshift/hive/pkg/awsclient"
 
 	"github.com/openshift/hive/pkg/manageddns"
 )
@@ -36,11 +44,20 @@ const (
 
 var (
 	mutableFields = []string{"CertificateBundles", "ClusterMetadata", "ControlPlaneConfig", "Ingress", "Installed", "PreserveOnDelete"}
+
+	// gcpResourceNameRegexp matches the names of GCP resources such as networks and subnets
+	gcpResourceNameRegexp = regexp.MustCompile(`^[a-z]([-a-z0-9]{0,61}[a-z0-9])?$`)
+	// gcpProjectIDRegexp matches the IDs of GCP projects
+	gcpProjectIDRegexp = regexp.MustCompile(`^[a-z][-a-z0-9]{4,28}[a-z0-9]$`)
 )
 
 // ClusterDeploymentValidatingAdmissionHook is a struct that is used to reference what code should be run by the generic-admission-server.
 type ClusterDeploymentValidatingAdmissionHook struct {
 	validManagedDomains []string
+	// kubeClient reads the cloud credentials of cluster deployments. The existing cloud resources referenced by
+	// cluster deployments are only validated when it is set.
+	kubeClient       client.Client
+	awsClientBuilder func(kubeClient client.Client, secretName, namespace, region string) (awsclient.Client, error)
 }
 
 // NewClusterDeploymentValidatingAdmissionHook constructs a new ClusterDeploymentValidatingAdmissionHook
@@ -83,7 +100,16 @@ func (a *ClusterDeploymentValidatingAdmissionHook) Initialize(kubeClientConfig *
 		"version":  clusterDeploymentAdmissionVersion,
 		"resource": "clusterdeploymentvalidator",
 	}).Info("Initializing validation REST resource")
-	return nil // No initialization needed right now.
+	if kubeClientConfig == nil {
+		return nil
+	}
+	kubeClient, err := client.New(kubeClientConfig, client.Options{})
+	if err != nil {
+		return err
+	}
+	a.kubeClient = kubeClient
+	a.awsClientBuilder = awsclient.NewClient
+	return nil
 }
 
 // Validate is called by generic-admission-server when the registered REST resource above is called with an admission request.
@@ -247,14 +273,15 @@ func (a *ClusterDeploymentValidatingAdmissionHook) validateCreate(admissionSpec
 	if newObject.Spec.Platform.AWS != nil {
 		numberOfPlatforms++
 		canManageDNS = true
-		aws := newObject.Spec.Platform.AWS
+		awsPlatform := newObject.Spec.Platform.AWS
 		awsPath := platformPath.Child("aws")
-		if aws.CredentialsSecretRef.Name == "" {
+		if awsPlatform.CredentialsSecretRef.Name == "" {
 			allErrs = append(allErrs, field.Required(awsPath.Child("credentialsSecretRef", "name"), "must specify secrets for AWS access"))
 		}
-		if aws.Region == "" {
+		if awsPlatform.Region == "" {
 			allErrs = append(allErrs, field.Required(awsPath.Child("region"), "must specify AWS region"))
 		}
+		allErrs = append(allErrs, validateAWSNetwork(awsPlatform, awsPath)...)
 	}
 	if newObject.Spec.Platform.Azure != nil {
 		numberOfPlatforms++
@@ -284,6 +311,7 @@ func (a *ClusterDeploymentValidatingAdmissionHook) validateCreate(admissionSpec
 		if gcp.Region == "" {
 			allErrs = append(allErrs, field.Required(gcpPath.Child("region"), "must specify GCP region"))
 		}
+		allErrs = append(allErrs, validateGCPNetwork(gcp, gcpPath)...)
 	}
 	switch {
 	case numberOfPlatforms == 0:
@@ -301,6 +329,11 @@ func (a *ClusterDeploymentValidatingAdmissionHook) validateCreate(admissionSpec
 		}
 	}
 
+	// The existing cloud resources are only looked up once the cluster deployment is otherwise valid
+	if len(allErrs) == 0 && newObject.Spec.Platform.AWS != nil && len(newObject.Spec.Platform.AWS.Subnets) > 0 && a.kubeClient != nil {
+		allErrs = append(allErrs, a.validateAWSNetworkResources(newObject, platformPath.Child("aws"), contextLogger)...)
+	}
+
 	if len(allErrs) > 0 {
 		status := errors.NewInvalid(schemaGVK(admissionSpec.Kind).GroupKind(), admissionSpec.Name, allErrs).Status()
 		return &admissionv1beta1.AdmissionResponse{
@@ -316,6 +349,151 @@ func (a *ClusterDeploymentValidatingAdmissionHook) validateCreate(admissionSpec
 	}
 }
 
+// validateAWSNetwork validates the existing subnets and security groups of an AWS cluster deployment.
+func validateAWSNetwork(platform *hivev1aws.Platform, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(platform.Subnets) == 0 {
+		if platform.Internal {
+			allErrs = append(allErrs, field.Required(fldPath.Child("subnets"), "must specify the existing subnets of an internal cluster"))
+		}
+		if len(platform.AdditionalSecurityGroupIDs) > 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child("subnets"), "must specify the existing subnets of a cluster with additional security groups"))
+		}
+	}
+	allErrs = append(allErrs, validateAWSResourceIDs(platform.Subnets, fldPath.Child("subnets"))...)
+	allErrs = append(allErrs, validateAWSResourceIDs(platform.AdditionalSecurityGroupIDs, fldPath.Child("additionalSecurityGroupIDs"))...)
+	return allErrs
+}
+
+func validateAWSResourceIDs(ids []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	seen := sets.NewString()
+	for i, id := range ids {
+		switch {
+		case id == "":
+			allErrs = append(allErrs, field.Required(fldPath.Index(i), "ID must not be empty"))
+		case seen.Has(id):
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), id))
+		}
+		seen.Insert(id)
+	}
+	return allErrs
+}
+
+// validateGCPNetwork validates the existing network of a GCP cluster deployment.
+func validateGCPNetwork(platform *hivev1gcp.Platform, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if platform.Network == "" {
+		if platform.NetworkProjectID != "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("network"), "must specify the existing network of a shared VPC"))
+		}
+		if platform.ControlPlaneSubnet != "" || platform.ComputeSubnet != "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("network"), "must specify the existing network of the subnets"))
+		}
+		return allErrs
+	}
+	allErrs = append(allErrs, validateGCPResourceName(platform.Network, fldPath.Child("network"))...)
+	if platform.ControlPlaneSubnet == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("controlPlaneSubnet"), "must specify the control plane subnet of the existing network"))
+	} else {
+		allErrs = append(allErrs, validateGCPResourceName(platform.ControlPlaneSubnet, fldPath.Child("controlPlaneSubnet"))...)
+	}
+	if platform.ComputeSubnet == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("computeSubnet"), "must specify the compute subnet of the existing network"))
+	} else {
+		allErrs = append(allErrs, validateGCPResourceName(platform.ComputeSubnet, fldPath.Child("computeSubnet"))...)
+	}
+	if platform.NetworkProjectID != "" && !gcpProjectIDRegexp.MatchString(platform.NetworkProjectID) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("networkProjectID"), platform.NetworkProjectID, "must be a valid GCP project ID"))
+	}
+	return allErrs
+}
+
+func validateGCPResourceName(name string, fldPath *field.Path) field.ErrorList {
+	if !gcpResourceNameRegexp.MatchString(name) {
+		return field.ErrorList{field.Invalid(fldPath, name, "must be a valid GCP resource name")}
+	}
+	return nil
+}
+
+// validateAWSNetworkResources checks that the existing subnets of an AWS cluster deployment are in a single available
+// VPC, and that its additional security groups are in that VPC.
+func (a *ClusterDeploymentValidatingAdmissionHook) validateAWSNetworkResources(cd *hivev1.ClusterDeployment, fldPath *field.Path, contextLogger *log.Entry) field.ErrorList {
+	allErrs := field.ErrorList{}
+	platform := cd.Spec.Platform.AWS
+	awsClient, err := a.awsClientBuilder(a.kubeClient, platform.CredentialsSecretRef.Name, cd.Namespace, platform.Region)
+	if err != nil {
+		contextLogger.WithError(err).Error("could not create AWS client")
+		return append(allErrs, field.InternalError(fldPath.Child("credentialsSecretRef"), err))
+	}
+
+	subnetsPath := fldPath.Child("subnets")
+	subnetsResp, err := awsClient.DescribeSubnets(&ec2.DescribeSubnetsInput{
+		Filters: []*ec2.Filter{{Name: aws.String("subnet-id"), Values: aws.StringSlice(platform.Subnets)}},
+	})
+	if err != nil {
+		contextLogger.WithError(err).Error("could not describe subnets")
+		return append(allErrs, field.InternalError(subnetsPath, err))
+	}
+	subnetVPCs := map[string]string{}
+	for _, subnet := range subnetsResp.Subnets {
+		subnetVPCs[aws.StringValue(subnet.SubnetId)] = aws.StringValue(subnet.VpcId)
+	}
+	vpcs := sets.NewString()
+	for i, id := range platform.Subnets {
+		vpc, ok := subnetVPCs[id]
+		if !ok {
+			allErrs = append(allErrs, field.NotFound(subnetsPath.Index(i), id))
+			continue
+		}
+		vpcs.Insert(vpc)
+	}
+	if len(allErrs) > 0 {
+		return allErrs
+	}
+	if vpcs.Len() != 1 {
+		return append(allErrs, field.Invalid(subnetsPath, platform.Subnets, fmt.Sprintf("subnets must belong to a single VPC, found %s", strings.Join(vpcs.List(), ", "))))
+	}
+	vpc, _ := vpcs.PopAny()
+
+	vpcsResp, err := awsClient.DescribeVpcs(&ec2.DescribeVpcsInput{
+		Filters: []*ec2.Filter{{Name: aws.String("vpc-id"), Values: aws.StringSlice([]string{vpc})}},
+	})
+	if err != nil {
+		contextLogger.WithError(err).Error("could not describe VPCs")
+		return append(allErrs, field.InternalError(subnetsPath, err))
+	}
+	if len(vpcsResp.Vpcs) == 0 || aws.StringValue(vpcsResp.Vpcs[0].State) != ec2.VpcStateAvailable {
+		return append(allErrs, field.Invalid(subnetsPath, platform.Subnets, fmt.Sprintf("VPC %s of the subnets is not available", vpc)))
+	}
+
+	if len(platform.AdditionalSecurityGroupIDs) == 0 {
+		return allErrs
+	}
+	securityGroupsPath := fldPath.Child("additionalSecurityGroupIDs")
+	securityGroupsResp, err := awsClient.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{
+		Filters: []*ec2.Filter{{Name: aws.String("group-id"), Values: aws.StringSlice(platform.AdditionalSecurityGroupIDs)}},
+	})
+	if err != nil {
+		contextLogger.WithError(err).Error("could not describe security groups")
+		return append(allErrs, field.InternalError(securityGroupsPath, err))
+	}
+	securityGroupVPCs := map[string]string{}
+	for _, sg := range securityGroupsResp.SecurityGroups {
+		securityGroupVPCs[aws.StringValue(sg.GroupId)] = aws.StringValue(sg.VpcId)
+	}
+	for i, id := range platform.AdditionalSecurityGroupIDs {
+		sgVPC, ok := securityGroupVPCs[id]
+		switch {
+		case !ok:
+			allErrs = append(allErrs, field.NotFound(securityGroupsPath.Index(i), id))
+		case sgVPC != vpc:
+			allErrs = append(allErrs, field.Invalid(securityGroupsPath.Index(i), id, fmt.Sprintf("security group must belong to VPC %s of the subnets", vpc)))
+		}
+	}
+	return allErrs
+}
+
 // validateUpdate specifically validates update operations for ClusterDeployment objects.
 func (a *ClusterDeploymentValidatingAdmissionHook) validateUpdate(admissionSpec *admissionv1beta1.AdmissionRequest) *admissionv1beta1.AdmissionResponse {
 	contextLogger := log.WithFields(log.Fields{
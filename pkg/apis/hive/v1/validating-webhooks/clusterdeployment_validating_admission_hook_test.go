@@ -7,6 +7,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
@@ -15,10 +16,18 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
 	hivev1aws "github.com/openshift/hive/pkg/apis/hive/v1/aws"
 	hivev1azure "github.com/openshift/hive/pkg/apis/hive/v1/azure"
 	hivev1gcp "github.com/openshift/hive/pkg/apis/hive/v1/gcp"
+	"github.com/openshift/hive/pkg/awsclient"
+	mockaws "github.com/openshift/hive/pkg/awsclient/mock"
 	"github.com/openshift/hive/pkg/constants"
 )
 
@@ -70,6 +79,14 @@ func validGCPClusterDeployment() *hivev1.ClusterDeployment {
 	return cd
 }
 
+func validGCPExistingNetworkClusterDeployment() *hivev1.ClusterDeployment {
+	cd := validGCPClusterDeployment()
+	cd.Spec.Platform.GCP.Network = "my-network"
+	cd.Spec.Platform.GCP.ControlPlaneSubnet = "my-master-subnet"
+	cd.Spec.Platform.GCP.ComputeSubnet = "my-worker-subnet"
+	return cd
+}
+
 func validAWSClusterDeployment() *hivev1.ClusterDeployment {
 	cd := clusterDeploymentTemplate()
 	cd.Spec.Platform.AWS = &hivev1aws.Platform{
@@ -79,6 +96,12 @@ func validAWSClusterDeployment() *hivev1.ClusterDeployment {
 	return cd
 }
 
+func validAWSExistingNetworkClusterDeployment() *hivev1.ClusterDeployment {
+	cd := validAWSClusterDeployment()
+	cd.Spec.Platform.AWS.Subnets = []string{"subnet-private", "subnet-public"}
+	return cd
+}
+
 func validAzureClusterDeployment() *hivev1.ClusterDeployment {
 	cd := clusterDeploymentTemplate()
 	cd.Spec.Platform.Azure = &hivev1azure.Platform{
@@ -634,6 +657,108 @@ func TestClusterDeploymentValidate(t *testing.T) {
 			operation:       admissionv1beta1.Create,
 			expectedAllowed: true,
 		},
+		{
+			name:            "GCP existing network",
+			newObject:       validGCPExistingNetworkClusterDeployment(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: true,
+		},
+		{
+			name: "GCP shared network",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validGCPExistingNetworkClusterDeployment()
+				cd.Spec.Platform.GCP.NetworkProjectID = "my-host-project"
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: true,
+		},
+		{
+			name: "GCP existing network without compute subnet",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validGCPExistingNetworkClusterDeployment()
+				cd.Spec.Platform.GCP.ComputeSubnet = ""
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name: "GCP subnets without network",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validGCPExistingNetworkClusterDeployment()
+				cd.Spec.Platform.GCP.Network = ""
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name: "GCP network project without network",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validGCPClusterDeployment()
+				cd.Spec.Platform.GCP.NetworkProjectID = "my-host-project"
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name: "GCP invalid network name",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validGCPExistingNetworkClusterDeployment()
+				cd.Spec.Platform.GCP.Network = "My_Network"
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name: "GCP invalid network project",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validGCPExistingNetworkClusterDeployment()
+				cd.Spec.Platform.GCP.NetworkProjectID = "host"
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name:            "AWS existing subnets",
+			newObject:       validAWSExistingNetworkClusterDeployment(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: true,
+		},
+		{
+			name: "AWS internal cluster without subnets",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validAWSClusterDeployment()
+				cd.Spec.Platform.AWS.Internal = true
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name: "AWS additional security groups without subnets",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validAWSClusterDeployment()
+				cd.Spec.Platform.AWS.AdditionalSecurityGroupIDs = []string{"sg-additional"}
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name: "AWS duplicate subnets",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validAWSExistingNetworkClusterDeployment()
+				cd.Spec.Platform.AWS.Subnets = append(cd.Spec.Platform.AWS.Subnets, "subnet-private")
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
 		{
 			name: "Provisioning is missing",
 			newObject: func() *hivev1.ClusterDeployment {
@@ -691,6 +816,121 @@ func TestClusterDeploymentValidate(t *testing.T) {
 	}
 }
 
+func TestClusterDeploymentValidateAWSNetworkResources(t *testing.T) {
+	cases := []struct {
+		name                 string
+		securityGroups       []string
+		subnetVPCs           map[string]string
+		vpcState             string
+		securityGroupVPCs    map[string]string
+		expectDescribeVPCs   bool
+		expectDescribeGroups bool
+		expectedAllowed      bool
+	}{
+		{
+			name:               "subnets of available VPC",
+			subnetVPCs:         map[string]string{"subnet-private": "vpc-1", "subnet-public": "vpc-1"},
+			vpcState:           ec2.VpcStateAvailable,
+			expectDescribeVPCs: true,
+			expectedAllowed:    true,
+		},
+		{
+			name:       "missing subnet",
+			subnetVPCs: map[string]string{"subnet-private": "vpc-1"},
+		},
+		{
+			name:       "subnets of several VPCs",
+			subnetVPCs: map[string]string{"subnet-private": "vpc-1", "subnet-public": "vpc-2"},
+		},
+		{
+			name:               "VPC not available",
+			subnetVPCs:         map[string]string{"subnet-private": "vpc-1", "subnet-public": "vpc-1"},
+			vpcState:           ec2.VpcStatePending,
+			expectDescribeVPCs: true,
+		},
+		{
+			name:                 "security groups of VPC",
+			securityGroups:       []string{"sg-1", "sg-2"},
+			subnetVPCs:           map[string]string{"subnet-private": "vpc-1", "subnet-public": "vpc-1"},
+			vpcState:             ec2.VpcStateAvailable,
+			securityGroupVPCs:    map[string]string{"sg-1": "vpc-1", "sg-2": "vpc-1"},
+			expectDescribeVPCs:   true,
+			expectDescribeGroups: true,
+			expectedAllowed:      true,
+		},
+		{
+			name:                 "security group of other VPC",
+			securityGroups:       []string{"sg-1", "sg-2"},
+			subnetVPCs:           map[string]string{"subnet-private": "vpc-1", "subnet-public": "vpc-1"},
+			vpcState:             ec2.VpcStateAvailable,
+			securityGroupVPCs:    map[string]string{"sg-1": "vpc-1", "sg-2": "vpc-2"},
+			expectDescribeVPCs:   true,
+			expectDescribeGroups: true,
+		},
+		{
+			name:                 "missing security group",
+			securityGroups:       []string{"sg-1", "sg-2"},
+			subnetVPCs:           map[string]string{"subnet-private": "vpc-1", "subnet-public": "vpc-1"},
+			vpcState:             ec2.VpcStateAvailable,
+			securityGroupVPCs:    map[string]string{"sg-1": "vpc-1"},
+			expectDescribeVPCs:   true,
+			expectDescribeGroups: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockAWSClient := mockaws.NewMockClient(mockCtrl)
+
+			subnets := []*ec2.Subnet{}
+			for id, vpc := range tc.subnetVPCs {
+				subnets = append(subnets, &ec2.Subnet{SubnetId: aws.String(id), VpcId: aws.String(vpc)})
+			}
+			mockAWSClient.EXPECT().DescribeSubnets(gomock.Any()).Return(&ec2.DescribeSubnetsOutput{Subnets: subnets}, nil)
+			if tc.expectDescribeVPCs {
+				mockAWSClient.EXPECT().DescribeVpcs(gomock.Any()).Return(&ec2.DescribeVpcsOutput{
+					Vpcs: []*ec2.Vpc{{VpcId: aws.String("vpc-1"), State: aws.String(tc.vpcState)}},
+				}, nil)
+			}
+			if tc.expectDescribeGroups {
+				groups := []*ec2.SecurityGroup{}
+				for id, vpc := range tc.securityGroupVPCs {
+					groups = append(groups, &ec2.SecurityGroup{GroupId: aws.String(id), VpcId: aws.String(vpc)})
+				}
+				mockAWSClient.EXPECT().DescribeSecurityGroups(gomock.Any()).Return(&ec2.DescribeSecurityGroupsOutput{SecurityGroups: groups}, nil)
+			}
+
+			data := ClusterDeploymentValidatingAdmissionHook{
+				validManagedDomains: validTestManagedDomains,
+				kubeClient:          fake.NewFakeClient(),
+				awsClientBuilder: func(client.Client, string, string, string) (awsclient.Client, error) {
+					return mockAWSClient, nil
+				},
+			}
+			cd := validAWSExistingNetworkClusterDeployment()
+			cd.Spec.Platform.AWS.AdditionalSecurityGroupIDs = tc.securityGroups
+			raw, _ := json.Marshal(cd)
+			request := &admissionv1beta1.AdmissionRequest{
+				Operation: admissionv1beta1.Create,
+				Resource: metav1.GroupVersionResource{
+					Group:    "hive.openshift.io",
+					Version:  "v1",
+					Resource: "clusterdeployments",
+				},
+				Object: runtime.RawExtension{Raw: raw},
+			}
+
+			response := data.Validate(request)
+
+			if !assert.Equal(t, tc.expectedAllowed, response.Allowed) {
+				t.Logf("Response result = %#v", response.Result)
+			}
+		})
+	}
+}
+
 func TestNewClusterDeploymentValidatingAdmissionHook(t *testing.T) {
 	tempFile, err := ioutil.TempFile("", "")
 	if err != nil {
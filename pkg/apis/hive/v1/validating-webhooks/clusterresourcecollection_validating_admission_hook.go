@@ -0,0 +1,225 @@
+package validatingwebhooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"net/http"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	clusterResourceCollectionGroup    = "hive.openshift.io"
+	clusterResourceCollectionVersion  = "v1"
+	clusterResourceCollectionResource = "clusterresourcecollections"
+)
+
+// sensitiveCollectedKinds are the kinds whose objects hold credentials, and which must not be copied from the remote
+// clusters to ConfigMaps on the hub.
+var sensitiveCollectedKinds = []schema.GroupKind{
+	{Group: "", Kind: "Secret"},
+	{Group: "oauth.openshift.io", Kind: "OAuthAccessToken"},
+	{Group: "oauth.openshift.io", Kind: "OAuthAuthorizeToken"},
+}
+
+// ClusterResourceCollectionValidatingAdmissionHook is a struct that is used to reference what code should be run by the generic-admission-server.
+type ClusterResourceCollectionValidatingAdmissionHook struct{}
+
+// ValidatingResource is called by generic-admission-server on startup to register the returned REST resource through which the
+//                    webhook is accessed by the kube apiserver.
+// For example, generic-admission-server uses the data below to register the webhook on the REST resource "/apis/admission.hive.openshift.io/v1/clusterresourcecollectionvalidators".
+//              When the kube apiserver calls this registered REST resource, the generic-admission-server calls the Validate() method below.
+func (a *ClusterResourceCollectionValidatingAdmissionHook) ValidatingResource() (plural schema.GroupVersionResource, singular string) {
+	log.WithFields(log.Fields{
+		"group":    "admission.hive.openshift.io",
+		"version":  "v1",
+		"resource": "clusterresourcecollectionvalidator",
+	}).Info("Registering validation REST resource")
+	// NOTE: This GVR is meant to be different than the ClusterResourceCollection CRD GVR which has group "hive.openshift.io".
+	return schema.GroupVersionResource{
+			Group:    "admission.hive.openshift.io",
+			Version:  "v1",
+			Resource: "clusterresourcecollectionvalidators",
+		},
+		"clusterresourcecollectionvalidator"
+}
+
+// Initialize is called by generic-admission-server on startup to setup any special initialization that your webhook needs.
+func (a *ClusterResourceCollectionValidatingAdmissionHook) Initialize(kubeClientConfig *rest.Config, stopCh <-chan struct{}) error {
+	log.WithFields(log.Fields{
+		"group":    "admission.hive.openshift.io",
+		"version":  "v1",
+		"resource": "clusterresourcecollectionvalidator",
+	}).Info("Initializing validation REST resource")
+	return nil // No initialization needed right now.
+}
+
+// Validate is called by generic-admission-server when the registered REST resource above is called with an admission request.
+// Usually it's the kube apiserver that is making the admission validation request.
+func (a *ClusterResourceCollectionValidatingAdmissionHook) Validate(admissionSpec *admissionv1beta1.AdmissionRequest) *admissionv1beta1.AdmissionResponse {
+	contextLogger := log.WithFields(log.Fields{
+		"operation": admissionSpec.Operation,
+		"group":     admissionSpec.Resource.Group,
+		"version":   admissionSpec.Resource.Version,
+		"resource":  admissionSpec.Resource.Resource,
+		"method":    "Validate",
+	})
+
+	if !a.shouldValidate(admissionSpec) {
+		contextLogger.Info("Skipping validation for request")
+		// The request object isn't something that this validator should validate.
+		// Therefore, we say that it's allowed.
+		return &admissionv1beta1.AdmissionResponse{
+			Allowed: true,
+		}
+	}
+
+	contextLogger.Info("Validating request")
+
+	if admissionSpec.Operation == admissionv1beta1.Create || admissionSpec.Operation == admissionv1beta1.Update {
+		return a.validateCreateOrUpdate(admissionSpec)
+	}
+
+	// We're only validating creates and updates at this time, so all other operations are explicitly allowed.
+	contextLogger.Info("Successful validation")
+	return &admissionv1beta1.AdmissionResponse{
+		Allowed: true,
+	}
+}
+
+// shouldValidate explicitly checks if the request should validated. For example, this webhook may have accidentally been registered to check
+// the validity of some other type of object with a different GVR.
+func (a *ClusterResourceCollectionValidatingAdmissionHook) shouldValidate(admissionSpec *admissionv1beta1.AdmissionRequest) bool {
+	contextLogger := log.WithFields(log.Fields{
+		"operation": admissionSpec.Operation,
+		"group":     admissionSpec.Resource.Group,
+		"version":   admissionSpec.Resource.Version,
+		"resource":  admissionSpec.Resource.Resource,
+		"method":    "shouldValidate",
+	})
+
+	if admissionSpec.Resource.Group != clusterResourceCollectionGroup {
+		contextLogger.Debug("Returning False, not our group")
+		return false
+	}
+
+	if admissionSpec.Resource.Version != clusterResourceCollectionVersion {
+		contextLogger.Debug("Returning False, it's our group, but not the right version")
+		return false
+	}
+
+	if admissionSpec.Resource.Resource != clusterResourceCollectionResource {
+		contextLogger.Debug("Returning False, it's our group and version, but not the right resource")
+		return false
+	}
+
+	// If we get here, then we're supposed to validate the object.
+	contextLogger.Debug("Returning True, passed all prerequisites.")
+	return true
+}
+
+// validateCreateOrUpdate validates create and update operations for ClusterResourceCollection objects. The spec of
+// a ClusterResourceCollection is mutable, so updates are validated the same way as creates.
+func (a *ClusterResourceCollectionValidatingAdmissionHook) validateCreateOrUpdate(admissionSpec *admissionv1beta1.AdmissionRequest) *admissionv1beta1.AdmissionResponse {
+	contextLogger := log.WithFields(log.Fields{
+		"operation": admissionSpec.Operation,
+		"group":     admissionSpec.Resource.Group,
+		"version":   admissionSpec.Resource.Version,
+		"resource":  admissionSpec.Resource.Resource,
+		"method":    "validateCreateOrUpdate",
+	})
+
+	newObject := &hivev1.ClusterResourceCollection{}
+	err := json.Unmarshal(admissionSpec.Object.Raw, newObject)
+	if err != nil {
+		contextLogger.Errorf("Failed unmarshaling Object: %v", err.Error())
+		return &admissionv1beta1.AdmissionResponse{
+			Allowed: false,
+			Result: &metav1.Status{
+				Status: metav1.StatusFailure, Code: http.StatusBadRequest, Reason: metav1.StatusReasonBadRequest,
+				Message: err.Error(),
+			},
+		}
+	}
+
+	// Add the new data to the contextLogger
+	contextLogger.Data["object.Name"] = newObject.Name
+
+	allErrs := field.ErrorList{}
+	if _, err := metav1.LabelSelectorAsSelector(&newObject.Spec.ClusterDeploymentSelector); err != nil {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "clusterDeploymentSelector"), newObject.Spec.ClusterDeploymentSelector, err.Error()))
+	}
+	allErrs = append(allErrs, validateCollectedResources(newObject.Spec.Resources, field.NewPath("spec", "resources"))...)
+	if newObject.Spec.Interval != nil && newObject.Spec.Interval.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "interval"), newObject.Spec.Interval.Duration.String(), "must not be negative"))
+	}
+
+	if len(allErrs) > 0 {
+		statusError := errors.NewInvalid(newObject.GroupVersionKind().GroupKind(), newObject.Name, allErrs).Status()
+		contextLogger.Info(statusError.Message)
+		return &admissionv1beta1.AdmissionResponse{
+			Allowed: false,
+			Result:  &statusError,
+		}
+	}
+
+	// If we get here, then all checks passed, so the object is valid.
+	contextLogger.Info("Successful validation")
+	return &admissionv1beta1.AdmissionResponse{
+		Allowed: true,
+	}
+}
+
+func validateCollectedResources(resources []hivev1.CollectedResource, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(resources) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath, "at least one resource must be collected"))
+	}
+	for i, resource := range resources {
+		allErrs = append(allErrs, validateCollectedResource(resource, fldPath.Index(i))...)
+	}
+	return allErrs
+}
+
+func validateCollectedResource(resource hivev1.CollectedResource, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	gv, err := schema.ParseGroupVersion(resource.APIVersion)
+	switch {
+	case resource.APIVersion == "":
+		allErrs = append(allErrs, field.Required(fldPath.Child("apiVersion"), "must specify an apiVersion"))
+	case err != nil:
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("apiVersion"), resource.APIVersion, err.Error()))
+	}
+	if resource.Kind == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("kind"), "must specify a kind"))
+	}
+	for _, sensitive := range sensitiveCollectedKinds {
+		// Kinds are compared case-insensitively so that the check cannot be bypassed by changing the case of the kind
+		if err == nil && gv.Group == sensitive.Group && strings.EqualFold(resource.Kind, sensitive.Kind) {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("kind"), fmt.Sprintf("%s objects hold credentials and cannot be collected", sensitive.Kind)))
+		}
+	}
+	if resource.Namespace != "" {
+		for _, msg := range apivalidation.ValidateNamespaceName(resource.Namespace, false) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("namespace"), resource.Namespace, msg))
+		}
+	}
+	if resource.LabelSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(resource.LabelSelector); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("labelSelector"), resource.LabelSelector, err.Error()))
+		}
+	}
+	return allErrs
+}
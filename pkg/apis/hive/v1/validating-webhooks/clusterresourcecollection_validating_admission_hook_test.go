@@ -0,0 +1,253 @@
+package validatingwebhooks
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/stretchr/testify/assert"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestClusterResourceCollectionValidatingResource(t *testing.T) {
+	// Arrange
+	data := ClusterResourceCollectionValidatingAdmissionHook{}
+	expectedPlural := schema.GroupVersionResource{
+		Group:    "admission.hive.openshift.io",
+		Version:  "v1",
+		Resource: "clusterresourcecollectionvalidators",
+	}
+	expectedSingular := "clusterresourcecollectionvalidator"
+
+	// Act
+	plural, singular := data.ValidatingResource()
+
+	// Assert
+	assert.Equal(t, expectedPlural, plural)
+	assert.Equal(t, expectedSingular, singular)
+}
+
+func TestClusterResourceCollectionInitialize(t *testing.T) {
+	// Arrange
+	data := ClusterResourceCollectionValidatingAdmissionHook{}
+
+	// Act
+	err := data.Initialize(nil, nil)
+
+	// Assert
+	assert.Nil(t, err)
+}
+
+func validClusterResourceCollectionSpec() hivev1.ClusterResourceCollectionSpec {
+	return hivev1.ClusterResourceCollectionSpec{
+		ClusterDeploymentSelector: metav1.LabelSelector{
+			MatchLabels: map[string]string{"environment": "production"},
+		},
+		Resources: []hivev1.CollectedResource{
+			{
+				APIVersion: "operators.coreos.com/v1alpha1",
+				Kind:       "Subscription",
+			},
+			{
+				APIVersion: "v1",
+				Kind:       "ConfigMap",
+				Namespace:  "openshift-config",
+				LabelSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"inventory": "true"},
+				},
+			},
+		},
+	}
+}
+
+func TestClusterResourceCollectionValidate(t *testing.T) {
+	cases := []struct {
+		name            string
+		modify          func(*hivev1.ClusterResourceCollectionSpec)
+		newObjectRaw    []byte
+		operation       admissionv1beta1.Operation
+		expectedAllowed bool
+		gvr             *metav1.GroupVersionResource
+	}{
+		{
+			name:            "Test valid create",
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: true,
+		},
+		{
+			name:            "Test valid update",
+			operation:       admissionv1beta1.Update,
+			expectedAllowed: true,
+		},
+		{
+			name:            "Test unable to marshal new object during create",
+			newObjectRaw:    []byte{0},
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name: "Test secrets cannot be collected",
+			modify: func(spec *hivev1.ClusterResourceCollectionSpec) {
+				spec.Resources = append(spec.Resources, hivev1.CollectedResource{APIVersion: "v1", Kind: "Secret"})
+			},
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name: "Test secrets cannot be collected with a differently cased kind",
+			modify: func(spec *hivev1.ClusterResourceCollectionSpec) {
+				spec.Resources = append(spec.Resources, hivev1.CollectedResource{APIVersion: "v1", Kind: "secret"})
+			},
+			operation:       admissionv1beta1.Update,
+			expectedAllowed: false,
+		},
+		{
+			name: "Test oauth access tokens cannot be collected",
+			modify: func(spec *hivev1.ClusterResourceCollectionSpec) {
+				spec.Resources = append(spec.Resources, hivev1.CollectedResource{APIVersion: "oauth.openshift.io/v1", Kind: "OAuthAccessToken"})
+			},
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name: "Test kind named Secret in another group can be collected",
+			modify: func(spec *hivev1.ClusterResourceCollectionSpec) {
+				spec.Resources = append(spec.Resources, hivev1.CollectedResource{APIVersion: "example.com/v1", Kind: "Secret"})
+			},
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: true,
+		},
+		{
+			name: "Test no resources",
+			modify: func(spec *hivev1.ClusterResourceCollectionSpec) {
+				spec.Resources = nil
+			},
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name: "Test missing apiVersion",
+			modify: func(spec *hivev1.ClusterResourceCollectionSpec) {
+				spec.Resources[0].APIVersion = ""
+			},
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name: "Test malformed apiVersion",
+			modify: func(spec *hivev1.ClusterResourceCollectionSpec) {
+				spec.Resources[0].APIVersion = "operators.coreos.com/v1alpha1/extra"
+			},
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name: "Test missing kind",
+			modify: func(spec *hivev1.ClusterResourceCollectionSpec) {
+				spec.Resources[0].Kind = ""
+			},
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name: "Test invalid namespace",
+			modify: func(spec *hivev1.ClusterResourceCollectionSpec) {
+				spec.Resources[1].Namespace = "Not_A_Namespace"
+			},
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name: "Test malformed resource label selector",
+			modify: func(spec *hivev1.ClusterResourceCollectionSpec) {
+				spec.Resources[1].LabelSelector = &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "inventory", Operator: "BadOperator"}},
+				}
+			},
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name: "Test malformed cluster deployment selector",
+			modify: func(spec *hivev1.ClusterResourceCollectionSpec) {
+				spec.ClusterDeploymentSelector = metav1.LabelSelector{
+					MatchLabels: map[string]string{"not a valid key!": "true"},
+				}
+			},
+			operation:       admissionv1beta1.Update,
+			expectedAllowed: false,
+		},
+		{
+			name: "Test negative interval",
+			modify: func(spec *hivev1.ClusterResourceCollectionSpec) {
+				spec.Interval = &metav1.Duration{Duration: -time.Hour}
+			},
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name: "Test that we don't validate deletes",
+			modify: func(spec *hivev1.ClusterResourceCollectionSpec) {
+				spec.Resources = nil
+			},
+			operation:       admissionv1beta1.Delete,
+			expectedAllowed: true,
+		},
+		{
+			name: "Test doesn't validate with right version and resource, but wrong group",
+			modify: func(spec *hivev1.ClusterResourceCollectionSpec) {
+				spec.Resources = nil
+			},
+			gvr: &metav1.GroupVersionResource{
+				Group:    "not the right group",
+				Version:  "v1",
+				Resource: "clusterresourcecollections",
+			},
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			data := ClusterResourceCollectionValidatingAdmissionHook{}
+			newObject := &hivev1.ClusterResourceCollection{
+				Spec: validClusterResourceCollectionSpec(),
+			}
+			if tc.modify != nil {
+				tc.modify(&newObject.Spec)
+			}
+
+			if tc.newObjectRaw == nil {
+				tc.newObjectRaw, _ = json.Marshal(newObject)
+			}
+
+			if tc.gvr == nil {
+				tc.gvr = &metav1.GroupVersionResource{
+					Group:    "hive.openshift.io",
+					Version:  "v1",
+					Resource: "clusterresourcecollections",
+				}
+			}
+
+			request := &admissionv1beta1.AdmissionRequest{
+				Operation: tc.operation,
+				Resource:  *tc.gvr,
+				Object: runtime.RawExtension{
+					Raw: tc.newObjectRaw,
+				},
+			}
+
+			// Act
+			response := data.Validate(request)
+
+			// Assert
+			assert.Equal(t, tc.expectedAllowed, response.Allowed)
+		})
+	}
+}
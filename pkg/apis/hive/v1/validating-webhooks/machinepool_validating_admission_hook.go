@@ -2,6 +2,7 @@ package validatingwebhooks
 
 import (
 	"fmt"
+	"strconv"
 
 	log "github.com/sirupsen/logrus"
 
@@ -15,6 +16,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/rest"
 
@@ -250,6 +252,24 @@ func validateMachinePoolSpecInvariants(spec *hivev1.MachinePoolSpec, fldPath *fi
 	if spec.Replicas != nil && *spec.Replicas < 0 {
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("replicas"), *spec.Replicas, "replicas count must not be negative"))
 	}
+	if spec.Autoscaling != nil {
+		autoscalingPath := fldPath.Child("autoscaling")
+		if spec.Replicas != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("replicas"), "replicas must not be specified when autoscaling is specified"))
+		}
+		if spec.Autoscaling.MinReplicas < 0 {
+			allErrs = append(allErrs, field.Invalid(autoscalingPath.Child("minReplicas"), spec.Autoscaling.MinReplicas, "minimum replicas must not be negative"))
+		}
+		if spec.Autoscaling.MaxReplicas < 1 {
+			allErrs = append(allErrs, field.Invalid(autoscalingPath.Child("maxReplicas"), spec.Autoscaling.MaxReplicas, "maximum replicas must be at least 1"))
+		}
+		if spec.Autoscaling.MinReplicas > spec.Autoscaling.MaxReplicas {
+			allErrs = append(allErrs, field.Invalid(autoscalingPath.Child("minReplicas"), spec.Autoscaling.MinReplicas, "minimum replicas must not be greater than maximum replicas"))
+		}
+	}
+	if spec.HealthCheck != nil {
+		allErrs = append(allErrs, validateMachinePoolHealthCheck(spec.HealthCheck, fldPath.Child("healthCheck"))...)
+	}
 	platformPath := fldPath.Child("platform")
 	platforms := []string{}
 	if spec.Platform.AWS != nil {
@@ -279,6 +299,35 @@ func validateMachinePoolSpecInvariants(spec *hivev1.MachinePoolSpec, fldPath *fi
 	return allErrs
 }
 
+func validateMachinePoolHealthCheck(healthCheck *hivev1.MachinePoolHealthCheck, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, condition := range healthCheck.UnhealthyConditions {
+		conditionPath := fldPath.Child("unhealthyConditions").Index(i)
+		if condition.Type == "" {
+			allErrs = append(allErrs, field.Required(conditionPath.Child("type"), "condition type is required"))
+		}
+		if condition.Status == "" {
+			allErrs = append(allErrs, field.Required(conditionPath.Child("status"), "condition status is required"))
+		}
+		if condition.Timeout.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(conditionPath.Child("timeout"), condition.Timeout.Duration.String(), "timeout must be positive"))
+		}
+	}
+	if maxUnhealthy := healthCheck.MaxUnhealthy; maxUnhealthy != nil {
+		value, err := intstr.GetValueFromIntOrPercent(maxUnhealthy, 100, false)
+		switch {
+		case err != nil:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("maxUnhealthy"), maxUnhealthy.String(), "maximum unhealthy machines must be a number or a percentage"))
+		case value < 0:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("maxUnhealthy"), maxUnhealthy.String(), "maximum unhealthy machines must not be negative"))
+		}
+	}
+	if timeout := healthCheck.NodeStartupTimeout; timeout != nil && timeout.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("nodeStartupTimeout"), timeout.Duration.String(), "node startup timeout must not be negative"))
+	}
+	return allErrs
+}
+
 func validateAWSMachinePoolPlatformInvariants(platform *hivev1aws.MachinePoolPlatform, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	for i, zone := range platform.Zones {
@@ -300,6 +349,11 @@ func validateAWSMachinePoolPlatformInvariants(platform *hivev1aws.MachinePoolPla
 	if rootVolume.Type == "" {
 		allErrs = append(allErrs, field.Required(rootVolumePath.Child("type"), "volume type is required"))
 	}
+	if spot := platform.SpotMarketOptions; spot != nil && spot.MaxPrice != nil {
+		if price, err := strconv.ParseFloat(*spot.MaxPrice, 64); err != nil || price <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("spotMarketOptions", "maxPrice"), *spot.MaxPrice, "maximum price must be a positive number"))
+		}
+	}
 	return allErrs
 }
 
@@ -318,6 +372,9 @@ func validateGCPMachinePoolPlatformInvariants(platform *hivev1gcp.MachinePool, f
 
 func validateAzureMachinePoolPlatformInvariants(platform *hivev1azure.MachinePool, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
+	if len(platform.Zones) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("zones"), "zones are required, as not every Azure region has availability zones"))
+	}
 	for i, zone := range platform.Zones {
 		if zone == "" {
 			allErrs = append(allErrs, field.Invalid(fldPath.Child("zones").Index(i), zone, "zone cannot be an empty string"))
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -11,6 +12,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/pointer"
 
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
@@ -172,6 +174,139 @@ func Test_MachinePoolAdmission_Validate_Create(t *testing.T) {
 				return pool
 			}(),
 		},
+		{
+			name: "autoscaling",
+			provision: func() *hivev1.MachinePool {
+				pool := testMachinePool()
+				pool.Spec.Replicas = nil
+				pool.Spec.Autoscaling = &hivev1.MachinePoolAutoscaling{MinReplicas: 3, MaxReplicas: 6}
+				return pool
+			}(),
+			expectAllowed: true,
+		},
+		{
+			name: "autoscaling with replicas",
+			provision: func() *hivev1.MachinePool {
+				pool := testMachinePool()
+				pool.Spec.Replicas = pointer.Int64Ptr(3)
+				pool.Spec.Autoscaling = &hivev1.MachinePoolAutoscaling{MinReplicas: 3, MaxReplicas: 6}
+				return pool
+			}(),
+		},
+		{
+			name: "autoscaling with negative min replicas",
+			provision: func() *hivev1.MachinePool {
+				pool := testMachinePool()
+				pool.Spec.Replicas = nil
+				pool.Spec.Autoscaling = &hivev1.MachinePoolAutoscaling{MinReplicas: -1, MaxReplicas: 6}
+				return pool
+			}(),
+		},
+		{
+			name: "autoscaling with zero max replicas",
+			provision: func() *hivev1.MachinePool {
+				pool := testMachinePool()
+				pool.Spec.Replicas = nil
+				pool.Spec.Autoscaling = &hivev1.MachinePoolAutoscaling{MinReplicas: 0, MaxReplicas: 0}
+				return pool
+			}(),
+		},
+		{
+			name: "autoscaling with min replicas greater than max replicas",
+			provision: func() *hivev1.MachinePool {
+				pool := testMachinePool()
+				pool.Spec.Replicas = nil
+				pool.Spec.Autoscaling = &hivev1.MachinePoolAutoscaling{MinReplicas: 6, MaxReplicas: 3}
+				return pool
+			}(),
+		},
+		{
+			name: "health check",
+			provision: func() *hivev1.MachinePool {
+				pool := testMachinePool()
+				maxUnhealthy := intstr.FromString("40%")
+				pool.Spec.HealthCheck = &hivev1.MachinePoolHealthCheck{
+					UnhealthyConditions: []hivev1.MachinePoolUnhealthyCondition{
+						{Type: corev1.NodeReady, Status: corev1.ConditionUnknown, Timeout: metav1.Duration{Duration: 300 * time.Second}},
+					},
+					MaxUnhealthy:       &maxUnhealthy,
+					NodeStartupTimeout: &metav1.Duration{Duration: 10 * time.Minute},
+				}
+				return pool
+			}(),
+			expectAllowed: true,
+		},
+		{
+			name: "health check with default conditions",
+			provision: func() *hivev1.MachinePool {
+				pool := testMachinePool()
+				pool.Spec.HealthCheck = &hivev1.MachinePoolHealthCheck{}
+				return pool
+			}(),
+			expectAllowed: true,
+		},
+		{
+			name: "health check condition without status",
+			provision: func() *hivev1.MachinePool {
+				pool := testMachinePool()
+				pool.Spec.HealthCheck = &hivev1.MachinePoolHealthCheck{
+					UnhealthyConditions: []hivev1.MachinePoolUnhealthyCondition{
+						{Type: corev1.NodeReady, Timeout: metav1.Duration{Duration: 300 * time.Second}},
+					},
+				}
+				return pool
+			}(),
+		},
+		{
+			name: "health check condition with negative timeout",
+			provision: func() *hivev1.MachinePool {
+				pool := testMachinePool()
+				pool.Spec.HealthCheck = &hivev1.MachinePoolHealthCheck{
+					UnhealthyConditions: []hivev1.MachinePoolUnhealthyCondition{
+						{Type: corev1.NodeReady, Status: corev1.ConditionFalse, Timeout: metav1.Duration{Duration: -time.Second}},
+					},
+				}
+				return pool
+			}(),
+		},
+		{
+			name: "health check condition with zero timeout",
+			provision: func() *hivev1.MachinePool {
+				pool := testMachinePool()
+				pool.Spec.HealthCheck = &hivev1.MachinePoolHealthCheck{
+					UnhealthyConditions: []hivev1.MachinePoolUnhealthyCondition{
+						{Type: corev1.NodeReady, Status: corev1.ConditionFalse},
+					},
+				}
+				return pool
+			}(),
+		},
+		{
+			name: "health check with negative max unhealthy",
+			provision: func() *hivev1.MachinePool {
+				pool := testMachinePool()
+				maxUnhealthy := intstr.FromInt(-1)
+				pool.Spec.HealthCheck = &hivev1.MachinePoolHealthCheck{MaxUnhealthy: &maxUnhealthy}
+				return pool
+			}(),
+		},
+		{
+			name: "health check with invalid max unhealthy",
+			provision: func() *hivev1.MachinePool {
+				pool := testMachinePool()
+				maxUnhealthy := intstr.FromString("most")
+				pool.Spec.HealthCheck = &hivev1.MachinePoolHealthCheck{MaxUnhealthy: &maxUnhealthy}
+				return pool
+			}(),
+		},
+		{
+			name: "health check with negative node startup timeout",
+			provision: func() *hivev1.MachinePool {
+				pool := testMachinePool()
+				pool.Spec.HealthCheck = &hivev1.MachinePoolHealthCheck{NodeStartupTimeout: &metav1.Duration{Duration: -time.Minute}}
+				return pool
+			}(),
+		},
 		{
 			name: "missing platform",
 			provision: func() *hivev1.MachinePool {
@@ -237,6 +372,41 @@ func Test_MachinePoolAdmission_Validate_Create(t *testing.T) {
 				return pool
 			}(),
 		},
+		{
+			name: "AWS spot instances",
+			provision: func() *hivev1.MachinePool {
+				pool := testAWSMachinePool()
+				pool.Spec.Platform.AWS.SpotMarketOptions = &hivev1aws.SpotMarketOptions{}
+				return pool
+			}(),
+			expectAllowed: true,
+		},
+		{
+			name: "AWS spot instances with max price",
+			provision: func() *hivev1.MachinePool {
+				pool := testAWSMachinePool()
+				pool.Spec.Platform.AWS.SpotMarketOptions = &hivev1aws.SpotMarketOptions{MaxPrice: pointer.StringPtr("0.05")}
+				return pool
+			}(),
+			expectAllowed: true,
+		},
+		{
+			name: "invalid AWS spot max price",
+			provision: func() *hivev1.MachinePool {
+				pool := testAWSMachinePool()
+				pool.Spec.Platform.AWS.SpotMarketOptions = &hivev1aws.SpotMarketOptions{MaxPrice: pointer.StringPtr("cheap")}
+				return pool
+			}(),
+		},
+		{
+			name: "GCP preemptible instances",
+			provision: func() *hivev1.MachinePool {
+				pool := testGCPMachinePool()
+				pool.Spec.Platform.GCP.Preemptible = true
+				return pool
+			}(),
+			expectAllowed: true,
+		},
 		{
 			name: "non-default GCP pool",
 			provision: func() *hivev1.MachinePool {
@@ -280,6 +450,14 @@ func Test_MachinePoolAdmission_Validate_Create(t *testing.T) {
 			}(),
 			expectAllowed: true,
 		},
+		{
+			name: "missing Azure zones",
+			provision: func() *hivev1.MachinePool {
+				pool := testAzureMachinePool()
+				pool.Spec.Platform.Azure.Zones = nil
+				return pool
+			}(),
+		},
 		{
 			name: "empty Azure zone name",
 			provision: func() *hivev1.MachinePool {
@@ -488,6 +666,7 @@ func validGCPMachinePoolPlatform() *hivev1gcp.MachinePool {
 
 func validAzureMachinePoolPlatform() *hivev1azure.MachinePool {
 	return &hivev1azure.MachinePool{
+		Zones:        []string{"1"},
 		InstanceType: "test-instance-type",
 		OSDisk: hivev1azure.OSDisk{
 			DiskSizeGB: 1,
@@ -8,6 +8,7 @@ import (
 	"net/http"
 
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/syncsetmatch"
 
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -24,7 +25,9 @@ const (
 )
 
 // SelectorSyncSetValidatingAdmissionHook is a struct that is used to reference what code should be run by the generic-admission-server.
-type SelectorSyncSetValidatingAdmissionHook struct{}
+type SelectorSyncSetValidatingAdmissionHook struct {
+	conflictChecker *syncSetConflictChecker
+}
 
 // ValidatingResource is called by generic-admission-server on startup to register the returned REST resource through which the
 //                    webhook is accessed by the kube apiserver.
@@ -52,7 +55,12 @@ func (a *SelectorSyncSetValidatingAdmissionHook) Initialize(kubeClientConfig *re
 		"version":  "v1",
 		"resource": "selectorsyncsetvalidator",
 	}).Info("Initializing validation REST resource")
-	return nil // No initialization needed right now.
+	conflictChecker, err := newSyncSetConflictChecker(kubeClientConfig, stopCh)
+	if err != nil {
+		return err
+	}
+	a.conflictChecker = conflictChecker
+	return nil
 }
 
 // Validate is called by generic-admission-server when the registered REST resource above is called with an admission request.
@@ -153,6 +161,16 @@ func (a *SelectorSyncSetValidatingAdmissionHook) validateCreate(admissionSpec *a
 	allErrs = append(allErrs, validateResources(newObject.Spec.Resources, field.NewPath("spec").Child("resources"))...)
 	allErrs = append(allErrs, validatePatches(newObject.Spec.Patches, field.NewPath("spec").Child("patches"))...)
 	allErrs = append(allErrs, validateSecretReferences(newObject.Spec.SecretReferences, field.NewPath("spec").Child("secretReferences"))...)
+	allErrs = append(allErrs, validateConfigMapReferences(newObject.Spec.ConfigMapReferences, field.NewPath("spec").Child("configMapReferences"))...)
+	allErrs = append(allErrs, validateDeletions(newObject.Spec.Deletions, newObject.Spec.Resources, field.NewPath("spec").Child("deletions"))...)
+	allErrs = append(allErrs, validateResourceTemplates(&newObject.Spec.SyncSetCommonSpec, field.NewPath("spec").Child("enableResourceTemplates"))...)
+	allErrs = append(allErrs, validateRollout(newObject.Spec.Rollout, field.NewPath("spec").Child("rollout"))...)
+	allErrs = append(allErrs, validateClusterMatch(newObject.Spec.ClusterMatch, field.NewPath("spec").Child("clusterMatch"))...)
+	allErrs = append(allErrs, validateSyncSetPriority(newObject.Annotations, field.NewPath("metadata").Child("annotations"))...)
+
+	if a.conflictChecker != nil && len(allErrs) == 0 {
+		allErrs = append(allErrs, a.conflictChecker.validate(selectorSyncSetConflictSource(newObject), field.NewPath("spec"))...)
+	}
 
 	if len(allErrs) > 0 {
 		statusError := errors.NewInvalid(newObject.GroupVersionKind().GroupKind(), newObject.Name, allErrs).Status()
@@ -200,6 +218,28 @@ func (a *SelectorSyncSetValidatingAdmissionHook) validateUpdate(admissionSpec *a
 	allErrs = append(allErrs, validateResources(newObject.Spec.Resources, field.NewPath("spec", "resources"))...)
 	allErrs = append(allErrs, validatePatches(newObject.Spec.Patches, field.NewPath("spec", "patches"))...)
 	allErrs = append(allErrs, validateSecretReferences(newObject.Spec.SecretReferences, field.NewPath("spec", "secretReferences"))...)
+	allErrs = append(allErrs, validateConfigMapReferences(newObject.Spec.ConfigMapReferences, field.NewPath("spec", "configMapReferences"))...)
+	allErrs = append(allErrs, validateDeletions(newObject.Spec.Deletions, newObject.Spec.Resources, field.NewPath("spec", "deletions"))...)
+	allErrs = append(allErrs, validateResourceTemplates(&newObject.Spec.SyncSetCommonSpec, field.NewPath("spec", "enableResourceTemplates"))...)
+	allErrs = append(allErrs, validateRollout(newObject.Spec.Rollout, field.NewPath("spec", "rollout"))...)
+	allErrs = append(allErrs, validateClusterMatch(newObject.Spec.ClusterMatch, field.NewPath("spec", "clusterMatch"))...)
+	allErrs = append(allErrs, validateSyncSetPriority(newObject.Annotations, field.NewPath("metadata", "annotations"))...)
+
+	if a.conflictChecker != nil && len(allErrs) == 0 {
+		oldObject := &hivev1.SelectorSyncSet{}
+		if err := json.Unmarshal(admissionSpec.OldObject.Raw, oldObject); err != nil {
+			contextLogger.Errorf("Failed unmarshaling OldObject: %v", err.Error())
+			return &admissionv1beta1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Status: metav1.StatusFailure, Code: http.StatusBadRequest, Reason: metav1.StatusReasonBadRequest,
+					Message: err.Error(),
+				},
+			}
+		}
+		oldSource := selectorSyncSetConflictSource(oldObject)
+		allErrs = append(allErrs, a.conflictChecker.validateUpdate(selectorSyncSetConflictSource(newObject), &oldSource, field.NewPath("spec"))...)
+	}
 
 	if len(allErrs) > 0 {
 		statusError := errors.NewInvalid(newObject.GroupVersionKind().GroupKind(), newObject.Name, allErrs).Status()
@@ -216,3 +256,37 @@ func (a *SelectorSyncSetValidatingAdmissionHook) validateUpdate(admissionSpec *a
 		Allowed: true,
 	}
 }
+
+func validateRollout(rollout *hivev1.SelectorSyncSetRolloutStrategy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if rollout == nil {
+		return allErrs
+	}
+	if rollout.CanarySelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(rollout.CanarySelector); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("canarySelector"), rollout.CanarySelector, err.Error()))
+		}
+	}
+	if rollout.MaxUnavailablePercentage < 0 || rollout.MaxUnavailablePercentage > 100 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxUnavailablePercentage"), rollout.MaxUnavailablePercentage, "must be between 0 and 100"))
+	}
+	return allErrs
+}
+
+func validateClusterMatch(match *hivev1.SelectorSyncSetClusterMatch, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if match == nil {
+		return allErrs
+	}
+	if match.VersionRange != "" {
+		if _, err := syncsetmatch.ParseVersionRange(match.VersionRange); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("versionRange"), match.VersionRange, err.Error()))
+		}
+	}
+	for i, platform := range match.Platforms {
+		if !syncsetmatch.IsSupportedPlatform(platform) {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("platforms").Index(i), platform, syncsetmatch.Platforms))
+		}
+	}
+	return allErrs
+}
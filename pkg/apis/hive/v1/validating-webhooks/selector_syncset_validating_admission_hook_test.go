@@ -356,6 +356,80 @@ func TestSelectorSyncSetValidate(t *testing.T) {
 			selectorSyncSet: testSelectorSyncSetWithResources(`{"apiVersion": "authorization.openshift.io/v1", "kind": "SubjectAccessReview"}`),
 			expectedAllowed: false,
 		},
+		{
+			name:            "Test valid rollout create",
+			operation:       admissionv1beta1.Create,
+			selectorSyncSet: testRolloutSelectorSyncSet(25, "canary"),
+			expectedAllowed: true,
+		},
+		{
+			name:            "Test valid rollout update",
+			operation:       admissionv1beta1.Update,
+			selectorSyncSet: testRolloutSelectorSyncSet(100, ""),
+			expectedAllowed: true,
+		},
+		{
+			name:            "Test invalid rollout max unavailable percentage create",
+			operation:       admissionv1beta1.Create,
+			selectorSyncSet: testRolloutSelectorSyncSet(150, ""),
+			expectedAllowed: false,
+		},
+		{
+			name:            "Test invalid rollout canary selector update",
+			operation:       admissionv1beta1.Update,
+			selectorSyncSet: testRolloutSelectorSyncSet(25, "not a valid label"),
+			expectedAllowed: false,
+		},
+		{
+			name:            "Test valid cluster match create",
+			operation:       admissionv1beta1.Create,
+			selectorSyncSet: testClusterMatchSelectorSyncSet(">=4.2.0 <4.4.0 || >=4.5", "aws", "gcp"),
+			expectedAllowed: true,
+		},
+		{
+			name:            "Test invalid cluster match version range create",
+			operation:       admissionv1beta1.Create,
+			selectorSyncSet: testClusterMatchSelectorSyncSet(">=4.2.x"),
+			expectedAllowed: false,
+		},
+		{
+			name:            "Test invalid cluster match platform update",
+			operation:       admissionv1beta1.Update,
+			selectorSyncSet: testClusterMatchSelectorSyncSet("", "openstack"),
+			expectedAllowed: false,
+		},
+		{
+			name:            "Test valid deletion create",
+			operation:       admissionv1beta1.Create,
+			selectorSyncSet: testDeletionSelectorSyncSet("addons"),
+			expectedAllowed: true,
+		},
+		{
+			name:            "Test valid deletion without namespace update",
+			operation:       admissionv1beta1.Update,
+			selectorSyncSet: testDeletionSelectorSyncSet(""),
+			expectedAllowed: true,
+		},
+		{
+			name:      "Test invalid deletion without kind update",
+			operation: admissionv1beta1.Update,
+			selectorSyncSet: func() *hivev1.SelectorSyncSet {
+				sss := testDeletionSelectorSyncSet("addons")
+				sss.Spec.Deletions[0].Kind = ""
+				return sss
+			}(),
+			expectedAllowed: false,
+		},
+		{
+			name:      "Test invalid deletion object also listed in resources create",
+			operation: admissionv1beta1.Create,
+			selectorSyncSet: func() *hivev1.SelectorSyncSet {
+				sss := testDeletionSelectorSyncSet("addons")
+				sss.Spec.Resources = []runtime.RawExtension{{Raw: []byte(`{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "deprecated-addon", "namespace": "addons"}}`)}}
+				return sss
+			}(),
+			expectedAllowed: false,
+		},
 	}
 
 	for _, tc := range cases {
@@ -436,6 +510,41 @@ func testSecretReferenceSelectorSyncSet() *hivev1.SelectorSyncSet {
 	return ss
 }
 
+func testRolloutSelectorSyncSet(maxUnavailablePercentage int, canaryLabel string) *hivev1.SelectorSyncSet {
+	sss := testSelectorSyncSet()
+	sss.Spec.Rollout = &hivev1.SelectorSyncSetRolloutStrategy{
+		MaxUnavailablePercentage: maxUnavailablePercentage,
+	}
+	if canaryLabel != "" {
+		sss.Spec.Rollout.CanarySelector = &metav1.LabelSelector{
+			MatchLabels: map[string]string{canaryLabel: "true"},
+		}
+	}
+	return sss
+}
+
+func testClusterMatchSelectorSyncSet(versionRange string, platforms ...string) *hivev1.SelectorSyncSet {
+	sss := testSelectorSyncSet()
+	sss.Spec.ClusterMatch = &hivev1.SelectorSyncSetClusterMatch{
+		VersionRange: versionRange,
+		Platforms:    platforms,
+	}
+	return sss
+}
+
+func testDeletionSelectorSyncSet(namespace string) *hivev1.SelectorSyncSet {
+	sss := testSelectorSyncSet()
+	sss.Spec.Deletions = []hivev1.SyncObjectDeletion{
+		{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Name:       "deprecated-addon",
+			Namespace:  namespace,
+		},
+	}
+	return sss
+}
+
 func testSelectorSyncSet() *hivev1.SelectorSyncSet {
 	return &hivev1.SelectorSyncSet{
 		ObjectMeta: metav1.ObjectMeta{
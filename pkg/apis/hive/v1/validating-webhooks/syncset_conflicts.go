@@ -0,0 +1,273 @@
+package validatingwebhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/rest"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	"github.com/openshift/hive/pkg/syncsetmatch"
+)
+
+// syncSetConflictChecker rejects SyncSets and SelectorSyncSets that manage objects which are also managed with different
+// content by an existing SyncSet or SelectorSyncSet of equal or higher priority targeting the same cluster.
+type syncSetConflictChecker struct {
+	client client.Reader
+}
+
+// syncSetSource is a SyncSet or SelectorSyncSet being checked for conflicts
+type syncSetSource struct {
+	kind     string
+	name     types.NamespacedName
+	priority int
+	spec     *hivev1.SyncSetCommonSpec
+	// targets returns true if the SyncSet applies to the cluster deployment
+	targets func(cd *hivev1.ClusterDeployment) bool
+}
+
+var (
+	syncSetCacheOnce sync.Once
+	syncSetCache     cache.Cache
+	syncSetCacheErr  error
+)
+
+// newSyncSetConflictChecker returns a conflict checker when the SyncSet conflict policy is "Reject", and nil otherwise.
+func newSyncSetConflictChecker(kubeClientConfig *rest.Config, stopCh <-chan struct{}) (*syncSetConflictChecker, error) {
+	if hivev1.SyncSetConflictPolicy(os.Getenv(constants.SyncSetConflictPolicyEnvVar)) != hivev1.RejectSyncSetConflictPolicy {
+		return nil, nil
+	}
+	c, err := sharedSyncSetCache(kubeClientConfig, stopCh)
+	if err != nil {
+		return nil, err
+	}
+	log.WithField("policy", hivev1.RejectSyncSetConflictPolicy).Info("Conflicting SyncSets will be rejected")
+	return &syncSetConflictChecker{client: c}, nil
+}
+
+// sharedSyncSetCache returns the informer cache of ClusterDeployments, SyncSets and SelectorSyncSets shared by the
+// SyncSet and SelectorSyncSet webhooks. The cache is started and synced the first time it is requested.
+func sharedSyncSetCache(kubeClientConfig *rest.Config, stopCh <-chan struct{}) (cache.Cache, error) {
+	syncSetCacheOnce.Do(func() {
+		scheme := runtime.NewScheme()
+		if err := hivev1.AddToScheme(scheme); err != nil {
+			syncSetCacheErr = err
+			return
+		}
+		c, err := cache.New(kubeClientConfig, cache.Options{Scheme: scheme})
+		if err != nil {
+			syncSetCacheErr = err
+			return
+		}
+		for _, obj := range []runtime.Object{&hivev1.ClusterDeployment{}, &hivev1.SyncSet{}, &hivev1.SelectorSyncSet{}} {
+			if _, err := c.GetInformer(obj); err != nil {
+				syncSetCacheErr = err
+				return
+			}
+		}
+		go func() {
+			if err := c.Start(stopCh); err != nil {
+				log.WithError(err).Error("error running the syncset conflict cache")
+			}
+		}()
+		if !c.WaitForCacheSync(stopCh) {
+			syncSetCacheErr = fmt.Errorf("syncset conflict cache did not sync")
+			return
+		}
+		syncSetCache = c
+	})
+	return syncSetCache, syncSetCacheErr
+}
+
+func syncSetConflictSource(ss *hivev1.SyncSet) syncSetSource {
+	refs := map[string]bool{}
+	for _, ref := range ss.Spec.ClusterDeploymentRefs {
+		refs[ref.Name] = true
+	}
+	priority, _ := syncSetPriority(ss.Annotations)
+	return syncSetSource{
+		kind:     "SyncSet",
+		name:     types.NamespacedName{Namespace: ss.Namespace, Name: ss.Name},
+		priority: priority,
+		spec:     &ss.Spec.SyncSetCommonSpec,
+		targets: func(cd *hivev1.ClusterDeployment) bool {
+			return cd.Namespace == ss.Namespace && refs[cd.Name]
+		},
+	}
+}
+
+func selectorSyncSetConflictSource(sss *hivev1.SelectorSyncSet) syncSetSource {
+	priority, _ := syncSetPriority(sss.Annotations)
+	return syncSetSource{
+		kind:     "SelectorSyncSet",
+		name:     types.NamespacedName{Name: sss.Name},
+		priority: priority,
+		spec:     &sss.Spec.SyncSetCommonSpec,
+		targets: func(cd *hivev1.ClusterDeployment) bool {
+			matches, _ := syncsetmatch.Matches(sss, cd)
+			return matches
+		},
+	}
+}
+
+// validate returns an error for each object managed by source that conflicts with an existing SyncSet or
+// SelectorSyncSet of equal or higher priority.
+func (c *syncSetConflictChecker) validate(source syncSetSource, fldPath *field.Path) field.ErrorList {
+	return c.validateUpdate(source, nil, fldPath)
+}
+
+// validateUpdate returns an error for each object managed by source that conflicts with an existing SyncSet or
+// SelectorSyncSet of equal or higher priority. When oldSource is set, conflicts that already existed before the update
+// are left out, so that SyncSets that already conflicted can still be updated.
+func (c *syncSetConflictChecker) validateUpdate(source syncSetSource, oldSource *syncSetSource, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(syncSetObjects(source.spec)) == 0 {
+		return allErrs
+	}
+
+	cdList := &hivev1.ClusterDeploymentList{}
+	if err := c.client.List(context.TODO(), cdList); err != nil {
+		return append(allErrs, field.InternalError(fldPath, err))
+	}
+	var others []syncSetSource
+	ssList := &hivev1.SyncSetList{}
+	if err := c.client.List(context.TODO(), ssList); err != nil {
+		return append(allErrs, field.InternalError(fldPath, err))
+	}
+	for i := range ssList.Items {
+		others = append(others, syncSetConflictSource(&ssList.Items[i]))
+	}
+	sssList := &hivev1.SelectorSyncSetList{}
+	if err := c.client.List(context.TODO(), sssList); err != nil {
+		return append(allErrs, field.InternalError(fldPath, err))
+	}
+	for i := range sssList.Items {
+		others = append(others, selectorSyncSetConflictSource(&sssList.Items[i]))
+	}
+
+	existing := map[string]bool{}
+	if oldSource != nil {
+		for _, err := range conflicts(*oldSource, cdList.Items, others, fldPath) {
+			existing[err.Error()] = true
+		}
+	}
+	for _, err := range conflicts(source, cdList.Items, others, fldPath) {
+		if !existing[err.Error()] {
+			allErrs = append(allErrs, err)
+		}
+	}
+	return allErrs
+}
+
+// conflicts returns an error for each object managed by source that is also managed with different content by one of
+// the other sources of equal or higher priority targeting one of the same cluster deployments.
+func conflicts(source syncSetSource, clusterDeployments []hivev1.ClusterDeployment, others []syncSetSource, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	objects := syncSetObjects(source.spec)
+	if len(objects) == 0 {
+		return allErrs
+	}
+	var targeted []*hivev1.ClusterDeployment
+	for i := range clusterDeployments {
+		if source.targets(&clusterDeployments[i]) {
+			targeted = append(targeted, &clusterDeployments[i])
+		}
+	}
+	if len(targeted) == 0 {
+		return allErrs
+	}
+	for _, other := range others {
+		if other.kind == source.kind && other.name == source.name {
+			continue
+		}
+		if other.priority < source.priority || !targetsAny(other, targeted) {
+			continue
+		}
+		for key, otherContent := range syncSetObjects(other.spec) {
+			if content, ok := objects[key]; ok && content != otherContent {
+				allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("%s %s/%s is also managed by %s %s with equal or higher priority",
+					key.Kind, key.Namespace, key.Name, other.kind, strings.TrimPrefix(other.name.String(), "/"))))
+			}
+		}
+	}
+	return allErrs
+}
+
+func targetsAny(source syncSetSource, clusterDeployments []*hivev1.ClusterDeployment) bool {
+	for _, cd := range clusterDeployments {
+		if source.targets(cd) {
+			return true
+		}
+	}
+	return false
+}
+
+// syncSetObjects returns the remote objects that a SyncSet creates, updates or deletes, with a description of the
+// content it manages them with. Patches are not included as they are meant to modify objects managed elsewhere.
+func syncSetObjects(spec *hivev1.SyncSetCommonSpec) map[controllerutils.SyncObjectKey]string {
+	objects := map[controllerutils.SyncObjectKey]string{}
+	for _, resource := range spec.Resources {
+		obj := &struct {
+			metav1.TypeMeta `json:",inline"`
+			Metadata        metav1.ObjectMeta `json:"metadata"`
+		}{}
+		if err := json.Unmarshal(resource.Raw, obj); err != nil {
+			continue
+		}
+		content := &bytes.Buffer{}
+		if err := json.Compact(content, resource.Raw); err != nil {
+			continue
+		}
+		key := controllerutils.SyncObjectKey{Group: obj.GroupVersionKind().Group, Kind: obj.Kind, Namespace: obj.Metadata.Namespace, Name: obj.Metadata.Name}
+		objects[key] = content.String()
+	}
+	for _, ref := range spec.SecretReferences {
+		key := controllerutils.SyncObjectKey{Kind: "Secret", Namespace: ref.Target.Namespace, Name: ref.Target.Name}
+		objects[key] = fmt.Sprintf("secret %s/%s", ref.Source.Namespace, ref.Source.Name)
+	}
+	for _, ref := range spec.ConfigMapReferences {
+		key := controllerutils.SyncObjectKey{Kind: "ConfigMap", Namespace: ref.Target.Namespace, Name: ref.Target.Name}
+		objects[key] = fmt.Sprintf("configmap %s/%s", ref.Source.Namespace, ref.Source.Name)
+	}
+	for _, deletion := range spec.Deletions {
+		gv, _ := schema.ParseGroupVersion(deletion.APIVersion)
+		key := controllerutils.SyncObjectKey{Group: gv.Group, Kind: deletion.Kind, Namespace: deletion.Namespace, Name: deletion.Name}
+		objects[key] = "deleted"
+	}
+	return objects
+}
+
+// syncSetPriority returns the priority set with the SyncSet priority annotation, 0 if unset.
+func syncSetPriority(annotations map[string]string) (int, error) {
+	value, ok := annotations[constants.SyncSetPriorityAnnotation]
+	if !ok {
+		return 0, nil
+	}
+	return strconv.Atoi(strings.TrimSpace(value))
+}
+
+func validateSyncSetPriority(annotations map[string]string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if _, err := syncSetPriority(annotations); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Key(constants.SyncSetPriorityAnnotation), annotations[constants.SyncSetPriorityAnnotation], "priority must be an integer"))
+	}
+	return allErrs
+}
@@ -0,0 +1,281 @@
+package validatingwebhooks
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	conflictTestResource      = `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "foo", "namespace": "default"}, "data": {"key": "value"}}`
+	conflictTestOtherResource = `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "foo", "namespace": "default"}, "data": {"key": "other"}}`
+)
+
+func TestSyncSetConflicts(t *testing.T) {
+	cases := []struct {
+		name            string
+		operation       admissionv1beta1.Operation
+		existing        []runtime.Object
+		syncSet         *hivev1.SyncSet
+		oldSyncSet      *hivev1.SyncSet
+		selectorSyncSet *hivev1.SelectorSyncSet
+		expectedAllowed bool
+	}{
+		{
+			name:      "SyncSet without conflicts",
+			operation: admissionv1beta1.Create,
+			existing: []runtime.Object{
+				testConflictClusterDeployment("cd1"),
+				testConflictSyncSet("existing", 0, "cd2", conflictTestOtherResource),
+			},
+			syncSet:         testConflictSyncSet("test-sync-set", 0, "cd1", conflictTestResource),
+			expectedAllowed: true,
+		},
+		{
+			name:      "SyncSet conflicting with existing SyncSet",
+			operation: admissionv1beta1.Create,
+			existing: []runtime.Object{
+				testConflictClusterDeployment("cd1"),
+				testConflictSyncSet("existing", 0, "cd1", conflictTestOtherResource),
+			},
+			syncSet:         testConflictSyncSet("test-sync-set", 0, "cd1", conflictTestResource),
+			expectedAllowed: false,
+		},
+		{
+			name:      "SyncSet with same content as existing SyncSet",
+			operation: admissionv1beta1.Create,
+			existing: []runtime.Object{
+				testConflictClusterDeployment("cd1"),
+				testConflictSyncSet("existing", 0, "cd1", conflictTestResource),
+			},
+			syncSet:         testConflictSyncSet("test-sync-set", 0, "cd1", conflictTestResource),
+			expectedAllowed: true,
+		},
+		{
+			name:      "SyncSet with higher priority than existing SyncSet",
+			operation: admissionv1beta1.Create,
+			existing: []runtime.Object{
+				testConflictClusterDeployment("cd1"),
+				testConflictSyncSet("existing", 0, "cd1", conflictTestOtherResource),
+			},
+			syncSet:         testConflictSyncSet("test-sync-set", 10, "cd1", conflictTestResource),
+			expectedAllowed: true,
+		},
+		{
+			name:      "SyncSet conflicting with matching SelectorSyncSet",
+			operation: admissionv1beta1.Create,
+			existing: []runtime.Object{
+				testConflictClusterDeployment("cd1"),
+				testConflictSelectorSyncSet("existing", 0, "us-east-1", conflictTestOtherResource),
+			},
+			syncSet:         testConflictSyncSet("test-sync-set", 0, "cd1", conflictTestResource),
+			expectedAllowed: false,
+		},
+		{
+			name:      "SyncSet and SelectorSyncSet not targeting the same cluster",
+			operation: admissionv1beta1.Create,
+			existing: []runtime.Object{
+				testConflictClusterDeployment("cd1"),
+				testConflictSelectorSyncSet("existing", 0, "us-west-1", conflictTestOtherResource),
+			},
+			syncSet:         testConflictSyncSet("test-sync-set", 0, "cd1", conflictTestResource),
+			expectedAllowed: true,
+		},
+		{
+			name:      "SyncSet and SelectorSyncSet with non-matching cluster match",
+			operation: admissionv1beta1.Create,
+			existing: []runtime.Object{
+				testConflictClusterDeployment("cd1"),
+				func() *hivev1.SelectorSyncSet {
+					sss := testConflictSelectorSyncSet("existing", 0, "us-east-1", conflictTestOtherResource)
+					sss.Spec.ClusterMatch = &hivev1.SelectorSyncSetClusterMatch{Platforms: []string{"azure"}}
+					return sss
+				}(),
+			},
+			syncSet:         testConflictSyncSet("test-sync-set", 0, "cd1", conflictTestResource),
+			expectedAllowed: true,
+		},
+		{
+			name:      "SelectorSyncSet conflicting with existing SyncSet",
+			operation: admissionv1beta1.Create,
+			existing: []runtime.Object{
+				testConflictClusterDeployment("cd1"),
+				testConflictSyncSet("existing", 0, "cd1", conflictTestOtherResource),
+			},
+			selectorSyncSet: testConflictSelectorSyncSet("test-selector-sync-set", 0, "us-east-1", conflictTestResource),
+			expectedAllowed: false,
+		},
+		{
+			name:      "SyncSet deleting object managed by existing SyncSet",
+			operation: admissionv1beta1.Create,
+			existing: []runtime.Object{
+				testConflictClusterDeployment("cd1"),
+				testConflictSyncSet("existing", 0, "cd1", conflictTestResource),
+			},
+			syncSet: func() *hivev1.SyncSet {
+				ss := testConflictSyncSet("test-sync-set", 0, "cd1")
+				ss.Spec.Deletions = []hivev1.SyncObjectDeletion{{APIVersion: "v1", Kind: "ConfigMap", Name: "foo", Namespace: "default"}}
+				return ss
+			}(),
+			expectedAllowed: false,
+		},
+		{
+			name:      "SyncSet update keeping existing conflict",
+			operation: admissionv1beta1.Update,
+			existing: []runtime.Object{
+				testConflictClusterDeployment("cd1"),
+				testConflictSyncSet("existing", 0, "cd1", conflictTestOtherResource),
+			},
+			oldSyncSet:      testConflictSyncSet("test-sync-set", 0, "cd1", conflictTestResource),
+			syncSet:         testConflictSyncSet("test-sync-set", 0, "cd1", conflictTestResource),
+			expectedAllowed: true,
+		},
+		{
+			name:      "SyncSet update introducing conflict",
+			operation: admissionv1beta1.Update,
+			existing: []runtime.Object{
+				testConflictClusterDeployment("cd1"),
+				testConflictSyncSet("existing", 0, "cd1", conflictTestOtherResource),
+			},
+			oldSyncSet:      testConflictSyncSet("test-sync-set", 0, "cd1"),
+			syncSet:         testConflictSyncSet("test-sync-set", 0, "cd1", conflictTestResource),
+			expectedAllowed: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			require.NoError(t, hivev1.AddToScheme(scheme))
+			checker := &syncSetConflictChecker{client: fake.NewFakeClientWithScheme(scheme, tc.existing...)}
+
+			var object, oldObject interface{}
+			var validate func(*admissionv1beta1.AdmissionRequest) *admissionv1beta1.AdmissionResponse
+			resource := "syncsets"
+			if tc.selectorSyncSet != nil {
+				object, oldObject = tc.selectorSyncSet, tc.selectorSyncSet
+				resource = "selectorsyncsets"
+				validate = (&SelectorSyncSetValidatingAdmissionHook{conflictChecker: checker}).Validate
+			} else {
+				object, oldObject = tc.syncSet, tc.syncSet
+				if tc.oldSyncSet != nil {
+					oldObject = tc.oldSyncSet
+				}
+				validate = (&SyncSetValidatingAdmissionHook{conflictChecker: checker}).Validate
+			}
+			objectRaw, _ := json.Marshal(object)
+			oldObjectRaw, _ := json.Marshal(oldObject)
+
+			request := &admissionv1beta1.AdmissionRequest{
+				Operation: tc.operation,
+				Resource: metav1.GroupVersionResource{
+					Group:    "hive.openshift.io",
+					Version:  "v1",
+					Resource: resource,
+				},
+				Object: runtime.RawExtension{
+					Raw: objectRaw,
+				},
+				OldObject: runtime.RawExtension{
+					Raw: oldObjectRaw,
+				},
+			}
+
+			response := validate(request)
+			assert.Equal(t, tc.expectedAllowed, response.Allowed)
+		})
+	}
+}
+
+func TestSyncSetConflictsUpdateListsOnce(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, hivev1.AddToScheme(scheme))
+	reader := &listCounter{Reader: fake.NewFakeClientWithScheme(scheme,
+		testConflictClusterDeployment("cd1"),
+		testConflictSyncSet("existing", 0, "cd1", conflictTestOtherResource),
+	)}
+	checker := &syncSetConflictChecker{client: reader}
+
+	oldSource := syncSetConflictSource(testConflictSyncSet("test-sync-set", 0, "cd1", conflictTestOtherResource))
+	errs := checker.validateUpdate(syncSetConflictSource(testConflictSyncSet("test-sync-set", 0, "cd1", conflictTestResource)), &oldSource, nil)
+	assert.Len(t, errs, 1, "expected conflict introduced by the update")
+	assert.Equal(t, 3, reader.lists, "expected cluster deployments, syncsets and selectorsyncsets to be listed once")
+}
+
+// listCounter counts the lists made through a reader
+type listCounter struct {
+	client.Reader
+	lists int
+}
+
+func (c *listCounter) List(ctx context.Context, list runtime.Object, opts ...client.ListOptionFunc) error {
+	c.lists++
+	return c.Reader.List(ctx, list, opts...)
+}
+
+func TestSyncSetPriorityAnnotation(t *testing.T) {
+	ss := testSyncSet()
+	ss.Annotations = map[string]string{constants.SyncSetPriorityAnnotation: "high"}
+	objectRaw, _ := json.Marshal(ss)
+	request := &admissionv1beta1.AdmissionRequest{
+		Operation: admissionv1beta1.Create,
+		Resource: metav1.GroupVersionResource{
+			Group:    "hive.openshift.io",
+			Version:  "v1",
+			Resource: "syncsets",
+		},
+		Object: runtime.RawExtension{
+			Raw: objectRaw,
+		},
+	}
+	response := (&SyncSetValidatingAdmissionHook{}).Validate(request)
+	assert.False(t, response.Allowed, "expected invalid priority to be rejected")
+}
+
+func testConflictClusterDeployment(name string) *hivev1.ClusterDeployment {
+	return &hivev1.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "test-namespace",
+			Labels:    map[string]string{"region": "us-east-1"},
+		},
+	}
+}
+
+func testConflictSyncSet(name string, priority int, clusterDeployment string, resources ...string) *hivev1.SyncSet {
+	ss := testSyncSetWithResources(resources...)
+	ss.Name = name
+	if priority != 0 {
+		ss.Annotations = map[string]string{constants.SyncSetPriorityAnnotation: strconv.Itoa(priority)}
+	}
+	ss.Spec.ClusterDeploymentRefs = []corev1.LocalObjectReference{{Name: clusterDeployment}}
+	return ss
+}
+
+func testConflictSelectorSyncSet(name string, priority int, region string, resources ...string) *hivev1.SelectorSyncSet {
+	sss := &hivev1.SelectorSyncSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+	for _, resource := range resources {
+		sss.Spec.Resources = append(sss.Spec.Resources, runtime.RawExtension{Raw: []byte(resource)})
+	}
+	if priority != 0 {
+		sss.Annotations = map[string]string{constants.SyncSetPriorityAnnotation: strconv.Itoa(priority)}
+	}
+	sss.Spec.ClusterDeploymentSelector = metav1.LabelSelector{MatchLabels: map[string]string{"region": region}}
+	return sss
+}
@@ -2,17 +2,24 @@ package validatingwebhooks
 
 import (
 	"encoding/json"
+	"strconv"
+	"strings"
 
 	log "github.com/sirupsen/logrus"
 
 	"net/http"
 
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	"github.com/openshift/hive/pkg/syncsetreadiness"
+	"github.com/openshift/hive/pkg/syncsettemplate"
 
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -23,6 +30,9 @@ const (
 	syncSetGroup    = "hive.openshift.io"
 	syncSetVersion  = "v1"
 	syncSetResource = "syncsets"
+
+	secretReferenceKind    = "Secret"
+	configMapReferenceKind = "ConfigMap"
 )
 
 var invalidResourceGroupKinds = map[string]map[string]bool{
@@ -43,8 +53,22 @@ var validPatchTypes = map[string]bool{
 
 var validPatchTypeSlice = []string{"json", "merge", "strategic"}
 
+var validPropagationPolicies = map[metav1.DeletionPropagation]bool{
+	metav1.DeletePropagationOrphan:     true,
+	metav1.DeletePropagationBackground: true,
+	metav1.DeletePropagationForeground: true,
+}
+
+var validPropagationPolicySlice = []string{
+	string(metav1.DeletePropagationOrphan),
+	string(metav1.DeletePropagationBackground),
+	string(metav1.DeletePropagationForeground),
+}
+
 // SyncSetValidatingAdmissionHook is a struct that is used to reference what code should be run by the generic-admission-server.
-type SyncSetValidatingAdmissionHook struct{}
+type SyncSetValidatingAdmissionHook struct {
+	conflictChecker *syncSetConflictChecker
+}
 
 // ValidatingResource is called by generic-admission-server on startup to register the returned REST resource through which the
 //                    webhook is accessed by the kube apiserver.
@@ -72,7 +96,12 @@ func (a *SyncSetValidatingAdmissionHook) Initialize(kubeClientConfig *rest.Confi
 		"version":  "v1",
 		"resource": "syncsetvalidator",
 	}).Info("Initializing validation REST resource")
-	return nil // No initialization needed right now.
+	conflictChecker, err := newSyncSetConflictChecker(kubeClientConfig, stopCh)
+	if err != nil {
+		return err
+	}
+	a.conflictChecker = conflictChecker
+	return nil
 }
 
 // Validate is called by generic-admission-server when the registered REST resource above is called with an admission request.
@@ -173,6 +202,14 @@ func (a *SyncSetValidatingAdmissionHook) validateCreate(admissionSpec *admission
 	allErrs = append(allErrs, validateResources(newObject.Spec.Resources, field.NewPath("spec").Child("resources"))...)
 	allErrs = append(allErrs, validatePatches(newObject.Spec.Patches, field.NewPath("spec").Child("patches"))...)
 	allErrs = append(allErrs, validateSecretReferences(newObject.Spec.SecretReferences, field.NewPath("spec").Child("secretReferences"))...)
+	allErrs = append(allErrs, validateConfigMapReferences(newObject.Spec.ConfigMapReferences, field.NewPath("spec").Child("configMapReferences"))...)
+	allErrs = append(allErrs, validateDeletions(newObject.Spec.Deletions, newObject.Spec.Resources, field.NewPath("spec").Child("deletions"))...)
+	allErrs = append(allErrs, validateResourceTemplates(&newObject.Spec.SyncSetCommonSpec, field.NewPath("spec").Child("enableResourceTemplates"))...)
+	allErrs = append(allErrs, validateSyncSetPriority(newObject.Annotations, field.NewPath("metadata").Child("annotations"))...)
+
+	if a.conflictChecker != nil && len(allErrs) == 0 {
+		allErrs = append(allErrs, a.conflictChecker.validate(syncSetConflictSource(newObject), field.NewPath("spec"))...)
+	}
 
 	if len(allErrs) > 0 {
 		statusError := errors.NewInvalid(newObject.GroupVersionKind().GroupKind(), newObject.Name, allErrs).Status()
@@ -220,6 +257,26 @@ func (a *SyncSetValidatingAdmissionHook) validateUpdate(admissionSpec *admission
 	allErrs = append(allErrs, validateResources(newObject.Spec.Resources, field.NewPath("spec", "resources"))...)
 	allErrs = append(allErrs, validatePatches(newObject.Spec.Patches, field.NewPath("spec", "patches"))...)
 	allErrs = append(allErrs, validateSecretReferences(newObject.Spec.SecretReferences, field.NewPath("spec", "secretReferences"))...)
+	allErrs = append(allErrs, validateConfigMapReferences(newObject.Spec.ConfigMapReferences, field.NewPath("spec", "configMapReferences"))...)
+	allErrs = append(allErrs, validateDeletions(newObject.Spec.Deletions, newObject.Spec.Resources, field.NewPath("spec", "deletions"))...)
+	allErrs = append(allErrs, validateResourceTemplates(&newObject.Spec.SyncSetCommonSpec, field.NewPath("spec", "enableResourceTemplates"))...)
+	allErrs = append(allErrs, validateSyncSetPriority(newObject.Annotations, field.NewPath("metadata", "annotations"))...)
+
+	if a.conflictChecker != nil && len(allErrs) == 0 {
+		oldObject := &hivev1.SyncSet{}
+		if err := json.Unmarshal(admissionSpec.OldObject.Raw, oldObject); err != nil {
+			contextLogger.Errorf("Failed unmarshaling OldObject: %v", err.Error())
+			return &admissionv1beta1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Status: metav1.StatusFailure, Code: http.StatusBadRequest, Reason: metav1.StatusReasonBadRequest,
+					Message: err.Error(),
+				},
+			}
+		}
+		oldSource := syncSetConflictSource(oldObject)
+		allErrs = append(allErrs, a.conflictChecker.validateUpdate(syncSetConflictSource(newObject), &oldSource, field.NewPath("spec"))...)
+	}
 
 	if len(allErrs) > 0 {
 		statusError := errors.NewInvalid(newObject.GroupVersionKind().GroupKind(), newObject.Name, allErrs).Status()
@@ -270,22 +327,104 @@ func validateResource(resource runtime.RawExtension, fldPath *field.Path) field.
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("APIVersion"), rType.APIVersion, "must use kubernetes group for this resource kind"))
 	}
 
+	rMeta := &struct {
+		Metadata metav1.ObjectMeta `json:"metadata"`
+	}{}
+	if err := json.Unmarshal(resource.Raw, rMeta); err == nil {
+		allErrs = append(allErrs, validateResourceApplyAnnotations(rMeta.Metadata.Annotations, fldPath.Child("metadata", "annotations"))...)
+	}
+
+	return allErrs
+}
+
+func validateResourceApplyAnnotations(annotations map[string]string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if wave, ok := annotations[constants.SyncSetApplyWaveAnnotation]; ok {
+		if _, err := strconv.Atoi(strings.TrimSpace(wave)); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(constants.SyncSetApplyWaveAnnotation), wave, "apply wave must be an integer"))
+		}
+	}
+	if check, ok := annotations[constants.SyncSetReadinessCheckAnnotation]; ok {
+		if _, _, _, err := syncsetreadiness.ParseCheck(check); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(constants.SyncSetReadinessCheckAnnotation), check, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+func validateResourceTemplates(spec *hivev1.SyncSetCommonSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if err := syncsettemplate.Validate(spec); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath, spec.EnableResourceTemplates, err.Error()))
+	}
 	return allErrs
 }
 
 func validateSecretReferences(secrets []hivev1.SecretReference, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	for i, secret := range secrets {
-		allErrs = append(allErrs, validateSecretRef(secret.Source, fldPath.Index(i).Child("source"))...)
-		allErrs = append(allErrs, validateSecretRef(secret.Target, fldPath.Index(i).Child("target"))...)
+		allErrs = append(allErrs, validateObjectRef(secret.Source, secretReferenceKind, fldPath.Index(i).Child("source"))...)
+		allErrs = append(allErrs, validateObjectRef(secret.Target, secretReferenceKind, fldPath.Index(i).Child("target"))...)
+	}
+	return allErrs
+}
+
+func validateConfigMapReferences(configMaps []hivev1.ConfigMapReference, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, configMap := range configMaps {
+		allErrs = append(allErrs, validateObjectRef(configMap.Source, configMapReferenceKind, fldPath.Index(i).Child("source"))...)
+		allErrs = append(allErrs, validateObjectRef(configMap.Target, configMapReferenceKind, fldPath.Index(i).Child("target"))...)
 	}
 	return allErrs
 }
 
-func validateSecretRef(ref corev1.ObjectReference, fldPath *field.Path) field.ErrorList {
+// validateDeletions validates the objects listed in deletions. Whether a deleted kind is namespaced is only known to the
+// target cluster, so the namespace of deletions is checked by the syncsetinstance controller.
+func validateDeletions(deletions []hivev1.SyncObjectDeletion, resources []runtime.RawExtension, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	resourceObjects := map[controllerutils.SyncObjectKey]bool{}
+	for _, resource := range resources {
+		u := &unstructured.Unstructured{}
+		if err := json.Unmarshal(resource.Raw, &u.Object); err != nil {
+			continue
+		}
+		resourceObjects[deletedObjectKey(u.GetAPIVersion(), u.GetKind(), u.GetNamespace(), u.GetName())] = true
+	}
+	for i, deletion := range deletions {
+		idxPath := fldPath.Index(i)
+		if len(deletion.APIVersion) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("apiVersion"), "APIVersion is required"))
+		} else if _, err := schema.ParseGroupVersion(deletion.APIVersion); err != nil {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("apiVersion"), deletion.APIVersion, err.Error()))
+		}
+		if len(deletion.Kind) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("kind"), "Kind is required"))
+		}
+		if len(deletion.Name) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), "Name is required"))
+		}
+		if deletion.PropagationPolicy != nil && !validPropagationPolicies[*deletion.PropagationPolicy] {
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("propagationPolicy"), *deletion.PropagationPolicy, validPropagationPolicySlice))
+		}
+		if resourceObjects[deletedObjectKey(deletion.APIVersion, deletion.Kind, deletion.Namespace, deletion.Name)] {
+			allErrs = append(allErrs, field.Invalid(idxPath, deletion.Name, "object is also listed in resources"))
+		}
+	}
+	return allErrs
+}
+
+// deletedObjectKey identifies an object regardless of the version used to refer to it
+func deletedObjectKey(apiVersion, kind, namespace, name string) controllerutils.SyncObjectKey {
+	gv, _ := schema.ParseGroupVersion(apiVersion)
+	return controllerutils.SyncObjectKey{Group: gv.Group, Kind: kind, Namespace: namespace, Name: name}
+}
+
+// validateObjectRef validates a reference to a secret or configmap of the given kind. The kind of the reference is
+// optional and compared case-insensitively, so that both "Secret" and "secret" refer to secrets.
+func validateObjectRef(ref corev1.ObjectReference, kind string, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
-	if len(ref.Kind) > 0 && ref.Kind != "secret" {
-		allErrs = append(allErrs, field.NotSupported(fldPath.Child("kind"), ref.Kind, []string{"secret"}))
+	if len(ref.Kind) > 0 && !strings.EqualFold(ref.Kind, kind) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("kind"), ref.Kind, []string{kind}))
 	}
 	if ref.GroupVersionKind().Group != "" {
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("apiVersion"), ref.APIVersion, "Group part of API version must be empty"))
@@ -137,6 +137,36 @@ func TestSyncSetValidate(t *testing.T) {
 			}(),
 			expectedAllowed: true,
 		},
+		{
+			name:      "Test valid SecretReference canonical source kind create",
+			operation: admissionv1beta1.Create,
+			syncSet: func() *hivev1.SyncSet {
+				ss := testSecretReferenceSyncSet()
+				ss.Spec.SecretReferences[0].Source.Kind = "Secret"
+				return ss
+			}(),
+			expectedAllowed: true,
+		},
+		{
+			name:      "Test valid SecretReference canonical target kind update",
+			operation: admissionv1beta1.Update,
+			syncSet: func() *hivev1.SyncSet {
+				ss := testSecretReferenceSyncSet()
+				ss.Spec.SecretReferences[0].Target.Kind = "Secret"
+				return ss
+			}(),
+			expectedAllowed: true,
+		},
+		{
+			name:      "Test invalid SecretReference configmap kind create",
+			operation: admissionv1beta1.Create,
+			syncSet: func() *hivev1.SyncSet {
+				ss := testSecretReferenceSyncSet()
+				ss.Spec.SecretReferences[0].Source.Kind = "ConfigMap"
+				return ss
+			}(),
+			expectedAllowed: false,
+		},
 		{
 			name:      "Test invalid SecretReference source kind create",
 			operation: admissionv1beta1.Create,
@@ -337,6 +367,180 @@ func TestSyncSetValidate(t *testing.T) {
 			}(),
 			expectedAllowed: false,
 		},
+		{
+			name:            "Test valid ConfigMapReference create",
+			operation:       admissionv1beta1.Create,
+			syncSet:         testConfigMapReferenceSyncSet(),
+			expectedAllowed: true,
+		},
+		{
+			name:            "Test valid ConfigMapReference update",
+			operation:       admissionv1beta1.Update,
+			syncSet:         testConfigMapReferenceSyncSet(),
+			expectedAllowed: true,
+		},
+		{
+			name:      "Test valid ConfigMapReference source kind create",
+			operation: admissionv1beta1.Create,
+			syncSet: func() *hivev1.SyncSet {
+				ss := testConfigMapReferenceSyncSet()
+				ss.Spec.ConfigMapReferences[0].Source.Kind = "ConfigMap"
+				return ss
+			}(),
+			expectedAllowed: true,
+		},
+		{
+			name:      "Test valid ConfigMapReference lowercase target kind create",
+			operation: admissionv1beta1.Create,
+			syncSet: func() *hivev1.SyncSet {
+				ss := testConfigMapReferenceSyncSet()
+				ss.Spec.ConfigMapReferences[0].Target.Kind = "configmap"
+				return ss
+			}(),
+			expectedAllowed: true,
+		},
+		{
+			name:      "Test invalid ConfigMapReference canonical secret kind update",
+			operation: admissionv1beta1.Update,
+			syncSet: func() *hivev1.SyncSet {
+				ss := testConfigMapReferenceSyncSet()
+				ss.Spec.ConfigMapReferences[0].Source.Kind = "Secret"
+				return ss
+			}(),
+			expectedAllowed: false,
+		},
+		{
+			name:      "Test invalid ConfigMapReference target kind create",
+			operation: admissionv1beta1.Create,
+			syncSet: func() *hivev1.SyncSet {
+				ss := testConfigMapReferenceSyncSet()
+				ss.Spec.ConfigMapReferences[0].Target.Kind = "secret"
+				return ss
+			}(),
+			expectedAllowed: false,
+		},
+		{
+			name:      "Test invalid ConfigMapReference no source name update",
+			operation: admissionv1beta1.Update,
+			syncSet: func() *hivev1.SyncSet {
+				ss := testConfigMapReferenceSyncSet()
+				ss.Spec.ConfigMapReferences[0].Source.Name = ""
+				return ss
+			}(),
+			expectedAllowed: false,
+		},
+		{
+			name:      "Test invalid ConfigMapReference source apiVersion group set create",
+			operation: admissionv1beta1.Create,
+			syncSet: func() *hivev1.SyncSet {
+				ss := testConfigMapReferenceSyncSet()
+				ss.Spec.ConfigMapReferences[0].Source.APIVersion = "wrong/v1"
+				return ss
+			}(),
+			expectedAllowed: false,
+		},
+		{
+			name:            "Test valid Deletions create",
+			operation:       admissionv1beta1.Create,
+			syncSet:         testDeletionSyncSet(metav1.DeletePropagationForeground),
+			expectedAllowed: true,
+		},
+		{
+			name:            "Test valid Deletions without propagation policy update",
+			operation:       admissionv1beta1.Update,
+			syncSet:         testDeletionSyncSet(""),
+			expectedAllowed: true,
+		},
+		{
+			name:            "Test invalid Deletions propagation policy create",
+			operation:       admissionv1beta1.Create,
+			syncSet:         testDeletionSyncSet("Immediate"),
+			expectedAllowed: false,
+		},
+		{
+			name:      "Test invalid Deletions no kind update",
+			operation: admissionv1beta1.Update,
+			syncSet: func() *hivev1.SyncSet {
+				ss := testDeletionSyncSet("")
+				ss.Spec.Deletions[0].Kind = ""
+				return ss
+			}(),
+			expectedAllowed: false,
+		},
+		{
+			name:      "Test invalid Deletions no name create",
+			operation: admissionv1beta1.Create,
+			syncSet: func() *hivev1.SyncSet {
+				ss := testDeletionSyncSet("")
+				ss.Spec.Deletions[0].Name = ""
+				return ss
+			}(),
+			expectedAllowed: false,
+		},
+		{
+			name:      "Test valid Deletions namespaced kind without namespace create",
+			operation: admissionv1beta1.Create,
+			syncSet: func() *hivev1.SyncSet {
+				ss := testDeletionSyncSet("")
+				ss.Spec.Deletions[0].Namespace = ""
+				return ss
+			}(),
+			expectedAllowed: true,
+		},
+		{
+			name:      "Test valid Deletions cluster-scoped kind without namespace create",
+			operation: admissionv1beta1.Create,
+			syncSet: func() *hivev1.SyncSet {
+				ss := testDeletionSyncSet("")
+				ss.Spec.Deletions[0].APIVersion = "v1"
+				ss.Spec.Deletions[0].Kind = "Namespace"
+				ss.Spec.Deletions[0].Namespace = ""
+				return ss
+			}(),
+			expectedAllowed: true,
+		},
+		{
+			name:      "Test valid Deletions unknown kind without namespace create",
+			operation: admissionv1beta1.Create,
+			syncSet: func() *hivev1.SyncSet {
+				ss := testDeletionSyncSet("")
+				ss.Spec.Deletions[0].APIVersion = "example.com/v1"
+				ss.Spec.Deletions[0].Kind = "Widget"
+				ss.Spec.Deletions[0].Namespace = ""
+				return ss
+			}(),
+			expectedAllowed: true,
+		},
+		{
+			name:      "Test invalid Deletions object also listed in Resources create",
+			operation: admissionv1beta1.Create,
+			syncSet: func() *hivev1.SyncSet {
+				ss := testDeletionSyncSet("")
+				ss.Spec.Resources = []runtime.RawExtension{{Raw: []byte(`{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "deprecated-addon", "namespace": "addons"}}`)}}
+				return ss
+			}(),
+			expectedAllowed: false,
+		},
+		{
+			name:      "Test invalid Deletions object listed in Resources with another version update",
+			operation: admissionv1beta1.Update,
+			syncSet: func() *hivev1.SyncSet {
+				ss := testDeletionSyncSet("")
+				ss.Spec.Resources = []runtime.RawExtension{{Raw: []byte(`{"apiVersion": "apps/v1beta1", "kind": "Deployment", "metadata": {"name": "deprecated-addon", "namespace": "addons"}}`)}}
+				return ss
+			}(),
+			expectedAllowed: false,
+		},
+		{
+			name:      "Test valid Deletions other object listed in Resources create",
+			operation: admissionv1beta1.Create,
+			syncSet: func() *hivev1.SyncSet {
+				ss := testDeletionSyncSet("")
+				ss.Spec.Resources = []runtime.RawExtension{{Raw: []byte(`{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "new-addon", "namespace": "addons"}}`)}}
+				return ss
+			}(),
+			expectedAllowed: true,
+		},
 		{
 			name:            "Test invalid unmarshalable TypeMeta Resource create",
 			operation:       admissionv1beta1.Create,
@@ -464,6 +668,70 @@ func TestSyncSetValidate(t *testing.T) {
 			syncSet:         testSyncSetWithResources(`{"apiVersion": "authorization.openshift.io/v1", "kind": "SubjectAccessReview"}`),
 			expectedAllowed: false,
 		},
+		{
+			name:            "Test valid apply wave and readiness check annotations create",
+			operation:       admissionv1beta1.Create,
+			syncSet:         testSyncSetWithResources(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"annotations": {"hive.openshift.io/syncset-apply-wave": "-2", "hive.openshift.io/syncset-readiness-check": "{.status.conditions[?(@.type==\"Ready\")].status}=True"}}}`),
+			expectedAllowed: true,
+		},
+		{
+			name:            "Test invalid apply wave annotation create",
+			operation:       admissionv1beta1.Create,
+			syncSet:         testSyncSetWithResources(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"annotations": {"hive.openshift.io/syncset-apply-wave": "first"}}}`),
+			expectedAllowed: false,
+		},
+		{
+			name:            "Test invalid readiness check annotation update",
+			operation:       admissionv1beta1.Update,
+			syncSet:         testSyncSetWithResources(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"annotations": {"hive.openshift.io/syncset-readiness-check": ".status.phase"}}}`),
+			expectedAllowed: false,
+		},
+		{
+			name:            "Test invalid readiness check expected value update",
+			operation:       admissionv1beta1.Update,
+			syncSet:         testSyncSetWithResources(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"annotations": {"hive.openshift.io/syncset-readiness-check": "{.status.phase}Running"}}}`),
+			expectedAllowed: false,
+		},
+		{
+			name:      "Test valid resource templates create",
+			operation: admissionv1beta1.Create,
+			syncSet: func() *hivev1.SyncSet {
+				ss := testSyncSetWithResources(`{"apiVersion": "v1", "kind": "ConfigMap", "data": {"domain": "apps.{{ .BaseDomain }}"}}`)
+				ss.Spec.EnableResourceTemplates = true
+				return ss
+			}(),
+			expectedAllowed: true,
+		},
+		{
+			name:      "Test invalid resource templates create",
+			operation: admissionv1beta1.Create,
+			syncSet: func() *hivev1.SyncSet {
+				ss := testSyncSetWithResources(`{"apiVersion": "v1", "kind": "ConfigMap", "data": {"domain": "apps.{{ .BaseDomain "}}`)
+				ss.Spec.EnableResourceTemplates = true
+				return ss
+			}(),
+			expectedAllowed: false,
+		},
+		{
+			name:      "Test invalid patch templates update",
+			operation: admissionv1beta1.Update,
+			syncSet: func() *hivev1.SyncSet {
+				ss := testSyncSet()
+				ss.Spec.EnableResourceTemplates = true
+				ss.Spec.Patches = []hivev1.SyncObjectPatch{{
+					Patch:     `{"data": {"region": "{{ index .Labels }"}}`,
+					PatchType: "merge",
+				}}
+				return ss
+			}(),
+			expectedAllowed: false,
+		},
+		{
+			name:            "Test template syntax ignored when resource templates disabled",
+			operation:       admissionv1beta1.Create,
+			syncSet:         testSyncSetWithResources(`{"apiVersion": "v1", "kind": "ConfigMap", "data": {"domain": "apps.{{ .BaseDomain "}}`),
+			expectedAllowed: true,
+		},
 	}
 
 	for _, tc := range cases {
@@ -544,6 +812,43 @@ func testSecretReferenceSyncSet() *hivev1.SyncSet {
 	return ss
 }
 
+func testConfigMapReferenceSyncSet() *hivev1.SyncSet {
+	ss := testSyncSet()
+	ss.Spec = hivev1.SyncSetSpec{
+		SyncSetCommonSpec: hivev1.SyncSetCommonSpec{
+			ConfigMapReferences: []hivev1.ConfigMapReference{
+				{
+					Source: corev1.ObjectReference{
+						Name:      "foo",
+						Namespace: "foo",
+					},
+					Target: corev1.ObjectReference{
+						Name:      "foo",
+						Namespace: "foo",
+					},
+				},
+			},
+		},
+	}
+	return ss
+}
+
+func testDeletionSyncSet(propagationPolicy metav1.DeletionPropagation) *hivev1.SyncSet {
+	ss := testSyncSet()
+	ss.Spec.Deletions = []hivev1.SyncObjectDeletion{
+		{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Name:       "deprecated-addon",
+			Namespace:  "addons",
+		},
+	}
+	if propagationPolicy != "" {
+		ss.Spec.Deletions[0].PropagationPolicy = &propagationPolicy
+	}
+	return ss
+}
+
 func testSyncSet() *hivev1.SyncSet {
 	return &hivev1.SyncSet{
 		ObjectMeta: metav1.ObjectMeta{
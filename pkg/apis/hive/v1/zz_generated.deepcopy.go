@@ -13,6 +13,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	intstr "k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -266,6 +267,115 @@ func (in *CheckpointStatus) DeepCopy() *CheckpointStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudHealthStatus) DeepCopyInto(out *CloudHealthStatus) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.ControlPlaneInstances != nil {
+		in, out := &in.ControlPlaneInstances, &out.ControlPlaneInstances
+		*out = make([]CloudInstanceState, len(*in))
+		copy(*out, *in)
+	}
+	if in.LoadBalancers != nil {
+		in, out := &in.LoadBalancers, &out.LoadBalancers
+		*out = make([]CloudLoadBalancerHealth, len(*in))
+		copy(*out, *in)
+	}
+	if in.RecentTerminations != nil {
+		in, out := &in.RecentTerminations, &out.RecentTerminations
+		*out = make([]CloudInstanceState, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudHealthStatus.
+func (in *CloudHealthStatus) DeepCopy() *CloudHealthStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudHealthStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudInstanceState) DeepCopyInto(out *CloudInstanceState) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudInstanceState.
+func (in *CloudInstanceState) DeepCopy() *CloudInstanceState {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudInstanceState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudLoadBalancerHealth) DeepCopyInto(out *CloudLoadBalancerHealth) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudLoadBalancerHealth.
+func (in *CloudLoadBalancerHealth) DeepCopy() *CloudLoadBalancerHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudLoadBalancerHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterCapacityState) DeepCopyInto(out *ClusterCapacityState) {
+	*out = *in
+	out.AllocatableCPU = in.AllocatableCPU.DeepCopy()
+	out.AllocatableMemory = in.AllocatableMemory.DeepCopy()
+	out.RequestedCPU = in.RequestedCPU.DeepCopy()
+	out.RequestedMemory = in.RequestedMemory.DeepCopy()
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCapacityState.
+func (in *ClusterCapacityState) DeepCopy() *ClusterCapacityState {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterCapacityState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterCollectionStatus) DeepCopyInto(out *ClusterCollectionStatus) {
+	*out = *in
+	if in.LastCollectionTime != nil {
+		in, out := &in.LastCollectionTime, &out.LastCollectionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]CollectedResourceStatus, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCollectionStatus.
+func (in *ClusterCollectionStatus) DeepCopy() *ClusterCollectionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterCollectionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterDeployment) DeepCopyInto(out *ClusterDeployment) {
 	*out = *in
@@ -431,6 +541,18 @@ func (in *ClusterDeploymentStatus) DeepCopyInto(out *ClusterDeploymentStatus) {
 		*out = new(corev1.LocalObjectReference)
 		**out = **in
 	}
+	if in.ReachabilityProbes != nil {
+		in, out := &in.ReachabilityProbes, &out.ReachabilityProbes
+		*out = make([]ReachabilityProbeRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CloudHealth != nil {
+		in, out := &in.CloudHealth, &out.CloudHealth
+		*out = new(CloudHealthStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -714,6 +836,27 @@ func (in *ClusterIngress) DeepCopy() *ClusterIngress {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterMachinesState) DeepCopyInto(out *ClusterMachinesState) {
+	*out = *in
+	if in.MachineSets != nil {
+		in, out := &in.MachineSets, &out.MachineSets
+		*out = make([]MachineSetState, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterMachinesState.
+func (in *ClusterMachinesState) DeepCopy() *ClusterMachinesState {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterMachinesState)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterMetadata) DeepCopyInto(out *ClusterMetadata) {
 	*out = *in
@@ -732,6 +875,32 @@ func (in *ClusterMetadata) DeepCopy() *ClusterMetadata {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterNodesState) DeepCopyInto(out *ClusterNodesState) {
+	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]NodeRoleState, len(*in))
+		copy(*out, *in)
+	}
+	if in.KubeletVersions != nil {
+		in, out := &in.KubeletVersions, &out.KubeletVersions
+		*out = make([]KubeletVersionState, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterNodesState.
+func (in *ClusterNodesState) DeepCopy() *ClusterNodesState {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterNodesState)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterOperatorState) DeepCopyInto(out *ClusterOperatorState) {
 	*out = *in
@@ -920,6 +1089,160 @@ func (in *ClusterProvisionStatus) DeepCopy() *ClusterProvisionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterReachabilityConfig) DeepCopyInto(out *ClusterReachabilityConfig) {
+	*out = *in
+	if in.AdditionalProbes != nil {
+		in, out := &in.AdditionalProbes, &out.AdditionalProbes
+		*out = make([]ClusterReachabilityProbe, len(*in))
+		copy(*out, *in)
+	}
+	if in.ReachableProbeInterval != nil {
+		in, out := &in.ReachableProbeInterval, &out.ReachableProbeInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.UnreachableProbeBackoffFactor != nil {
+		in, out := &in.UnreachableProbeBackoffFactor, &out.UnreachableProbeBackoffFactor
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxUnreachableProbeInterval != nil {
+		in, out := &in.MaxUnreachableProbeInterval, &out.MaxUnreachableProbeInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ProbeHistoryLimit != nil {
+		in, out := &in.ProbeHistoryLimit, &out.ProbeHistoryLimit
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterReachabilityConfig.
+func (in *ClusterReachabilityConfig) DeepCopy() *ClusterReachabilityConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterReachabilityConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterResourceCollection) DeepCopyInto(out *ClusterResourceCollection) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourceCollection.
+func (in *ClusterResourceCollection) DeepCopy() *ClusterResourceCollection {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterResourceCollection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterResourceCollection) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterResourceCollectionList) DeepCopyInto(out *ClusterResourceCollectionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterResourceCollection, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourceCollectionList.
+func (in *ClusterResourceCollectionList) DeepCopy() *ClusterResourceCollectionList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterResourceCollectionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterResourceCollectionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterResourceCollectionSpec) DeepCopyInto(out *ClusterResourceCollectionSpec) {
+	*out = *in
+	in.ClusterDeploymentSelector.DeepCopyInto(&out.ClusterDeploymentSelector)
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]CollectedResource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourceCollectionSpec.
+func (in *ClusterResourceCollectionSpec) DeepCopy() *ClusterResourceCollectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterResourceCollectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterResourceCollectionStatus) DeepCopyInto(out *ClusterResourceCollectionStatus) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]ClusterCollectionStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResourceCollectionStatus.
+func (in *ClusterResourceCollectionStatus) DeepCopy() *ClusterResourceCollectionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterResourceCollectionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterState) DeepCopyInto(out *ClusterState) {
 	*out = *in
@@ -1011,6 +1334,25 @@ func (in *ClusterStateStatus) DeepCopyInto(out *ClusterStateStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ResourcesLastUpdated != nil {
+		in, out := &in.ResourcesLastUpdated, &out.ResourcesLastUpdated
+		*out = (*in).DeepCopy()
+	}
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = new(ClusterNodesState)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Machines != nil {
+		in, out := &in.Machines, &out.Machines
+		*out = new(ClusterMachinesState)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = new(ClusterCapacityState)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1024,6 +1366,61 @@ func (in *ClusterStateStatus) DeepCopy() *ClusterStateStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CollectedResource) DeepCopyInto(out *CollectedResource) {
+	*out = *in
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CollectedResource.
+func (in *CollectedResource) DeepCopy() *CollectedResource {
+	if in == nil {
+		return nil
+	}
+	out := new(CollectedResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CollectedResourceStatus) DeepCopyInto(out *CollectedResourceStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CollectedResourceStatus.
+func (in *CollectedResourceStatus) DeepCopy() *CollectedResourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CollectedResourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapReference) DeepCopyInto(out *ConfigMapReference) {
+	*out = *in
+	out.Source = in.Source
+	out.Target = in.Target
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapReference.
+func (in *ConfigMapReference) DeepCopy() *ConfigMapReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControlPlaneAdditionalCertificate) DeepCopyInto(out *ControlPlaneAdditionalCertificate) {
 	*out = *in
@@ -1585,6 +1982,8 @@ func (in *HiveConfigSpec) DeepCopyInto(out *HiveConfigSpec) {
 	}
 	in.Backup.DeepCopyInto(&out.Backup)
 	out.FailedProvisionConfig = in.FailedProvisionConfig
+	out.SyncSetApplier = in.SyncSetApplier
+	in.ClusterReachability.DeepCopyInto(&out.ClusterReachability)
 	return
 }
 
@@ -1630,6 +2029,22 @@ func (in *IdentityProviderStatus) DeepCopy() *IdentityProviderStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeletVersionState) DeepCopyInto(out *KubeletVersionState) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeletVersionState.
+func (in *KubeletVersionState) DeepCopy() *KubeletVersionState {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeletVersionState)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in Labels) DeepCopyInto(out *Labels) {
 	{
@@ -1662,24 +2077,40 @@ func (in *MachinePool) DeepCopyInto(out *MachinePool) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachinePool.
-func (in *MachinePool) DeepCopy() *MachinePool {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachinePool.
+func (in *MachinePool) DeepCopy() *MachinePool {
+	if in == nil {
+		return nil
+	}
+	out := new(MachinePool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MachinePool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachinePoolAutoscaling) DeepCopyInto(out *MachinePoolAutoscaling) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachinePoolAutoscaling.
+func (in *MachinePoolAutoscaling) DeepCopy() *MachinePoolAutoscaling {
 	if in == nil {
 		return nil
 	}
-	out := new(MachinePool)
+	out := new(MachinePoolAutoscaling)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MachinePool) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MachinePoolCondition) DeepCopyInto(out *MachinePoolCondition) {
 	*out = *in
@@ -1698,6 +2129,37 @@ func (in *MachinePoolCondition) DeepCopy() *MachinePoolCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachinePoolHealthCheck) DeepCopyInto(out *MachinePoolHealthCheck) {
+	*out = *in
+	if in.UnhealthyConditions != nil {
+		in, out := &in.UnhealthyConditions, &out.UnhealthyConditions
+		*out = make([]MachinePoolUnhealthyCondition, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxUnhealthy != nil {
+		in, out := &in.MaxUnhealthy, &out.MaxUnhealthy
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.NodeStartupTimeout != nil {
+		in, out := &in.NodeStartupTimeout, &out.NodeStartupTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachinePoolHealthCheck.
+func (in *MachinePoolHealthCheck) DeepCopy() *MachinePoolHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(MachinePoolHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MachinePoolList) DeepCopyInto(out *MachinePoolList) {
 	*out = *in
@@ -1771,6 +2233,11 @@ func (in *MachinePoolSpec) DeepCopyInto(out *MachinePoolSpec) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(MachinePoolAutoscaling)
+		**out = **in
+	}
 	in.Platform.DeepCopyInto(&out.Platform)
 	if in.Labels != nil {
 		in, out := &in.Labels, &out.Labels
@@ -1786,6 +2253,11 @@ func (in *MachinePoolSpec) DeepCopyInto(out *MachinePoolSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(MachinePoolHealthCheck)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1802,6 +2274,11 @@ func (in *MachinePoolSpec) DeepCopy() *MachinePoolSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MachinePoolStatus) DeepCopyInto(out *MachinePoolStatus) {
 	*out = *in
+	if in.MachineSets != nil {
+		in, out := &in.MachineSets, &out.MachineSets
+		*out = make([]MachineSetStatus, len(*in))
+		copy(*out, *in)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]MachinePoolCondition, len(*in))
@@ -1822,6 +2299,71 @@ func (in *MachinePoolStatus) DeepCopy() *MachinePoolStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachinePoolUnhealthyCondition) DeepCopyInto(out *MachinePoolUnhealthyCondition) {
+	*out = *in
+	out.Timeout = in.Timeout
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachinePoolUnhealthyCondition.
+func (in *MachinePoolUnhealthyCondition) DeepCopy() *MachinePoolUnhealthyCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(MachinePoolUnhealthyCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineSetState) DeepCopyInto(out *MachineSetState) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineSetState.
+func (in *MachineSetState) DeepCopy() *MachineSetState {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineSetState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineSetStatus) DeepCopyInto(out *MachineSetStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineSetStatus.
+func (in *MachineSetStatus) DeepCopy() *MachineSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineSetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeRoleState) DeepCopyInto(out *NodeRoleState) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeRoleState.
+func (in *NodeRoleState) DeepCopy() *NodeRoleState {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeRoleState)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Platform) DeepCopyInto(out *Platform) {
 	*out = *in
@@ -1923,6 +2465,45 @@ func (in *Provisioning) DeepCopy() *Provisioning {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReachabilityProbeResult) DeepCopyInto(out *ReachabilityProbeResult) {
+	*out = *in
+	out.Latency = in.Latency
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReachabilityProbeResult.
+func (in *ReachabilityProbeResult) DeepCopy() *ReachabilityProbeResult {
+	if in == nil {
+		return nil
+	}
+	out := new(ReachabilityProbeResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReachabilityProbeRun) DeepCopyInto(out *ReachabilityProbeRun) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.Results != nil {
+		in, out := &in.Results, &out.Results
+		*out = make([]ReachabilityProbeResult, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReachabilityProbeRun.
+func (in *ReachabilityProbeRun) DeepCopy() *ReachabilityProbeRun {
+	if in == nil {
+		return nil
+	}
+	out := new(ReachabilityProbeRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretReference) DeepCopyInto(out *SecretReference) {
 	*out = *in
@@ -2026,7 +2607,7 @@ func (in *SelectorSyncSet) DeepCopyInto(out *SelectorSyncSet) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -2048,6 +2629,32 @@ func (in *SelectorSyncSet) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SelectorSyncSetClusterMatch) DeepCopyInto(out *SelectorSyncSetClusterMatch) {
+	*out = *in
+	if in.Platforms != nil {
+		in, out := &in.Platforms, &out.Platforms
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Regions != nil {
+		in, out := &in.Regions, &out.Regions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelectorSyncSetClusterMatch.
+func (in *SelectorSyncSetClusterMatch) DeepCopy() *SelectorSyncSetClusterMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(SelectorSyncSetClusterMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SelectorSyncSetList) DeepCopyInto(out *SelectorSyncSetList) {
 	*out = *in
@@ -2097,11 +2704,63 @@ func (in *SelectorSyncSetReference) DeepCopy() *SelectorSyncSetReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SelectorSyncSetRolloutStatus) DeepCopyInto(out *SelectorSyncSetRolloutStatus) {
+	*out = *in
+	if in.AllowedClusters != nil {
+		in, out := &in.AllowedClusters, &out.AllowedClusters
+		*out = make([]SyncSetClusterReference, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelectorSyncSetRolloutStatus.
+func (in *SelectorSyncSetRolloutStatus) DeepCopy() *SelectorSyncSetRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SelectorSyncSetRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SelectorSyncSetRolloutStrategy) DeepCopyInto(out *SelectorSyncSetRolloutStrategy) {
+	*out = *in
+	if in.CanarySelector != nil {
+		in, out := &in.CanarySelector, &out.CanarySelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelectorSyncSetRolloutStrategy.
+func (in *SelectorSyncSetRolloutStrategy) DeepCopy() *SelectorSyncSetRolloutStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(SelectorSyncSetRolloutStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SelectorSyncSetSpec) DeepCopyInto(out *SelectorSyncSetSpec) {
 	*out = *in
 	in.SyncSetCommonSpec.DeepCopyInto(&out.SyncSetCommonSpec)
 	in.ClusterDeploymentSelector.DeepCopyInto(&out.ClusterDeploymentSelector)
+	if in.ClusterMatch != nil {
+		in, out := &in.ClusterMatch, &out.ClusterMatch
+		*out = new(SelectorSyncSetClusterMatch)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(SelectorSyncSetRolloutStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -2118,6 +2777,12 @@ func (in *SelectorSyncSetSpec) DeepCopy() *SelectorSyncSetSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SelectorSyncSetStatus) DeepCopyInto(out *SelectorSyncSetStatus) {
 	*out = *in
+	in.SyncSetCommonStatus.DeepCopyInto(&out.SyncSetCommonStatus)
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(SelectorSyncSetRolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -2255,6 +2920,27 @@ func (in *SyncIdentityProviderSpec) DeepCopy() *SyncIdentityProviderSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyncObjectDeletion) DeepCopyInto(out *SyncObjectDeletion) {
+	*out = *in
+	if in.PropagationPolicy != nil {
+		in, out := &in.PropagationPolicy, &out.PropagationPolicy
+		*out = new(metav1.DeletionPropagation)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncObjectDeletion.
+func (in *SyncObjectDeletion) DeepCopy() *SyncObjectDeletion {
+	if in == nil {
+		return nil
+	}
+	out := new(SyncObjectDeletion)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SyncObjectPatch) DeepCopyInto(out *SyncObjectPatch) {
 	*out = *in
@@ -2277,7 +2963,7 @@ func (in *SyncSet) DeepCopyInto(out *SyncSet) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -2299,6 +2985,54 @@ func (in *SyncSet) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyncSetApplierConfig) DeepCopyInto(out *SyncSetApplierConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncSetApplierConfig.
+func (in *SyncSetApplierConfig) DeepCopy() *SyncSetApplierConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SyncSetApplierConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyncSetClusterFailure) DeepCopyInto(out *SyncSetClusterFailure) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncSetClusterFailure.
+func (in *SyncSetClusterFailure) DeepCopy() *SyncSetClusterFailure {
+	if in == nil {
+		return nil
+	}
+	out := new(SyncSetClusterFailure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyncSetClusterReference) DeepCopyInto(out *SyncSetClusterReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncSetClusterReference.
+func (in *SyncSetClusterReference) DeepCopy() *SyncSetClusterReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SyncSetClusterReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SyncSetCommonSpec) DeepCopyInto(out *SyncSetCommonSpec) {
 	*out = *in
@@ -2319,6 +3053,18 @@ func (in *SyncSetCommonSpec) DeepCopyInto(out *SyncSetCommonSpec) {
 		*out = make([]SecretReference, len(*in))
 		copy(*out, *in)
 	}
+	if in.ConfigMapReferences != nil {
+		in, out := &in.ConfigMapReferences, &out.ConfigMapReferences
+		*out = make([]ConfigMapReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Deletions != nil {
+		in, out := &in.Deletions, &out.Deletions
+		*out = make([]SyncObjectDeletion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -2332,6 +3078,27 @@ func (in *SyncSetCommonSpec) DeepCopy() *SyncSetCommonSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyncSetCommonStatus) DeepCopyInto(out *SyncSetCommonStatus) {
+	*out = *in
+	if in.FailingClusters != nil {
+		in, out := &in.FailingClusters, &out.FailingClusters
+		*out = make([]SyncSetClusterFailure, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncSetCommonStatus.
+func (in *SyncSetCommonStatus) DeepCopy() *SyncSetCommonStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SyncSetCommonStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SyncSetInstance) DeepCopyInto(out *SyncSetInstance) {
 	*out = *in
@@ -2444,6 +3211,20 @@ func (in *SyncSetInstanceStatus) DeepCopyInto(out *SyncSetInstanceStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ConfigMapReferences != nil {
+		in, out := &in.ConfigMapReferences, &out.ConfigMapReferences
+		*out = make([]SyncStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Deletions != nil {
+		in, out := &in.Deletions, &out.Deletions
+		*out = make([]SyncStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]SyncCondition, len(*in))
@@ -2521,6 +3302,20 @@ func (in *SyncSetObjectStatus) DeepCopyInto(out *SyncSetObjectStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ConfigMapReferences != nil {
+		in, out := &in.ConfigMapReferences, &out.ConfigMapReferences
+		*out = make([]SyncStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Deletions != nil {
+		in, out := &in.Deletions, &out.Deletions
+		*out = make([]SyncStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]SyncCondition, len(*in))
@@ -2566,6 +3361,7 @@ func (in *SyncSetSpec) DeepCopy() *SyncSetSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SyncSetStatus) DeepCopyInto(out *SyncSetStatus) {
 	*out = *in
+	in.SyncSetCommonStatus.DeepCopyInto(&out.SyncSetCommonStatus)
 	return
 }
 
@@ -19,6 +19,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/aws/aws-sdk-go/service/elb"
 	"github.com/aws/aws-sdk-go/service/elb/elbiface"
+	"github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
 	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
@@ -57,6 +58,7 @@ type Client interface {
 	DescribeVpcs(*ec2.DescribeVpcsInput) (*ec2.DescribeVpcsOutput, error)
 	DescribeSubnets(*ec2.DescribeSubnetsInput) (*ec2.DescribeSubnetsOutput, error)
 	DescribeSecurityGroups(*ec2.DescribeSecurityGroupsInput) (*ec2.DescribeSecurityGroupsOutput, error)
+	DescribeRouteTables(*ec2.DescribeRouteTablesInput) (*ec2.DescribeRouteTablesOutput, error)
 	RunInstances(*ec2.RunInstancesInput) (*ec2.Reservation, error)
 	DescribeInstances(*ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error)
 	TerminateInstances(*ec2.TerminateInstancesInput) (*ec2.TerminateInstancesOutput, error)
@@ -64,6 +66,11 @@ type Client interface {
 	//ELB
 	RegisterInstancesWithLoadBalancer(*elb.RegisterInstancesWithLoadBalancerInput) (*elb.RegisterInstancesWithLoadBalancerOutput, error)
 
+	//ELBV2
+	DescribeLoadBalancers(*elbv2.DescribeLoadBalancersInput) (*elbv2.DescribeLoadBalancersOutput, error)
+	DescribeTargetGroups(*elbv2.DescribeTargetGroupsInput) (*elbv2.DescribeTargetGroupsOutput, error)
+	DescribeTargetHealth(*elbv2.DescribeTargetHealthInput) (*elbv2.DescribeTargetHealthOutput, error)
+
 	//IAM
 	CreateAccessKey(*iam.CreateAccessKeyInput) (*iam.CreateAccessKeyOutput, error)
 	CreateUser(*iam.CreateUserInput) (*iam.CreateUserOutput, error)
@@ -101,6 +108,7 @@ type Client interface {
 type awsClient struct {
 	ec2Client     ec2iface.EC2API
 	elbClient     elbiface.ELBAPI
+	elbv2Client   *elbv2.ELBV2
 	iamClient     iamiface.IAMAPI
 	route53Client route53iface.Route53API
 	s3Client      s3iface.S3API
@@ -132,6 +140,11 @@ func (c *awsClient) DescribeSecurityGroups(input *ec2.DescribeSecurityGroupsInpu
 	return c.ec2Client.DescribeSecurityGroups(input)
 }
 
+func (c *awsClient) DescribeRouteTables(input *ec2.DescribeRouteTablesInput) (*ec2.DescribeRouteTablesOutput, error) {
+	metricAWSAPICalls.WithLabelValues("DescribeRouteTables").Inc()
+	return c.ec2Client.DescribeRouteTables(input)
+}
+
 func (c *awsClient) RunInstances(input *ec2.RunInstancesInput) (*ec2.Reservation, error) {
 	metricAWSAPICalls.WithLabelValues("RunInstances").Inc()
 	return c.ec2Client.RunInstances(input)
@@ -152,6 +165,21 @@ func (c *awsClient) RegisterInstancesWithLoadBalancer(input *elb.RegisterInstanc
 	return c.elbClient.RegisterInstancesWithLoadBalancer(input)
 }
 
+func (c *awsClient) DescribeLoadBalancers(input *elbv2.DescribeLoadBalancersInput) (*elbv2.DescribeLoadBalancersOutput, error) {
+	metricAWSAPICalls.WithLabelValues("DescribeLoadBalancers").Inc()
+	return c.elbv2Client.DescribeLoadBalancers(input)
+}
+
+func (c *awsClient) DescribeTargetGroups(input *elbv2.DescribeTargetGroupsInput) (*elbv2.DescribeTargetGroupsOutput, error) {
+	metricAWSAPICalls.WithLabelValues("DescribeTargetGroups").Inc()
+	return c.elbv2Client.DescribeTargetGroups(input)
+}
+
+func (c *awsClient) DescribeTargetHealth(input *elbv2.DescribeTargetHealthInput) (*elbv2.DescribeTargetHealthOutput, error) {
+	metricAWSAPICalls.WithLabelValues("DescribeTargetHealth").Inc()
+	return c.elbv2Client.DescribeTargetHealth(input)
+}
+
 func (c *awsClient) CreateAccessKey(input *iam.CreateAccessKeyInput) (*iam.CreateAccessKeyOutput, error) {
 	metricAWSAPICalls.WithLabelValues("CreateAccessKey").Inc()
 	return c.iamClient.CreateAccessKey(input)
@@ -327,6 +355,7 @@ func NewClientFromSecret(secret *corev1.Secret, region string) (Client, error) {
 	return &awsClient{
 		ec2Client:     ec2.New(s),
 		elbClient:     elb.New(s),
+		elbv2Client:   elbv2.New(s),
 		iamClient:     iam.New(s),
 		s3Client:      s3.New(s),
 		route53Client: route53.New(s),
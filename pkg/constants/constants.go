@@ -29,6 +29,26 @@ const (
 	// install pods which do the actual log gathering.
 	SkipGatherLogsEnvVar = "SKIP_GATHER_LOGS"
 
+	// SyncSetApplierEnvVar is the environment variable which passes the type of applier used by the syncsetinstance
+	// controller to apply SyncSet resources and patches to target clusters.
+	SyncSetApplierEnvVar = "HIVE_SYNCSET_APPLIER"
+
+	// SyncSetApplierFieldManagerEnvVar is the environment variable which passes the field manager used by the
+	// server-side SyncSet applier.
+	SyncSetApplierFieldManagerEnvVar = "HIVE_SYNCSET_APPLIER_FIELD_MANAGER"
+
+	// SyncSetApplierForceConflictsEnvVar is the environment variable which tells the server-side SyncSet applier
+	// to take ownership of conflicting fields.
+	SyncSetApplierForceConflictsEnvVar = "HIVE_SYNCSET_APPLIER_FORCE_CONFLICTS"
+
+	// SyncSetConflictPolicyEnvVar is the environment variable which passes the SyncSet conflict policy to the
+	// SyncSet and SelectorSyncSet validating webhooks.
+	SyncSetConflictPolicyEnvVar = "HIVE_SYNCSET_CONFLICT_POLICY"
+
+	// ClusterReachabilityEnvVar is the environment variable which passes the JSON encoded
+	// ClusterReachabilityConfig of the HiveConfig to the unreachable controller.
+	ClusterReachabilityEnvVar = "HIVE_CLUSTER_REACHABILITY"
+
 	// InstallJobLabel is the label used for artifacts specific to Hive cluster installations.
 	InstallJobLabel = "hive.openshift.io/install"
 
@@ -50,6 +70,44 @@ const (
 	// SyncsetPauseAnnotation is a annotation used by clusterDeployment, if it's true, then we will disable syncing to a specific cluster
 	SyncsetPauseAnnotation = "hive.openshift.io/syncset-pause"
 
+	// SyncSetApplyWaveAnnotation is an annotation set on SyncSet resources to control the order in which they are
+	// applied. Resources are applied in ascending order of their integer wave (0 if unset), and each wave must become
+	// ready before the next one is applied.
+	SyncSetApplyWaveAnnotation = "hive.openshift.io/syncset-apply-wave"
+
+	// SyncSetReadinessCheckAnnotation is an annotation set on SyncSet resources to override how their readiness is
+	// determined before the next apply wave. The value is a JSONPath template optionally followed by "=" and the
+	// expected value, e.g. "{.status.phase}=Succeeded". Without an expected value, any non-empty result is ready.
+	SyncSetReadinessCheckAnnotation = "hive.openshift.io/syncset-readiness-check"
+
+	// SyncSetPriorityAnnotation is an annotation set on SyncSets and SelectorSyncSets to resolve conflicts when the
+	// SyncSet conflict policy is "Reject". A SyncSet is rejected when it manages an object that is also managed by an
+	// existing SyncSet targeting the same cluster with an equal or higher integer priority (0 if unset).
+	SyncSetPriorityAnnotation = "hive.openshift.io/syncset-priority"
+
+	// SyncSetOwnerLabel is a label set on remote objects created from SyncSets and SelectorSyncSets. Its value is a hash
+	// identifying the SyncSetInstance that owns the object, and is used to find objects that need to be pruned.
+	SyncSetOwnerLabel = "hive.openshift.io/syncset-owner"
+
+	// SyncSetOwnerAnnotation is an annotation set on remote objects created from SyncSets and SelectorSyncSets which
+	// names the SyncSet or SelectorSyncSet that owns the object.
+	SyncSetOwnerAnnotation = "hive.openshift.io/syncset-owner"
+
+	// SyncSetOwnerClusterDeploymentAnnotation is an annotation set on remote objects created from SyncSets and
+	// SelectorSyncSets which names the ClusterDeployment the object was synced for.
+	SyncSetOwnerClusterDeploymentAnnotation = "hive.openshift.io/syncset-clusterdeployment"
+
+	// SelectorSyncSetUIDLabel is the label that is used to identify the rollout snapshots of a particular
+	// SelectorSyncSet by its UID.
+	SelectorSyncSetUIDLabel = "hive.openshift.io/selector-syncset-uid"
+
+	// SyncSetHashLabel is the label that holds the hash of the SelectorSyncSet spec kept by a rollout snapshot.
+	SyncSetHashLabel = "hive.openshift.io/syncset-hash"
+
+	// ClusterResourceCollectionNameLabel is the label that is used to identify the ConfigMaps of a particular
+	// cluster resource collection.
+	ClusterResourceCollectionNameLabel = "hive.openshift.io/cluster-resource-collection-name"
+
 	// ManagedDomainsFileEnvVar if present, points to a simple text
 	// file that includes a valid managed domain per line. Cluster deployments
 	// requesting that their domains be managed must have a base domain
@@ -1,10 +1,10 @@
 package controller
 
 import (
-	"github.com/openshift/hive/pkg/controller/syncmachineset"
+	"github.com/openshift/hive/pkg/controller/syncsetstatus"
 )
 
 func init() {
 	// AddToManagerFuncs is a list of functions to create controllers and add them to a manager.
-	AddToManagerFuncs = append(AddToManagerFuncs, syncmachineset.Add)
+	AddToManagerFuncs = append(AddToManagerFuncs, syncsetstatus.Add)
 }
@@ -0,0 +1,161 @@
+package clusterdeployment
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	hivev1aws "github.com/openshift/hive/pkg/apis/hive/v1/aws"
+	"github.com/openshift/hive/pkg/awsclient"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+const (
+	invalidAWSNetworkReason = "InvalidAWSNetwork"
+	validAWSNetworkReason   = "AWSNetworkValid"
+
+	// awsNetworkRecheckInterval is how long to wait before looking up the existing network of a cluster deployment
+	// again when it was invalid
+	awsNetworkRecheckInterval = 5 * time.Minute
+)
+
+// validateAWSNetwork checks that the existing subnets of an AWS cluster deployment are in a single available VPC,
+// and that its additional security groups are in that VPC, before the cluster is provisioned. The result is recorded
+// in the InvalidAWSNetwork condition. The platform of a cluster deployment cannot change, so a network that was found
+// valid is not looked up again.
+func (r *ReconcileClusterDeployment) validateAWSNetwork(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) (bool, error) {
+	platform := cd.Spec.Platform.AWS
+	if platform == nil || len(platform.Subnets) == 0 {
+		return true, nil
+	}
+	if cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.InvalidAWSNetworkCondition); cond != nil && cond.Status == corev1.ConditionFalse {
+		return true, nil
+	}
+
+	awsClient, err := r.awsClientBuilder(r.Client, platform.CredentialsSecretRef.Name, cd.Namespace, platform.Region)
+	if err != nil {
+		cdLog.WithError(err).Error("could not create AWS client")
+		return false, err
+	}
+	problems, err := awsNetworkProblems(awsClient, platform)
+	if err != nil {
+		cdLog.WithError(err).Error("could not look up the existing network")
+		return false, err
+	}
+
+	status := corev1.ConditionFalse
+	reason := validAWSNetworkReason
+	message := "The existing subnets and security groups are in an available VPC"
+	if len(problems) > 0 {
+		status = corev1.ConditionTrue
+		reason = invalidAWSNetworkReason
+		message = strings.Join(problems, "; ")
+	}
+	conds, changed := controllerutils.SetClusterDeploymentConditionWithChangeCheck(
+		cd.Status.Conditions,
+		hivev1.InvalidAWSNetworkCondition,
+		status,
+		reason,
+		message,
+		controllerutils.UpdateConditionNever)
+	if controllerutils.FindClusterDeploymentCondition(conds, hivev1.InvalidAWSNetworkCondition) == nil {
+		// A missing condition is not added when false, but a valid network is recorded so that it is not looked
+		// up again
+		now := metav1.Now()
+		conds = append(conds, hivev1.ClusterDeploymentCondition{
+			Type:               hivev1.InvalidAWSNetworkCondition,
+			Status:             status,
+			Reason:             reason,
+			Message:            message,
+			LastTransitionTime: now,
+			LastProbeTime:      now,
+		})
+		changed = true
+	}
+	if changed {
+		cdLog.Infof("setting InvalidAWSNetworkCondition to %v", status)
+		cd.Status.Conditions = conds
+		if err := r.Status().Update(context.TODO(), cd); err != nil {
+			cdLog.WithError(err).Log(controllerutils.LogLevel(err), "cannot update status conditions")
+			return false, err
+		}
+	}
+	return len(problems) == 0, nil
+}
+
+// awsNetworkProblems returns why the existing subnets and additional security groups of an AWS platform are invalid,
+// or nothing when they are valid.
+func awsNetworkProblems(awsClient awsclient.Client, platform *hivev1aws.Platform) ([]string, error) {
+	subnetsResp, err := awsClient.DescribeSubnets(&ec2.DescribeSubnetsInput{
+		Filters: []*ec2.Filter{{Name: aws.String("subnet-id"), Values: aws.StringSlice(platform.Subnets)}},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to describe subnets")
+	}
+	subnetVPCs := map[string]string{}
+	for _, subnet := range subnetsResp.Subnets {
+		subnetVPCs[aws.StringValue(subnet.SubnetId)] = aws.StringValue(subnet.VpcId)
+	}
+	problems := []string{}
+	vpcs := sets.NewString()
+	for _, id := range platform.Subnets {
+		vpc, ok := subnetVPCs[id]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("subnet %s not found", id))
+			continue
+		}
+		vpcs.Insert(vpc)
+	}
+	if len(problems) > 0 {
+		return problems, nil
+	}
+	if vpcs.Len() != 1 {
+		return []string{fmt.Sprintf("subnets must belong to a single VPC, found %s", strings.Join(vpcs.List(), ", "))}, nil
+	}
+	vpc, _ := vpcs.PopAny()
+
+	vpcsResp, err := awsClient.DescribeVpcs(&ec2.DescribeVpcsInput{
+		Filters: []*ec2.Filter{{Name: aws.String("vpc-id"), Values: aws.StringSlice([]string{vpc})}},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to describe VPCs")
+	}
+	if len(vpcsResp.Vpcs) == 0 || aws.StringValue(vpcsResp.Vpcs[0].State) != ec2.VpcStateAvailable {
+		return []string{fmt.Sprintf("VPC %s of the subnets is not available", vpc)}, nil
+	}
+
+	if len(platform.AdditionalSecurityGroupIDs) == 0 {
+		return nil, nil
+	}
+	securityGroupsResp, err := awsClient.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{
+		Filters: []*ec2.Filter{{Name: aws.String("group-id"), Values: aws.StringSlice(platform.AdditionalSecurityGroupIDs)}},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to describe security groups")
+	}
+	securityGroupVPCs := map[string]string{}
+	for _, sg := range securityGroupsResp.SecurityGroups {
+		securityGroupVPCs[aws.StringValue(sg.GroupId)] = aws.StringValue(sg.VpcId)
+	}
+	for _, id := range platform.AdditionalSecurityGroupIDs {
+		sgVPC, ok := securityGroupVPCs[id]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("security group %s not found", id))
+		case sgVPC != vpc:
+			problems = append(problems, fmt.Sprintf("security group %s must belong to VPC %s of the subnets", id, vpc))
+		}
+	}
+	return problems, nil
+}
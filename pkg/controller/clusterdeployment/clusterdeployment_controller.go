@@ -6,6 +6,7 @@ import (
 	"os"
 	"reflect"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -35,6 +36,7 @@ import (
 
 	apihelpers "github.com/openshift/hive/pkg/apis/helpers"
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/awsclient"
 	"github.com/openshift/hive/pkg/constants"
 	"github.com/openshift/hive/pkg/controller/images"
 	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
@@ -147,7 +149,8 @@ func NewReconciler(mgr manager.Manager) reconcile.Reconciler {
 		scheme:                        mgr.GetScheme(),
 		logger:                        logger,
 		expectations:                  controllerutils.NewExpectations(logger),
-		remoteClusterAPIClientBuilder: controllerutils.BuildClusterAPIClientFromKubeconfig,
+		remoteClusterAPIClientBuilder: controllerutils.RemoteClusters.ClusterAPIClient,
+		awsClientBuilder:              awsclient.NewClient,
 	}
 }
 
@@ -241,6 +244,10 @@ type ReconcileClusterDeployment struct {
 	// remoteClusterAPIClientBuilder is a function pointer to the function that builds a client for the
 	// remote cluster's cluster-api
 	remoteClusterAPIClientBuilder func(string, string) (client.Client, error)
+
+	// awsClientBuilder is a function pointer to the function that builds the aws client used to validate the
+	// existing network of AWS clusters
+	awsClientBuilder func(kClient client.Client, secretName, namespace, region string) (awsclient.Client, error)
 }
 
 // Reconcile reads that state of the cluster for a ClusterDeployment object and makes changes based on the state read
@@ -434,6 +441,13 @@ func (r *ReconcileClusterDeployment) reconcile(request reconcile.Request, cd *hi
 			cdLog.Debug("not creating new provision since the deployment is set to try install only once")
 			return reconcile.Result{}, nil
 		}
+		switch valid, err := r.validateAWSNetwork(cd, cdLog); {
+		case err != nil:
+			return reconcile.Result{}, err
+		case !valid:
+			cdLog.Info("not creating new provision since the existing network is invalid")
+			return reconcile.Result{RequeueAfter: awsNetworkRecheckInterval}, nil
+		}
 		return r.startNewProvision(cd, releaseImage, cdLog)
 	}
 
@@ -1412,6 +1426,7 @@ func generateDeprovision(cd *hivev1.ClusterDeployment) (*hivev1.ClusterDeprovisi
 		req.Spec.Platform.GCP = &hivev1.GCPClusterDeprovision{
 			Region:               cd.Spec.Platform.GCP.Region,
 			ProjectID:            cd.Spec.Platform.GCP.ProjectID,
+			NetworkProjectID:     cd.Spec.Platform.GCP.NetworkProjectID,
 			CredentialsSecretRef: &cd.Spec.Platform.GCP.CredentialsSecretRef,
 		}
 	default:
@@ -1688,6 +1703,16 @@ func checkForFailedSyncSetInstance(syncSetInstances []*hivev1.SyncSetInstance) b
 				return true
 			}
 		}
+		for _, c := range syncSetInstance.Status.ConfigMapReferences {
+			if checkSyncSetConditionsForFailure(c.Conditions) {
+				return true
+			}
+		}
+		for _, d := range syncSetInstance.Status.Deletions {
+			if checkSyncSetConditionsForFailure(d.Conditions) {
+				return true
+			}
+		}
 	}
 	return false
 }
@@ -1707,7 +1732,8 @@ func checkSyncSetConditionsForFailure(conds []hivev1.SyncCondition) bool {
 	return false
 }
 
-// setSyncSetFailedCondition returns true when it sets or updates the hivev1.SyncSetFailedCondition
+// setSyncSetFailedCondition returns true when it sets or updates the hivev1.SyncSetFailedCondition or the
+// hivev1.SyncSetConflictCondition
 func (r *ReconcileClusterDeployment) setSyncSetFailedCondition(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) (bool, error) {
 	// get all syncset instances for this cluster deployment
 	syncSetInstances, err := r.getAllSyncSetInstances(cd)
@@ -1726,7 +1752,7 @@ func (r *ReconcileClusterDeployment) setSyncSetFailedCondition(cd *hivev1.Cluste
 		reason = "SyncSetApplyFailure"
 		message = "One of the SyncSetInstance apply has failed"
 	}
-	conds, changed := controllerutils.SetClusterDeploymentConditionWithChangeCheck(
+	conds, failedChanged := controllerutils.SetClusterDeploymentConditionWithChangeCheck(
 		cd.Status.Conditions,
 		hivev1.SyncSetFailedCondition,
 		status,
@@ -1734,7 +1760,25 @@ func (r *ReconcileClusterDeployment) setSyncSetFailedCondition(cd *hivev1.Cluste
 		message,
 		controllerutils.UpdateConditionNever,
 	)
-	if !changed {
+
+	conflicting := conflictingSyncSetInstances(syncSetInstances)
+	status = corev1.ConditionFalse
+	reason = "NoSyncSetConflict"
+	message = "No objects are managed by more than one SyncSet"
+	if len(conflicting) > 0 {
+		status = corev1.ConditionTrue
+		reason = "SyncSetConflict"
+		message = fmt.Sprintf("SyncSetInstances manage the same objects with different content: %s", strings.Join(conflicting, ", "))
+	}
+	conds, conflictChanged := controllerutils.SetClusterDeploymentConditionWithChangeCheck(
+		conds,
+		hivev1.SyncSetConflictCondition,
+		status,
+		reason,
+		message,
+		controllerutils.UpdateConditionIfReasonOrMessageChange,
+	)
+	if !failedChanged && !conflictChanged {
 		return false, nil
 	}
 	cd.Status.Conditions = conds
@@ -1745,6 +1789,19 @@ func (r *ReconcileClusterDeployment) setSyncSetFailedCondition(cd *hivev1.Cluste
 	return true, nil
 }
 
+// conflictingSyncSetInstances returns the sorted names of the syncset instances that manage objects also managed by
+// another syncset instance
+func conflictingSyncSetInstances(syncSetInstances []*hivev1.SyncSetInstance) []string {
+	var conflicting []string
+	for _, syncSetInstance := range syncSetInstances {
+		if len(controllerutils.SyncSetInstanceConflicts(syncSetInstance, syncSetInstances)) > 0 {
+			conflicting = append(conflicting, syncSetInstance.Name)
+		}
+	}
+	sort.Strings(conflicting)
+	return conflicting
+}
+
 // getClusterPlatform returns the platform of a given ClusterDeployment
 func getClusterPlatform(cd *hivev1.ClusterDeployment) string {
 	switch {
@@ -7,6 +7,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 
@@ -32,6 +35,9 @@ import (
 	"github.com/openshift/hive/pkg/apis"
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
 	hivev1aws "github.com/openshift/hive/pkg/apis/hive/v1/aws"
+	hivev1gcp "github.com/openshift/hive/pkg/apis/hive/v1/gcp"
+	"github.com/openshift/hive/pkg/awsclient"
+	mockaws "github.com/openshift/hive/pkg/awsclient/mock"
 	"github.com/openshift/hive/pkg/constants"
 	controllerutils "github.com/openshift/hive/pkg/controller/utils"
 )
@@ -986,6 +992,39 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "setSyncSetConflictCondition should be present",
+			existing: []runtime.Object{
+				testInstalledClusterDeployment(time.Now()),
+				createSyncSetInstanceWithResource("ssi1", "hash1"),
+				createSyncSetInstanceWithResource("ssi2", "hash2"),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				cd := getCD(c)
+				if assert.NotNil(t, cd, "missing clusterdeployment") {
+					cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.SyncSetConflictCondition)
+					if assert.NotNil(t, cond, "missing SyncSetConflictCondition status condition") {
+						assert.Equal(t, corev1.ConditionTrue, cond.Status, "did not get expected state for SyncSetConflictCondition condition")
+						assert.Contains(t, cond.Message, "ssi1, ssi2", "expected conflicting syncset instances in message")
+					}
+				}
+			},
+		},
+		{
+			name: "setSyncSetConflictCondition should not be present for identical content",
+			existing: []runtime.Object{
+				testInstalledClusterDeployment(time.Now()),
+				createSyncSetInstanceWithResource("ssi1", "hash1"),
+				createSyncSetInstanceWithResource("ssi2", "hash1"),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				cd := getCD(c)
+				if assert.NotNil(t, cd, "missing clusterdeployment") {
+					cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.SyncSetConflictCondition)
+					assert.Nil(t, cond, "unexpected SyncSetConflictCondition status condition")
+				}
+			},
+		},
 		{
 			name: "Add cluster platform label",
 			existing: []runtime.Object{
@@ -1148,6 +1187,166 @@ func TestClusterDeploymentReconcileResults(t *testing.T) {
 	}
 }
 
+func TestClusterDeploymentReconcileAWSNetwork(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	cases := []struct {
+		name                    string
+		securityGroups          []string
+		validated               bool
+		subnetVPCs              map[string]string
+		vpcState                string
+		securityGroupVPCs       map[string]string
+		expectDescribeSubnets   bool
+		expectDescribeVPCs      bool
+		expectDescribeGroups    bool
+		expectedConditionStatus corev1.ConditionStatus
+	}{
+		{
+			name:                    "subnets of available VPC",
+			subnetVPCs:              map[string]string{"subnet-private": "vpc-1", "subnet-public": "vpc-1"},
+			vpcState:                ec2.VpcStateAvailable,
+			expectDescribeSubnets:   true,
+			expectDescribeVPCs:      true,
+			expectedConditionStatus: corev1.ConditionFalse,
+		},
+		{
+			name:                    "validated network is not looked up again",
+			validated:               true,
+			expectedConditionStatus: corev1.ConditionFalse,
+		},
+		{
+			name:                    "missing subnet",
+			subnetVPCs:              map[string]string{"subnet-private": "vpc-1"},
+			expectDescribeSubnets:   true,
+			expectedConditionStatus: corev1.ConditionTrue,
+		},
+		{
+			name:                    "subnets of several VPCs",
+			subnetVPCs:              map[string]string{"subnet-private": "vpc-1", "subnet-public": "vpc-2"},
+			expectDescribeSubnets:   true,
+			expectedConditionStatus: corev1.ConditionTrue,
+		},
+		{
+			name:                    "VPC not available",
+			subnetVPCs:              map[string]string{"subnet-private": "vpc-1", "subnet-public": "vpc-1"},
+			vpcState:                ec2.VpcStatePending,
+			expectDescribeSubnets:   true,
+			expectDescribeVPCs:      true,
+			expectedConditionStatus: corev1.ConditionTrue,
+		},
+		{
+			name:                    "security groups of VPC",
+			securityGroups:          []string{"sg-1", "sg-2"},
+			subnetVPCs:              map[string]string{"subnet-private": "vpc-1", "subnet-public": "vpc-1"},
+			vpcState:                ec2.VpcStateAvailable,
+			securityGroupVPCs:       map[string]string{"sg-1": "vpc-1", "sg-2": "vpc-1"},
+			expectDescribeSubnets:   true,
+			expectDescribeVPCs:      true,
+			expectDescribeGroups:    true,
+			expectedConditionStatus: corev1.ConditionFalse,
+		},
+		{
+			name:                    "security group of other VPC",
+			securityGroups:          []string{"sg-1", "sg-2"},
+			subnetVPCs:              map[string]string{"subnet-private": "vpc-1", "subnet-public": "vpc-1"},
+			vpcState:                ec2.VpcStateAvailable,
+			securityGroupVPCs:       map[string]string{"sg-1": "vpc-1", "sg-2": "vpc-2"},
+			expectDescribeSubnets:   true,
+			expectDescribeVPCs:      true,
+			expectDescribeGroups:    true,
+			expectedConditionStatus: corev1.ConditionTrue,
+		},
+		{
+			name:                    "missing security group",
+			securityGroups:          []string{"sg-1", "sg-2"},
+			subnetVPCs:              map[string]string{"subnet-private": "vpc-1", "subnet-public": "vpc-1"},
+			vpcState:                ec2.VpcStateAvailable,
+			securityGroupVPCs:       map[string]string{"sg-1": "vpc-1"},
+			expectDescribeSubnets:   true,
+			expectDescribeVPCs:      true,
+			expectDescribeGroups:    true,
+			expectedConditionStatus: corev1.ConditionTrue,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockAWSClient := mockaws.NewMockClient(mockCtrl)
+
+			if tc.expectDescribeSubnets {
+				subnets := []*ec2.Subnet{}
+				for id, vpc := range tc.subnetVPCs {
+					subnets = append(subnets, &ec2.Subnet{SubnetId: aws.String(id), VpcId: aws.String(vpc)})
+				}
+				mockAWSClient.EXPECT().DescribeSubnets(gomock.Any()).Return(&ec2.DescribeSubnetsOutput{Subnets: subnets}, nil)
+			}
+			if tc.expectDescribeVPCs {
+				mockAWSClient.EXPECT().DescribeVpcs(gomock.Any()).Return(&ec2.DescribeVpcsOutput{
+					Vpcs: []*ec2.Vpc{{VpcId: aws.String("vpc-1"), State: aws.String(tc.vpcState)}},
+				}, nil)
+			}
+			if tc.expectDescribeGroups {
+				groups := []*ec2.SecurityGroup{}
+				for id, vpc := range tc.securityGroupVPCs {
+					groups = append(groups, &ec2.SecurityGroup{GroupId: aws.String(id), VpcId: aws.String(vpc)})
+				}
+				mockAWSClient.EXPECT().DescribeSecurityGroups(gomock.Any()).Return(&ec2.DescribeSecurityGroupsOutput{SecurityGroups: groups}, nil)
+			}
+
+			cd := testClusterDeployment()
+			cd.Spec.Platform.AWS.Subnets = []string{"subnet-private", "subnet-public"}
+			cd.Spec.Platform.AWS.AdditionalSecurityGroupIDs = tc.securityGroups
+			if tc.validated {
+				cd.Status.Conditions = []hivev1.ClusterDeploymentCondition{{
+					Type:   hivev1.InvalidAWSNetworkCondition,
+					Status: corev1.ConditionFalse,
+					Reason: validAWSNetworkReason,
+				}}
+			}
+			logger := log.WithField("controller", "clusterDeployment")
+			fakeClient := fake.NewFakeClient(
+				cd,
+				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testSecret(corev1.SecretTypeDockerConfigJson, constants.GetMergedPullSecretName(cd), corev1.DockerConfigJsonKey, "{}"),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			)
+			rcd := &ReconcileClusterDeployment{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				logger:                        logger,
+				expectations:                  controllerutils.NewExpectations(logger),
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				awsClientBuilder: func(client.Client, string, string, string) (awsclient.Client, error) {
+					return mockAWSClient, nil
+				},
+			}
+
+			result, err := rcd.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testName,
+					Namespace: testNamespace,
+				},
+			})
+			if !assert.NoError(t, err, "unexpected error") {
+				return
+			}
+
+			cd = getCDFromClient(fakeClient)
+			assertConditionStatus(t, cd, hivev1.InvalidAWSNetworkCondition, tc.expectedConditionStatus)
+			provisions := getProvisions(fakeClient)
+			if tc.expectedConditionStatus == corev1.ConditionTrue {
+				assert.Empty(t, provisions, "expected no provision for invalid network")
+				assert.Equal(t, awsNetworkRecheckInterval, result.RequeueAfter, "unexpected requeue after")
+			} else {
+				assert.Len(t, provisions, 1, "expected provision to exist")
+			}
+		})
+	}
+}
+
 func TestCalculateNextProvisionTime(t *testing.T) {
 	cases := []struct {
 		name             string
@@ -1263,6 +1462,29 @@ func TestDeleteStaleProvisions(t *testing.T) {
 	}
 }
 
+func TestGenerateGCPDeprovision(t *testing.T) {
+	cd := testClusterDeployment()
+	cd.Spec.Platform = hivev1.Platform{
+		GCP: &hivev1gcp.Platform{
+			CredentialsSecretRef: corev1.LocalObjectReference{
+				Name: "gcp-credentials",
+			},
+			ProjectID:        "test-project",
+			NetworkProjectID: "test-host-project",
+			Region:           "us-east1",
+		},
+	}
+	req, err := generateDeprovision(cd)
+	if assert.NoError(t, err, "unexpected error generating deprovision") {
+		assert.Equal(t, &hivev1.GCPClusterDeprovision{
+			Region:               "us-east1",
+			ProjectID:            "test-project",
+			NetworkProjectID:     "test-host-project",
+			CredentialsSecretRef: &corev1.LocalObjectReference{Name: "gcp-credentials"},
+		}, req.Spec.Platform.GCP, "unexpected GCP deprovision")
+	}
+}
+
 func testEmptyClusterDeployment() *hivev1.ClusterDeployment {
 	cd := &hivev1.ClusterDeployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1799,6 +2021,26 @@ func createSyncSetInstanceObj(syncCondType hivev1.SyncConditionType) *hivev1.Syn
 	return ssi
 }
 
+func createSyncSetInstanceWithResource(name, hash string) *hivev1.SyncSetInstance {
+	ssi := &hivev1.SyncSetInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+		},
+	}
+	ssi.Spec.ClusterDeploymentRef.Name = testName
+	ssi.Status.Resources = []hivev1.SyncStatus{
+		{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+			Name:       "foo",
+			Namespace:  "default",
+			Hash:       hash,
+		},
+	}
+	return ssi
+}
+
 func createSyncSetInstanceStatus(syncCondType hivev1.SyncConditionType) hivev1.SyncSetInstanceStatus {
 	conditionTime := metav1.NewTime(time.Now())
 	var ssiStatus corev1.ConditionStatus
@@ -0,0 +1,522 @@
+package clusterresourcecollection
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	apihelpers "github.com/openshift/hive/pkg/apis/helpers"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+const (
+	controllerName = "clusterResourceCollection"
+
+	// defaultCollectionInterval is how often objects are collected from a cluster when the collection does not set an
+	// interval
+	defaultCollectionInterval = time.Hour
+
+	// maxConfigMapDataSize is the maximum size of the collected objects stored in a single ConfigMap. It leaves room
+	// below the 1MiB limit of objects in etcd for the rest of the ConfigMap.
+	maxConfigMapDataSize = 900 * 1024
+
+	// clusterCollectionTimeout is how long objects are collected from a single cluster. The objects collected when
+	// the timeout expires are stored, and the remaining resources are reported as timed out.
+	clusterCollectionTimeout = 2 * time.Minute
+
+	// maxConcurrentCollections is the maximum number of clusters objects of a collection are collected from in parallel
+	maxConcurrentCollections = 10
+
+	collectionTimedOutMessage = "timed out collecting objects from cluster"
+)
+
+// Add creates a new ClusterResourceCollection controller and adds it to the manager with default RBAC.
+func Add(mgr manager.Manager) error {
+	return AddToManager(mgr, NewReconciler(mgr))
+}
+
+// NewReconciler returns a new reconcile.Reconciler
+func NewReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileClusterResourceCollection{
+		Client:              controllerutils.NewClientWithMetricsOrDie(mgr, controllerName),
+		scheme:              mgr.GetScheme(),
+		logger:              log.WithField("controller", controllerName),
+		remoteClientBuilder: controllerutils.RemoteClusters.ClusterAPIClient,
+		collectionTimeout:   clusterCollectionTimeout,
+	}
+}
+
+// AddToManager adds a new Controller to mgr with r as the reconcile.Reconciler
+func AddToManager(mgr manager.Manager, r reconcile.Reconciler) error {
+	c, err := controller.New("clusterresourcecollection-controller", mgr, controller.Options{Reconciler: r, MaxConcurrentReconciles: controllerutils.GetConcurrentReconciles()})
+	if err != nil {
+		log.WithField("controller", controllerName).WithError(err).Error("Error creating new clusterresourcecollection controller")
+		return err
+	}
+
+	reconciler := r.(*ReconcileClusterResourceCollection)
+
+	// Watch for changes to ClusterResourceCollection
+	err = c.Watch(&source.Kind{Type: &hivev1.ClusterResourceCollection{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		log.WithField("controller", controllerName).WithError(err).Error("Error watching cluster resource collection")
+		return err
+	}
+
+	// Watch for changes to ClusterDeployment, to start collecting from newly selected and installed clusters
+	err = c.Watch(&source.Kind{Type: &hivev1.ClusterDeployment{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(reconciler.clusterDeploymentWatchHandler),
+	})
+	if err != nil {
+		log.WithField("controller", controllerName).WithError(err).Error("Error watching cluster deployment")
+		return err
+	}
+	return nil
+}
+
+// clusterDeploymentWatchHandler returns requests for the collections that select the cluster deployment
+func (r *ReconcileClusterResourceCollection) clusterDeploymentWatchHandler(a handler.MapObject) []reconcile.Request {
+	retval := []reconcile.Request{}
+
+	cd, ok := a.Object.(*hivev1.ClusterDeployment)
+	if !ok {
+		// Wasn't a ClusterDeployment, bail out. This should not happen.
+		r.logger.Errorf("Error converting MapObject.Object to ClusterDeployment. Value: %+v", a.Object)
+		return retval
+	}
+
+	collections := &hivev1.ClusterResourceCollectionList{}
+	if err := r.List(context.TODO(), collections); err != nil {
+		r.logger.WithError(err).Error("failed to list cluster resource collections")
+		return retval
+	}
+	for _, collection := range collections.Items {
+		selector, err := metav1.LabelSelectorAsSelector(&collection.Spec.ClusterDeploymentSelector)
+		if err != nil {
+			r.logger.WithError(err).WithField("clusterResourceCollection", collection.Name).Error("invalid cluster deployment selector")
+			continue
+		}
+		// Collections that already have a status for the cluster deployment are enqueued as well, so that the
+		// collected objects are removed when the cluster deployment is no longer selected.
+		if selector.Matches(labels.Set(cd.Labels)) || findClusterStatus(collection.Status.Clusters, cd.Namespace, cd.Name) != nil {
+			retval = append(retval, reconcile.Request{NamespacedName: types.NamespacedName{Name: collection.Name}})
+		}
+	}
+	return retval
+}
+
+var _ reconcile.Reconciler = &ReconcileClusterResourceCollection{}
+
+// ReconcileClusterResourceCollection is the reconciler for ClusterResourceCollection. It periodically collects the
+// objects of a collection from the clusters it selects, and stores them in ConfigMaps on the hub.
+type ReconcileClusterResourceCollection struct {
+	client.Client
+	scheme *runtime.Scheme
+	logger log.FieldLogger
+
+	// remoteClientBuilder is a function pointer to the function that builds a client for the
+	// remote cluster
+	remoteClientBuilder func(string, string) (client.Client, error)
+
+	// collectionTimeout is how long objects are collected from a single cluster
+	collectionTimeout time.Duration
+}
+
+// Reconcile collects the objects of a ClusterResourceCollection from the clusters that are due for collection, and
+// removes the collected objects of clusters that are no longer selected.
+func (r *ReconcileClusterResourceCollection) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	start := time.Now()
+	logger := r.logger.WithField("clusterResourceCollection", request.Name)
+
+	// For logging, we need to see when the reconciliation loop starts and ends.
+	logger.Info("reconciling cluster resource collection")
+	defer func() {
+		dur := time.Since(start)
+		hivemetrics.MetricControllerReconcileTime.WithLabelValues(controllerName).Observe(dur.Seconds())
+		logger.WithField("elapsed", dur).Info("reconcile complete")
+	}()
+
+	collection := &hivev1.ClusterResourceCollection{}
+	err := r.Get(context.TODO(), request.NamespacedName, collection)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// Object not found, return. Collected objects are garbage collected through their owner references.
+			logger.Debug("cluster resource collection not found")
+			return reconcile.Result{}, nil
+		}
+		logger.WithError(err).Error("Error getting cluster resource collection")
+		return reconcile.Result{}, err
+	}
+	if !collection.DeletionTimestamp.IsZero() {
+		logger.Debug("ClusterResourceCollection resource has been deleted")
+		return reconcile.Result{}, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&collection.Spec.ClusterDeploymentSelector)
+	if err != nil {
+		logger.WithError(err).Error("invalid cluster deployment selector")
+		return reconcile.Result{}, nil
+	}
+
+	cdList := &hivev1.ClusterDeploymentList{}
+	if err := r.List(context.TODO(), cdList, func(opts *client.ListOptions) { opts.LabelSelector = selector }); err != nil {
+		logger.WithError(err).Error("failed to list cluster deployments")
+		return reconcile.Result{}, err
+	}
+	cds := cdList.Items
+	sort.Slice(cds, func(i, j int) bool {
+		if cds[i].Namespace != cds[j].Namespace {
+			return cds[i].Namespace < cds[j].Namespace
+		}
+		return cds[i].Name < cds[j].Name
+	})
+
+	interval := defaultCollectionInterval
+	if collection.Spec.Interval != nil && collection.Spec.Interval.Duration > 0 {
+		interval = collection.Spec.Interval.Duration
+	}
+	nextCollection := interval
+
+	// Clusters due for collection get a placeholder status that is filled in once their objects were collected
+	clusterStatuses := []hivev1.ClusterCollectionStatus{}
+	dueClusters := map[int]*hivev1.ClusterDeployment{}
+	for i := range cds {
+		cd := &cds[i]
+		cdLog := logger.WithField("clusterDeployment", fmt.Sprintf("%s/%s", cd.Namespace, cd.Name))
+		if !cd.DeletionTimestamp.IsZero() || !cd.Spec.Installed || cd.Spec.ClusterMetadata == nil {
+			cdLog.Debug("skipping cluster that is not installed")
+			continue
+		}
+		existing := findClusterStatus(collection.Status.Clusters, cd.Namespace, cd.Name)
+		if controllerutils.HasUnreachableCondition(cd) {
+			// Keep the objects collected while the cluster was reachable
+			cdLog.Debug("skipping cluster with unreachable condition")
+			if existing != nil {
+				clusterStatuses = append(clusterStatuses, *existing)
+			}
+			continue
+		}
+		if existing != nil && existing.LastCollectionTime != nil {
+			if sinceLastCollection := time.Since(existing.LastCollectionTime.Time); sinceLastCollection < interval {
+				clusterStatuses = append(clusterStatuses, *existing)
+				if wait := interval - sinceLastCollection; wait < nextCollection {
+					nextCollection = wait
+				}
+				continue
+			}
+		}
+		dueClusters[len(clusterStatuses)] = cd
+		clusterStatuses = append(clusterStatuses, hivev1.ClusterCollectionStatus{})
+	}
+
+	// The results of the clusters that were collected are recorded even if storing the objects of another cluster
+	// failed
+	storeErrs := r.collectClusters(collection, dueClusters, clusterStatuses, logger)
+
+	if err := r.removeStaleConfigMaps(collection, clusterStatuses, logger); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if !reflect.DeepEqual(collection.Status.Clusters, clusterStatuses) {
+		collection.Status.Clusters = clusterStatuses
+		if err := r.Status().Update(context.TODO(), collection); err != nil {
+			logger.WithError(err).Log(controllerutils.LogLevel(err), "failed to update cluster resource collection status")
+			return reconcile.Result{}, err
+		}
+	}
+	if len(storeErrs) > 0 {
+		return reconcile.Result{}, utilerrors.NewAggregate(storeErrs)
+	}
+	return reconcile.Result{RequeueAfter: nextCollection}, nil
+}
+
+// collectClusters collects the objects of the collection from the clusters in parallel, and sets the status of each
+// cluster at its index in clusterStatuses. It returns the errors storing the collected objects.
+func (r *ReconcileClusterResourceCollection) collectClusters(collection *hivev1.ClusterResourceCollection, cds map[int]*hivev1.ClusterDeployment, clusterStatuses []hivev1.ClusterCollectionStatus, logger log.FieldLogger) []error {
+	var (
+		wg        sync.WaitGroup
+		mutex     sync.Mutex
+		storeErrs []error
+	)
+	semaphore := make(chan struct{}, maxConcurrentCollections)
+	for i, cd := range cds {
+		wg.Add(1)
+		go func(i int, cd *hivev1.ClusterDeployment) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			cdLog := logger.WithField("clusterDeployment", fmt.Sprintf("%s/%s", cd.Namespace, cd.Name))
+			clusterStatus, err := r.collect(collection, cd, cdLog)
+			mutex.Lock()
+			defer mutex.Unlock()
+			clusterStatuses[i] = clusterStatus
+			if err != nil {
+				storeErrs = append(storeErrs, err)
+			}
+		}(i, cd)
+	}
+	wg.Wait()
+	return storeErrs
+}
+
+// collectedResource is the result of listing the objects of a resource of a collection
+type collectedResource struct {
+	items []unstructured.Unstructured
+	err   error
+}
+
+// collect collects the objects of the collection from the cluster of cd. Errors collecting from the cluster are
+// reported in the returned status; the returned error is only set when the collected objects could not be stored.
+// Collection is given up once collectionTimeout expires, and the objects collected until then are stored.
+func (r *ReconcileClusterResourceCollection) collect(collection *hivev1.ClusterResourceCollection, cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) (hivev1.ClusterCollectionStatus, error) {
+	now := metav1.Now()
+	clusterStatus := hivev1.ClusterCollectionStatus{
+		ClusterDeploymentNamespace: cd.Namespace,
+		ClusterDeploymentName:      cd.Name,
+		LastCollectionTime:         &now,
+	}
+
+	// The remote cluster is queried in a goroutine that is abandoned when the timeout expires. The channels are
+	// buffered so that it does not block once abandoned.
+	clientErrs := make(chan error, 1)
+	results := make(chan collectedResource, len(collection.Spec.Resources))
+	go func() {
+		remoteClient, err := r.buildRemoteClient(cd)
+		clientErrs <- err
+		if err != nil {
+			return
+		}
+		for _, resource := range collection.Spec.Resources {
+			items, err := listRemoteObjects(remoteClient, resource)
+			results <- collectedResource{items: items, err: err}
+		}
+	}()
+	timeout := time.NewTimer(r.collectionTimeout)
+	defer timeout.Stop()
+
+	select {
+	case err := <-clientErrs:
+		if err != nil {
+			cdLog.WithError(err).Error("error building remote cluster client connection")
+			clusterStatus.Error = err.Error()
+			return clusterStatus, nil
+		}
+	case <-timeout.C:
+		cdLog.Warn(collectionTimedOutMessage)
+		clusterStatus.Error = collectionTimedOutMessage
+		return clusterStatus, nil
+	}
+
+	data := map[string]string{}
+	dataSize := 0
+	timedOut := false
+	for _, resource := range collection.Spec.Resources {
+		resourceStatus := hivev1.CollectedResourceStatus{
+			APIVersion: resource.APIVersion,
+			Kind:       resource.Kind,
+		}
+		var result collectedResource
+		if !timedOut {
+			select {
+			case result = <-results:
+			case <-timeout.C:
+				cdLog.Warn(collectionTimedOutMessage)
+				timedOut = true
+			}
+		}
+		if timedOut {
+			resourceStatus.Error = collectionTimedOutMessage
+			clusterStatus.Resources = append(clusterStatus.Resources, resourceStatus)
+			continue
+		}
+		if result.err != nil {
+			cdLog.WithError(result.err).WithField("kind", resource.Kind).Warn("failed to collect remote objects")
+			resourceStatus.Error = result.err.Error()
+			clusterStatus.Resources = append(clusterStatus.Resources, resourceStatus)
+			continue
+		}
+		resourceStatus.Count = len(result.items)
+		if !collection.Spec.CountOnly {
+			content, err := json.Marshal(result.items)
+			if err != nil {
+				return clusterStatus, err
+			}
+			if dataSize+len(content) > maxConfigMapDataSize {
+				resourceStatus.Error = "collected objects are too large to be stored, only their count is recorded"
+			} else {
+				data[dataKey(data, resource)] = string(content)
+				dataSize += len(content)
+			}
+		}
+		clusterStatus.Resources = append(clusterStatus.Resources, resourceStatus)
+	}
+
+	if collection.Spec.CountOnly {
+		return clusterStatus, nil
+	}
+	clusterStatus.ConfigMapName = configMapName(collection, cd)
+	if err := r.storeCollectedObjects(collection, cd, clusterStatus.ConfigMapName, data, cdLog); err != nil {
+		clusterStatus.Error = fmt.Sprintf("failed to store collected objects: %v", err)
+		return clusterStatus, err
+	}
+	return clusterStatus, nil
+}
+
+func (r *ReconcileClusterResourceCollection) buildRemoteClient(cd *hivev1.ClusterDeployment) (client.Client, error) {
+	kubeconfigSecret := &corev1.Secret{}
+	err := r.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: cd.Spec.ClusterMetadata.AdminKubeconfigSecretRef.Name}, kubeconfigSecret)
+	if err != nil {
+		return nil, fmt.Errorf("could not get cluster's admin kubeconfig: %v", err)
+	}
+	kubeconfig, err := controllerutils.FixupKubeconfigSecretData(kubeconfigSecret.Data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot fixup kubeconfig for remote cluster: %v", err)
+	}
+	return r.remoteClientBuilder(string(kubeconfig), controllerName)
+}
+
+// listRemoteObjects lists the remote objects of resource. Fields of the objects that are only of interest to the
+// API server of the remote cluster are removed.
+func listRemoteObjects(remoteClient client.Client, resource hivev1.CollectedResource) ([]unstructured.Unstructured, error) {
+	opts := []client.ListOptionFunc{client.InNamespace(resource.Namespace)}
+	if resource.LabelSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(resource.LabelSelector)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, func(o *client.ListOptions) { o.LabelSelector = selector })
+	}
+	list := &unstructured.UnstructuredList{}
+	list.SetAPIVersion(resource.APIVersion)
+	list.SetKind(resource.Kind + "List")
+	if err := remoteClient.List(context.TODO(), list, opts...); err != nil {
+		return nil, err
+	}
+	for i := range list.Items {
+		unstructured.RemoveNestedField(list.Items[i].Object, "metadata", "managedFields")
+		unstructured.RemoveNestedField(list.Items[i].Object, "metadata", "annotations", "kubectl.kubernetes.io/last-applied-configuration")
+	}
+	return list.Items, nil
+}
+
+// dataKey returns the ConfigMap data key the objects of resource are stored under, e.g. "Subscription.v1alpha1.operators.coreos.com".
+// A numeric suffix is added when several resources of a collection have the same kind.
+func dataKey(data map[string]string, resource hivev1.CollectedResource) string {
+	parts := []string{resource.Kind}
+	if gv, err := schema.ParseGroupVersion(resource.APIVersion); err == nil {
+		parts = append(parts, gv.Version)
+		if gv.Group != "" {
+			parts = append(parts, gv.Group)
+		}
+	}
+	key := strings.Join(parts, ".")
+	if _, ok := data[key]; !ok {
+		return key
+	}
+	for i := 1; ; i++ {
+		if _, ok := data[fmt.Sprintf("%s.%d", key, i)]; !ok {
+			return fmt.Sprintf("%s.%d", key, i)
+		}
+	}
+}
+
+// configMapName returns the name of the ConfigMap that stores the objects collected from the cluster of cd
+func configMapName(collection *hivev1.ClusterResourceCollection, cd *hivev1.ClusterDeployment) string {
+	return apihelpers.GetResourceName(cd.Name, collection.Name)
+}
+
+// storeCollectedObjects creates or updates the ConfigMap that stores the objects collected from the cluster of cd
+func (r *ReconcileClusterResourceCollection) storeCollectedObjects(collection *hivev1.ClusterResourceCollection, cd *hivev1.ClusterDeployment, name string, data map[string]string, cdLog log.FieldLogger) error {
+	cm := &corev1.ConfigMap{}
+	err := r.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: name}, cm)
+	isNew := apierrors.IsNotFound(err)
+	if err != nil && !isNew {
+		cdLog.WithError(err).Error("error getting collected objects configmap")
+		return err
+	}
+	cm.Name = name
+	cm.Namespace = cd.Namespace
+	if cm.Labels == nil {
+		cm.Labels = map[string]string{}
+	}
+	cm.Labels[constants.ClusterResourceCollectionNameLabel] = collection.Name
+	cm.Labels[constants.ClusterDeploymentNameLabel] = cd.Name
+	cm.Data = data
+	if err := controllerutil.SetControllerReference(collection, cm, r.scheme); err != nil {
+		cdLog.WithError(err).Error("error setting controller reference on collected objects configmap")
+		return err
+	}
+	if isNew {
+		cdLog.WithField("configMap", name).Info("creating collected objects configmap")
+		err = r.Create(context.TODO(), cm)
+	} else {
+		cdLog.WithField("configMap", name).Debug("updating collected objects configmap")
+		err = r.Update(context.TODO(), cm)
+	}
+	if err != nil {
+		cdLog.WithError(err).Log(controllerutils.LogLevel(err), "failed to store collected objects")
+	}
+	return err
+}
+
+// removeStaleConfigMaps deletes the ConfigMaps of the collection that do not belong to a cluster in clusterStatuses
+func (r *ReconcileClusterResourceCollection) removeStaleConfigMaps(collection *hivev1.ClusterResourceCollection, clusterStatuses []hivev1.ClusterCollectionStatus, logger log.FieldLogger) error {
+	configMaps := &corev1.ConfigMapList{}
+	err := r.List(context.TODO(), configMaps, client.MatchingLabels(map[string]string{constants.ClusterResourceCollectionNameLabel: collection.Name}))
+	if err != nil {
+		logger.WithError(err).Error("failed to list collected objects configmaps")
+		return err
+	}
+	for i, cm := range configMaps.Items {
+		if !metav1.IsControlledBy(&cm, collection) {
+			continue
+		}
+		if clusterStatus := findClusterStatus(clusterStatuses, cm.Namespace, cm.Labels[constants.ClusterDeploymentNameLabel]); clusterStatus != nil && clusterStatus.ConfigMapName == cm.Name {
+			continue
+		}
+		logger.WithField("configMap", fmt.Sprintf("%s/%s", cm.Namespace, cm.Name)).Info("deleting collected objects of cluster that is no longer selected")
+		if err := r.Delete(context.TODO(), &configMaps.Items[i]); err != nil && !apierrors.IsNotFound(err) {
+			logger.WithError(err).Log(controllerutils.LogLevel(err), "failed to delete collected objects configmap")
+			return err
+		}
+	}
+	return nil
+}
+
+func findClusterStatus(clusterStatuses []hivev1.ClusterCollectionStatus, namespace, name string) *hivev1.ClusterCollectionStatus {
+	for i, clusterStatus := range clusterStatuses {
+		if clusterStatus.ClusterDeploymentNamespace == namespace && clusterStatus.ClusterDeploymentName == name {
+			return &clusterStatuses[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,465 @@
+package clusterresourcecollection
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+)
+
+const (
+	testName                 = "inventory"
+	testCDName               = "cluster1"
+	testNamespace            = "cluster1namespace"
+	testKubeconfigSecretName = "kubeconfig-secret"
+)
+
+func TestClusterResourceCollectionReconcile(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	log.SetLevel(log.DebugLevel)
+
+	recent := metav1.NewTime(time.Now().Add(-10 * time.Minute))
+	old := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+
+	tests := []struct {
+		name      string
+		existing  []runtime.Object
+		remote    []runtime.Object
+		remoteErr error
+		// blockKind is a remote kind whose list does not return before the collection times out
+		blockKind string
+		timeout   time.Duration
+		validate  func(*testing.T, client.Client, reconcile.Result)
+	}{
+		{
+			name: "collect objects",
+			existing: []runtime.Object{
+				testCollection(),
+				testClusterDeployment(),
+				testKubeconfigSecret(),
+			},
+			remote: []runtime.Object{
+				testRemoteConfigMap("ns1", "a", "collect"),
+				testRemoteConfigMap("ns2", "b", "collect"),
+				testRemoteConfigMap("ns2", "c", "ignore"),
+			},
+			validate: func(t *testing.T, c client.Client, result reconcile.Result) {
+				collection := getCollection(t, c)
+				require.Len(t, collection.Status.Clusters, 1, "expected status for one cluster")
+				clusterStatus := collection.Status.Clusters[0]
+				assert.Equal(t, testNamespace, clusterStatus.ClusterDeploymentNamespace, "unexpected cluster deployment namespace")
+				assert.Equal(t, testCDName, clusterStatus.ClusterDeploymentName, "unexpected cluster deployment name")
+				assert.NotNil(t, clusterStatus.LastCollectionTime, "expected last collection time")
+				assert.Empty(t, clusterStatus.Error, "unexpected cluster error")
+				require.Len(t, clusterStatus.Resources, 1, "expected status for one resource")
+				assert.Empty(t, clusterStatus.Resources[0].Error, "unexpected resource error")
+				assert.Equal(t, 2, clusterStatus.Resources[0].Count, "unexpected number of collected objects")
+
+				cm := getConfigMap(t, c, clusterStatus.ConfigMapName)
+				require.NotNil(t, cm, "expected collected objects configmap")
+				assert.Equal(t, testName, cm.Labels[constants.ClusterResourceCollectionNameLabel], "unexpected collection label")
+				assert.Equal(t, testCDName, cm.Labels[constants.ClusterDeploymentNameLabel], "unexpected cluster deployment label")
+				items := []map[string]interface{}{}
+				require.NoError(t, json.Unmarshal([]byte(cm.Data["ConfigMap.v1"]), &items), "unexpected error unmarshalling collected objects")
+				assert.Len(t, items, 2, "unexpected number of stored objects")
+				assert.Equal(t, defaultCollectionInterval, result.RequeueAfter, "unexpected requeue")
+			},
+		},
+		{
+			name: "count only",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterResourceCollection {
+					collection := testCollection()
+					collection.Spec.CountOnly = true
+					return collection
+				}(),
+				testClusterDeployment(),
+				testKubeconfigSecret(),
+				testCollectedConfigMap(),
+			},
+			remote: []runtime.Object{
+				testRemoteConfigMap("ns1", "a", "collect"),
+			},
+			validate: func(t *testing.T, c client.Client, result reconcile.Result) {
+				collection := getCollection(t, c)
+				require.Len(t, collection.Status.Clusters, 1, "expected status for one cluster")
+				assert.Empty(t, collection.Status.Clusters[0].ConfigMapName, "unexpected configmap name")
+				assert.Equal(t, 1, collection.Status.Clusters[0].Resources[0].Count, "unexpected number of collected objects")
+				assert.Nil(t, getConfigMap(t, c, testConfigMapName()), "expected configmap to be deleted")
+			},
+		},
+		{
+			name: "not due for collection",
+			existing: []runtime.Object{
+				testCollectionWithStatus(recent, 5),
+				testClusterDeployment(),
+				testKubeconfigSecret(),
+				testCollectedConfigMap(),
+			},
+			remote: []runtime.Object{
+				testRemoteConfigMap("ns1", "a", "collect"),
+			},
+			validate: func(t *testing.T, c client.Client, result reconcile.Result) {
+				collection := getCollection(t, c)
+				require.Len(t, collection.Status.Clusters, 1, "expected status for one cluster")
+				assert.Equal(t, 5, collection.Status.Clusters[0].Resources[0].Count, "expected previous collection to be kept")
+				assert.NotNil(t, getConfigMap(t, c, testConfigMapName()), "expected configmap to be kept")
+				assert.True(t, result.RequeueAfter > 0 && result.RequeueAfter <= 50*time.Minute, "unexpected requeue %v", result.RequeueAfter)
+			},
+		},
+		{
+			name: "due for collection",
+			existing: []runtime.Object{
+				testCollectionWithStatus(old, 5),
+				testClusterDeployment(),
+				testKubeconfigSecret(),
+				testCollectedConfigMap(),
+			},
+			remote: []runtime.Object{
+				testRemoteConfigMap("ns1", "a", "collect"),
+			},
+			validate: func(t *testing.T, c client.Client, result reconcile.Result) {
+				collection := getCollection(t, c)
+				require.Len(t, collection.Status.Clusters, 1, "expected status for one cluster")
+				assert.Equal(t, 1, collection.Status.Clusters[0].Resources[0].Count, "expected objects to be collected again")
+				assert.True(t, collection.Status.Clusters[0].LastCollectionTime.After(old.Time), "expected last collection time to be updated")
+			},
+		},
+		{
+			name: "unreachable cluster",
+			existing: []runtime.Object{
+				testCollectionWithStatus(old, 5),
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Status.Conditions = []hivev1.ClusterDeploymentCondition{{
+						Type:   hivev1.UnreachableCondition,
+						Status: corev1.ConditionTrue,
+					}}
+					return cd
+				}(),
+				testKubeconfigSecret(),
+				testCollectedConfigMap(),
+			},
+			remoteErr: fmt.Errorf("should not connect to unreachable cluster"),
+			validate: func(t *testing.T, c client.Client, result reconcile.Result) {
+				collection := getCollection(t, c)
+				require.Len(t, collection.Status.Clusters, 1, "expected status for one cluster")
+				assert.Equal(t, 5, collection.Status.Clusters[0].Resources[0].Count, "expected previous collection to be kept")
+				assert.NotNil(t, getConfigMap(t, c, testConfigMapName()), "expected configmap to be kept")
+			},
+		},
+		{
+			name: "cluster no longer selected",
+			existing: []runtime.Object{
+				testCollectionWithStatus(recent, 5),
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Labels = nil
+					return cd
+				}(),
+				testKubeconfigSecret(),
+				testCollectedConfigMap(),
+			},
+			validate: func(t *testing.T, c client.Client, result reconcile.Result) {
+				collection := getCollection(t, c)
+				assert.Empty(t, collection.Status.Clusters, "expected cluster to be removed from status")
+				assert.Nil(t, getConfigMap(t, c, testConfigMapName()), "expected configmap to be deleted")
+			},
+		},
+		{
+			name: "remote connection error",
+			existing: []runtime.Object{
+				testCollection(),
+				testClusterDeployment(),
+				testKubeconfigSecret(),
+			},
+			remoteErr: fmt.Errorf("connection refused"),
+			validate: func(t *testing.T, c client.Client, result reconcile.Result) {
+				collection := getCollection(t, c)
+				require.Len(t, collection.Status.Clusters, 1, "expected status for one cluster")
+				assert.Equal(t, "connection refused", collection.Status.Clusters[0].Error, "unexpected cluster error")
+				assert.Nil(t, getConfigMap(t, c, testConfigMapName()), "unexpected configmap")
+			},
+		},
+		{
+			name: "collection times out",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterResourceCollection {
+					collection := testCollection()
+					collection.Spec.Resources = append(collection.Spec.Resources, hivev1.CollectedResource{
+						APIVersion: "v1",
+						Kind:       "Secret",
+					})
+					return collection
+				}(),
+				testClusterDeployment(),
+				testKubeconfigSecret(),
+			},
+			remote: []runtime.Object{
+				testRemoteConfigMap("ns1", "a", "collect"),
+			},
+			blockKind: "Secret",
+			timeout:   100 * time.Millisecond,
+			validate: func(t *testing.T, c client.Client, result reconcile.Result) {
+				collection := getCollection(t, c)
+				require.Len(t, collection.Status.Clusters, 1, "expected status for one cluster")
+				clusterStatus := collection.Status.Clusters[0]
+				require.Len(t, clusterStatus.Resources, 2, "expected status for both resources")
+				assert.Equal(t, 1, clusterStatus.Resources[0].Count, "expected objects collected before the timeout")
+				assert.Equal(t, collectionTimedOutMessage, clusterStatus.Resources[1].Error, "expected timeout error")
+				cm := getConfigMap(t, c, clusterStatus.ConfigMapName)
+				require.NotNil(t, cm, "expected objects collected before the timeout to be stored")
+				assert.Contains(t, cm.Data, "ConfigMap.v1", "expected collected configmaps")
+			},
+		},
+		{
+			name: "clusters collected in parallel",
+			existing: []runtime.Object{
+				testCollection(),
+				testClusterDeployment(),
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Name = "cluster2"
+					return cd
+				}(),
+				testKubeconfigSecret(),
+			},
+			remote: []runtime.Object{
+				testRemoteConfigMap("ns1", "a", "collect"),
+			},
+			validate: func(t *testing.T, c client.Client, result reconcile.Result) {
+				collection := getCollection(t, c)
+				require.Len(t, collection.Status.Clusters, 2, "expected status for both clusters")
+				assert.Equal(t, testCDName, collection.Status.Clusters[0].ClusterDeploymentName, "expected cluster statuses in order")
+				assert.Equal(t, "cluster2", collection.Status.Clusters[1].ClusterDeploymentName, "expected cluster statuses in order")
+				for _, clusterStatus := range collection.Status.Clusters {
+					assert.Equal(t, 1, clusterStatus.Resources[0].Count, "unexpected number of collected objects")
+					assert.NotNil(t, getConfigMap(t, c, clusterStatus.ConfigMapName), "expected collected objects configmap")
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			unblock := make(chan struct{})
+			defer close(unblock)
+			timeout := test.timeout
+			if timeout == 0 {
+				timeout = time.Minute
+			}
+			fakeClient := fake.NewFakeClient(test.existing...)
+			r := &ReconcileClusterResourceCollection{
+				Client: fakeClient,
+				scheme: scheme.Scheme,
+				logger: log.WithField("controller", controllerName),
+				remoteClientBuilder: func(secret string, controllerName string) (client.Client, error) {
+					if test.remoteErr != nil {
+						return nil, test.remoteErr
+					}
+					return &unstructuredListClient{
+						Client:    fake.NewFakeClient(test.remote...),
+						blockKind: test.blockKind,
+						unblock:   unblock,
+					}, nil
+				},
+				collectionTimeout: timeout,
+			}
+
+			result, err := r.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: testName},
+			})
+			require.NoError(t, err, "unexpected error from reconcile")
+			if test.validate != nil {
+				test.validate(t, fakeClient, result)
+			}
+		})
+	}
+}
+
+// unstructuredListClient lists unstructured objects through the typed objects of the fake client, which does not
+// support unstructured lists. Lists of blockKind do not return until unblock is closed.
+type unstructuredListClient struct {
+	client.Client
+	blockKind string
+	unblock   chan struct{}
+}
+
+func (c *unstructuredListClient) List(ctx context.Context, obj runtime.Object, opts ...client.ListOptionFunc) error {
+	list, ok := obj.(*unstructured.UnstructuredList)
+	if !ok {
+		return c.Client.List(ctx, obj, opts...)
+	}
+	if c.blockKind != "" && list.GetKind() == c.blockKind+"List" {
+		<-c.unblock
+		return fmt.Errorf("list of %s was unblocked", c.blockKind)
+	}
+	typedList, err := scheme.Scheme.New(list.GroupVersionKind())
+	if err != nil {
+		return err
+	}
+	if err := c.Client.List(ctx, typedList, opts...); err != nil {
+		return err
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(typedList)
+	if err != nil {
+		return err
+	}
+	list.Items = nil
+	items, _ := content["items"].([]interface{})
+	for _, item := range items {
+		list.Items = append(list.Items, unstructured.Unstructured{Object: item.(map[string]interface{})})
+	}
+	return nil
+}
+
+func getCollection(t *testing.T, c client.Client) *hivev1.ClusterResourceCollection {
+	collection := &hivev1.ClusterResourceCollection{}
+	err := c.Get(context.TODO(), types.NamespacedName{Name: testName}, collection)
+	require.NoError(t, err, "unexpected error getting cluster resource collection")
+	return collection
+}
+
+func getConfigMap(t *testing.T, c client.Client, name string) *corev1.ConfigMap {
+	cm := &corev1.ConfigMap{}
+	err := c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: name}, cm)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	require.NoError(t, err, "unexpected error getting configmap")
+	return cm
+}
+
+func testCollection() *hivev1.ClusterResourceCollection {
+	return &hivev1.ClusterResourceCollection{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: testName,
+			UID:  types.UID("1234"),
+		},
+		Spec: hivev1.ClusterResourceCollectionSpec{
+			ClusterDeploymentSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{"inventory": "true"},
+			},
+			Resources: []hivev1.CollectedResource{
+				{
+					APIVersion: "v1",
+					Kind:       "ConfigMap",
+					LabelSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"inventory": "collect"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func testCollectionWithStatus(lastCollectionTime metav1.Time, count int) *hivev1.ClusterResourceCollection {
+	collection := testCollection()
+	collection.Status.Clusters = []hivev1.ClusterCollectionStatus{
+		{
+			ClusterDeploymentNamespace: testNamespace,
+			ClusterDeploymentName:      testCDName,
+			LastCollectionTime:         &lastCollectionTime,
+			ConfigMapName:              testConfigMapName(),
+			Resources: []hivev1.CollectedResourceStatus{
+				{
+					APIVersion: "v1",
+					Kind:       "ConfigMap",
+					Count:      count,
+				},
+			},
+		},
+	}
+	return collection
+}
+
+func testConfigMapName() string {
+	return testCDName + "-" + testName
+}
+
+func testCollectedConfigMap() *corev1.ConfigMap {
+	controller := true
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNamespace,
+			Name:      testConfigMapName(),
+			Labels: map[string]string{
+				constants.ClusterResourceCollectionNameLabel: testName,
+				constants.ClusterDeploymentNameLabel:         testCDName,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: hivev1.SchemeGroupVersion.String(),
+					Kind:       "ClusterResourceCollection",
+					Name:       testName,
+					UID:        types.UID("1234"),
+					Controller: &controller,
+				},
+			},
+		},
+		Data: map[string]string{"ConfigMap.v1": "[]"},
+	}
+}
+
+func testClusterDeployment() *hivev1.ClusterDeployment {
+	return &hivev1.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNamespace,
+			Name:      testCDName,
+			Labels:    map[string]string{"inventory": "true"},
+		},
+		Spec: hivev1.ClusterDeploymentSpec{
+			Installed: true,
+			ClusterMetadata: &hivev1.ClusterMetadata{
+				AdminKubeconfigSecretRef: corev1.LocalObjectReference{
+					Name: testKubeconfigSecretName,
+				},
+			},
+		},
+	}
+}
+
+func testKubeconfigSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNamespace,
+			Name:      testKubeconfigSecretName,
+		},
+		Data: map[string][]byte{
+			"kubeconfig": []byte("foo"),
+		},
+	}
+}
+
+func testRemoteConfigMap(namespace, name, inventory string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+			Labels:    map[string]string{"inventory": inventory},
+		},
+		Data: map[string]string{"key": "value"},
+	}
+}
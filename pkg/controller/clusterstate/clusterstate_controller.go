@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -32,8 +33,17 @@ import (
 const (
 	controllerName       = "clusterState"
 	statusUpdateInterval = 10 * time.Minute
+
+	// remoteResyncInterval is how often the cluster state is updated from the watched cluster operators of a cluster
+	// in case changes were missed
+	remoteResyncInterval = 30 * time.Minute
+
+	// resourceSummaryInterval is how often the node, machine and capacity summaries of a cluster are updated
+	resourceSummaryInterval = 30 * time.Minute
 )
 
+var clusterOperatorResource = configv1.SchemeGroupVersion.WithResource("clusteroperators")
+
 // Add creates a new ClusterState controller and adds it to the manager with default RBAC.
 func Add(mgr manager.Manager) error {
 	return AddToManager(mgr, NewReconciler(mgr))
@@ -46,7 +56,8 @@ func NewReconciler(mgr manager.Manager) reconcile.Reconciler {
 		scheme:              mgr.GetScheme(),
 		logger:              log.WithField("controller", controllerName),
 		updateStatus:        updateClusterStateStatus,
-		remoteClientBuilder: controllerutils.BuildClusterAPIClientFromKubeconfig,
+		remoteClientBuilder: controllerutils.RemoteClusters.ClusterAPIClient,
+		remoteWatcher:       controllerutils.NewRemoteWatcher(controllerName, clusterOperatorResource, remoteResyncInterval),
 	}
 }
 
@@ -64,6 +75,15 @@ func AddToManager(mgr manager.Manager, r reconcile.Reconciler) error {
 		log.WithField("controller", controllerName).WithError(err).Error("Error watching cluster deployment")
 		return err
 	}
+
+	// Watch for changes to the cluster operators of installed clusters
+	if reconciler, ok := r.(*ReconcileClusterState); ok && reconciler.remoteWatcher != nil {
+		err = c.Watch(reconciler.remoteWatcher.Source(), &handler.EnqueueRequestForObject{})
+		if err != nil {
+			log.WithField("controller", controllerName).WithError(err).Error("Error watching remote cluster operators")
+			return err
+		}
+	}
 	return nil
 }
 
@@ -80,6 +100,9 @@ type ReconcileClusterState struct {
 
 	// updateStatus updates a given cluster state's status, exposed for testing
 	updateStatus func(client.Client, *hivev1.ClusterState) error
+
+	// remoteWatcher watches the cluster operators of installed clusters. Cluster operators are polled if it is nil.
+	remoteWatcher controllerutils.RemoteWatcher
 }
 
 // Reconcile ensures that a given ClusterState resource exists and reflects the state of cluster operators from its target cluster
@@ -106,6 +129,7 @@ func (r *ReconcileClusterState) Reconcile(request reconcile.Request) (reconcile.
 			// Object not found, return.  Created objects are automatically garbage collected.
 			// For additional cleanup logic use finalizers.
 			logger.Debug("cluster deployment not found")
+			r.stopWatching(request.NamespacedName)
 			return reconcile.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
@@ -114,6 +138,7 @@ func (r *ReconcileClusterState) Reconcile(request reconcile.Request) (reconcile.
 	}
 	if !cd.DeletionTimestamp.IsZero() {
 		logger.Debug("ClusterDeployment resource has been deleted")
+		r.stopWatching(request.NamespacedName)
 		return reconcile.Result{}, nil
 	}
 	if !cd.Spec.Installed {
@@ -129,6 +154,7 @@ func (r *ReconcileClusterState) Reconcile(request reconcile.Request) (reconcile.
 	// If the cluster is unreachable, do not reconcile.
 	if controllerutils.HasUnreachableCondition(cd) {
 		logger.Debug("skipping cluster with unreachable condition")
+		r.stopWatching(request.NamespacedName)
 		return reconcile.Result{}, nil
 	}
 
@@ -160,14 +186,6 @@ func (r *ReconcileClusterState) Reconcile(request reconcile.Request) (reconcile.
 		logger.Info("Waiting 60 seconds for cluster state to finish deleting")
 		return reconcile.Result{RequeueAfter: 60 * time.Second}, nil
 	}
-	if st.Status.LastUpdated != nil {
-		timeSinceLastUpdate := time.Since(st.Status.LastUpdated.Time)
-		if timeSinceLastUpdate < statusUpdateInterval {
-			nextUpdateWait := statusUpdateInterval - timeSinceLastUpdate
-			logger.Debugf("Waiting to fetch clusteroperator status in %v", nextUpdateWait)
-			return reconcile.Result{RequeueAfter: nextUpdateWait}, nil
-		}
-	}
 	kubeconfigSecret := &corev1.Secret{}
 	err = r.Get(context.Background(), types.NamespacedName{Namespace: cd.Namespace, Name: cd.Spec.ClusterMetadata.AdminKubeconfigSecretRef.Name}, kubeconfigSecret)
 	if err != nil {
@@ -179,6 +197,28 @@ func (r *ReconcileClusterState) Reconcile(request reconcile.Request) (reconcile.
 		logger.WithError(err).Error("cannot fixup kubeconfig for remote cluster")
 		return reconcile.Result{}, err
 	}
+	summariesUpdated := r.syncResourceSummaries(string(kubeconfig), st, logger)
+	if r.remoteWatcher != nil {
+		if err := r.remoteWatcher.Watch(cd, string(kubeconfig)); err != nil {
+			logger.WithError(err).Warn("unable to watch remote cluster operators, polling instead")
+		} else {
+			return r.syncWatchedOperatorStates(request.NamespacedName, st, summariesUpdated, logger)
+		}
+	}
+	if st.Status.LastUpdated != nil {
+		timeSinceLastUpdate := time.Since(st.Status.LastUpdated.Time)
+		if timeSinceLastUpdate < statusUpdateInterval {
+			if summariesUpdated {
+				if err := r.updateStatus(r, st); err != nil {
+					logger.WithError(err).Log(controllerutils.LogLevel(err), "failed to update cluster resource summaries")
+					return reconcile.Result{}, err
+				}
+			}
+			nextUpdateWait := statusUpdateInterval - timeSinceLastUpdate
+			logger.Debugf("Waiting to fetch clusteroperator status in %v", nextUpdateWait)
+			return reconcile.Result{RequeueAfter: nextUpdateWait}, nil
+		}
+	}
 	remoteClient, err := r.remoteClientBuilder(string(kubeconfig), controllerName)
 	if err != nil {
 		logger.WithError(err).Error("error building remote cluster client connection")
@@ -190,10 +230,78 @@ func (r *ReconcileClusterState) Reconcile(request reconcile.Request) (reconcile.
 		logger.WithError(err).Error("failed to list target cluster operators")
 		return reconcile.Result{}, err
 	}
-	return r.syncOperatorStates(clusterOperators.Items, st, logger)
+	return r.syncOperatorStates(clusterOperators.Items, st, summariesUpdated, logger)
 }
 
-func (r *ReconcileClusterState) syncOperatorStates(operators []configv1.ClusterOperator, st *hivev1.ClusterState, logger log.FieldLogger) (reconcile.Result, error) {
+// syncResourceSummaries updates the node, machine and capacity summaries in the cluster state if they are due, and
+// returns whether they were updated. The status of the cluster state is not updated.
+func (r *ReconcileClusterState) syncResourceSummaries(kubeconfig string, st *hivev1.ClusterState, logger log.FieldLogger) bool {
+	if st.Status.ResourcesLastUpdated != nil && time.Since(st.Status.ResourcesLastUpdated.Time) < resourceSummaryInterval {
+		return false
+	}
+	remoteClient, err := r.remoteClientBuilder(kubeconfig, controllerName)
+	if err != nil {
+		logger.WithError(err).Warn("error building remote cluster client connection to gather resource summaries")
+		return false
+	}
+	updateResourceSummaries(remoteClient, st, logger)
+	now := metav1.Now()
+	st.Status.ResourcesLastUpdated = &now
+	return true
+}
+
+// nextResourceSummaryWait returns how long until the resource summaries of the cluster state are due. Summaries that
+// are overdue because they could not be gathered are retried after the status update interval.
+func nextResourceSummaryWait(st *hivev1.ClusterState) time.Duration {
+	if st.Status.ResourcesLastUpdated != nil {
+		if wait := resourceSummaryInterval - time.Since(st.Status.ResourcesLastUpdated.Time); wait > 0 {
+			return wait
+		}
+	}
+	return statusUpdateInterval
+}
+
+// syncWatchedOperatorStates updates the cluster state from the watched cluster operators of the cluster. The cluster
+// state is updated as soon as the cluster operators change, so the status update interval does not apply.
+func (r *ReconcileClusterState) syncWatchedOperatorStates(cd types.NamespacedName, st *hivev1.ClusterState, summariesUpdated bool, logger log.FieldLogger) (reconcile.Result, error) {
+	objects, synced := r.remoteWatcher.List(cd)
+	if !synced {
+		if summariesUpdated {
+			if err := r.updateStatus(r, st); err != nil {
+				logger.WithError(err).Log(controllerutils.LogLevel(err), "failed to update cluster resource summaries")
+				return reconcile.Result{}, err
+			}
+		}
+		// The cluster deployment is reconciled again once the cluster operators have been listed
+		logger.Debug("waiting for remote cluster operators to be listed")
+		return reconcile.Result{RequeueAfter: statusUpdateInterval}, nil
+	}
+	operators := make([]configv1.ClusterOperator, len(objects))
+	for i, obj := range objects {
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &operators[i]); err != nil {
+			logger.WithError(err).WithField("clusterOperator", obj.GetName()).Error("failed to convert remote cluster operator")
+			return reconcile.Result{}, err
+		}
+	}
+	sort.Slice(operators, func(i, j int) bool { return operators[i].Name < operators[j].Name })
+	if _, err := r.syncOperatorStates(operators, st, summariesUpdated, logger); err != nil {
+		return reconcile.Result{}, err
+	}
+	requeueAfter := remoteResyncInterval
+	if wait := nextResourceSummaryWait(st); wait < requeueAfter {
+		requeueAfter = wait
+	}
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// stopWatching stops watching the cluster operators of the cluster deployment
+func (r *ReconcileClusterState) stopWatching(cd types.NamespacedName) {
+	if r.remoteWatcher != nil {
+		r.remoteWatcher.Stop(cd)
+	}
+}
+
+func (r *ReconcileClusterState) syncOperatorStates(operators []configv1.ClusterOperator, st *hivev1.ClusterState, summariesUpdated bool, logger log.FieldLogger) (reconcile.Result, error) {
 	operatorStates := make([]hivev1.ClusterOperatorState, len(operators))
 	for i, clusterOperator := range operators {
 		operatorStates[i] = hivev1.ClusterOperatorState{
@@ -201,10 +309,12 @@ func (r *ReconcileClusterState) syncOperatorStates(operators []configv1.ClusterO
 			Conditions: clusterOperator.Status.Conditions,
 		}
 	}
-	if operatorStatesChanged(logger, st.Status.ClusterOperators, operatorStates) {
-		st.Status.ClusterOperators = operatorStates
-		now := metav1.Now()
-		st.Status.LastUpdated = &now
+	if operatorsChanged := operatorStatesChanged(logger, st.Status.ClusterOperators, operatorStates); operatorsChanged || summariesUpdated {
+		if operatorsChanged {
+			st.Status.ClusterOperators = operatorStates
+			now := metav1.Now()
+			st.Status.LastUpdated = &now
+		}
 		if err := r.updateStatus(r, st); err != nil {
 			logger.WithError(err).Log(controllerutils.LogLevel(err), "failed to update cluster operator state")
 			return reconcile.Result{}, err
@@ -2,14 +2,17 @@ package clusterstate
 
 import (
 	"context"
+	"fmt"
 	"sort"
 	"testing"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -20,8 +23,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	configv1 "github.com/openshift/api/config/v1"
+	machineapi "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
 	"github.com/openshift/hive/pkg/apis"
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/test/remotewatcher"
 )
 
 const (
@@ -33,6 +38,7 @@ const (
 func TestClusterStateReconcile(t *testing.T) {
 	apis.AddToScheme(scheme.Scheme)
 	configv1.Install(scheme.Scheme)
+	machineapi.AddToScheme(scheme.Scheme)
 
 	log.SetLevel(log.DebugLevel)
 
@@ -54,6 +60,7 @@ func TestClusterStateReconcile(t *testing.T) {
 		name     string
 		existing []runtime.Object
 		remote   []runtime.Object
+		watcher  *remotewatcher.Fake
 		validate func(*testing.T, client.Client, reconcile.Result)
 		noUpdate bool
 	}{
@@ -147,6 +154,117 @@ func TestClusterStateReconcile(t *testing.T) {
 				validateStatus(t, st.Status, co("a"), removeCond(co("b")))
 			},
 		},
+		{
+			name: "watched cluster operators",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterState {
+					st := testClusterStateWithStatus(co("a"), co("b"))
+					now := metav1.Now()
+					st.Status.LastUpdated = &now
+					return st
+				}(),
+				testClusterDeployment(),
+				testKubeconfigSecret(),
+			},
+			watcher: remotewatcher.New(co("a"), uco("b"), co("c")),
+			validate: func(t *testing.T, c client.Client, result reconcile.Result) {
+				st := cs(t, c)
+				validateStatus(t, st.Status, co("a"), uco("b"), co("c"))
+				assert.InDelta(t, float64(remoteResyncInterval), float64(result.RequeueAfter), float64(time.Minute), "unexpected requeue")
+			},
+		},
+		{
+			name: "watched cluster operators not listed yet",
+			existing: []runtime.Object{
+				testClusterStateWithStatus(co("a")),
+				testClusterDeployment(),
+				testKubeconfigSecret(),
+			},
+			watcher: &remotewatcher.Fake{},
+			validate: func(t *testing.T, c client.Client, result reconcile.Result) {
+				st := cs(t, c)
+				validateStatus(t, st.Status, co("a"))
+			},
+			noUpdate: true,
+		},
+		{
+			name: "watch error falls back to polling",
+			existing: []runtime.Object{
+				testClusterState(),
+				testClusterDeployment(),
+				testKubeconfigSecret(),
+			},
+			remote:  []runtime.Object{co("a"), co("b")},
+			watcher: &remotewatcher.Fake{WatchErr: fmt.Errorf("cannot watch")},
+			validate: func(t *testing.T, c client.Client, result reconcile.Result) {
+				st := cs(t, c)
+				validateStatus(t, st.Status, co("a"), co("b"))
+			},
+		},
+		{
+			name: "resource summaries",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterState {
+					st := testClusterStateWithStatus(co("a"))
+					now := metav1.Now()
+					st.Status.LastUpdated = &now
+					st.Status.ResourcesLastUpdated = nil
+					return st
+				}(),
+				testClusterDeployment(),
+				testKubeconfigSecret(),
+			},
+			remote: []runtime.Object{
+				co("a"),
+				testNode("master-0", "v1.14.6", true, "master"),
+				testNode("worker-0", "v1.14.6", true, "worker"),
+				testNode("worker-1", "v1.13.4", false, "worker", "infra"),
+				testPod("running", corev1.PodRunning, "500m", "1Gi"),
+				testPod("pending", corev1.PodPending, "250m", "512Mi"),
+				testPod("succeeded", corev1.PodSucceeded, "1", "1Gi"),
+				testMachine("master-0", "Running", false),
+				testMachine("worker-0", "Running", false),
+				testMachine("worker-1", "Provisioning", true),
+				testMachineSet("worker", 2, 1),
+			},
+			validate: func(t *testing.T, c client.Client, result reconcile.Result) {
+				st := cs(t, c)
+				validateStatus(t, st.Status, co("a"))
+				assert.NotNil(t, st.Status.ResourcesLastUpdated, "expected resource summaries to be updated")
+				assert.Equal(t, &hivev1.ClusterNodesState{
+					Total:    3,
+					Ready:    2,
+					NotReady: 1,
+					Roles: []hivev1.NodeRoleState{
+						{Role: "infra", Total: 1, Ready: 0},
+						{Role: "master", Total: 1, Ready: 1},
+						{Role: "worker", Total: 2, Ready: 1},
+					},
+					KubeletVersions: []hivev1.KubeletVersionState{
+						{Version: "v1.13.4", Count: 1},
+						{Version: "v1.14.6", Count: 2},
+					},
+				}, st.Status.Nodes, "unexpected node summary")
+				assert.Equal(t, &hivev1.ClusterMachinesState{
+					Total:   3,
+					Running: 2,
+					Failed:  1,
+					MachineSets: []hivev1.MachineSetState{
+						{Name: "worker", Replicas: 2, ReadyReplicas: 1, AvailableReplicas: 1},
+					},
+				}, st.Status.Machines, "unexpected machine summary")
+				if assert.NotNil(t, st.Status.Capacity, "expected capacity summary") {
+					capacity := st.Status.Capacity
+					assert.Equal(t, int64(12000), capacity.AllocatableCPU.MilliValue(), "unexpected allocatable CPU")
+					assert.Equal(t, int64(3*16*1024*1024*1024), capacity.AllocatableMemory.Value(), "unexpected allocatable memory")
+					assert.Equal(t, int64(750), capacity.AllocatablePods, "unexpected allocatable pods")
+					assert.Equal(t, int64(750), capacity.RequestedCPU.MilliValue(), "unexpected requested CPU")
+					assert.Equal(t, int64(1536*1024*1024), capacity.RequestedMemory.Value(), "unexpected requested memory")
+					assert.Equal(t, 2, capacity.Pods, "unexpected pods")
+					assert.Equal(t, 1, capacity.PendingPods, "unexpected pending pods")
+				}
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -166,6 +284,10 @@ func TestClusterStateReconcile(t *testing.T) {
 				},
 			}
 
+			if test.watcher != nil {
+				rcd.remoteWatcher = test.watcher
+			}
+
 			result, err := rcd.Reconcile(reconcile.Request{
 				NamespacedName: types.NamespacedName{
 					Name:      testName,
@@ -187,6 +309,49 @@ func TestClusterStateReconcile(t *testing.T) {
 	}
 }
 
+func TestClusterStateStopsWatching(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	unreachable := testClusterDeployment()
+	unreachable.Status.Conditions = []hivev1.ClusterDeploymentCondition{{
+		Type:   hivev1.UnreachableCondition,
+		Status: corev1.ConditionTrue,
+	}}
+
+	tests := []struct {
+		name     string
+		existing []runtime.Object
+	}{
+		{
+			name: "cluster deployment deleted",
+		},
+		{
+			name:     "cluster unreachable",
+			existing: []runtime.Object{unreachable, testKubeconfigSecret()},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			watcher := remotewatcher.New()
+			watcher.Watched = []types.NamespacedName{{Namespace: testNamespace, Name: testName}}
+			rcd := &ReconcileClusterState{
+				Client:        fake.NewFakeClient(test.existing...),
+				scheme:        scheme.Scheme,
+				logger:        log.WithField("controller", "clusterState"),
+				remoteWatcher: watcher,
+			}
+			_, err := rcd.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testName,
+					Namespace: testNamespace,
+				},
+			})
+			assert.NoError(t, err, "unexpected error")
+			assert.Empty(t, watcher.Watched, "expected cluster to no longer be watched")
+		})
+	}
+}
+
 func testClusterState() *hivev1.ClusterState {
 	return &hivev1.ClusterState{
 		ObjectMeta: metav1.ObjectMeta{
@@ -198,6 +363,8 @@ func testClusterState() *hivev1.ClusterState {
 
 func testClusterStateWithStatus(operators ...*configv1.ClusterOperator) *hivev1.ClusterState {
 	cs := testClusterState()
+	now := metav1.Now()
+	cs.Status.ResourcesLastUpdated = &now
 	for _, op := range operators {
 		cs.Status.ClusterOperators = append(cs.Status.ClusterOperators, hivev1.ClusterOperatorState{
 			Name:       op.Name,
@@ -288,6 +455,91 @@ func removeCond(co *configv1.ClusterOperator) *configv1.ClusterOperator {
 	return co
 }
 
+func testNode(name, kubeletVersion string, ready bool, roles ...string) *corev1.Node {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{},
+		},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("16Gi"),
+				corev1.ResourcePods:   resource.MustParse("250"),
+			},
+			NodeInfo: corev1.NodeSystemInfo{
+				KubeletVersion: kubeletVersion,
+			},
+		},
+	}
+	for _, role := range roles {
+		node.Labels["node-role.kubernetes.io/"+role] = ""
+	}
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	node.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}}
+	return node
+}
+
+func testPod(name string, phase corev1.PodPhase, cpu, memory string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name: "main",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse(cpu),
+						corev1.ResourceMemory: resource.MustParse(memory),
+					},
+				},
+			}},
+		},
+		Status: corev1.PodStatus{
+			Phase: phase,
+		},
+	}
+}
+
+func testMachine(name, phase string, failed bool) *machineapi.Machine {
+	machine := &machineapi.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: machineAPINamespace,
+			Name:      name,
+		},
+		Status: machineapi.MachineStatus{
+			Phase: &phase,
+		},
+	}
+	if failed {
+		msg := "failed to create instance"
+		machine.Status.ErrorMessage = &msg
+	}
+	return machine
+}
+
+func testMachineSet(name string, replicas, readyReplicas int32) *machineapi.MachineSet {
+	return &machineapi.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: machineAPINamespace,
+			Name:      name,
+		},
+		Spec: machineapi.MachineSetSpec{
+			Replicas: &replicas,
+		},
+		Status: machineapi.MachineSetStatus{
+			Replicas:          replicas,
+			ReadyReplicas:     readyReplicas,
+			AvailableReplicas: readyReplicas,
+		},
+	}
+}
+
 func validateStatus(t *testing.T, status hivev1.ClusterStateStatus, operators ...*configv1.ClusterOperator) {
 	if !assert.Len(t, status.ClusterOperators, len(operators)) {
 		return
@@ -0,0 +1,177 @@
+package clusterstate
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	machineapi "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+)
+
+const (
+	machineAPINamespace = "openshift-machine-api"
+	nodeRoleLabelPrefix = "node-role.kubernetes.io/"
+	machineRunningPhase = "Running"
+)
+
+// updateResourceSummaries gathers the node, machine and capacity summaries of the remote cluster into the cluster
+// state. A summary that cannot be gathered keeps its previous value, and is gathered again with the others at the
+// next resource summary interval.
+func updateResourceSummaries(remoteClient client.Client, st *hivev1.ClusterState, logger log.FieldLogger) {
+	nodes := &corev1.NodeList{}
+	if err := remoteClient.List(context.TODO(), nodes); err != nil {
+		logger.WithError(err).Warn("failed to list target cluster nodes")
+	} else {
+		st.Status.Nodes = summarizeNodes(nodes.Items)
+		pods := &corev1.PodList{}
+		if err := remoteClient.List(context.TODO(), pods); err != nil {
+			logger.WithError(err).Warn("failed to list target cluster pods")
+		} else {
+			st.Status.Capacity = summarizeCapacity(nodes.Items, pods.Items)
+		}
+	}
+
+	machines := &machineapi.MachineList{}
+	machineSets := &machineapi.MachineSetList{}
+	if err := remoteClient.List(context.TODO(), machines, client.InNamespace(machineAPINamespace)); err != nil {
+		logger.WithError(err).Warn("failed to list target cluster machines")
+	} else if err := remoteClient.List(context.TODO(), machineSets, client.InNamespace(machineAPINamespace)); err != nil {
+		logger.WithError(err).Warn("failed to list target cluster machine sets")
+	} else {
+		st.Status.Machines = summarizeMachines(machines.Items, machineSets.Items)
+	}
+}
+
+func summarizeNodes(nodes []corev1.Node) *hivev1.ClusterNodesState {
+	summary := &hivev1.ClusterNodesState{}
+	roles := map[string]*hivev1.NodeRoleState{}
+	kubeletVersions := map[string]int{}
+	for _, node := range nodes {
+		ready := isNodeReady(&node)
+		summary.Total++
+		if ready {
+			summary.Ready++
+		} else {
+			summary.NotReady++
+		}
+		for label := range node.Labels {
+			if !strings.HasPrefix(label, nodeRoleLabelPrefix) {
+				continue
+			}
+			role := strings.TrimPrefix(label, nodeRoleLabelPrefix)
+			if roles[role] == nil {
+				roles[role] = &hivev1.NodeRoleState{Role: role}
+			}
+			roles[role].Total++
+			if ready {
+				roles[role].Ready++
+			}
+		}
+		kubeletVersions[node.Status.NodeInfo.KubeletVersion]++
+	}
+	for _, role := range roles {
+		summary.Roles = append(summary.Roles, *role)
+	}
+	sort.Slice(summary.Roles, func(i, j int) bool { return summary.Roles[i].Role < summary.Roles[j].Role })
+	for version, count := range kubeletVersions {
+		summary.KubeletVersions = append(summary.KubeletVersions, hivev1.KubeletVersionState{Version: version, Count: count})
+	}
+	sort.Slice(summary.KubeletVersions, func(i, j int) bool {
+		return summary.KubeletVersions[i].Version < summary.KubeletVersions[j].Version
+	})
+	return summary
+}
+
+func isNodeReady(node *corev1.Node) bool {
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func summarizeMachines(machines []machineapi.Machine, machineSets []machineapi.MachineSet) *hivev1.ClusterMachinesState {
+	summary := &hivev1.ClusterMachinesState{}
+	for _, machine := range machines {
+		summary.Total++
+		if machine.Status.Phase != nil && *machine.Status.Phase == machineRunningPhase {
+			summary.Running++
+		}
+		if machine.Status.ErrorReason != nil || machine.Status.ErrorMessage != nil {
+			summary.Failed++
+		}
+	}
+	for _, ms := range machineSets {
+		state := hivev1.MachineSetState{
+			Name:              ms.Name,
+			ReadyReplicas:     ms.Status.ReadyReplicas,
+			AvailableReplicas: ms.Status.AvailableReplicas,
+		}
+		if ms.Spec.Replicas != nil {
+			state.Replicas = *ms.Spec.Replicas
+		}
+		summary.MachineSets = append(summary.MachineSets, state)
+	}
+	sort.Slice(summary.MachineSets, func(i, j int) bool { return summary.MachineSets[i].Name < summary.MachineSets[j].Name })
+	return summary
+}
+
+func summarizeCapacity(nodes []corev1.Node, pods []corev1.Pod) *hivev1.ClusterCapacityState {
+	summary := &hivev1.ClusterCapacityState{
+		AllocatableCPU:    *resource.NewMilliQuantity(0, resource.DecimalSI),
+		AllocatableMemory: *resource.NewQuantity(0, resource.BinarySI),
+		RequestedCPU:      *resource.NewMilliQuantity(0, resource.DecimalSI),
+		RequestedMemory:   *resource.NewQuantity(0, resource.BinarySI),
+	}
+	for _, node := range nodes {
+		allocatable := node.Status.Allocatable
+		summary.AllocatableCPU.Add(allocatable[corev1.ResourceCPU])
+		summary.AllocatableMemory.Add(allocatable[corev1.ResourceMemory])
+		if pods, ok := allocatable[corev1.ResourcePods]; ok {
+			summary.AllocatablePods += pods.Value()
+		}
+	}
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		summary.Pods++
+		if pod.Status.Phase == corev1.PodPending {
+			summary.PendingPods++
+		}
+		requests := podRequests(&pod)
+		summary.RequestedCPU.Add(requests[corev1.ResourceCPU])
+		summary.RequestedMemory.Add(requests[corev1.ResourceMemory])
+	}
+	return summary
+}
+
+// podRequests returns the resources requested by the pod, which is the greater of the sum of the requests of its
+// containers and the largest request of its init containers, as init containers run one at a time.
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		for name, quantity := range container.Resources.Requests {
+			total := requests[name]
+			total.Add(quantity)
+			requests[name] = total
+		}
+	}
+	for _, container := range pod.Spec.InitContainers {
+		for name, quantity := range container.Resources.Requests {
+			if total, ok := requests[name]; !ok || quantity.Cmp(total) > 0 {
+				requests[name] = quantity.DeepCopy()
+			}
+		}
+	}
+	return requests
+}
@@ -28,8 +28,14 @@ import (
 const (
 	clusterVersionObjectName = "version"
 	controllerName           = "clusterversion"
+
+	// remoteResyncInterval is how often the cluster deployment status is updated from the watched cluster version of
+	// a cluster in case changes were missed
+	remoteResyncInterval = 30 * time.Minute
 )
 
+var clusterVersionResource = openshiftapiv1.SchemeGroupVersion.WithResource("clusterversions")
+
 // Add creates a new ClusterDeployment Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager) error {
@@ -41,7 +47,8 @@ func NewReconciler(mgr manager.Manager) reconcile.Reconciler {
 	return &ReconcileClusterVersion{
 		Client:                        controllerutils.NewClientWithMetricsOrDie(mgr, controllerName),
 		scheme:                        mgr.GetScheme(),
-		remoteClusterAPIClientBuilder: controllerutils.BuildClusterAPIClientFromKubeconfig,
+		remoteClusterAPIClientBuilder: controllerutils.RemoteClusters.ClusterAPIClient,
+		remoteWatcher:                 controllerutils.NewRemoteWatcher(controllerName, clusterVersionResource, remoteResyncInterval),
 	}
 }
 
@@ -59,6 +66,14 @@ func AddToManager(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Watch for changes to the cluster version of installed clusters
+	if reconciler, ok := r.(*ReconcileClusterVersion); ok && reconciler.remoteWatcher != nil {
+		err = c.Watch(reconciler.remoteWatcher.Source(), &handler.EnqueueRequestForObject{})
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -71,6 +86,10 @@ type ReconcileClusterVersion struct {
 	// remoteClusterAPIClientBuilder is a function pointer to the function that builds a client for the
 	// remote cluster's cluster-api
 	remoteClusterAPIClientBuilder func(string, string) (client.Client, error)
+
+	// remoteWatcher watches the cluster version of installed clusters. The cluster version is fetched on every
+	// reconcile if it is nil.
+	remoteWatcher controllerutils.RemoteWatcher
 }
 
 // Reconcile reads that state of the cluster for a ClusterDeployment object and syncs the remote ClusterVersion status
@@ -97,6 +116,7 @@ func (r *ReconcileClusterVersion) Reconcile(request reconcile.Request) (reconcil
 		if errors.IsNotFound(err) {
 			// Object not found, return.  Created objects are automatically garbage collected.
 			// For additional cleanup logic use finalizers.
+			r.stopWatching(request.NamespacedName)
 			return reconcile.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
@@ -104,12 +124,14 @@ func (r *ReconcileClusterVersion) Reconcile(request reconcile.Request) (reconcil
 	}
 	// If the clusterdeployment is deleted, do not reconcile.
 	if cd.DeletionTimestamp != nil {
+		r.stopWatching(request.NamespacedName)
 		return reconcile.Result{}, nil
 	}
 
 	// If the cluster is unreachable, do not reconcile.
 	if controllerutils.HasUnreachableCondition(cd) {
 		cdLog.Debug("skipping cluster with unreachable condition")
+		r.stopWatching(request.NamespacedName)
 		return reconcile.Result{}, nil
 	}
 
@@ -135,6 +157,14 @@ func (r *ReconcileClusterVersion) Reconcile(request reconcile.Request) (reconcil
 		cdLog.WithError(err).Error("cannot fixup kubeconfig for remote cluster")
 		return reconcile.Result{}, err
 	}
+	if r.remoteWatcher != nil {
+		if err := r.remoteWatcher.Watch(cd, string(kubeConfig)); err != nil {
+			cdLog.WithError(err).Warn("unable to watch remote clusterversion, fetching it instead")
+		} else {
+			return r.syncWatchedClusterVersion(cd, cdLog)
+		}
+	}
+
 	remoteClient, err := r.remoteClusterAPIClientBuilder(string(kubeConfig), controllerName)
 	if err != nil {
 		cdLog.WithError(err).Error("error building remote cluster-api client connection")
@@ -157,6 +187,39 @@ func (r *ReconcileClusterVersion) Reconcile(request reconcile.Request) (reconcil
 	return reconcile.Result{}, nil
 }
 
+// syncWatchedClusterVersion updates the cluster deployment status from the watched cluster version of the cluster
+func (r *ReconcileClusterVersion) syncWatchedClusterVersion(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) (reconcile.Result, error) {
+	objects, synced := r.remoteWatcher.List(types.NamespacedName{Namespace: cd.Namespace, Name: cd.Name})
+	if !synced {
+		// The cluster deployment is reconciled again once the cluster version has been listed
+		cdLog.Debug("waiting for remote clusterversion to be listed")
+		return reconcile.Result{RequeueAfter: remoteResyncInterval}, nil
+	}
+	for _, obj := range objects {
+		if obj.GetName() != clusterVersionObjectName {
+			continue
+		}
+		clusterVersion := &openshiftapiv1.ClusterVersion{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, clusterVersion); err != nil {
+			cdLog.WithError(err).Error("failed to convert remote clusterversion object")
+			return reconcile.Result{}, err
+		}
+		if err := r.updateClusterVersionStatus(cd, clusterVersion, cdLog); err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{RequeueAfter: remoteResyncInterval}, nil
+	}
+	cdLog.Error("remote clusterversion object not found")
+	return reconcile.Result{RequeueAfter: remoteResyncInterval}, nil
+}
+
+// stopWatching stops watching the cluster version of the cluster deployment
+func (r *ReconcileClusterVersion) stopWatching(cd types.NamespacedName) {
+	if r.remoteWatcher != nil {
+		r.remoteWatcher.Stop(cd)
+	}
+}
+
 func (r *ReconcileClusterVersion) updateClusterVersionStatus(cd *hivev1.ClusterDeployment, clusterVersion *openshiftapiv1.ClusterVersion, cdLog log.FieldLogger) error {
 	origCD := cd.DeepCopy()
 	cdLog.WithField("clusterversion.status", clusterVersion.Status).Debug("remote cluster version status")
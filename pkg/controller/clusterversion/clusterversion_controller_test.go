@@ -22,6 +22,7 @@ import (
 	"github.com/openshift/hive/pkg/apis"
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
 	hivev1aws "github.com/openshift/hive/pkg/apis/hive/v1/aws"
+	"github.com/openshift/hive/pkg/test/remotewatcher"
 )
 
 const (
@@ -43,10 +44,12 @@ func TestClusterVersionReconcile(t *testing.T) {
 	configv1.Install(scheme.Scheme)
 
 	tests := []struct {
-		name        string
-		existing    []runtime.Object
-		expectError bool
-		validate    func(*testing.T, *hivev1.ClusterDeployment)
+		name          string
+		existing      []runtime.Object
+		watcher       *remotewatcher.Fake
+		expectWatched bool
+		expectError   bool
+		validate      func(*testing.T, *hivev1.ClusterDeployment)
 	}{
 		{
 			// no cluster deployment, no error expected
@@ -78,6 +81,50 @@ func TestClusterVersionReconcile(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "watched clusterversion",
+			existing: []runtime.Object{
+				testClusterDeployment(),
+				testKubeconfigSecret(),
+			},
+			watcher: func() *remotewatcher.Fake {
+				clusterVersion := testRemoteClusterVersion()
+				clusterVersion.Status.Desired.Version = "4.1.0"
+				return remotewatcher.New(clusterVersion)
+			}(),
+			expectWatched: true,
+			validate: func(t *testing.T, cd *hivev1.ClusterDeployment) {
+				if cd.Status.ClusterVersionStatus.Desired.Version != "4.1.0" {
+					t.Errorf("did not get clusterversion status from watched clusterversion. Got: \n%#v", cd.Status.ClusterVersionStatus)
+				}
+			},
+		},
+		{
+			name: "watched clusterversion not listed yet",
+			existing: []runtime.Object{
+				testClusterDeployment(),
+				testKubeconfigSecret(),
+			},
+			watcher:       &remotewatcher.Fake{},
+			expectWatched: true,
+			validate: func(t *testing.T, cd *hivev1.ClusterDeployment) {
+				if !reflect.DeepEqual(cd.Status.ClusterVersionStatus, configv1.ClusterVersionStatus{}) {
+					t.Errorf("unexpected clusterversion status update. Got: \n%#v", cd.Status.ClusterVersionStatus)
+				}
+			},
+		},
+		{
+			name: "stop watching deleted clusterdeployment",
+			existing: []runtime.Object{
+				testDeletedClusterDeployment(),
+			},
+			watcher: func() *remotewatcher.Fake {
+				watcher := remotewatcher.New()
+				watcher.Watched = []types.NamespacedName{{Namespace: testNamespace, Name: testName}}
+				return watcher
+			}(),
+			validate: func(t *testing.T, cd *hivev1.ClusterDeployment) {},
+		},
 	}
 
 	for _, test := range tests {
@@ -88,6 +135,9 @@ func TestClusterVersionReconcile(t *testing.T) {
 				scheme:                        scheme.Scheme,
 				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
 			}
+			if test.watcher != nil {
+				rcd.remoteWatcher = test.watcher
+			}
 
 			namespacedName := types.NamespacedName{
 				Name:      testName,
@@ -106,6 +156,10 @@ func TestClusterVersionReconcile(t *testing.T) {
 				test.validate(t, cd)
 			}
 
+			if test.watcher != nil && (len(test.watcher.Watched) > 0) != test.expectWatched {
+				t.Errorf("unexpected watched clusters: %v", test.watcher.Watched)
+			}
+
 			if err != nil && !test.expectError {
 				t.Errorf("Unexpected error: %v", err)
 			}
@@ -174,15 +228,18 @@ func testSecret(name, key, value string) *corev1.Secret {
 }
 
 func testRemoteClusterAPIClientBuilder(secretData string, controllerName string) (client.Client, error) {
+	remoteClient := fake.NewFakeClient(testRemoteClusterVersion())
+	return remoteClient, nil
+}
+
+func testRemoteClusterVersion() *configv1.ClusterVersion {
 	remoteClusterVersion := &configv1.ClusterVersion{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: remoteClusterVersionObjectName,
 		},
 	}
 	remoteClusterVersion.Status = testRemoteClusterVersionStatus()
-
-	remoteClient := fake.NewFakeClient(remoteClusterVersion)
-	return remoteClient, nil
+	return remoteClusterVersion
 }
 
 func testRemoteClusterVersionStatus() configv1.ClusterVersionStatus {
@@ -0,0 +1,43 @@
+package remotemachineset
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	machineapi "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+)
+
+// Actuator is the interface that must be implemented to standardize generating
+// and returning the list of MachineSets to be synced to the remote cluster.
+type Actuator interface {
+	// GenerateMachineSets returns the MachineSets for the machine pool of the cluster deployment
+	GenerateMachineSets(*hivev1.ClusterDeployment, *hivev1.MachinePool, log.FieldLogger) ([]*machineapi.MachineSet, error)
+}
+
+// setProviderSpecFields sets fields of the provider spec of a generated MachineSet that are not in the vendored
+// provider spec types. The provider spec object is kept for use by the controller, while the raw provider spec
+// with the additional fields is what is sent to the remote cluster, so changes to the provider spec object must be
+// made before. Fields set by earlier calls are kept.
+func setProviderSpecFields(ms *machineapi.MachineSet, fields map[string]interface{}) error {
+	providerSpec := ms.Spec.Template.Spec.ProviderSpec.Value
+	raw := providerSpec.Raw
+	var err error
+	if raw == nil {
+		if raw, err = json.Marshal(providerSpec.Object); err != nil {
+			return errors.Wrap(err, "failed to encode provider spec")
+		}
+	}
+	obj := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return errors.Wrap(err, "failed to decode provider spec")
+	}
+	for key, value := range fields {
+		obj[key] = value
+	}
+	providerSpec.Raw, err = json.Marshal(obj)
+	return errors.Wrap(err, "failed to encode provider spec")
+}
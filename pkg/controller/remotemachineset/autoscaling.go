@@ -0,0 +1,188 @@
+package remotemachineset
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	machineapi "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+)
+
+const (
+	machineAPINamespace   = "openshift-machine-api"
+	clusterAutoscalerName = "default"
+)
+
+var (
+	// The autoscaling APIs of the cluster-autoscaler-operator are not vendored, so the ClusterAutoscaler and
+	// MachineAutoscalers of remote clusters are handled as unstructured objects.
+	clusterAutoscalerGVK = schema.GroupVersionKind{Group: "autoscaling.openshift.io", Version: "v1", Kind: "ClusterAutoscaler"}
+	machineAutoscalerGVK = schema.GroupVersionKind{Group: "autoscaling.openshift.io", Version: "v1beta1", Kind: "MachineAutoscaler"}
+)
+
+// machineSetReplicaRange returns the min and max replicas of the machine set at index i of the n machine sets of an
+// autoscaled machine pool. The replicas of the pool are spread as evenly as possible across its machine sets, with
+// the first machine sets getting any remainder.
+func machineSetReplicaRange(autoscaling *hivev1.MachinePoolAutoscaling, i, n int) (int32, int32) {
+	return spreadReplicas(autoscaling.MinReplicas, i, n), spreadReplicas(autoscaling.MaxReplicas, i, n)
+}
+
+// clampReplicas returns the replicas of the machine set at index i of the n machine sets of an autoscaled machine
+// pool, brought into the range of replicas of the machine set
+func clampReplicas(replicas int32, autoscaling *hivev1.MachinePoolAutoscaling, i, n int) int32 {
+	minReplicas, maxReplicas := machineSetReplicaRange(autoscaling, i, n)
+	switch {
+	case replicas < minReplicas:
+		return minReplicas
+	case replicas > maxReplicas:
+		return maxReplicas
+	}
+	return replicas
+}
+
+func spreadReplicas(replicas int32, i, n int) int32 {
+	share := replicas / int32(n)
+	if int32(i) < replicas%int32(n) {
+		share++
+	}
+	return share
+}
+
+// syncAutoscalers makes sure the remote cluster has a ClusterAutoscaler and a MachineAutoscaler for each of the
+// machine sets of an autoscaled machine pool, and no MachineAutoscalers for the machine sets of a pool that is not
+// autoscaled or that are deleted. A ClusterAutoscaler that already exists is left as is, as it may be configured
+// by the owners of the cluster, and it is never deleted, as it is shared by all the machine pools of the cluster.
+func syncAutoscalers(
+	pool *hivev1.MachinePool,
+	machineSets []*machineapi.MachineSet,
+	deletedMachineSets []*machineapi.MachineSet,
+	remoteClusterAPIClient client.Client,
+	logger log.FieldLogger) error {
+
+	if pool.Spec.Autoscaling != nil && len(machineSets) > 0 {
+		if err := ensureClusterAutoscaler(remoteClusterAPIClient, logger); err != nil {
+			return err
+		}
+		for i, ms := range machineSets {
+			minReplicas, maxReplicas := machineSetReplicaRange(pool.Spec.Autoscaling, i, len(machineSets))
+			if err := ensureMachineAutoscaler(remoteClusterAPIClient, pool, ms.Name, minReplicas, maxReplicas, logger); err != nil {
+				return err
+			}
+		}
+	} else {
+		deletedMachineSets = append(deletedMachineSets, machineSets...)
+	}
+
+	for _, ms := range deletedMachineSets {
+		ma := &unstructured.Unstructured{}
+		ma.SetGroupVersionKind(machineAutoscalerGVK)
+		ma.SetNamespace(machineAPINamespace)
+		ma.SetName(ms.Name)
+		switch err := remoteClusterAPIClient.Delete(context.Background(), ma); {
+		case errors.IsNotFound(err):
+		case err != nil:
+			logger.WithError(err).WithField("machineautoscaler", ms.Name).Error("unable to delete machine autoscaler")
+			return err
+		default:
+			logger.WithField("machineautoscaler", ms.Name).Info("deleted machine autoscaler")
+		}
+	}
+	return nil
+}
+
+func ensureClusterAutoscaler(remoteClusterAPIClient client.Client, logger log.FieldLogger) error {
+	ca := &unstructured.Unstructured{}
+	ca.SetGroupVersionKind(clusterAutoscalerGVK)
+	switch err := remoteClusterAPIClient.Get(context.Background(), client.ObjectKey{Name: clusterAutoscalerName}, ca); {
+	case err == nil:
+		return nil
+	case !errors.IsNotFound(err):
+		logger.WithError(err).Error("unable to fetch cluster autoscaler")
+		return err
+	}
+
+	ca = &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"balanceSimilarNodeGroups": true,
+			"scaleDown": map[string]interface{}{
+				"enabled": true,
+			},
+		},
+	}}
+	ca.SetGroupVersionKind(clusterAutoscalerGVK)
+	ca.SetName(clusterAutoscalerName)
+	logger.Info("creating cluster autoscaler")
+	if err := remoteClusterAPIClient.Create(context.Background(), ca); err != nil {
+		logger.WithError(err).Error("unable to create cluster autoscaler")
+		return err
+	}
+	return nil
+}
+
+func ensureMachineAutoscaler(
+	remoteClusterAPIClient client.Client,
+	pool *hivev1.MachinePool,
+	machineSetName string,
+	minReplicas, maxReplicas int32,
+	logger log.FieldLogger) error {
+
+	maLog := logger.WithField("machineautoscaler", machineSetName)
+	ma := &unstructured.Unstructured{}
+	ma.SetGroupVersionKind(machineAutoscalerGVK)
+	err := remoteClusterAPIClient.Get(context.Background(), client.ObjectKey{Namespace: machineAPINamespace, Name: machineSetName}, ma)
+	if err != nil && !errors.IsNotFound(err) {
+		maLog.WithError(err).Error("unable to fetch machine autoscaler")
+		return err
+	}
+	exists := err == nil
+
+	if exists {
+		observedMin, _, _ := unstructured.NestedInt64(ma.Object, "spec", "minReplicas")
+		observedMax, _, _ := unstructured.NestedInt64(ma.Object, "spec", "maxReplicas")
+		if observedMin == int64(minReplicas) && observedMax == int64(maxReplicas) {
+			return nil
+		}
+		maLog.WithFields(log.Fields{
+			"desiredMin":  minReplicas,
+			"desiredMax":  maxReplicas,
+			"observedMin": observedMin,
+			"observedMax": observedMax,
+		}).Info("machine autoscaler replicas out of sync")
+	} else {
+		ma.SetNamespace(machineAPINamespace)
+		ma.SetName(machineSetName)
+		ma.SetLabels(map[string]string{machinePoolNameLabel: pool.Spec.Name})
+	}
+
+	if err := unstructured.SetNestedMap(ma.Object, map[string]interface{}{
+		"minReplicas": int64(minReplicas),
+		"maxReplicas": int64(maxReplicas),
+		"scaleTargetRef": map[string]interface{}{
+			"apiVersion": machineapi.SchemeGroupVersion.String(),
+			"kind":       "MachineSet",
+			"name":       machineSetName,
+		},
+	}, "spec"); err != nil {
+		return fmt.Errorf("unable to set spec of machine autoscaler %s: %v", machineSetName, err)
+	}
+
+	if exists {
+		maLog.Info("updating machine autoscaler")
+		err = remoteClusterAPIClient.Update(context.Background(), ma)
+	} else {
+		maLog.Info("creating machine autoscaler")
+		err = remoteClusterAPIClient.Create(context.Background(), ma)
+	}
+	if err != nil {
+		maLog.WithError(err).Error("unable to sync machine autoscaler")
+	}
+	return err
+}
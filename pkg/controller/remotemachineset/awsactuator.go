@@ -0,0 +1,340 @@
+package remotemachineset
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	jsonserializer "k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/utils/pointer"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	machineapi "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+	awsprovider "sigs.k8s.io/cluster-api-provider-aws/pkg/apis/awsproviderconfig/v1beta1"
+
+	installaws "github.com/openshift/installer/pkg/asset/machines/aws"
+	installertypes "github.com/openshift/installer/pkg/types"
+	installertypesaws "github.com/openshift/installer/pkg/types/aws"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/awsclient"
+)
+
+const (
+	// privateSubnetsCacheSize is the number of cluster deployments whose private subnets are cached
+	privateSubnetsCacheSize = 1000
+	// privateSubnetsCacheTTL is how long the private subnets of a cluster deployment are cached, after which changes
+	// to the route tables of its VPC are picked up
+	privateSubnetsCacheTTL = time.Hour
+)
+
+// AWSActuator encapsulates the pieces necessary to be able to generate
+// a list of MachineSets to sync to the remote cluster.
+type AWSActuator struct {
+	client awsclient.Client
+	logger log.FieldLogger
+	amiID  string
+	// privateSubnetsCache holds the private subnets of cluster deployments installed in existing subnets by the UID
+	// of the cluster deployment. The private subnets are looked up on every call when it is nil.
+	privateSubnetsCache *cache.LRUExpireCache
+}
+
+var _ Actuator = &AWSActuator{}
+
+// NewAWSActuator is the constructor for building an AWSActuator. The AMI of the generated MachineSets is taken from
+// the MachineSets of the remote cluster.
+func NewAWSActuator(awsClient awsclient.Client, remoteMachineSets []machineapi.MachineSet, privateSubnetsCache *cache.LRUExpireCache, scheme *runtime.Scheme, logger log.FieldLogger) (*AWSActuator, error) {
+	amiID, err := getAWSAMIID(remoteMachineSets, scheme, logger)
+	if err != nil {
+		return nil, err
+	}
+	actuator := &AWSActuator{
+		client:              awsClient,
+		logger:              logger,
+		amiID:               amiID,
+		privateSubnetsCache: privateSubnetsCache,
+	}
+	return actuator, nil
+}
+
+// GenerateMachineSets satisfies the Actuator interface and will take a clusterDeployment and return a list of MachineSets
+// to sync to the remote cluster.
+func (a *AWSActuator) GenerateMachineSets(cd *hivev1.ClusterDeployment, pool *hivev1.MachinePool, logger log.FieldLogger) ([]*machineapi.MachineSet, error) {
+	if cd.Spec.ClusterMetadata == nil {
+		return nil, fmt.Errorf("ClusterDeployment does not have cluster metadata")
+	}
+	if cd.Spec.Platform.AWS == nil {
+		return nil, fmt.Errorf("ClusterDeployment is not for AWS")
+	}
+
+	// Generate minimal/partial InstallConfig from ClusterDeployment
+	ic := &installertypes.InstallConfig{
+		Platform: installertypes.Platform{
+			AWS: &installertypesaws.Platform{
+				Region: cd.Spec.Platform.AWS.Region,
+			},
+		},
+	}
+
+	// The machines of clusters installed in existing subnets are placed in the private subnets among them
+	var privateSubnets map[string]string
+	if len(cd.Spec.Platform.AWS.Subnets) > 0 {
+		var err error
+		privateSubnets, err = a.getPrivateSubnets(cd)
+		if err != nil {
+			logger.WithError(err).Error("unable to fetch private subnets of cluster")
+			return nil, err
+		}
+	}
+
+	computePool := baseMachinePool(pool)
+	if computePool.Platform.AWS == nil {
+		computePool.Platform.AWS = &installertypesaws.MachinePool{}
+	}
+	if len(computePool.Platform.AWS.Zones) == 0 {
+		var azs []string
+		if privateSubnets != nil {
+			for zone := range privateSubnets {
+				azs = append(azs, zone)
+			}
+			sort.Strings(azs)
+		} else {
+			var err error
+			azs, err = fetchAvailabilityZones(a.client, cd.Spec.Platform.AWS.Region)
+			if err != nil {
+				return nil, err
+			}
+		}
+		// Safety net from deleting machine sets. Do we expect to return 0 availability zones successfully?
+		if len(azs) == 0 {
+			return nil, fmt.Errorf("fetched 0 availability zones")
+		}
+		computePool.Platform.AWS.Zones = azs
+	}
+
+	installerMachineSets, err := installaws.MachineSets(cd.Spec.ClusterMetadata.InfraID, ic, computePool, a.amiID, computePool.Name, "worker-user-data")
+	if err != nil {
+		return nil, err
+	}
+	for _, ms := range installerMachineSets {
+		// Re-use existing AWS resources for generated MachineSets.
+		updateMachineSetAWSMachineProviderConfig(ms, cd.Spec.ClusterMetadata.InfraID, privateSubnets, cd.Spec.Platform.AWS.AdditionalSecurityGroupIDs)
+
+		// The vendored provider spec does not have the spot market options of the machine API.
+		if pool.Spec.Platform.AWS != nil && pool.Spec.Platform.AWS.SpotMarketOptions != nil {
+			spotMarketOptions := map[string]interface{}{}
+			if maxPrice := pool.Spec.Platform.AWS.SpotMarketOptions.MaxPrice; maxPrice != nil {
+				spotMarketOptions["maxPrice"] = *maxPrice
+			}
+			if err := setProviderSpecFields(ms, map[string]interface{}{"spotMarketOptions": spotMarketOptions}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return installerMachineSets, nil
+}
+
+// getAWSAMIID scans the pre-existing machinesets to find an AMI ID we can use if we need to create
+// new machinesets.
+// TODO: this will need work at some point in the future, ideally the AMI should come from
+// release image someday, hopefully we can hold off until that is the case, and look it up when
+// we extract installer image refs.
+func getAWSAMIID(remoteMachineSets []machineapi.MachineSet, scheme *runtime.Scheme, logger log.FieldLogger) (string, error) {
+	for _, ms := range remoteMachineSets {
+		awsProviderSpec, err := decodeAWSMachineProviderSpec(ms.Spec.Template.Spec.ProviderSpec.Value, scheme)
+		if err != nil {
+			logger.WithError(err).Warn("error decoding AWSMachineProviderConfig, skipping MachineSet for AMI check")
+			continue
+		}
+		if awsProviderSpec.AMI.ID == nil {
+			// Really weird, but keep looking...
+			continue
+		}
+		amiID := *awsProviderSpec.AMI.ID
+		logger.WithFields(log.Fields{
+			"fromRemoteMachineSet": ms.Name,
+			"ami":                  amiID,
+		}).Debug("resolved AMI to use for new machinesets")
+		return amiID, nil
+	}
+	return "", fmt.Errorf("unable to locate AMI to use from pre-existing machine set")
+}
+
+// updateMachineSetAWSMachineProviderConfig modifies values in a MachineSet's AWSMachineProviderConfig.
+// Currently we modify the AWSMachineProviderConfig IAMInstanceProfile, Subnet and SecurityGroups such that
+// the values match the worker pool originally created by the installer. For clusters installed in existing
+// subnets, the private subnets are given by zone, and a MachineSet in a zone without a private subnet is left
+// without a subnet. The additional security groups are added to the worker security group.
+func updateMachineSetAWSMachineProviderConfig(machineSet *machineapi.MachineSet, infraID string, privateSubnets map[string]string, additionalSecurityGroupIDs []string) {
+	providerConfig := machineSet.Spec.Template.Spec.ProviderSpec.Value.Object.(*awsprovider.AWSMachineProviderConfig)
+
+	// TODO: assumptions about pre-existing objects by name here is quite dangerous, it's already
+	// broken on us once via renames in the installer. We need to start querying for what exists
+	// here.
+	providerConfig.IAMInstanceProfile = &awsprovider.AWSResourceReference{ID: pointer.StringPtr(fmt.Sprintf("%s-worker-profile", infraID))}
+	if privateSubnets != nil {
+		providerConfig.Subnet = awsprovider.AWSResourceReference{}
+		if subnet, ok := privateSubnets[providerConfig.Placement.AvailabilityZone]; ok {
+			providerConfig.Subnet.ID = pointer.StringPtr(subnet)
+		}
+	} else {
+		providerConfig.Subnet = awsprovider.AWSResourceReference{
+			Filters: []awsprovider.Filter{{
+				Name:   "tag:Name",
+				Values: []string{fmt.Sprintf("%s-private-%s", infraID, providerConfig.Placement.AvailabilityZone)},
+			}},
+		}
+	}
+	providerConfig.SecurityGroups = []awsprovider.AWSResourceReference{{
+		Filters: []awsprovider.Filter{{
+			Name:   "tag:Name",
+			Values: []string{fmt.Sprintf("%s-worker-sg", infraID)},
+		}},
+	}}
+	for _, sg := range additionalSecurityGroupIDs {
+		providerConfig.SecurityGroups = append(providerConfig.SecurityGroups, awsprovider.AWSResourceReference{ID: pointer.StringPtr(sg)})
+	}
+	machineSet.Spec.Template.Spec.ProviderSpec = machineapi.ProviderSpec{
+		Value: &runtime.RawExtension{Object: providerConfig},
+	}
+}
+
+// fetchAvailabilityZones fetches availability zones for the specified region
+func fetchAvailabilityZones(client awsclient.Client, region string) ([]string, error) {
+	zoneFilter := &ec2.Filter{
+		Name:   aws.String("region-name"),
+		Values: []*string{aws.String(region)},
+	}
+	req := &ec2.DescribeAvailabilityZonesInput{
+		Filters: []*ec2.Filter{zoneFilter},
+	}
+	resp, err := client.DescribeAvailabilityZones(req)
+	if err != nil {
+		return nil, err
+	}
+	zones := []string{}
+	for _, zone := range resp.AvailabilityZones {
+		zones = append(zones, *zone.ZoneName)
+	}
+	return zones, nil
+}
+
+// getPrivateSubnets returns the private subnets of a cluster deployment installed in existing subnets by availability
+// zone. The subnets of a cluster deployment cannot change, so they are cached instead of being looked up on every
+// reconcile.
+func (a *AWSActuator) getPrivateSubnets(cd *hivev1.ClusterDeployment) (map[string]string, error) {
+	if a.privateSubnetsCache != nil {
+		if cached, ok := a.privateSubnetsCache.Get(cd.UID); ok {
+			return cached.(map[string]string), nil
+		}
+	}
+	privateSubnets, err := fetchPrivateSubnets(a.client, cd.Spec.Platform.AWS.Subnets)
+	if err != nil {
+		return nil, err
+	}
+	if a.privateSubnetsCache != nil {
+		a.privateSubnetsCache.Add(cd.UID, privateSubnets, privateSubnetsCacheTTL)
+	}
+	return privateSubnets, nil
+}
+
+// fetchPrivateSubnets returns the private subnets among the given subnets by availability zone. Like in the
+// installer, a subnet is public when its route table, or the main route table of its VPC when it has none, routes
+// to an internet gateway. When a zone has several private subnets, the first one given is used.
+func fetchPrivateSubnets(client awsclient.Client, subnetIDs []string) (map[string]string, error) {
+	subnetsResp, err := client.DescribeSubnets(&ec2.DescribeSubnetsInput{
+		Filters: []*ec2.Filter{{Name: aws.String("subnet-id"), Values: aws.StringSlice(subnetIDs)}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	subnetZones := map[string]string{}
+	vpcIDs := []string{}
+	for _, subnet := range subnetsResp.Subnets {
+		subnetZones[aws.StringValue(subnet.SubnetId)] = aws.StringValue(subnet.AvailabilityZone)
+		vpcIDs = append(vpcIDs, aws.StringValue(subnet.VpcId))
+	}
+	if len(vpcIDs) == 0 {
+		return nil, fmt.Errorf("none of the subnets of the cluster were found")
+	}
+
+	routeTablesResp, err := client.DescribeRouteTables(&ec2.DescribeRouteTablesInput{
+		Filters: []*ec2.Filter{{Name: aws.String("vpc-id"), Values: aws.StringSlice(vpcIDs[:1])}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	publicSubnets := map[string]bool{}
+	mainRouteTablePublic := false
+	for _, table := range routeTablesResp.RouteTables {
+		public := false
+		for _, route := range table.Routes {
+			if strings.HasPrefix(aws.StringValue(route.GatewayId), "igw-") {
+				public = true
+			}
+		}
+		for _, association := range table.Associations {
+			if aws.BoolValue(association.Main) {
+				mainRouteTablePublic = public
+			}
+			if association.SubnetId != nil {
+				publicSubnets[aws.StringValue(association.SubnetId)] = public
+			}
+		}
+	}
+
+	privateSubnets := map[string]string{}
+	for _, id := range subnetIDs {
+		zone, ok := subnetZones[id]
+		if !ok {
+			continue
+		}
+		public, ok := publicSubnets[id]
+		if !ok {
+			public = mainRouteTablePublic
+		}
+		if _, found := privateSubnets[zone]; !public && !found {
+			privateSubnets[zone] = id
+		}
+	}
+	return privateSubnets, nil
+}
+
+func decodeAWSMachineProviderSpec(rawExt *runtime.RawExtension, scheme *runtime.Scheme) (*awsprovider.AWSMachineProviderConfig, error) {
+	codecFactory := serializer.NewCodecFactory(scheme)
+	decoder := codecFactory.UniversalDecoder(awsprovider.SchemeGroupVersion)
+	if rawExt == nil {
+		return nil, fmt.Errorf("MachineSet has no ProviderSpec")
+	}
+	obj, gvk, err := decoder.Decode([]byte(rawExt.Raw), nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode AWS ProviderConfig: %v", err)
+	}
+	spec, ok := obj.(*awsprovider.AWSMachineProviderConfig)
+	if !ok {
+		return nil, fmt.Errorf("Unexpected object: %#v", gvk)
+	}
+	return spec, nil
+}
+
+func encodeAWSMachineProviderSpec(awsProviderSpec *awsprovider.AWSMachineProviderConfig, scheme *runtime.Scheme) (*runtime.RawExtension, error) {
+
+	serializer := jsonserializer.NewSerializer(jsonserializer.DefaultMetaFactory, scheme, scheme, false)
+	var buffer bytes.Buffer
+	err := serializer.Encode(awsProviderSpec, &buffer)
+	if err != nil {
+		return nil, err
+	}
+	return &runtime.RawExtension{
+		Raw: buffer.Bytes(),
+	}, nil
+}
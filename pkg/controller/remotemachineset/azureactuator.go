@@ -0,0 +1,179 @@
+package remotemachineset
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	machineapi "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+)
+
+const (
+	// The installer defaults of the compute pools of Azure clusters
+	defaultAzureInstanceType = "Standard_D2s_v3"
+	defaultAzureDiskSizeGB   = 128
+)
+
+// azureClusterResources are the names of the Azure resources that the installer creates for a cluster and that the
+// compute machines of the cluster use. They are laid out as in the provider specs of the compute machines the
+// installer creates.
+type azureClusterResources struct {
+	resourceGroup   string
+	vnet            string
+	subnet          string
+	managedIdentity string
+	imageResourceID string
+}
+
+// newAzureClusterResources returns the names of the Azure resources of a cluster, which the installer derives from the
+// infrastructure ID of the cluster.
+func newAzureClusterResources(metadata *hivev1.ClusterMetadata) azureClusterResources {
+	infraID := metadata.InfraID
+	resourceGroup := fmt.Sprintf("%s-rg", infraID)
+	return azureClusterResources{
+		resourceGroup:   resourceGroup,
+		vnet:            fmt.Sprintf("%s-vnet", infraID),
+		subnet:          fmt.Sprintf("%s-worker-subnet", infraID),
+		managedIdentity: fmt.Sprintf("%s-identity", infraID),
+		imageResourceID: fmt.Sprintf("/resourceGroups/%s/providers/Microsoft.Compute/images/%s", resourceGroup, infraID),
+	}
+}
+
+// AzureActuator encapsulates the pieces necessary to be able to generate
+// a list of MachineSets to sync to the remote cluster.
+type AzureActuator struct {
+	logger log.FieldLogger
+}
+
+var _ Actuator = &AzureActuator{}
+
+// NewAzureActuator is the constructor for building an AzureActuator
+func NewAzureActuator(logger log.FieldLogger) (*AzureActuator, error) {
+	return &AzureActuator{logger: logger}, nil
+}
+
+// GenerateMachineSets satisfies the Actuator interface and will take a clusterDeployment and return a list of MachineSets
+// to sync to the remote cluster. The Azure machine provider API is not vendored, so the provider specs of the
+// MachineSets are raw, laid out as the installer lays out the provider specs of the compute machines it creates.
+func (a *AzureActuator) GenerateMachineSets(cd *hivev1.ClusterDeployment, pool *hivev1.MachinePool, logger log.FieldLogger) ([]*machineapi.MachineSet, error) {
+	if cd.Spec.ClusterMetadata == nil {
+		return nil, errors.New("ClusterDeployment does not have cluster metadata")
+	}
+	if cd.Spec.Platform.Azure == nil {
+		return nil, errors.New("ClusterDeployment is not for Azure")
+	}
+
+	// Not every Azure region has availability zones, and the zones of a region cannot be looked up without the Azure
+	// compute API, so the pool has to list them
+	if pool.Spec.Platform.Azure == nil || len(pool.Spec.Platform.Azure.Zones) == 0 {
+		return nil, errors.New("MachinePool does not list its Azure availability zones")
+	}
+
+	infraID := cd.Spec.ClusterMetadata.InfraID
+	resources := newAzureClusterResources(cd.Spec.ClusterMetadata)
+	region := cd.Spec.Platform.Azure.Region
+	p := pool.Spec.Platform.Azure
+	zones := p.Zones
+	instanceType := defaultAzureInstanceType
+	if p.InstanceType != "" {
+		instanceType = p.InstanceType
+	}
+	diskSizeGB := int32(defaultAzureDiskSizeGB)
+	if p.OSDisk.DiskSizeGB != 0 {
+		diskSizeGB = p.OSDisk.DiskSizeGB
+	}
+
+	total := int64(0)
+	if pool.Spec.Replicas != nil {
+		total = *pool.Spec.Replicas
+	}
+	numOfZones := int64(len(zones))
+	machineSets := make([]*machineapi.MachineSet, 0, len(zones))
+	for idx, zone := range zones {
+		replicas := int32(total / numOfZones)
+		if int64(idx) < total%numOfZones {
+			replicas++
+		}
+		name := fmt.Sprintf("%s-%s-%s%s", infraID, pool.Spec.Name, region, zone)
+		ms := &machineapi.MachineSet{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: machineapi.SchemeGroupVersion.String(),
+				Kind:       "MachineSet",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: machineAPINamespace,
+				Name:      name,
+				Labels: map[string]string{
+					"machine.openshift.io/cluster-api-cluster": infraID,
+				},
+			},
+			Spec: machineapi.MachineSetSpec{
+				Replicas: &replicas,
+				Selector: metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						machineSetNameLabel:                        name,
+						"machine.openshift.io/cluster-api-cluster": infraID,
+					},
+				},
+				Template: machineapi.MachineTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							machineSetNameLabel:                             name,
+							"machine.openshift.io/cluster-api-cluster":      infraID,
+							"machine.openshift.io/cluster-api-machine-role": "worker",
+							"machine.openshift.io/cluster-api-machine-type": "worker",
+						},
+					},
+					Spec: machineapi.MachineSpec{
+						ProviderSpec: machineapi.ProviderSpec{
+							Value: &runtime.RawExtension{Raw: []byte("{}")},
+						},
+					},
+				},
+			},
+		}
+		if err := setProviderSpecFields(ms, map[string]interface{}{
+			"apiVersion":        "azureproviderconfig.openshift.io/v1beta1",
+			"kind":              "AzureMachineProviderSpec",
+			"userDataSecret":    map[string]interface{}{"name": "worker-user-data"},
+			"credentialsSecret": map[string]interface{}{"name": "azure-cloud-credentials", "namespace": machineAPINamespace},
+			"location":          region,
+			"zone":              zone,
+			"vmSize":            instanceType,
+			"image":             map[string]interface{}{"resourceID": resources.imageResourceID},
+			"osDisk": map[string]interface{}{
+				"osType":      "Linux",
+				"diskSizeGB":  diskSizeGB,
+				"managedDisk": map[string]interface{}{"storageAccountType": "Premium_LRS"},
+			},
+			"subnet":               resources.subnet,
+			"vnet":                 resources.vnet,
+			"managedIdentity":      resources.managedIdentity,
+			"resourceGroup":        resources.resourceGroup,
+			"networkResourceGroup": resources.resourceGroup,
+			"publicIP":             false,
+		}); err != nil {
+			return nil, err
+		}
+		machineSets = append(machineSets, ms)
+	}
+	return machineSets, nil
+}
+
+// azureMachineSetZone returns the availability zone of a generated Azure machine set
+func azureMachineSetZone(ms *machineapi.MachineSet) string {
+	providerSpec := struct {
+		Zone string `json:"zone"`
+	}{}
+	if err := json.Unmarshal(ms.Spec.Template.Spec.ProviderSpec.Value.Raw, &providerSpec); err != nil {
+		return ""
+	}
+	return providerSpec.Zone
+}
@@ -0,0 +1,180 @@
+package remotemachineset
+
+import (
+	"encoding/json"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+
+	machineapi "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	hivev1aws "github.com/openshift/hive/pkg/apis/hive/v1/aws"
+	hivev1azure "github.com/openshift/hive/pkg/apis/hive/v1/azure"
+)
+
+const (
+	testAzureRegion = "eastus"
+
+	testAzureCredentialsSecret = "azure-credentials"
+)
+
+func TestAzureActuator(t *testing.T) {
+	tests := []struct {
+		name                string
+		clusterDeployment   *hivev1.ClusterDeployment
+		pool                *hivev1.MachinePool
+		expectedMachineSets []machineSetInfo
+		expectedDiskSizeGB  float64
+		expectedErr         bool
+	}{
+		{
+			name:              "generate machinesets for zones of pool",
+			clusterDeployment: testAzureClusterDeployment(),
+			pool:              testAzureMachinePool("worker", 4, []string{"1", "2", "3"}),
+			expectedMachineSets: []machineSetInfo{
+				{
+					name:         "foo-12345-worker-eastus1",
+					zone:         "1",
+					replicas:     2,
+					instanceType: "Standard_D4s_v3",
+				},
+				{
+					name:         "foo-12345-worker-eastus2",
+					zone:         "2",
+					replicas:     1,
+					instanceType: "Standard_D4s_v3",
+				},
+				{
+					name:         "foo-12345-worker-eastus3",
+					zone:         "3",
+					replicas:     1,
+					instanceType: "Standard_D4s_v3",
+				},
+			},
+			expectedDiskSizeGB: 128,
+		},
+		{
+			name:              "generate machinesets with disk size of pool",
+			clusterDeployment: testAzureClusterDeployment(),
+			pool: func() *hivev1.MachinePool {
+				pool := testAzureMachinePool("infra", 1, []string{"2"})
+				pool.Spec.Platform.Azure.OSDisk.DiskSizeGB = 256
+				return pool
+			}(),
+			expectedMachineSets: []machineSetInfo{
+				{
+					name:         "foo-12345-infra-eastus2",
+					zone:         "2",
+					replicas:     1,
+					instanceType: "Standard_D4s_v3",
+				},
+			},
+			expectedDiskSizeGB: 256,
+		},
+		{
+			name: "cluster deployment not for Azure",
+			clusterDeployment: func() *hivev1.ClusterDeployment {
+				cd := testAzureClusterDeployment()
+				cd.Spec.Platform.Azure = nil
+				cd.Spec.Platform.AWS = &hivev1aws.Platform{}
+				return cd
+			}(),
+			pool:        testAzureMachinePool("worker", 3, []string{"1"}),
+			expectedErr: true,
+		},
+		{
+			name:              "pool without zones",
+			clusterDeployment: testAzureClusterDeployment(),
+			pool:              testAzureMachinePool("worker", 3, nil),
+			expectedErr:       true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aa, err := NewAzureActuator(log.WithField("actuator", "azureactuator"))
+			if !assert.NoError(t, err, "unexpected error creating Azure actuator") {
+				return
+			}
+
+			generatedMachineSets, err := aa.GenerateMachineSets(test.clusterDeployment, test.pool, aa.logger)
+
+			if test.expectedErr {
+				assert.Error(t, err, "expected error for test case")
+			} else if assert.NoError(t, err, "unexpected error for test case") {
+				validateAzureMachineSets(t, generatedMachineSets, test.expectedMachineSets, test.expectedDiskSizeGB)
+			}
+		})
+	}
+}
+
+func validateAzureMachineSets(t *testing.T, mSets []*machineapi.MachineSet, expectedMS []machineSetInfo, expectedDiskSizeGB float64) {
+	if !assert.Equal(t, len(expectedMS), len(mSets), "different number of machine sets generated than expected") {
+		return
+	}
+
+	for i, expected := range expectedMS {
+		assert.Equal(t, expected.name, mSets[i].Name, "did not find expected machineset: %s", expected.name)
+		assert.Equal(t, expected.name, mSets[i].Spec.Template.Labels["machine.openshift.io/cluster-api-machineset"], "unexpected machineset label")
+		assert.Equal(t, expected.replicas, *mSets[i].Spec.Replicas, "replica mismatch")
+		assert.Equal(t, expected.zone, machineSetZone(mSets[i]), "unexpected zone")
+
+		providerSpec := map[string]interface{}{}
+		if assert.NoError(t, json.Unmarshal(mSets[i].Spec.Template.Spec.ProviderSpec.Value.Raw, &providerSpec), "failed to decode provider spec") {
+			assert.Equal(t, testAzureProviderSpec(expected.zone, expected.instanceType, expectedDiskSizeGB), providerSpec, "unexpected provider spec")
+		}
+	}
+}
+
+// testAzureProviderSpec returns the provider spec of a compute machine in the given zone of the test cluster as laid
+// out by the installer
+func testAzureProviderSpec(zone, instanceType string, diskSizeGB float64) map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion":        "azureproviderconfig.openshift.io/v1beta1",
+		"kind":              "AzureMachineProviderSpec",
+		"userDataSecret":    map[string]interface{}{"name": "worker-user-data"},
+		"credentialsSecret": map[string]interface{}{"name": "azure-cloud-credentials", "namespace": "openshift-machine-api"},
+		"location":          testAzureRegion,
+		"zone":              zone,
+		"vmSize":            instanceType,
+		"image": map[string]interface{}{
+			"resourceID": "/resourceGroups/foo-12345-rg/providers/Microsoft.Compute/images/foo-12345",
+		},
+		"osDisk": map[string]interface{}{
+			"osType":      "Linux",
+			"diskSizeGB":  diskSizeGB,
+			"managedDisk": map[string]interface{}{"storageAccountType": "Premium_LRS"},
+		},
+		"subnet":               "foo-12345-worker-subnet",
+		"vnet":                 "foo-12345-vnet",
+		"managedIdentity":      "foo-12345-identity",
+		"resourceGroup":        "foo-12345-rg",
+		"networkResourceGroup": "foo-12345-rg",
+		"publicIP":             false,
+	}
+}
+
+func testAzureClusterDeployment() *hivev1.ClusterDeployment {
+	cd := testClusterDeployment()
+	cd.Spec.Platform.AWS = nil
+	cd.Spec.Platform.Azure = &hivev1azure.Platform{
+		CredentialsSecretRef:        corev1.LocalObjectReference{Name: testAzureCredentialsSecret},
+		Region:                      testAzureRegion,
+		BaseDomainResourceGroupName: "test-rg",
+	}
+	return cd
+}
+
+func testAzureMachinePool(name string, replicas int, zones []string) *hivev1.MachinePool {
+	pool := testMachinePool(name, replicas, nil)
+	pool.Spec.Platform.AWS = nil
+	pool.Spec.Platform.Azure = &hivev1azure.MachinePool{
+		Zones:        zones,
+		InstanceType: "Standard_D4s_v3",
+	}
+	return pool
+}
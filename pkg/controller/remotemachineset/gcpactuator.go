@@ -0,0 +1,172 @@
+package remotemachineset
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	gcpprovider "github.com/openshift/cluster-api-provider-gcp/pkg/apis/gcpprovider/v1beta1"
+	machineapi "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+
+	installgcp "github.com/openshift/installer/pkg/asset/machines/gcp"
+	installertypes "github.com/openshift/installer/pkg/types"
+	installertypesgcp "github.com/openshift/installer/pkg/types/gcp"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/gcpclient"
+)
+
+// installerWorkerPoolName is the name of the compute pool created by the installer. The installer names the GCP
+// MachineSets of a pool after the first letter of the pool.
+const installerWorkerPoolName = "worker"
+
+// GCPActuator encapsulates the pieces necessary to be able to generate
+// a list of MachineSets to sync to the remote cluster.
+type GCPActuator struct {
+	client gcpclient.Client
+	logger log.FieldLogger
+}
+
+var _ Actuator = &GCPActuator{}
+
+// NewGCPActuator is the constructor for building a GCPActuator
+func NewGCPActuator(gcpClient gcpclient.Client, logger log.FieldLogger) (*GCPActuator, error) {
+	actuator := &GCPActuator{
+		client: gcpClient,
+		logger: logger,
+	}
+	return actuator, nil
+}
+
+// GenerateMachineSets satisfies the Actuator interface and will take a clusterDeployment and return a list of MachineSets
+// to sync to the remote cluster.
+func (a *GCPActuator) GenerateMachineSets(cd *hivev1.ClusterDeployment, pool *hivev1.MachinePool, logger log.FieldLogger) ([]*machineapi.MachineSet, error) {
+	if cd.Spec.ClusterMetadata == nil {
+		return nil, errors.New("ClusterDeployment does not have cluster metadata")
+	}
+	if cd.Spec.Platform.GCP == nil {
+		return nil, errors.New("ClusterDeployment is not for GCP")
+	}
+
+	// Generate minimal/partial InstallConfig from ClusterDeployment
+	ic := &installertypes.InstallConfig{
+		Platform: installertypes.Platform{
+			GCP: &installertypesgcp.Platform{
+				ProjectID: cd.Spec.Platform.GCP.ProjectID,
+				Region:    cd.Spec.Platform.GCP.Region,
+			},
+		},
+	}
+
+	computePool := baseMachinePool(pool)
+	if computePool.Platform.GCP == nil {
+		computePool.Platform.GCP = &installertypesgcp.MachinePool{}
+	}
+	if len(computePool.Platform.GCP.Zones) == 0 {
+		zones, err := a.getZones(cd.Spec.Platform.GCP.Region)
+		if err != nil {
+			logger.WithError(err).Error("compute pool not providing list of zones and failed to fetch list of zones")
+			return nil, err
+		}
+		// Safety net from deleting machine sets. Do we expect to return 0 zones successfully?
+		if len(zones) == 0 {
+			return nil, fmt.Errorf("zero zones returned for region %s", cd.Spec.Platform.GCP.Region)
+		}
+		computePool.Platform.GCP.Zones = zones
+	}
+
+	// The installer uses the image it created for the cluster regardless of the image passed in
+	installerMachineSets, err := installgcp.MachineSets(cd.Spec.ClusterMetadata.InfraID, ic, computePool, "", "worker", "worker-user-data")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate machinesets")
+	}
+	if network := cd.Spec.Platform.GCP.Network; network != "" {
+		// The installer generates MachineSets in the network it creates for the cluster.
+		for _, ms := range installerMachineSets {
+			providerSpec := ms.Spec.Template.Spec.ProviderSpec.Value.Object.(*gcpprovider.GCPMachineProviderSpec)
+			for _, networkInterface := range providerSpec.NetworkInterfaces {
+				networkInterface.Network = network
+				networkInterface.Subnetwork = cd.Spec.Platform.GCP.ComputeSubnet
+			}
+			if networkProjectID := cd.Spec.Platform.GCP.NetworkProjectID; networkProjectID != "" {
+				// The vendored provider spec does not have the project of the network of a shared VPC.
+				networkInterfaces := make([]interface{}, len(providerSpec.NetworkInterfaces))
+				for i, networkInterface := range providerSpec.NetworkInterfaces {
+					networkInterfaces[i] = map[string]interface{}{
+						"publicIP":   networkInterface.PublicIP,
+						"network":    networkInterface.Network,
+						"subnetwork": networkInterface.Subnetwork,
+						"projectID":  networkProjectID,
+					}
+				}
+				if err := setProviderSpecFields(ms, map[string]interface{}{"networkInterfaces": networkInterfaces}); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	if pool.Spec.Platform.GCP != nil && pool.Spec.Platform.GCP.Preemptible {
+		// The vendored provider spec does not have the preemptible option of the machine API.
+		for _, ms := range installerMachineSets {
+			if err := setProviderSpecFields(ms, map[string]interface{}{"preemptible": true}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if pool.Spec.Name != installerWorkerPoolName {
+		// Pools other than the one created by the installer get the whole pool name in the name of their MachineSets,
+		// so that they do not collide with the MachineSets of other pools starting with the same letter.
+		for _, ms := range installerMachineSets {
+			zone := ms.Spec.Template.Spec.ProviderSpec.Value.Object.(*gcpprovider.GCPMachineProviderSpec).Zone
+			renameMachineSet(ms, fmt.Sprintf("%s-%s-%s",
+				cd.Spec.ClusterMetadata.InfraID,
+				pool.Spec.Name,
+				strings.TrimPrefix(zone, cd.Spec.Platform.GCP.Region+"-"),
+			))
+		}
+	}
+	return installerMachineSets, nil
+}
+
+func (a *GCPActuator) getZones(region string) ([]string, error) {
+	zones := []string{}
+
+	// Filter to regions matching '.*<region>.*' (where the zone is actually UP)
+	zoneFilter := fmt.Sprintf("(region eq '.*%s.*') (status eq UP)", region)
+
+	pageToken := ""
+
+	for {
+		zoneList, err := a.client.ListComputeZones(gcpclient.ListComputeZonesOptions{
+			Filter:    zoneFilter,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return zones, err
+		}
+
+		for _, zone := range zoneList.Items {
+			zones = append(zones, zone.Name)
+		}
+
+		if zoneList.NextPageToken == "" {
+			break
+		}
+		pageToken = zoneList.NextPageToken
+	}
+
+	return zones, nil
+}
+
+// renameMachineSet changes the name of a generated MachineSet along with the labels selecting its machines
+func renameMachineSet(ms *machineapi.MachineSet, name string) {
+	ms.Name = name
+	if _, ok := ms.Spec.Selector.MatchLabels[machineSetNameLabel]; ok {
+		ms.Spec.Selector.MatchLabels[machineSetNameLabel] = name
+	}
+	if _, ok := ms.Spec.Template.Labels[machineSetNameLabel]; ok {
+		ms.Spec.Template.Labels[machineSetNameLabel] = name
+	}
+}
@@ -0,0 +1,329 @@
+package remotemachineset
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	compute "google.golang.org/api/compute/v1"
+
+	corev1 "k8s.io/api/core/v1"
+
+	gcpprovider "github.com/openshift/cluster-api-provider-gcp/pkg/apis/gcpprovider/v1beta1"
+	machineapi "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	hivev1aws "github.com/openshift/hive/pkg/apis/hive/v1/aws"
+	hivev1gcp "github.com/openshift/hive/pkg/apis/hive/v1/gcp"
+	"github.com/openshift/hive/pkg/gcpclient"
+	mockgcp "github.com/openshift/hive/pkg/gcpclient/mock"
+)
+
+const (
+	testGCPRegion  = "us-east1"
+	testGCPProject = "test-project"
+
+	testGCPCredentialsSecret = "gcp-credentials"
+)
+
+type machineSetInfo struct {
+	name         string
+	zone         string
+	replicas     int32
+	instanceType string
+}
+
+func TestGCPActuator(t *testing.T) {
+	tests := []struct {
+		name                string
+		mockGCPClient       func(*mockgcp.MockClient)
+		clusterDeployment   *hivev1.ClusterDeployment
+		pool                *hivev1.MachinePool
+		expectedMachineSets []machineSetInfo
+		// expectedNetworkInterface is the network interface expected in the provider spec sent to the remote cluster
+		expectedNetworkInterface map[string]interface{}
+		expectedErr              bool
+	}{
+		{
+			name:              "generate single machineset for single zone",
+			clusterDeployment: testGCPClusterDeployment(),
+			pool:              testGCPMachinePool("worker", 3, nil),
+			mockGCPClient: func(client *mockgcp.MockClient) {
+				mockListComputeZones(client, []string{"us-east1-b"}, testGCPRegion)
+			},
+			expectedMachineSets: []machineSetInfo{
+				{
+					name:         "foo-12345-w-b",
+					zone:         "us-east1-b",
+					replicas:     3,
+					instanceType: "n1-standard-4",
+				},
+			},
+		},
+		{
+			name:              "generate machinesets across zones",
+			clusterDeployment: testGCPClusterDeployment(),
+			pool:              testGCPMachinePool("worker", 4, nil),
+			mockGCPClient: func(client *mockgcp.MockClient) {
+				mockListComputeZones(client, []string{"us-east1-b", "us-east1-c", "us-east1-d"}, testGCPRegion)
+			},
+			expectedMachineSets: []machineSetInfo{
+				{
+					name:         "foo-12345-w-b",
+					zone:         "us-east1-b",
+					replicas:     2,
+					instanceType: "n1-standard-4",
+				},
+				{
+					name:         "foo-12345-w-c",
+					zone:         "us-east1-c",
+					replicas:     1,
+					instanceType: "n1-standard-4",
+				},
+				{
+					name:         "foo-12345-w-d",
+					zone:         "us-east1-d",
+					replicas:     1,
+					instanceType: "n1-standard-4",
+				},
+			},
+		},
+		{
+			name:              "generate machinesets for zones of pool",
+			clusterDeployment: testGCPClusterDeployment(),
+			pool:              testGCPMachinePool("worker", 2, []string{"us-east1-c", "us-east1-d"}),
+			mockGCPClient:     func(client *mockgcp.MockClient) {},
+			expectedMachineSets: []machineSetInfo{
+				{
+					name:         "foo-12345-w-c",
+					zone:         "us-east1-c",
+					replicas:     1,
+					instanceType: "n1-standard-4",
+				},
+				{
+					name:         "foo-12345-w-d",
+					zone:         "us-east1-d",
+					replicas:     1,
+					instanceType: "n1-standard-4",
+				},
+			},
+		},
+		{
+			name:              "machinesets named after pool other than worker",
+			clusterDeployment: testGCPClusterDeployment(),
+			pool:              testGCPMachinePool("infra", 1, nil),
+			mockGCPClient: func(client *mockgcp.MockClient) {
+				mockListComputeZones(client, []string{"us-east1-b"}, testGCPRegion)
+			},
+			expectedMachineSets: []machineSetInfo{
+				{
+					name:         "foo-12345-infra-b",
+					zone:         "us-east1-b",
+					replicas:     1,
+					instanceType: "n1-standard-4",
+				},
+			},
+		},
+		{
+			name:              "machinesets in installer provided network",
+			clusterDeployment: testGCPClusterDeployment(),
+			pool:              testGCPMachinePool("worker", 1, []string{"us-east1-b"}),
+			mockGCPClient:     func(client *mockgcp.MockClient) {},
+			expectedMachineSets: []machineSetInfo{
+				{
+					name:         "foo-12345-w-b",
+					zone:         "us-east1-b",
+					replicas:     1,
+					instanceType: "n1-standard-4",
+				},
+			},
+			expectedNetworkInterface: map[string]interface{}{
+				"network":    "foo-12345-network",
+				"subnetwork": "foo-12345-worker-subnet",
+			},
+		},
+		{
+			name: "machinesets in existing network",
+			clusterDeployment: func() *hivev1.ClusterDeployment {
+				cd := testGCPClusterDeployment()
+				cd.Spec.Platform.GCP.Network = "test-network"
+				cd.Spec.Platform.GCP.ControlPlaneSubnet = "test-master-subnet"
+				cd.Spec.Platform.GCP.ComputeSubnet = "test-worker-subnet"
+				return cd
+			}(),
+			pool:          testGCPMachinePool("worker", 1, []string{"us-east1-b"}),
+			mockGCPClient: func(client *mockgcp.MockClient) {},
+			expectedMachineSets: []machineSetInfo{
+				{
+					name:         "foo-12345-w-b",
+					zone:         "us-east1-b",
+					replicas:     1,
+					instanceType: "n1-standard-4",
+				},
+			},
+			expectedNetworkInterface: map[string]interface{}{
+				"network":    "test-network",
+				"subnetwork": "test-worker-subnet",
+			},
+		},
+		{
+			name: "preemptible machinesets in shared network",
+			clusterDeployment: func() *hivev1.ClusterDeployment {
+				cd := testGCPClusterDeployment()
+				cd.Spec.Platform.GCP.Network = "test-network"
+				cd.Spec.Platform.GCP.NetworkProjectID = "test-host-project"
+				cd.Spec.Platform.GCP.ControlPlaneSubnet = "test-master-subnet"
+				cd.Spec.Platform.GCP.ComputeSubnet = "test-worker-subnet"
+				return cd
+			}(),
+			pool: func() *hivev1.MachinePool {
+				pool := testGCPMachinePool("worker", 1, []string{"us-east1-b"})
+				pool.Spec.Platform.GCP.Preemptible = true
+				return pool
+			}(),
+			mockGCPClient: func(client *mockgcp.MockClient) {},
+			expectedMachineSets: []machineSetInfo{
+				{
+					name:         "foo-12345-w-b",
+					zone:         "us-east1-b",
+					replicas:     1,
+					instanceType: "n1-standard-4",
+				},
+			},
+			expectedNetworkInterface: map[string]interface{}{
+				"publicIP":   false,
+				"network":    "test-network",
+				"subnetwork": "test-worker-subnet",
+				"projectID":  "test-host-project",
+			},
+		},
+		{
+			name:              "list zones returns zero",
+			clusterDeployment: testGCPClusterDeployment(),
+			pool:              testGCPMachinePool("worker", 3, nil),
+			mockGCPClient: func(client *mockgcp.MockClient) {
+				mockListComputeZones(client, []string{}, testGCPRegion)
+			},
+			expectedErr: true,
+		},
+		{
+			name: "cluster deployment not for GCP",
+			clusterDeployment: func() *hivev1.ClusterDeployment {
+				cd := testGCPClusterDeployment()
+				cd.Spec.Platform.GCP = nil
+				cd.Spec.Platform.AWS = &hivev1aws.Platform{}
+				return cd
+			}(),
+			pool:          testGCPMachinePool("worker", 3, nil),
+			mockGCPClient: func(client *mockgcp.MockClient) {},
+			expectedErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			gClient := mockgcp.NewMockClient(mockCtrl)
+
+			// set up mock expectations
+			test.mockGCPClient(gClient)
+
+			ga, err := NewGCPActuator(gClient, log.WithField("actuator", "gcpactuator"))
+			if !assert.NoError(t, err, "unexpected error creating GCP actuator") {
+				return
+			}
+
+			generatedMachineSets, err := ga.GenerateMachineSets(test.clusterDeployment, test.pool, ga.logger)
+
+			if test.expectedErr {
+				assert.Error(t, err, "expected error for test case")
+			} else if assert.NoError(t, err, "unexpected error for test case") {
+				validateGCPMachineSets(t, generatedMachineSets, test.expectedMachineSets)
+				if test.expectedNetworkInterface != nil {
+					validateGCPNetworkInterfaces(t, generatedMachineSets, test.expectedNetworkInterface)
+				}
+			}
+		})
+	}
+}
+
+func validateGCPMachineSets(t *testing.T, mSets []*machineapi.MachineSet, expectedMS []machineSetInfo) {
+	if !assert.Equal(t, len(expectedMS), len(mSets), "different number of machine sets generated than expected") {
+		return
+	}
+
+	for i, expected := range expectedMS {
+		assert.Equal(t, expected.name, mSets[i].Name, "did not find expected machineset: %s", expected.name)
+		assert.Equal(t, expected.name, mSets[i].Spec.Template.Labels["machine.openshift.io/cluster-api-machineset"], "unexpected machineset label")
+		assert.Equal(t, expected.replicas, *mSets[i].Spec.Replicas, "replica mismatch")
+
+		gcpProvider, ok := mSets[i].Spec.Template.Spec.ProviderSpec.Value.Object.(*gcpprovider.GCPMachineProviderSpec)
+		if assert.True(t, ok, "failed to convert to gcpProviderSpec") {
+			assert.Equal(t, expected.instanceType, gcpProvider.MachineType, "unexpected instance type")
+			assert.Equal(t, expected.zone, gcpProvider.Zone, "unexpected zone")
+			assert.Equal(t, testGCPProject, gcpProvider.ProjectID, "unexpected project")
+		}
+	}
+}
+
+// validateGCPNetworkInterfaces checks the network interfaces of the provider specs of the machine sets as sent to the
+// remote cluster
+func validateGCPNetworkInterfaces(t *testing.T, mSets []*machineapi.MachineSet, expected map[string]interface{}) {
+	for _, ms := range mSets {
+		raw, err := json.Marshal(ms.Spec.Template.Spec.ProviderSpec.Value)
+		if !assert.NoError(t, err, "unexpected error encoding provider spec") {
+			return
+		}
+		providerSpec := struct {
+			NetworkInterfaces []map[string]interface{} `json:"networkInterfaces"`
+		}{}
+		if assert.NoError(t, json.Unmarshal(raw, &providerSpec), "unexpected error decoding provider spec") {
+			assert.Equal(t, []map[string]interface{}{expected}, providerSpec.NetworkInterfaces, "unexpected network interfaces")
+		}
+	}
+}
+
+func mockListComputeZones(gClient *mockgcp.MockClient, zones []string, region string) {
+	zoneList := &compute.ZoneList{}
+
+	for _, zone := range zones {
+		zoneList.Items = append(zoneList.Items,
+			&compute.Zone{
+				Name: zone,
+			})
+	}
+
+	filter := gcpclient.ListComputeZonesOptions{
+		Filter: fmt.Sprintf("(region eq '.*%s.*') (status eq UP)", region),
+	}
+	gClient.EXPECT().ListComputeZones(gomock.Eq(filter)).Return(
+		zoneList, nil,
+	)
+}
+
+func testGCPClusterDeployment() *hivev1.ClusterDeployment {
+	cd := testClusterDeployment()
+	cd.Spec.Platform.AWS = nil
+	cd.Spec.Platform.GCP = &hivev1gcp.Platform{
+		CredentialsSecretRef: corev1.LocalObjectReference{Name: testGCPCredentialsSecret},
+		ProjectID:            testGCPProject,
+		Region:               testGCPRegion,
+	}
+	return cd
+}
+
+func testGCPMachinePool(name string, replicas int, zones []string) *hivev1.MachinePool {
+	pool := testMachinePool(name, replicas, nil)
+	pool.Spec.Platform.AWS = nil
+	pool.Spec.Platform.GCP = &hivev1gcp.MachinePool{
+		Zones:        zones,
+		InstanceType: "n1-standard-4",
+	}
+	return pool
+}
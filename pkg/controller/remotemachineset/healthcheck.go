@@ -0,0 +1,165 @@
+package remotemachineset
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	machineapi "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+)
+
+const machineSetNameLabel = "machine.openshift.io/cluster-api-machineset"
+
+// The healthchecking API of the machine API is not vendored, so the MachineHealthChecks of remote clusters are
+// handled as unstructured objects.
+var machineHealthCheckGVK = machineapi.SchemeGroupVersion.WithKind("MachineHealthCheck")
+
+// The defaults of the health checks of machine pools are those of the machine API, set explicitly so that unsetting
+// a field of a health check resets the field on the remote cluster.
+var (
+	defaultUnhealthyConditions = []hivev1.MachinePoolUnhealthyCondition{
+		{Type: corev1.NodeReady, Status: corev1.ConditionFalse, Timeout: metav1.Duration{Duration: 5 * time.Minute}},
+		{Type: corev1.NodeReady, Status: corev1.ConditionUnknown, Timeout: metav1.Duration{Duration: 5 * time.Minute}},
+	}
+	defaultMaxUnhealthy       = intstr.FromString("100%")
+	defaultNodeStartupTimeout = metav1.Duration{Duration: 10 * time.Minute}
+)
+
+// machineHealthCheckName returns the name of the MachineHealthCheck of a machine pool
+func machineHealthCheckName(cd *hivev1.ClusterDeployment, pool *hivev1.MachinePool) string {
+	return fmt.Sprintf("%s-%s", cd.Spec.ClusterMetadata.InfraID, pool.Spec.Name)
+}
+
+// syncMachineHealthCheck makes sure the remote cluster has a MachineHealthCheck selecting the machines of the
+// machine sets of a machine pool with a health check, and no MachineHealthCheck for a pool without a health check
+// or without machine sets.
+func syncMachineHealthCheck(
+	pool *hivev1.MachinePool,
+	cd *hivev1.ClusterDeployment,
+	machineSets []*machineapi.MachineSet,
+	remoteClusterAPIClient client.Client,
+	logger log.FieldLogger) error {
+
+	name := machineHealthCheckName(cd, pool)
+	mhcLog := logger.WithField("machinehealthcheck", name)
+	mhc := &unstructured.Unstructured{}
+	mhc.SetGroupVersionKind(machineHealthCheckGVK)
+	err := remoteClusterAPIClient.Get(context.Background(), client.ObjectKey{Namespace: machineAPINamespace, Name: name}, mhc)
+	if err != nil && !errors.IsNotFound(err) {
+		mhcLog.WithError(err).Error("unable to fetch machine health check")
+		return err
+	}
+	exists := err == nil
+
+	if pool.Spec.HealthCheck == nil || len(machineSets) == 0 {
+		if !exists {
+			return nil
+		}
+		mhcLog.Info("deleting machine health check")
+		if err := remoteClusterAPIClient.Delete(context.Background(), mhc); err != nil && !errors.IsNotFound(err) {
+			mhcLog.WithError(err).Error("unable to delete machine health check")
+			return err
+		}
+		return nil
+	}
+
+	desiredSpec := machineHealthCheckSpec(pool.Spec.HealthCheck, machineSets)
+	if exists {
+		observedSpec, _, _ := unstructured.NestedMap(mhc.Object, "spec")
+		inSync := true
+		for key, value := range desiredSpec {
+			// Fields that are not managed by hive are left as they are on the remote cluster
+			if !reflect.DeepEqual(observedSpec[key], value) {
+				inSync = false
+			}
+		}
+		if inSync {
+			return nil
+		}
+		mhcLog.WithFields(log.Fields{
+			"desired":  desiredSpec,
+			"observed": observedSpec,
+		}).Info("machine health check out of sync")
+	} else {
+		mhc.SetNamespace(machineAPINamespace)
+		mhc.SetName(name)
+		mhc.SetLabels(map[string]string{machinePoolNameLabel: pool.Spec.Name})
+	}
+
+	for key, value := range desiredSpec {
+		if err := unstructured.SetNestedField(mhc.Object, value, "spec", key); err != nil {
+			return fmt.Errorf("unable to set spec of machine health check %s: %v", name, err)
+		}
+	}
+	if exists {
+		mhcLog.Info("updating machine health check")
+		err = remoteClusterAPIClient.Update(context.Background(), mhc)
+	} else {
+		mhcLog.Info("creating machine health check")
+		err = remoteClusterAPIClient.Create(context.Background(), mhc)
+	}
+	if err != nil {
+		mhcLog.WithError(err).Error("unable to sync machine health check")
+	}
+	return err
+}
+
+// machineHealthCheckSpec returns the unstructured spec of the MachineHealthCheck of a machine pool
+func machineHealthCheckSpec(healthCheck *hivev1.MachinePoolHealthCheck, machineSets []*machineapi.MachineSet) map[string]interface{} {
+	machineSetNames := make([]interface{}, len(machineSets))
+	for i, ms := range machineSets {
+		machineSetNames[i] = ms.Name
+	}
+	conditions := healthCheck.UnhealthyConditions
+	if len(conditions) == 0 {
+		conditions = defaultUnhealthyConditions
+	}
+	unhealthyConditions := make([]interface{}, len(conditions))
+	for i, condition := range conditions {
+		unhealthyConditions[i] = map[string]interface{}{
+			"type":    string(condition.Type),
+			"status":  string(condition.Status),
+			"timeout": condition.Timeout.Duration.String(),
+		}
+	}
+
+	spec := map[string]interface{}{
+		"selector": map[string]interface{}{
+			"matchExpressions": []interface{}{
+				map[string]interface{}{
+					"key":      machineSetNameLabel,
+					"operator": string(metav1.LabelSelectorOpIn),
+					"values":   machineSetNames,
+				},
+			},
+		},
+		"unhealthyConditions": unhealthyConditions,
+	}
+	maxUnhealthy := &defaultMaxUnhealthy
+	if healthCheck.MaxUnhealthy != nil {
+		maxUnhealthy = healthCheck.MaxUnhealthy
+	}
+	if maxUnhealthy.Type == intstr.Int {
+		spec["maxUnhealthy"] = int64(maxUnhealthy.IntVal)
+	} else {
+		spec["maxUnhealthy"] = maxUnhealthy.StrVal
+	}
+	nodeStartupTimeout := &defaultNodeStartupTimeout
+	if healthCheck.NodeStartupTimeout != nil {
+		nodeStartupTimeout = healthCheck.NodeStartupTimeout
+	}
+	spec["nodeStartupTimeout"] = nodeStartupTimeout.Duration.String()
+	return spec
+}
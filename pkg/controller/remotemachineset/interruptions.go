@@ -0,0 +1,152 @@
+package remotemachineset
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	machineapi "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/awsclient"
+	"github.com/openshift/hive/pkg/gcpclient"
+)
+
+const (
+	// awsSpotTerminationReason is the state reason code of spot instances terminated by AWS
+	awsSpotTerminationReason = "Server.SpotInstanceTermination"
+
+	// gcpTerminatedStatus is the status of stopped instances, including preempted instances
+	gcpTerminatedStatus = "TERMINATED"
+)
+
+// isInterruptible returns true for machine pools of spot or preemptible instances
+func isInterruptible(pool *hivev1.MachinePool) bool {
+	return (pool.Spec.Platform.AWS != nil && pool.Spec.Platform.AWS.SpotMarketOptions != nil) ||
+		(pool.Spec.Platform.GCP != nil && pool.Spec.Platform.GCP.Preemptible)
+}
+
+// setRecentInterruptions sets the number of instances of an interruptible machine pool that were recently
+// interrupted by the cloud provider. The count is left as is when the cloud provider cannot be queried.
+func (r *ReconcileRemoteMachineSet) setRecentInterruptions(
+	pool *hivev1.MachinePool,
+	cd *hivev1.ClusterDeployment,
+	machineSets []*machineapi.MachineSet,
+	logger log.FieldLogger) {
+
+	if !isInterruptible(pool) || len(machineSets) == 0 {
+		pool.Status.RecentInterruptions = 0
+		return
+	}
+	machineSetNames := make([]string, len(machineSets))
+	for i, ms := range machineSets {
+		machineSetNames[i] = ms.Name
+	}
+
+	var interruptions int32
+	var err error
+	switch {
+	case cd.Spec.Platform.AWS != nil:
+		var awsClient awsclient.Client
+		if awsClient, err = r.getAWSClient(cd); err == nil {
+			interruptions, err = countAWSInterruptions(awsClient, cd.Spec.ClusterMetadata.InfraID, machineSetNames)
+		}
+	case cd.Spec.Platform.GCP != nil:
+		var gcpClient gcpclient.Client
+		if gcpClient, err = r.getGCPClient(cd, logger); err == nil {
+			interruptions, err = countGCPInterruptions(gcpClient, cd.Spec.ClusterMetadata.InfraID, machineSetNames)
+		}
+	}
+	if err != nil {
+		logger.WithError(err).Warn("unable to count interrupted instances of machine pool")
+		return
+	}
+	if interruptions > 0 {
+		logger.WithField("interruptions", interruptions).Info("instances of machine pool recently interrupted")
+	}
+	pool.Status.RecentInterruptions = interruptions
+}
+
+// countAWSInterruptions counts the spot instances of the machine sets that were terminated by AWS. AWS keeps
+// terminated instances visible for about an hour, so all of them are recent.
+func countAWSInterruptions(awsClient awsclient.Client, infraID string, machineSetNames []string) (int32, error) {
+	input := &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("tag:kubernetes.io/cluster/" + infraID),
+				Values: aws.StringSlice([]string{"owned"}),
+			},
+			{
+				Name:   aws.String("instance-lifecycle"),
+				Values: aws.StringSlice([]string{"spot"}),
+			},
+			{
+				Name:   aws.String("state-reason-code"),
+				Values: aws.StringSlice([]string{awsSpotTerminationReason}),
+			},
+		},
+	}
+	var interruptions int32
+	for {
+		out, err := awsClient.DescribeInstances(input)
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to describe instances")
+		}
+		for _, reservation := range out.Reservations {
+			for _, instance := range reservation.Instances {
+				for _, tag := range instance.Tags {
+					if aws.StringValue(tag.Key) == "Name" && isMachineSetInstance(aws.StringValue(tag.Value), machineSetNames) {
+						interruptions++
+					}
+				}
+			}
+		}
+		if aws.StringValue(out.NextToken) == "" {
+			break
+		}
+		input.NextToken = out.NextToken
+	}
+	return interruptions, nil
+}
+
+// countGCPInterruptions counts the preemptible instances of the machine sets that are stopped. GCP stops preempted
+// instances, and the machine API replaces them, so the stopped instances are the recently preempted ones.
+func countGCPInterruptions(gcpClient gcpclient.Client, infraID string, machineSetNames []string) (int32, error) {
+	opts := gcpclient.ListComputeInstancesOptions{Filter: fmt.Sprintf("name eq \"%s-.*\"", infraID)}
+	var interruptions int32
+	for {
+		list, err := gcpClient.ListComputeInstances(opts)
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to list instances")
+		}
+		for _, scopedList := range list.Items {
+			for _, instance := range scopedList.Instances {
+				if instance.Scheduling != nil && instance.Scheduling.Preemptible &&
+					instance.Status == gcpTerminatedStatus &&
+					isMachineSetInstance(instance.Name, machineSetNames) {
+					interruptions++
+				}
+			}
+		}
+		if list.NextPageToken == "" {
+			break
+		}
+		opts.PageToken = list.NextPageToken
+	}
+	return interruptions, nil
+}
+
+// isMachineSetInstance returns true for the instances of the machines of the machine sets, which are named after
+// their machine set
+func isMachineSetInstance(name string, machineSetNames []string) bool {
+	for _, msName := range machineSetNames {
+		if strings.HasPrefix(name, msName+"-") {
+			return true
+		}
+	}
+	return false
+}
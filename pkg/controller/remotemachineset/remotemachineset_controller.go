@@ -1,7 +1,6 @@
 package remotemachineset
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"reflect"
@@ -14,10 +13,8 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/runtime/serializer"
-	jsonserializer "k8s.io/apimachinery/pkg/runtime/serializer/json"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/utils/pointer"
+	"k8s.io/apimachinery/pkg/util/cache"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -26,20 +23,16 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/service/ec2"
-
 	"github.com/openshift/library-go/pkg/operator/resource/resourcemerge"
 
 	machineapi "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
-	awsprovider "sigs.k8s.io/cluster-api-provider-aws/pkg/apis/awsproviderconfig/v1beta1"
 
-	awsmachines "github.com/openshift/installer/pkg/asset/machines/aws"
 	installertypes "github.com/openshift/installer/pkg/types"
 	installeraws "github.com/openshift/installer/pkg/types/aws"
 	installerazure "github.com/openshift/installer/pkg/types/azure"
 	installergcp "github.com/openshift/installer/pkg/types/gcp"
 
+	apihelpers "github.com/openshift/hive/pkg/apis/helpers"
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
 	hivev1aws "github.com/openshift/hive/pkg/apis/hive/v1/aws"
 	hivev1azure "github.com/openshift/hive/pkg/apis/hive/v1/azure"
@@ -48,6 +41,7 @@ import (
 	"github.com/openshift/hive/pkg/constants"
 	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
 	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	"github.com/openshift/hive/pkg/gcpclient"
 )
 
 const (
@@ -55,6 +49,10 @@ const (
 
 	machinePoolNameLabel = "hive.openshift.io/machine-pool"
 	finalizer            = "hive.openshift.io/remotemachineset"
+
+	// legacyMachineSetsSyncSetRecheckInterval is how long to wait for the syncset instance of the machinesets syncset
+	// of the former syncmachineset controller to stop syncing before deleting the syncset
+	legacyMachineSetsSyncSetRecheckInterval = 10 * time.Second
 )
 
 // Add creates a new RemoteMachineSet Controller and adds it to the Manager with default RBAC. The Manager will set fields on the
@@ -64,8 +62,10 @@ func Add(mgr manager.Manager) error {
 		Client:                        controllerutils.NewClientWithMetricsOrDie(mgr, controllerName),
 		scheme:                        mgr.GetScheme(),
 		logger:                        log.WithField("controller", controllerName),
-		remoteClusterAPIClientBuilder: controllerutils.BuildClusterAPIClientFromKubeconfig,
+		remoteClusterAPIClientBuilder: controllerutils.RemoteClusters.ClusterAPIClient,
 		awsClientBuilder:              awsclient.NewClient,
+		gcpClientBuilder:              gcpclient.NewClientFromSecret,
+		privateSubnetsCache:           cache.NewLRUExpireCache(privateSubnetsCacheSize),
 	}
 
 	// Create a new controller
@@ -135,6 +135,13 @@ type ReconcileRemoteMachineSet struct {
 
 	// awsClientBuilder is a function pointer to the function that builds the aws client
 	awsClientBuilder func(kClient client.Client, secretName, namespace, region string) (awsclient.Client, error)
+
+	// gcpClientBuilder is a function pointer to the function that builds the gcp client
+	gcpClientBuilder func(secret *kapi.Secret) (gcpclient.Client, error)
+
+	// privateSubnetsCache holds the private subnets of AWS clusters installed in existing subnets, which are
+	// looked up on every reconcile when it is nil
+	privateSubnetsCache *cache.LRUExpireCache
 }
 
 // Reconcile reads that state of the cluster for a MachinePool object and makes changes to the
@@ -164,6 +171,7 @@ func (r *ReconcileRemoteMachineSet) Reconcile(request reconcile.Request) (reconc
 		log.WithError(err).Error("error looking up machine pool")
 		return reconcile.Result{}, err
 	}
+	originalStatus := pool.Status.DeepCopy()
 
 	if !controllerutils.HasFinalizer(pool, finalizer) {
 		if pool.DeletionTimestamp != nil {
@@ -212,10 +220,19 @@ func (r *ReconcileRemoteMachineSet) Reconcile(request reconcile.Request) (reconc
 		return reconcile.Result{}, nil
 	}
 
-	if cd.Spec.Platform.AWS == nil {
-		// TODO: add support for GCP and azure
-		cdLog.Warn("skipping machine set management for unsupported cloud platform")
-		return reconcile.Result{}, nil
+	if cd.Spec.Platform.GCP != nil {
+		deleted, err := r.deleteLegacyMachineSetsSyncSet(cd, cdLog)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if !deleted {
+			return reconcile.Result{RequeueAfter: legacyMachineSetsSyncSetRecheckInterval}, nil
+		}
+	}
+
+	if !checkSupportedConfiguration(pool, cd) {
+		cdLog.Warn("skipping machine set management for unsupported configuration")
+		return reconcile.Result{}, r.updatePoolStatus(pool, originalStatus, cdLog)
 	}
 
 	if !controllerutils.HasFinalizer(pool, finalizer) {
@@ -256,7 +273,7 @@ func (r *ReconcileRemoteMachineSet) Reconcile(request reconcile.Request) (reconc
 		return r.removeFinalizer(pool)
 	}
 
-	return reconcile.Result{}, nil
+	return reconcile.Result{}, r.updatePoolStatus(pool, originalStatus, cdLog)
 }
 
 func (r *ReconcileRemoteMachineSet) syncMachineSets(
@@ -286,35 +303,8 @@ func (r *ReconcileRemoteMachineSet) syncMachineSets(
 	machineSetsToUpdate := []*machineapi.MachineSet{}
 
 	if pool.DeletionTimestamp == nil {
-		// Scan the pre-existing machinesets to find an AMI ID we can use if we need to create
-		// new machinesets.
-		// TODO: this will need work at some point in the future, ideally the AMI should come from
-		// release image someday, hopefully we can hold off until that is the case, and look it up when
-		// we extract installer image refs.
-		var amiID string
-		for _, ms := range remoteMachineSets.Items {
-			awsProviderSpec, err := decodeAWSMachineProviderSpec(ms.Spec.Template.Spec.ProviderSpec.Value, r.scheme)
-			if err != nil {
-				cdLog.WithError(err).Warn("error decoding AWSMachineProviderConfig, skipping MachineSet for AMI check")
-				continue
-			}
-			if awsProviderSpec.AMI.ID == nil {
-				// Really weird, but keep looking...
-				continue
-			}
-			amiID = *awsProviderSpec.AMI.ID
-			cdLog.WithFields(log.Fields{
-				"fromRemoteMachineSet": ms.Name,
-				"ami":                  amiID,
-			}).Debug("resolved AMI to use for new machinesets")
-			break
-		}
-		if amiID == "" {
-			return fmt.Errorf("unable to locate AMI to use from pre-existing machine set")
-		}
-
 		// Generate expected MachineSets for machine pool
-		generatedMachineSets, err = r.generateMachineSetsForMachinePool(cd, pool, amiID)
+		generatedMachineSets, err = r.generateMachineSetsForMachinePool(cd, pool, remoteMachineSets.Items, cdLog)
 		if err != nil {
 			cdLog.WithError(err).Error("unable to generate machine sets for machine pool")
 			return err
@@ -322,8 +312,28 @@ func (r *ReconcileRemoteMachineSet) syncMachineSets(
 
 		cdLog.Infof("generated %v worker machine sets", len(generatedMachineSets))
 
+		if !checkEnoughReplicas(pool, len(generatedMachineSets)) {
+			cdLog.Warn("skipping machine set management for machine pool with not enough replicas")
+			return nil
+		}
+
+		if cd.Spec.Platform.AWS != nil {
+			switch ok, err := r.checkSubnets(pool, cd, generatedMachineSets, cdLog); {
+			case err != nil:
+				return err
+			case !ok:
+				cdLog.Warn("skipping machine set management for machine pool with invalid subnets")
+				return nil
+			}
+		}
+
 		// Find MachineSets that need updating/creating
-		for _, ms := range generatedMachineSets {
+		for i, ms := range generatedMachineSets {
+			if pool.Spec.Autoscaling != nil {
+				// New MachineSets start at their minimum replicas and are scaled from there by the autoscaler
+				minReplicas, _ := machineSetReplicaRange(pool.Spec.Autoscaling, i, len(generatedMachineSets))
+				ms.Spec.Replicas = &minReplicas
+			}
 			found := false
 			for _, rMS := range remoteMachineSets.Items {
 				if ms.Name == rMS.Name {
@@ -333,14 +343,21 @@ func (r *ReconcileRemoteMachineSet) syncMachineSets(
 					resourcemerge.EnsureObjectMeta(&objectMetaModified, &rMS.ObjectMeta, ms.ObjectMeta)
 					msLog := cdLog.WithField("machineset", rMS.Name)
 
-					if *rMS.Spec.Replicas != *ms.Spec.Replicas {
+					desiredReplicas := *ms.Spec.Replicas
+					if pool.Spec.Autoscaling != nil {
+						// The autoscaler manages the replicas of the MachineSet, but they are kept in its range
+						// for when the range of the machine pool changes
+						desiredReplicas = clampReplicas(*rMS.Spec.Replicas, pool.Spec.Autoscaling, i, len(generatedMachineSets))
+					}
+					if *rMS.Spec.Replicas != desiredReplicas {
 						msLog.WithFields(log.Fields{
-							"desired":  *ms.Spec.Replicas,
+							"desired":  desiredReplicas,
 							"observed": *rMS.Spec.Replicas,
 						}).Info("replicas out of sync")
-						rMS.Spec.Replicas = ms.Spec.Replicas
+						rMS.Spec.Replicas = &desiredReplicas
 						objectModified = true
 					}
+					ms.Spec.Replicas = rMS.Spec.Replicas
 
 					// Update if the labels on the remote machineset are different than the labels on the generated machineset.
 					// If the length of both labels is zero, then they match, even if one is a nil map and the other is an empty map.
@@ -419,128 +436,84 @@ func (r *ReconcileRemoteMachineSet) syncMachineSets(
 		}
 	}
 
+	if err := syncAutoscalers(pool, generatedMachineSets, machineSetsToDelete, remoteClusterAPIClient, cdLog); err != nil {
+		return err
+	}
+
+	if err := syncMachineHealthCheck(pool, cd, generatedMachineSets, remoteClusterAPIClient, cdLog); err != nil {
+		return err
+	}
+
+	setMachineSetsStatus(pool, generatedMachineSets, remoteMachineSets.Items)
+	r.setRecentInterruptions(pool, cd, generatedMachineSets, cdLog)
+
 	cdLog.Info("done reconciling machine sets for cluster deployment")
 	return nil
 }
 
 // generateMachineSetsForMachinePool generates expected MachineSets for a machine pool
 // using the installer MachineSets API for the MachinePool Platform.
-func (r *ReconcileRemoteMachineSet) generateMachineSetsForMachinePool(cd *hivev1.ClusterDeployment, pool *hivev1.MachinePool, defaultAMI string) ([]*machineapi.MachineSet, error) {
-	generatedMachineSets := []*machineapi.MachineSet{}
-	installerMachineSets := []machineapi.MachineSet{}
+func (r *ReconcileRemoteMachineSet) generateMachineSetsForMachinePool(
+	cd *hivev1.ClusterDeployment,
+	pool *hivev1.MachinePool,
+	remoteMachineSets []machineapi.MachineSet,
+	logger log.FieldLogger) ([]*machineapi.MachineSet, error) {
 
-	// Generate minimal/partial InstallConfig from ClusterDeployment
-	ic, err := r.generateInstallConfigForMachinePool(cd, pool)
+	actuator, err := r.createActuator(cd, remoteMachineSets, logger)
 	if err != nil {
 		return nil, err
 	}
-
-	// Generate expected MachineSets for Platform from InstallConfig
-	workerPool := ic.Compute[0]
-	switch ic.Platform.Name() {
-	case "aws":
-		if workerPool.Platform.AWS == nil {
-			workerPool.Platform.AWS = &installeraws.MachinePool{}
-		}
-		if len(workerPool.Platform.AWS.Zones) == 0 {
-			awsClient, err := r.getAWSClient(cd)
-			if err != nil {
-				return nil, err
-			}
-			azs, err := fetchAvailabilityZones(awsClient, ic.Platform.AWS.Region)
-			if err != nil {
-				return nil, err
-			}
-			// Safety net from deleting machine sets. Do we expect to return 0 availability zones successfully?
-			if len(azs) == 0 {
-				return nil, fmt.Errorf("fetched 0 availability zones")
-			}
-			workerPool.Platform.AWS.Zones = azs
+	generatedMachineSets, err := actuator.GenerateMachineSets(cd, pool, logger)
+	if err != nil {
+		return nil, err
+	}
+	for _, ms := range generatedMachineSets {
+		if ms.Labels == nil {
+			ms.Labels = map[string]string{}
 		}
+		ms.Labels[machinePoolNameLabel] = pool.Spec.Name
 
-		icMachineSets, err := awsmachines.MachineSets(cd.Spec.ClusterMetadata.InfraID, ic, &workerPool, defaultAMI, workerPool.Name, "worker-user-data")
-		if err != nil {
-			return nil, err
+		// Apply hive MachinePool labels to MachineSet MachineSpec.
+		ms.Spec.Template.Spec.ObjectMeta.Labels = make(map[string]string, len(pool.Spec.Labels))
+		for key, value := range pool.Spec.Labels {
+			ms.Spec.Template.Spec.ObjectMeta.Labels[key] = value
 		}
-		for _, ms := range icMachineSets {
-			if ms.Labels == nil {
-				ms.Labels = map[string]string{}
-			}
-			ms.Labels[machinePoolNameLabel] = pool.Spec.Name
-
-			// Apply hive MachinePool labels to MachineSet MachineSpec.
-			ms.Spec.Template.Spec.ObjectMeta.Labels = make(map[string]string, len(pool.Spec.Labels))
-			for key, value := range pool.Spec.Labels {
-				ms.Spec.Template.Spec.ObjectMeta.Labels[key] = value
-			}
-
-			// Apply hive MachinePool taints to MachineSet MachineSpec.
-			ms.Spec.Template.Spec.Taints = pool.Spec.Taints
 
-			// Re-use existing AWS resources for generated MachineSets.
-			updateMachineSetAWSMachineProviderConfig(ms, cd.Spec.ClusterMetadata.InfraID)
-			installerMachineSets = append(installerMachineSets, *ms)
-		}
-	// TODO: Add other platforms. openstack does not currently support openstack.MachineSets()
-	default:
-		return nil, fmt.Errorf("invalid platform")
-	}
-	// Convert installer API []machineapi.MachineSets to []*machineapi.MachineSets
-	for _, ms := range installerMachineSets {
-		nMS := ms
-		generatedMachineSets = append(generatedMachineSets, &nMS)
+		// Apply hive MachinePool taints to MachineSet MachineSpec.
+		ms.Spec.Template.Spec.Taints = pool.Spec.Taints
 	}
 	return generatedMachineSets, nil
 }
 
-// updateMachineSetAWSMachineProviderConfig modifies values in a MachineSet's AWSMachineProviderConfig.
-// Currently we modify the AWSMachineProviderConfig IAMInstanceProfile, Subnet and SecurityGroups such that
-// the values match the worker pool originally created by the installer.
-func updateMachineSetAWSMachineProviderConfig(machineSet *machineapi.MachineSet, infraID string) {
-	providerConfig := machineSet.Spec.Template.Spec.ProviderSpec.Value.Object.(*awsprovider.AWSMachineProviderConfig)
-
-	// TODO: assumptions about pre-existing objects by name here is quite dangerous, it's already
-	// broken on us once via renames in the installer. We need to start querying for what exists
-	// here.
-	providerConfig.IAMInstanceProfile = &awsprovider.AWSResourceReference{ID: pointer.StringPtr(fmt.Sprintf("%s-worker-profile", infraID))}
-	providerConfig.Subnet = awsprovider.AWSResourceReference{
-		Filters: []awsprovider.Filter{{
-			Name:   "tag:Name",
-			Values: []string{fmt.Sprintf("%s-private-%s", infraID, providerConfig.Placement.AvailabilityZone)},
-		}},
-	}
-	providerConfig.SecurityGroups = []awsprovider.AWSResourceReference{{
-		Filters: []awsprovider.Filter{{
-			Name:   "tag:Name",
-			Values: []string{fmt.Sprintf("%s-worker-sg", infraID)},
-		}},
-	}}
-	machineSet.Spec.Template.Spec.ProviderSpec = machineapi.ProviderSpec{
-		Value: &runtime.RawExtension{Object: providerConfig},
-	}
-}
+// createActuator creates the actuator generating the MachineSets for the platform of the cluster deployment
+func (r *ReconcileRemoteMachineSet) createActuator(
+	cd *hivev1.ClusterDeployment,
+	remoteMachineSets []machineapi.MachineSet,
+	logger log.FieldLogger) (Actuator, error) {
 
-// generateInstallConfigForMachinePool creates a partial InstallConfig containing just enough of the information we need
-// to reconcile machine pools. (most notably the platform information) We cannot load the InstallConfig
-// the cluster was created with as this data is meant to be provision time only, all future reconciliation is
-// driven by the ClusterDeployment.
-func (r *ReconcileRemoteMachineSet) generateInstallConfigForMachinePool(cd *hivev1.ClusterDeployment, pool *hivev1.MachinePool) (*installertypes.InstallConfig, error) {
-	// TODO: AWS only right now, add GCP and someday Azure
-	var ic *installertypes.InstallConfig
-	if cd.Spec.Platform.AWS != nil {
-		ic = &installertypes.InstallConfig{
-			Platform: installertypes.Platform{
-				AWS: &installeraws.Platform{
-					Region: cd.Spec.Platform.AWS.Region,
-				},
-			},
-			Compute: convertMachinePools(pool),
+	switch {
+	case cd.Spec.Platform.AWS != nil:
+		awsClient, err := r.getAWSClient(cd)
+		if err != nil {
+			return nil, err
+		}
+		return NewAWSActuator(awsClient, remoteMachineSets, r.privateSubnetsCache, r.scheme, logger)
+	case cd.Spec.Platform.GCP != nil:
+		gcpClient, err := r.getGCPClient(cd, logger)
+		if err != nil {
+			return nil, err
 		}
-	} else {
+		return NewGCPActuator(gcpClient, logger)
+	case cd.Spec.Platform.Azure != nil:
+		return NewAzureActuator(logger)
+	default:
 		return nil, fmt.Errorf("unsupported platform for remote machineset management")
 	}
+}
 
-	return ic, nil
+// baseMachinePool converts a machine pool to the installer machine pool that its MachineSets are generated from
+func baseMachinePool(pool *hivev1.MachinePool) *installertypes.MachinePool {
+	return &convertMachinePools(pool)[0]
 }
 
 func convertMachinePools(pools ...*hivev1.MachinePool) []installertypes.MachinePool {
@@ -619,54 +592,23 @@ func (r *ReconcileRemoteMachineSet) getAWSClient(cd *hivev1.ClusterDeployment) (
 	return awsClient, nil
 }
 
-// fetchAvailabilityZones fetches availability zones for the specified region
-func fetchAvailabilityZones(client awsclient.Client, region string) ([]string, error) {
-	zoneFilter := &ec2.Filter{
-		Name:   aws.String("region-name"),
-		Values: []*string{aws.String(region)},
-	}
-	req := &ec2.DescribeAvailabilityZonesInput{
-		Filters: []*ec2.Filter{zoneFilter},
-	}
-	resp, err := client.DescribeAvailabilityZones(req)
-	if err != nil {
+// getGCPClient generates a gcpclient
+func (r *ReconcileRemoteMachineSet) getGCPClient(cd *hivev1.ClusterDeployment, logger log.FieldLogger) (gcpclient.Client, error) {
+	secret := &kapi.Secret{}
+	if err := r.Get(
+		context.TODO(),
+		types.NamespacedName{Name: cd.Spec.Platform.GCP.CredentialsSecretRef.Name, Namespace: cd.Namespace},
+		secret,
+	); err != nil {
+		logger.WithError(err).Error("unable to fetch GCP credentials secret")
 		return nil, err
 	}
-	zones := []string{}
-	for _, zone := range resp.AvailabilityZones {
-		zones = append(zones, *zone.ZoneName)
-	}
-	return zones, nil
-}
-
-func decodeAWSMachineProviderSpec(rawExt *runtime.RawExtension, scheme *runtime.Scheme) (*awsprovider.AWSMachineProviderConfig, error) {
-	codecFactory := serializer.NewCodecFactory(scheme)
-	decoder := codecFactory.UniversalDecoder(awsprovider.SchemeGroupVersion)
-	if rawExt == nil {
-		return nil, fmt.Errorf("MachineSet has no ProviderSpec")
-	}
-	obj, gvk, err := decoder.Decode([]byte(rawExt.Raw), nil, nil)
-	if err != nil {
-		return nil, fmt.Errorf("could not decode AWS ProviderConfig: %v", err)
-	}
-	spec, ok := obj.(*awsprovider.AWSMachineProviderConfig)
-	if !ok {
-		return nil, fmt.Errorf("Unexpected object: %#v", gvk)
-	}
-	return spec, nil
-}
-
-func encodeAWSMachineProviderSpec(awsProviderSpec *awsprovider.AWSMachineProviderConfig, scheme *runtime.Scheme) (*runtime.RawExtension, error) {
-
-	serializer := jsonserializer.NewSerializer(jsonserializer.DefaultMetaFactory, scheme, scheme, false)
-	var buffer bytes.Buffer
-	err := serializer.Encode(awsProviderSpec, &buffer)
+	gcpClient, err := r.gcpClientBuilder(secret)
 	if err != nil {
+		logger.WithError(err).Error("unable to create GCP client")
 		return nil, err
 	}
-	return &runtime.RawExtension{
-		Raw: buffer.Bytes(),
-	}, nil
+	return gcpClient, nil
 }
 
 func isMachineSetControlledByMachinePool(cd *hivev1.ClusterDeployment, pool *hivev1.MachinePool, machineSet *machineapi.MachineSet) bool {
@@ -677,6 +619,59 @@ func isMachineSetControlledByMachinePool(cd *hivev1.ClusterDeployment, pool *hiv
 		machineSet.Labels[machinePoolNameLabel] == pool.Spec.Name
 }
 
+// deleteLegacyMachineSetsSyncSet deletes the SyncSet with the worker MachineSets of a GCP cluster deployment that was
+// kept by the former syncmachineset controller. The SyncSet would otherwise keep applying the MachineSets now managed
+// by this controller. The SyncSet syncs its resources, so deleting it right away would delete the MachineSets from the
+// cluster. It is switched to upsert first, and only deleted once its SyncSetInstance has picked up the change, leaving
+// the MachineSets in place. Returns whether the SyncSet is gone.
+func (r *ReconcileRemoteMachineSet) deleteLegacyMachineSetsSyncSet(cd *hivev1.ClusterDeployment, logger log.FieldLogger) (bool, error) {
+	syncSet := &hivev1.SyncSet{}
+	name := apihelpers.GetResourceName(cd.Name, "machinesets")
+	switch err := r.Get(context.TODO(), client.ObjectKey{Namespace: cd.Namespace, Name: name}, syncSet); {
+	case errors.IsNotFound(err):
+		return true, nil
+	case err != nil:
+		logger.WithError(err).Error("error looking up legacy machinesets syncset")
+		return false, err
+	}
+	if !metav1.IsControlledBy(syncSet, cd) {
+		return true, nil
+	}
+	logger = logger.WithField("syncset", name)
+
+	if syncSet.Spec.ResourceApplyMode != hivev1.UpsertResourceApplyMode {
+		logger.Info("switching legacy machinesets syncset to upsert")
+		syncSet.Spec.ResourceApplyMode = hivev1.UpsertResourceApplyMode
+		if err := r.Update(context.TODO(), syncSet); err != nil {
+			logger.WithError(err).Log(controllerutils.LogLevel(err), "error updating legacy machinesets syncset")
+			return false, err
+		}
+		return false, nil
+	}
+
+	ssiList := &hivev1.SyncSetInstanceList{}
+	if err := r.List(context.TODO(), ssiList, client.InNamespace(cd.Namespace)); err != nil {
+		logger.WithError(err).Error("error listing syncset instances")
+		return false, err
+	}
+	for _, ssi := range ssiList.Items {
+		if ssi.Spec.SyncSetRef == nil || ssi.Spec.SyncSetRef.Name != name {
+			continue
+		}
+		if ssi.Spec.ResourceApplyMode != hivev1.UpsertResourceApplyMode {
+			logger.WithField("syncSetInstance", ssi.Name).Debug("waiting for syncset instance to switch to upsert")
+			return false, nil
+		}
+	}
+
+	logger.Info("deleting legacy machinesets syncset")
+	if err := r.Delete(context.TODO(), syncSet); err != nil && !errors.IsNotFound(err) {
+		logger.WithError(err).Log(controllerutils.LogLevel(err), "error deleting legacy machinesets syncset")
+		return false, err
+	}
+	return true, nil
+}
+
 func (r *ReconcileRemoteMachineSet) removeFinalizer(pool *hivev1.MachinePool) (reconcile.Result, error) {
 	if !controllerutils.HasFinalizer(pool, finalizer) {
 		return reconcile.Result{}, nil
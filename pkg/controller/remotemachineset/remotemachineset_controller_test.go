@@ -6,15 +6,20 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/utils/pointer"
 
@@ -24,15 +29,20 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	compute "google.golang.org/api/compute/v1"
 
 	machineapi "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
 	"github.com/openshift/hive/pkg/apis"
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
 	hivev1aws "github.com/openshift/hive/pkg/apis/hive/v1/aws"
+	hivev1azure "github.com/openshift/hive/pkg/apis/hive/v1/azure"
+	hivev1gcp "github.com/openshift/hive/pkg/apis/hive/v1/gcp"
 	"github.com/openshift/hive/pkg/awsclient"
 	mockaws "github.com/openshift/hive/pkg/awsclient/mock"
 	"github.com/openshift/hive/pkg/constants"
 	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	"github.com/openshift/hive/pkg/gcpclient"
+	mockgcp "github.com/openshift/hive/pkg/gcpclient/mock"
 	awsprovider "sigs.k8s.io/cluster-api-provider-aws/pkg/apis/awsproviderconfig/v1beta1"
 )
 
@@ -41,7 +51,6 @@ const (
 	testNamespace            = "default"
 	testClusterID            = "foo-12345-uuid"
 	testInfraID              = "foo-12345"
-	machineAPINamespace      = "openshift-machine-api"
 	adminKubeconfigSecret    = "foo-admin-kubeconfig"
 	adminKubeconfigSecretKey = "kubeconfig"
 	adminPasswordSecret      = "foo-admin-creds"
@@ -89,6 +98,42 @@ func TestRemoteMachineSetReconcile(t *testing.T) {
 		expectErr                 bool
 		expectNoFinalizer         bool
 		expectedRemoteMachineSets *machineapi.MachineSetList
+		// expectedRemoteMachineAutoscalers are the min and max replicas of the expected remote machine
+		// autoscalers by name, or nil to not check them
+		expectedRemoteMachineAutoscalers map[string][2]int64
+		// expectedRemoteMachineHealthCheckSpec is the spec of the expected remote machine health check of the pool. An
+		// empty spec means that no remote machine health check is expected.
+		expectedRemoteMachineHealthCheckSpec map[string]interface{}
+		expectedPoolReplicas                 *int32
+		expectedPoolReadyReplicas            *int32
+		expectedMachineSetStatus             []hivev1.MachineSetStatus
+		// expectedConditions are the statuses of the expected conditions of the machine pool by type
+		expectedConditions map[hivev1.MachinePoolConditionType]corev1.ConditionStatus
+		// missingSubnetZones are the zones that have no subnets in the cluster
+		missingSubnetZones []string
+		// existingNetwork mocks the existing subnets of a cluster deployment from testExistingNetworkClusterDeployment
+		existingNetwork bool
+		// expectNoSubnetLookup fails the test when the subnets of the cluster are looked up
+		expectNoSubnetLookup bool
+		// expectedSubnetIDs are the IDs of the subnets of the expected remote machine sets by name
+		expectedSubnetIDs map[string]string
+		// expectedSecurityGroupIDs are the IDs of the security groups expected in the remote machine sets of
+		// expectedSubnetIDs in addition to the worker security group
+		expectedSecurityGroupIDs []string
+		gcpZones                 []string
+		// interruptedInstances are the names of the instances of the cluster that were recently interrupted
+		interruptedInstances        []string
+		expectedRecentInterruptions *int32
+		// expectedProviderSpecFields are fields expected in the provider spec of the remote machine sets of the pool
+		expectedProviderSpecFields map[string]interface{}
+		// expectLegacySyncSet is whether the machinesets syncset of the former syncmachineset controller is expected
+		// to remain, or nil to not check it
+		expectLegacySyncSet *bool
+		// expectedLegacySyncSetApplyMode is the expected resource apply mode of a remaining legacy machinesets syncset
+		expectedLegacySyncSetApplyMode hivev1.SyncSetResourceApplyMode
+		// keptRemoteMachineSets are the names of remote machine sets that are expected to still exist with the
+		// annotations they had before the reconcile, i.e. that were not deleted and created again
+		keptRemoteMachineSets []string
 	}{
 		{
 			name: "Kubeconfig doesn't exist yet",
@@ -409,6 +454,619 @@ func TestRemoteMachineSetReconcile(t *testing.T) {
 				}
 			}(),
 		},
+		{
+			name: "Create machine autoscalers",
+			localExisting: []runtime.Object{
+				testClusterDeployment(),
+				testAutoscalingMachinePool(3, 12),
+				testSecret(adminKubeconfigSecret, adminKubeconfigSecretKey, testName),
+			},
+			remoteExisting: []runtime.Object{
+				testMachineSet("foo-12345-worker-us-east-1a", "worker", true, 1, 0),
+				testMachineSet("foo-12345-worker-us-east-1b", "worker", true, 1, 0),
+				testMachineSet("foo-12345-worker-us-east-1c", "worker", true, 1, 0),
+			},
+			expectedRemoteMachineSets: func() *machineapi.MachineSetList {
+				return &machineapi.MachineSetList{
+					Items: []machineapi.MachineSet{
+						*testMachineSet("foo-12345-worker-us-east-1a", "worker", true, 1, 0),
+						*testMachineSet("foo-12345-worker-us-east-1b", "worker", true, 1, 0),
+						*testMachineSet("foo-12345-worker-us-east-1c", "worker", true, 1, 0),
+					},
+				}
+			}(),
+			expectedRemoteMachineAutoscalers: map[string][2]int64{
+				"foo-12345-worker-us-east-1a": {1, 4},
+				"foo-12345-worker-us-east-1b": {1, 4},
+				"foo-12345-worker-us-east-1c": {1, 4},
+			},
+			expectedPoolReplicas: pointer.Int32Ptr(3),
+		},
+		{
+			name: "Update machine autoscalers and bring replicas into range",
+			localExisting: []runtime.Object{
+				testClusterDeployment(),
+				testAutoscalingMachinePool(10, 11),
+				testSecret(adminKubeconfigSecret, adminKubeconfigSecretKey, testName),
+			},
+			remoteExisting: []runtime.Object{
+				testMachineSet("foo-12345-worker-us-east-1a", "worker", true, 1, 0),
+				testMachineSet("foo-12345-worker-us-east-1b", "worker", true, 5, 0),
+				testMachineSet("foo-12345-worker-us-east-1c", "worker", true, 3, 0),
+				testMachineAutoscaler("foo-12345-worker-us-east-1a", 1, 4),
+				testMachineAutoscaler("foo-12345-worker-us-east-1b", 1, 4),
+				testMachineAutoscaler("foo-12345-worker-us-east-1c", 1, 4),
+			},
+			expectedRemoteMachineSets: func() *machineapi.MachineSetList {
+				return &machineapi.MachineSetList{
+					Items: []machineapi.MachineSet{
+						*testMachineSet("foo-12345-worker-us-east-1a", "worker", true, 4, 1),
+						*testMachineSet("foo-12345-worker-us-east-1b", "worker", true, 4, 1),
+						*testMachineSet("foo-12345-worker-us-east-1c", "worker", true, 3, 0),
+					},
+				}
+			}(),
+			expectedRemoteMachineAutoscalers: map[string][2]int64{
+				"foo-12345-worker-us-east-1a": {4, 4},
+				"foo-12345-worker-us-east-1b": {3, 4},
+				"foo-12345-worker-us-east-1c": {3, 3},
+			},
+			expectedPoolReplicas: pointer.Int32Ptr(11),
+		},
+		{
+			name: "Delete machine autoscalers when autoscaling is disabled",
+			localExisting: []runtime.Object{
+				testClusterDeployment(),
+				testMachinePool("worker", 3, []string{}),
+				testSecret(adminKubeconfigSecret, adminKubeconfigSecretKey, testName),
+			},
+			remoteExisting: []runtime.Object{
+				testMachineSet("foo-12345-worker-us-east-1a", "worker", true, 1, 0),
+				testMachineSet("foo-12345-worker-us-east-1b", "worker", true, 1, 0),
+				testMachineSet("foo-12345-worker-us-east-1c", "worker", true, 1, 0),
+				testMachineAutoscaler("foo-12345-worker-us-east-1a", 1, 4),
+				testMachineAutoscaler("foo-12345-worker-us-east-1b", 1, 4),
+			},
+			expectedRemoteMachineSets: func() *machineapi.MachineSetList {
+				return &machineapi.MachineSetList{
+					Items: []machineapi.MachineSet{
+						*testMachineSet("foo-12345-worker-us-east-1a", "worker", true, 1, 0),
+						*testMachineSet("foo-12345-worker-us-east-1b", "worker", true, 1, 0),
+						*testMachineSet("foo-12345-worker-us-east-1c", "worker", true, 1, 0),
+					},
+				}
+			}(),
+			expectedRemoteMachineAutoscalers: map[string][2]int64{},
+			expectedPoolReplicas:             pointer.Int32Ptr(3),
+		},
+		{
+			name: "Create machine health check",
+			localExisting: []runtime.Object{
+				testClusterDeployment(),
+				func() runtime.Object {
+					pool := testMachinePool("worker", 3, []string{})
+					maxUnhealthy := intstr.FromString("40%")
+					pool.Spec.HealthCheck = &hivev1.MachinePoolHealthCheck{MaxUnhealthy: &maxUnhealthy}
+					return pool
+				}(),
+				testSecret(adminKubeconfigSecret, adminKubeconfigSecretKey, testName),
+			},
+			remoteExisting: []runtime.Object{
+				testMachineSet("foo-12345-worker-us-east-1a", "worker", true, 1, 0),
+				testMachineSet("foo-12345-worker-us-east-1b", "worker", true, 1, 0),
+				testMachineSet("foo-12345-worker-us-east-1c", "worker", true, 1, 0),
+			},
+			expectedRemoteMachineHealthCheckSpec: testMachineHealthCheckSpec(
+				[]interface{}{
+					map[string]interface{}{"type": "Ready", "status": "False", "timeout": "5m0s"},
+					map[string]interface{}{"type": "Ready", "status": "Unknown", "timeout": "5m0s"},
+				},
+				"40%",
+				"10m0s",
+			),
+		},
+		{
+			name: "Update machine health check",
+			localExisting: []runtime.Object{
+				testClusterDeployment(),
+				func() runtime.Object {
+					pool := testMachinePool("worker", 3, []string{})
+					pool.Spec.HealthCheck = &hivev1.MachinePoolHealthCheck{
+						UnhealthyConditions: []hivev1.MachinePoolUnhealthyCondition{
+							{Type: corev1.NodeReady, Status: corev1.ConditionUnknown, Timeout: metav1.Duration{Duration: 300 * time.Second}},
+						},
+						NodeStartupTimeout: &metav1.Duration{Duration: 20 * time.Minute},
+					}
+					return pool
+				}(),
+				testSecret(adminKubeconfigSecret, adminKubeconfigSecretKey, testName),
+			},
+			remoteExisting: []runtime.Object{
+				testMachineSet("foo-12345-worker-us-east-1a", "worker", true, 1, 0),
+				testMachineSet("foo-12345-worker-us-east-1b", "worker", true, 1, 0),
+				testMachineSet("foo-12345-worker-us-east-1c", "worker", true, 1, 0),
+				testMachineHealthCheck(testMachineHealthCheckSpec(
+					[]interface{}{
+						map[string]interface{}{"type": "Ready", "status": "False", "timeout": "5m0s"},
+					},
+					int64(2),
+					"10m0s",
+				)),
+			},
+			expectedRemoteMachineHealthCheckSpec: testMachineHealthCheckSpec(
+				[]interface{}{
+					map[string]interface{}{"type": "Ready", "status": "Unknown", "timeout": "5m0s"},
+				},
+				"100%",
+				"20m0s",
+			),
+		},
+		{
+			name: "Delete machine health check when health check is disabled",
+			localExisting: []runtime.Object{
+				testClusterDeployment(),
+				testMachinePool("worker", 3, []string{}),
+				testSecret(adminKubeconfigSecret, adminKubeconfigSecretKey, testName),
+			},
+			remoteExisting: []runtime.Object{
+				testMachineSet("foo-12345-worker-us-east-1a", "worker", true, 1, 0),
+				testMachineSet("foo-12345-worker-us-east-1b", "worker", true, 1, 0),
+				testMachineSet("foo-12345-worker-us-east-1c", "worker", true, 1, 0),
+				testMachineHealthCheck(testMachineHealthCheckSpec(nil, "100%", "10m0s")),
+			},
+			expectedRemoteMachineHealthCheckSpec: map[string]interface{}{},
+		},
+		{
+			name: "Max replicas less than machine sets",
+			localExisting: []runtime.Object{
+				testClusterDeployment(),
+				testAutoscalingMachinePool(1, 2),
+				testSecret(adminKubeconfigSecret, adminKubeconfigSecretKey, testName),
+			},
+			remoteExisting: []runtime.Object{
+				testMachineSet("foo-12345-worker-us-east-1a", "worker", true, 1, 0),
+				testMachineSet("foo-12345-worker-us-east-1b", "worker", true, 1, 0),
+				testMachineSet("foo-12345-worker-us-east-1c", "worker", true, 1, 0),
+			},
+			expectedRemoteMachineSets: func() *machineapi.MachineSetList {
+				return &machineapi.MachineSetList{
+					Items: []machineapi.MachineSet{
+						*testMachineSet("foo-12345-worker-us-east-1a", "worker", true, 1, 0),
+						*testMachineSet("foo-12345-worker-us-east-1b", "worker", true, 1, 0),
+						*testMachineSet("foo-12345-worker-us-east-1c", "worker", true, 1, 0),
+					},
+				}
+			}(),
+			expectedRemoteMachineAutoscalers: map[string][2]int64{},
+			expectedConditions: map[hivev1.MachinePoolConditionType]corev1.ConditionStatus{
+				hivev1.NotEnoughReplicasMachinePoolCondition: corev1.ConditionTrue,
+			},
+		},
+		{
+			name: "Machine set status",
+			localExisting: []runtime.Object{
+				testClusterDeployment(),
+				testMachinePool("worker", 3, []string{}),
+				testSecret(adminKubeconfigSecret, adminKubeconfigSecretKey, testName),
+			},
+			remoteExisting: []runtime.Object{
+				testMachineSetWithReadyReplicas("foo-12345-worker-us-east-1a", 1, 1),
+				testMachineSetWithReadyReplicas("foo-12345-worker-us-east-1b", 1, 0),
+				testMachineSetWithReadyReplicas("foo-12345-worker-us-east-1c", 1, 1),
+			},
+			expectedPoolReplicas:      pointer.Int32Ptr(3),
+			expectedPoolReadyReplicas: pointer.Int32Ptr(2),
+			expectedMachineSetStatus: []hivev1.MachineSetStatus{
+				{Name: "foo-12345-worker-us-east-1a", Zone: "us-east-1a", Replicas: 1, ReadyReplicas: 1},
+				{Name: "foo-12345-worker-us-east-1b", Zone: "us-east-1b", Replicas: 1},
+				{Name: "foo-12345-worker-us-east-1c", Zone: "us-east-1c", Replicas: 1, ReadyReplicas: 1},
+			},
+		},
+		{
+			name: "Autoscaled machine set status",
+			localExisting: []runtime.Object{
+				testClusterDeployment(),
+				testAutoscalingMachinePool(3, 7),
+				testSecret(adminKubeconfigSecret, adminKubeconfigSecretKey, testName),
+			},
+			remoteExisting: []runtime.Object{
+				testMachineSetWithReadyReplicas("foo-12345-worker-us-east-1a", 2, 2),
+				testMachineSetWithReadyReplicas("foo-12345-worker-us-east-1b", 1, 1),
+				testMachineSetWithReadyReplicas("foo-12345-worker-us-east-1c", 1, 1),
+			},
+			expectedPoolReplicas:      pointer.Int32Ptr(4),
+			expectedPoolReadyReplicas: pointer.Int32Ptr(4),
+			expectedMachineSetStatus: []hivev1.MachineSetStatus{
+				{Name: "foo-12345-worker-us-east-1a", Zone: "us-east-1a", Replicas: 2, ReadyReplicas: 2, MinReplicas: 1, MaxReplicas: 3},
+				{Name: "foo-12345-worker-us-east-1b", Zone: "us-east-1b", Replicas: 1, ReadyReplicas: 1, MinReplicas: 1, MaxReplicas: 2},
+				{Name: "foo-12345-worker-us-east-1c", Zone: "us-east-1c", Replicas: 1, ReadyReplicas: 1, MinReplicas: 1, MaxReplicas: 2},
+			},
+		},
+		{
+			name: "Missing subnets",
+			localExisting: []runtime.Object{
+				testClusterDeployment(),
+				testMachinePool("worker", 3, []string{}),
+				testSecret(adminKubeconfigSecret, adminKubeconfigSecretKey, testName),
+			},
+			remoteExisting: []runtime.Object{
+				testMachineSet("foo-12345-worker-us-east-1a", "worker", true, 1, 0),
+				testMachineSet("foo-12345-worker-us-east-1b", "worker", true, 1, 0),
+			},
+			missingSubnetZones: []string{"us-east-1c"},
+			expectedRemoteMachineSets: func() *machineapi.MachineSetList {
+				return &machineapi.MachineSetList{
+					Items: []machineapi.MachineSet{
+						*testMachineSet("foo-12345-worker-us-east-1a", "worker", true, 1, 0),
+						*testMachineSet("foo-12345-worker-us-east-1b", "worker", true, 1, 0),
+					},
+				}
+			}(),
+			expectedConditions: map[hivev1.MachinePoolConditionType]corev1.ConditionStatus{
+				hivev1.InvalidSubnetsMachinePoolCondition: corev1.ConditionTrue,
+			},
+		},
+		{
+			name: "Subnets found again",
+			localExisting: []runtime.Object{
+				testClusterDeployment(),
+				func() runtime.Object {
+					mp := testMachinePool("worker", 3, []string{})
+					mp.Status.Conditions = []hivev1.MachinePoolCondition{{
+						Type:   hivev1.InvalidSubnetsMachinePoolCondition,
+						Status: corev1.ConditionTrue,
+						Reason: "SubnetsNotFound",
+					}}
+					return mp
+				}(),
+				testSecret(adminKubeconfigSecret, adminKubeconfigSecretKey, testName),
+			},
+			remoteExisting: []runtime.Object{
+				testMachineSet("foo-12345-worker-us-east-1a", "worker", true, 1, 0),
+				testMachineSet("foo-12345-worker-us-east-1b", "worker", true, 1, 0),
+			},
+			expectedRemoteMachineSets: func() *machineapi.MachineSetList {
+				return &machineapi.MachineSetList{
+					Items: []machineapi.MachineSet{
+						*testMachineSet("foo-12345-worker-us-east-1a", "worker", true, 1, 0),
+						*testMachineSet("foo-12345-worker-us-east-1b", "worker", true, 1, 0),
+						*testMachineSet("foo-12345-worker-us-east-1c", "worker", false, 1, 0),
+					},
+				}
+			}(),
+			expectedConditions: map[hivev1.MachinePoolConditionType]corev1.ConditionStatus{
+				hivev1.InvalidSubnetsMachinePoolCondition: corev1.ConditionFalse,
+			},
+		},
+		{
+			name: "Subnets not looked up again for the same generation",
+			localExisting: []runtime.Object{
+				testClusterDeployment(),
+				func() runtime.Object {
+					mp := testMachinePool("worker", 3, []string{})
+					mp.Status.SubnetsCheckedGeneration = mp.Generation
+					return mp
+				}(),
+				testSecret(adminKubeconfigSecret, adminKubeconfigSecretKey, testName),
+			},
+			remoteExisting: []runtime.Object{
+				testMachineSet("foo-12345-worker-us-east-1a", "worker", true, 1, 0),
+				testMachineSet("foo-12345-worker-us-east-1b", "worker", true, 1, 0),
+				testMachineSet("foo-12345-worker-us-east-1c", "worker", true, 1, 0),
+			},
+			expectNoSubnetLookup: true,
+			expectedRemoteMachineSets: func() *machineapi.MachineSetList {
+				return &machineapi.MachineSetList{
+					Items: []machineapi.MachineSet{
+						*testMachineSet("foo-12345-worker-us-east-1a", "worker", true, 1, 0),
+						*testMachineSet("foo-12345-worker-us-east-1b", "worker", true, 1, 0),
+						*testMachineSet("foo-12345-worker-us-east-1c", "worker", true, 1, 0),
+					},
+				}
+			}(),
+		},
+		{
+			name: "Subnets looked up again for a new generation",
+			localExisting: []runtime.Object{
+				testClusterDeployment(),
+				func() runtime.Object {
+					mp := testMachinePool("worker", 3, []string{})
+					mp.Status.SubnetsCheckedGeneration = mp.Generation
+					mp.Generation++
+					return mp
+				}(),
+				testSecret(adminKubeconfigSecret, adminKubeconfigSecretKey, testName),
+			},
+			remoteExisting: []runtime.Object{
+				testMachineSet("foo-12345-worker-us-east-1a", "worker", true, 1, 0),
+				testMachineSet("foo-12345-worker-us-east-1b", "worker", true, 1, 0),
+			},
+			missingSubnetZones: []string{"us-east-1c"},
+			expectedRemoteMachineSets: func() *machineapi.MachineSetList {
+				return &machineapi.MachineSetList{
+					Items: []machineapi.MachineSet{
+						*testMachineSet("foo-12345-worker-us-east-1a", "worker", true, 1, 0),
+						*testMachineSet("foo-12345-worker-us-east-1b", "worker", true, 1, 0),
+					},
+				}
+			}(),
+			expectedConditions: map[hivev1.MachinePoolConditionType]corev1.ConditionStatus{
+				hivev1.InvalidSubnetsMachinePoolCondition: corev1.ConditionTrue,
+			},
+		},
+		{
+			name: "Machine pool platform mismatch",
+			localExisting: []runtime.Object{
+				func() runtime.Object {
+					cd := testClusterDeployment()
+					cd.Spec.Platform.AWS = nil
+					cd.Spec.Platform.Azure = &hivev1azure.Platform{Region: "eastus"}
+					return cd
+				}(),
+				testMachinePool("worker", 3, []string{}),
+				testSecret(adminKubeconfigSecret, adminKubeconfigSecretKey, testName),
+			},
+			expectedConditions: map[hivev1.MachinePoolConditionType]corev1.ConditionStatus{
+				hivev1.UnsupportedConfigurationMachinePoolCondition: corev1.ConditionTrue,
+			},
+		},
+		{
+			name: "Azure machine pool without zones",
+			localExisting: []runtime.Object{
+				testAzureClusterDeployment(),
+				testAzureMachinePool("worker", 3, []string{}),
+				testSecret(adminKubeconfigSecret, adminKubeconfigSecretKey, testName),
+			},
+			expectedConditions: map[hivev1.MachinePoolConditionType]corev1.ConditionStatus{
+				hivev1.UnsupportedConfigurationMachinePoolCondition: corev1.ConditionTrue,
+			},
+		},
+		{
+			name: "Create Azure machine sets",
+			localExisting: []runtime.Object{
+				testAzureClusterDeployment(),
+				testAzureMachinePool("worker", 3, []string{"1", "2", "3"}),
+				testSecret(adminKubeconfigSecret, adminKubeconfigSecretKey, testName),
+			},
+			expectedPoolReplicas: pointer.Int32Ptr(3),
+			expectedMachineSetStatus: []hivev1.MachineSetStatus{
+				{Name: "foo-12345-worker-eastus1", Zone: "1", Replicas: 1},
+				{Name: "foo-12345-worker-eastus2", Zone: "2", Replicas: 1},
+				{Name: "foo-12345-worker-eastus3", Zone: "3", Replicas: 1},
+			},
+			expectedProviderSpecFields: map[string]interface{}{
+				"kind":     "AzureMachineProviderSpec",
+				"location": "eastus",
+				"vmSize":   "Standard_D4s_v3",
+			},
+		},
+		{
+			name: "Create GCP machine sets",
+			localExisting: []runtime.Object{
+				testGCPClusterDeployment(),
+				testGCPMachinePool("worker", 3, []string{}),
+				testSecret(adminKubeconfigSecret, adminKubeconfigSecretKey, testName),
+				testSecret(testGCPCredentialsSecret, constants.GCPCredentialsName, "{}"),
+			},
+			gcpZones:             []string{"us-east1-b", "us-east1-c"},
+			expectedPoolReplicas: pointer.Int32Ptr(3),
+			expectedMachineSetStatus: []hivev1.MachineSetStatus{
+				{Name: "foo-12345-w-b", Zone: "us-east1-b", Replicas: 2},
+				{Name: "foo-12345-w-c", Zone: "us-east1-c", Replicas: 1},
+			},
+		},
+		{
+			name: "Create GCP preemptible machine sets",
+			localExisting: []runtime.Object{
+				testGCPClusterDeployment(),
+				func() runtime.Object {
+					pool := testGCPMachinePool("worker", 3, []string{})
+					pool.Spec.Platform.GCP.Preemptible = true
+					return pool
+				}(),
+				testSecret(adminKubeconfigSecret, adminKubeconfigSecretKey, testName),
+				testSecret(testGCPCredentialsSecret, constants.GCPCredentialsName, "{}"),
+			},
+			gcpZones:                    []string{"us-east1-b", "us-east1-c"},
+			interruptedInstances:        []string{"foo-12345-w-b-abcde", "foo-12345-m-0"},
+			expectedRecentInterruptions: pointer.Int32Ptr(1),
+			expectedProviderSpecFields: map[string]interface{}{
+				"preemptible": true,
+			},
+		},
+		{
+			name: "Switch legacy machinesets syncset to upsert before deleting it",
+			localExisting: []runtime.Object{
+				testGCPClusterDeployment(),
+				testGCPMachinePool("worker", 3, []string{}),
+				testLegacyMachineSetsSyncSet(true, hivev1.SyncResourceApplyMode),
+				testLegacyMachineSetsSyncSetInstance(hivev1.SyncResourceApplyMode),
+				testSecret(adminKubeconfigSecret, adminKubeconfigSecretKey, testName),
+				testSecret(testGCPCredentialsSecret, constants.GCPCredentialsName, "{}"),
+			},
+			remoteExisting: []runtime.Object{
+				testMachineSet("foo-12345-w-b", "worker", true, 2, 0),
+				testMachineSet("foo-12345-w-c", "worker", true, 1, 0),
+			},
+			expectLegacySyncSet:            pointer.BoolPtr(true),
+			expectedLegacySyncSetApplyMode: hivev1.UpsertResourceApplyMode,
+			keptRemoteMachineSets:          []string{"foo-12345-w-b", "foo-12345-w-c"},
+		},
+		{
+			name: "Wait for legacy machinesets syncset instance to switch to upsert",
+			localExisting: []runtime.Object{
+				testGCPClusterDeployment(),
+				testGCPMachinePool("worker", 3, []string{}),
+				testLegacyMachineSetsSyncSet(true, hivev1.UpsertResourceApplyMode),
+				testLegacyMachineSetsSyncSetInstance(hivev1.SyncResourceApplyMode),
+				testSecret(adminKubeconfigSecret, adminKubeconfigSecretKey, testName),
+				testSecret(testGCPCredentialsSecret, constants.GCPCredentialsName, "{}"),
+			},
+			remoteExisting: []runtime.Object{
+				testMachineSet("foo-12345-w-b", "worker", true, 2, 0),
+				testMachineSet("foo-12345-w-c", "worker", true, 1, 0),
+			},
+			expectLegacySyncSet:            pointer.BoolPtr(true),
+			expectedLegacySyncSetApplyMode: hivev1.UpsertResourceApplyMode,
+			keptRemoteMachineSets:          []string{"foo-12345-w-b", "foo-12345-w-c"},
+		},
+		{
+			name: "Delete legacy machinesets syncset and keep its machine sets",
+			localExisting: []runtime.Object{
+				testGCPClusterDeployment(),
+				testGCPMachinePool("worker", 3, []string{}),
+				testLegacyMachineSetsSyncSet(true, hivev1.UpsertResourceApplyMode),
+				testLegacyMachineSetsSyncSetInstance(hivev1.UpsertResourceApplyMode),
+				testSecret(adminKubeconfigSecret, adminKubeconfigSecretKey, testName),
+				testSecret(testGCPCredentialsSecret, constants.GCPCredentialsName, "{}"),
+			},
+			remoteExisting: []runtime.Object{
+				testMachineSet("foo-12345-w-b", "worker", true, 2, 0),
+				testMachineSet("foo-12345-w-c", "worker", true, 1, 0),
+			},
+			gcpZones:              []string{"us-east1-b", "us-east1-c"},
+			expectLegacySyncSet:   pointer.BoolPtr(false),
+			keptRemoteMachineSets: []string{"foo-12345-w-b", "foo-12345-w-c"},
+			expectedMachineSetStatus: []hivev1.MachineSetStatus{
+				{Name: "foo-12345-w-b", Zone: "us-east1-b", Replicas: 2},
+				{Name: "foo-12345-w-c", Zone: "us-east1-c", Replicas: 1},
+			},
+		},
+		{
+			name: "Keep user syncset named like legacy machinesets syncset",
+			localExisting: []runtime.Object{
+				testGCPClusterDeployment(),
+				testGCPMachinePool("worker", 3, []string{}),
+				testLegacyMachineSetsSyncSet(false, hivev1.SyncResourceApplyMode),
+				testSecret(adminKubeconfigSecret, adminKubeconfigSecretKey, testName),
+				testSecret(testGCPCredentialsSecret, constants.GCPCredentialsName, "{}"),
+			},
+			gcpZones:            []string{"us-east1-b", "us-east1-c"},
+			expectLegacySyncSet: pointer.BoolPtr(true),
+		},
+		{
+			name: "Create AWS spot machine sets",
+			localExisting: []runtime.Object{
+				testClusterDeployment(),
+				func() runtime.Object {
+					pool := testMachinePool("worker", 3, []string{})
+					pool.Spec.Platform.AWS.SpotMarketOptions = &hivev1aws.SpotMarketOptions{MaxPrice: pointer.StringPtr("0.05")}
+					return pool
+				}(),
+				testSecret(adminKubeconfigSecret, adminKubeconfigSecretKey, testName),
+			},
+			remoteExisting: []runtime.Object{
+				testMachineSet("foo-12345-infra-us-east-1a", "infra", true, 1, 0),
+			},
+			interruptedInstances:        []string{"foo-12345-worker-us-east-1a-abcde", "foo-12345-worker-us-east-1c-fghij", "foo-12345-infra-us-east-1a-klmno"},
+			expectedRecentInterruptions: pointer.Int32Ptr(2),
+			expectedProviderSpecFields: map[string]interface{}{
+				"spotMarketOptions": map[string]interface{}{"maxPrice": "0.05"},
+			},
+		},
+		{
+			name: "Not interruptible",
+			localExisting: []runtime.Object{
+				testClusterDeployment(),
+				func() runtime.Object {
+					pool := testMachinePool("worker", 3, []string{})
+					pool.Status.RecentInterruptions = 2
+					return pool
+				}(),
+				testSecret(adminKubeconfigSecret, adminKubeconfigSecretKey, testName),
+			},
+			remoteExisting: []runtime.Object{
+				testMachineSet("foo-12345-worker-us-east-1a", "worker", true, 1, 0),
+				testMachineSet("foo-12345-worker-us-east-1b", "worker", true, 1, 0),
+				testMachineSet("foo-12345-worker-us-east-1c", "worker", true, 1, 0),
+			},
+			expectedRecentInterruptions: pointer.Int32Ptr(0),
+		},
+		{
+			name: "Create machine sets in existing subnets",
+			localExisting: []runtime.Object{
+				testExistingNetworkClusterDeployment(),
+				testMachinePool("worker", 3, []string{}),
+				testSecret(adminKubeconfigSecret, adminKubeconfigSecretKey, testName),
+			},
+			remoteExisting: []runtime.Object{
+				testMachineSet("foo-12345-infra-us-east-1a", "infra", true, 1, 0),
+			},
+			existingNetwork: true,
+			expectedRemoteMachineSets: func() *machineapi.MachineSetList {
+				return &machineapi.MachineSetList{
+					Items: []machineapi.MachineSet{
+						*testMachineSet("foo-12345-infra-us-east-1a", "infra", true, 1, 0),
+						*testMachineSet("foo-12345-worker-us-east-1a", "worker", false, 2, 0),
+						*testMachineSet("foo-12345-worker-us-east-1b", "worker", false, 1, 0),
+					},
+				}
+			}(),
+			expectedSubnetIDs: map[string]string{
+				"foo-12345-worker-us-east-1a": "subnet-private-a",
+				"foo-12345-worker-us-east-1b": "subnet-private-b",
+			},
+			expectedSecurityGroupIDs: []string{"sg-additional"},
+		},
+		{
+			name: "No existing private subnet for zone",
+			localExisting: []runtime.Object{
+				testExistingNetworkClusterDeployment(),
+				testMachinePool("worker", 3, []string{"us-east-1a", "us-east-1c"}),
+				testSecret(adminKubeconfigSecret, adminKubeconfigSecretKey, testName),
+			},
+			remoteExisting: []runtime.Object{
+				testMachineSet("foo-12345-infra-us-east-1a", "infra", true, 1, 0),
+			},
+			existingNetwork: true,
+			expectedRemoteMachineSets: func() *machineapi.MachineSetList {
+				return &machineapi.MachineSetList{
+					Items: []machineapi.MachineSet{
+						*testMachineSet("foo-12345-infra-us-east-1a", "infra", true, 1, 0),
+					},
+				}
+			}(),
+			expectedConditions: map[hivev1.MachinePoolConditionType]corev1.ConditionStatus{
+				hivev1.InvalidSubnetsMachinePoolCondition: corev1.ConditionTrue,
+			},
+		},
+		{
+			name: "GCP machine pool on AWS cluster",
+			localExisting: []runtime.Object{
+				testClusterDeployment(),
+				testGCPMachinePool("worker", 3, []string{}),
+				testSecret(adminKubeconfigSecret, adminKubeconfigSecretKey, testName),
+			},
+			expectedConditions: map[hivev1.MachinePoolConditionType]corev1.ConditionStatus{
+				hivev1.UnsupportedConfigurationMachinePoolCondition: corev1.ConditionTrue,
+			},
+		},
+		{
+			name: "Machine pool platform mismatch",
+			localExisting: []runtime.Object{
+				testClusterDeployment(),
+				func() runtime.Object {
+					mp := testMachinePool("worker", 3, []string{})
+					mp.Spec.Platform.AWS = nil
+					mp.Spec.Platform.GCP = &hivev1gcp.MachinePool{InstanceType: "n1-standard-4"}
+					return mp
+				}(),
+				testSecret(adminKubeconfigSecret, adminKubeconfigSecretKey, testName),
+			},
+			remoteExisting: []runtime.Object{
+				testMachineSet("foo-12345-worker-us-east-1a", "worker", true, 1, 0),
+			},
+			expectedRemoteMachineSets: func() *machineapi.MachineSetList {
+				return &machineapi.MachineSetList{
+					Items: []machineapi.MachineSet{
+						*testMachineSet("foo-12345-worker-us-east-1a", "worker", true, 1, 0),
+					},
+				}
+			}(),
+			expectedConditions: map[hivev1.MachinePoolConditionType]corev1.ConditionStatus{
+				hivev1.UnsupportedConfigurationMachinePoolCondition: corev1.ConditionTrue,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -422,6 +1080,21 @@ func TestRemoteMachineSetReconcile(t *testing.T) {
 			mockAWSClient := mockaws.NewMockClient(mockCtrl)
 			// Test availability zone retrieval when zones have not been set for machine pool
 			mockTestAvailabilityZones(mockAWSClient, "us-east-1", []string{"us-east-1a", "us-east-1b", "us-east-1c"})
+			switch {
+			case test.expectNoSubnetLookup:
+			case test.existingNetwork:
+				mockTestExistingSubnets(mockAWSClient)
+			default:
+				mockTestSubnets(mockAWSClient, test.missingSubnetZones...)
+			}
+			mockGCPClient := mockgcp.NewMockClient(mockCtrl)
+			if test.gcpZones != nil {
+				mockListComputeZones(mockGCPClient, test.gcpZones, testGCPRegion)
+			}
+			if test.interruptedInstances != nil {
+				mockTestInterruptedInstances(mockAWSClient, test.interruptedInstances)
+				mockTestPreemptedInstances(mockGCPClient, test.interruptedInstances)
+			}
 
 			rcd := &ReconcileRemoteMachineSet{
 				Client: fakeClient,
@@ -433,6 +1106,9 @@ func TestRemoteMachineSetReconcile(t *testing.T) {
 				awsClientBuilder: func(client.Client, string, string, string) (awsclient.Client, error) {
 					return mockAWSClient, nil
 				},
+				gcpClientBuilder: func(*corev1.Secret) (gcpclient.Client, error) {
+					return mockGCPClient, nil
+				},
 			}
 			_, err := rcd.Reconcile(reconcile.Request{
 				NamespacedName: types.NamespacedName{
@@ -514,16 +1190,176 @@ func TestRemoteMachineSetReconcile(t *testing.T) {
 					}
 				}
 			}
+
+			if test.expectedRemoteMachineHealthCheckSpec != nil {
+				mhc := &unstructured.Unstructured{}
+				mhc.SetGroupVersionKind(machineHealthCheckGVK)
+				err := remoteFakeClient.Get(context.TODO(), client.ObjectKey{Namespace: machineAPINamespace, Name: "foo-12345-worker"}, mhc)
+				if len(test.expectedRemoteMachineHealthCheckSpec) == 0 {
+					assert.Error(t, err, "found unexpected remote machine health check")
+				} else if assert.NoError(t, err, "missing remote machine health check") {
+					spec, _, _ := unstructured.NestedMap(mhc.Object, "spec")
+					assert.Equal(t, test.expectedRemoteMachineHealthCheckSpec, spec, "unexpected spec of machine health check")
+				}
+			}
+
+			if test.expectedRemoteMachineAutoscalers != nil {
+				for _, ms := range []string{"foo-12345-worker-us-east-1a", "foo-12345-worker-us-east-1b", "foo-12345-worker-us-east-1c"} {
+					ma := &unstructured.Unstructured{}
+					ma.SetGroupVersionKind(machineAutoscalerGVK)
+					err := remoteFakeClient.Get(context.TODO(), client.ObjectKey{Namespace: machineAPINamespace, Name: ms}, ma)
+					expected, ok := test.expectedRemoteMachineAutoscalers[ms]
+					if !ok {
+						assert.Error(t, err, "found unexpected remote machine autoscaler %s", ms)
+						continue
+					}
+					if assert.NoError(t, err, "missing remote machine autoscaler %s", ms) {
+						minReplicas, _, _ := unstructured.NestedInt64(ma.Object, "spec", "minReplicas")
+						maxReplicas, _, _ := unstructured.NestedInt64(ma.Object, "spec", "maxReplicas")
+						assert.Equal(t, expected, [2]int64{minReplicas, maxReplicas}, "unexpected replicas of machine autoscaler %s", ms)
+						targetName, _, _ := unstructured.NestedString(ma.Object, "spec", "scaleTargetRef", "name")
+						assert.Equal(t, ms, targetName, "unexpected target of machine autoscaler %s", ms)
+					}
+				}
+				if len(test.expectedRemoteMachineAutoscalers) > 0 {
+					ca := &unstructured.Unstructured{}
+					ca.SetGroupVersionKind(clusterAutoscalerGVK)
+					assert.NoError(t, remoteFakeClient.Get(context.TODO(), client.ObjectKey{Name: clusterAutoscalerName}, ca), "missing remote cluster autoscaler")
+				}
+			}
+
+			if test.expectedPoolReplicas != nil {
+				if pool := getPool(fakeClient, "worker"); assert.NotNil(t, pool, "missing machinepool") {
+					assert.Equal(t, *test.expectedPoolReplicas, pool.Status.Replicas, "unexpected machine pool replicas")
+				}
+			}
+
+			if test.expectedPoolReadyReplicas != nil {
+				if pool := getPool(fakeClient, "worker"); assert.NotNil(t, pool, "missing machinepool") {
+					assert.Equal(t, *test.expectedPoolReadyReplicas, pool.Status.ReadyReplicas, "unexpected machine pool ready replicas")
+				}
+			}
+
+			if test.expectedMachineSetStatus != nil {
+				if pool := getPool(fakeClient, "worker"); assert.NotNil(t, pool, "missing machinepool") {
+					assert.Equal(t, test.expectedMachineSetStatus, pool.Status.MachineSets, "unexpected machine set status")
+				}
+			}
+
+			if test.expectedRecentInterruptions != nil {
+				if pool := getPool(fakeClient, "worker"); assert.NotNil(t, pool, "missing machinepool") {
+					assert.Equal(t, *test.expectedRecentInterruptions, pool.Status.RecentInterruptions, "unexpected recent interruptions")
+				}
+			}
+
+			if test.expectLegacySyncSet != nil {
+				ss := &hivev1.SyncSet{}
+				err := fakeClient.Get(context.TODO(), client.ObjectKey{Namespace: testNamespace, Name: "foo-machinesets"}, ss)
+				if *test.expectLegacySyncSet {
+					if assert.NoError(t, err, "missing syncset") && test.expectedLegacySyncSetApplyMode != "" {
+						assert.Equal(t, test.expectedLegacySyncSetApplyMode, ss.Spec.ResourceApplyMode, "unexpected resource apply mode of legacy machinesets syncset")
+					}
+				} else {
+					assert.True(t, errors.IsNotFound(err), "unexpected legacy machinesets syncset")
+				}
+			}
+
+			for _, name := range test.keptRemoteMachineSets {
+				ms := &machineapi.MachineSet{}
+				if assert.NoError(t, remoteFakeClient.Get(context.TODO(), client.ObjectKey{Namespace: machineAPINamespace, Name: name}, ms), "missing remote machineset %s", name) {
+					assert.Equal(t, "true", ms.Annotations["hive.openshift.io/unstomped"], "remote machineset %s was created again", name)
+				}
+			}
+
+			if test.expectedProviderSpecFields != nil {
+				rMSL, err := getRMSL(remoteFakeClient)
+				if assert.NoError(t, err) {
+					found := 0
+					for _, rMS := range rMSL.Items {
+						if rMS.Labels[machinePoolNameLabel] != "worker" {
+							continue
+						}
+						found++
+						providerSpec := map[string]interface{}{}
+						if assert.NoError(t, json.Unmarshal(rMS.Spec.Template.Spec.ProviderSpec.Value.Raw, &providerSpec)) {
+							for key, value := range test.expectedProviderSpecFields {
+								assert.Equal(t, value, providerSpec[key], "unexpected %s in provider spec of machineset %s", key, rMS.Name)
+							}
+						}
+					}
+					assert.NotZero(t, found, "no remote machinesets for machine pool")
+				}
+			}
+
+			if test.expectedSubnetIDs != nil {
+				rMSL, err := getRMSL(remoteFakeClient)
+				if assert.NoError(t, err) {
+					for _, rMS := range rMSL.Items {
+						expectedSubnetID, ok := test.expectedSubnetIDs[rMS.Name]
+						if !ok {
+							continue
+						}
+						providerConfig, err := decodeAWSMachineProviderSpec(rMS.Spec.Template.Spec.ProviderSpec.Value, scheme.Scheme)
+						if assert.NoError(t, err, "unable to decode provider spec of machineset %s", rMS.Name) {
+							assert.Equal(t, expectedSubnetID, aws.StringValue(providerConfig.Subnet.ID), "unexpected subnet of machineset %s", rMS.Name)
+							securityGroupIDs := []string{}
+							for _, sg := range providerConfig.SecurityGroups {
+								if sg.ID != nil {
+									securityGroupIDs = append(securityGroupIDs, *sg.ID)
+								}
+							}
+							assert.Equal(t, test.expectedSecurityGroupIDs, securityGroupIDs, "unexpected security groups of machineset %s", rMS.Name)
+						}
+					}
+				}
+			}
+
+			for conditionType, status := range test.expectedConditions {
+				if pool := getPool(fakeClient, "worker"); assert.NotNil(t, pool, "missing machinepool") {
+					cond := controllerutils.FindMachinePoolCondition(pool.Status.Conditions, conditionType)
+					if assert.NotNil(t, cond, "missing %s condition", conditionType) {
+						assert.Equal(t, status, cond.Status, "unexpected status of %s condition", conditionType)
+					}
+				}
+			}
 		})
 	}
 }
 
+func TestAWSActuatorPrivateSubnetsCache(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockAWSClient := mockaws.NewMockClient(mockCtrl)
+	// The subnets of each cluster deployment are only looked up once
+	subnetsOutput, routeTablesOutput := testExistingSubnetsOutputs()
+	mockAWSClient.EXPECT().DescribeSubnets(gomock.Any()).Return(subnetsOutput, nil).Times(2)
+	mockAWSClient.EXPECT().DescribeRouteTables(gomock.Any()).Return(routeTablesOutput, nil).Times(2)
+
+	actuator := &AWSActuator{
+		client:              mockAWSClient,
+		logger:              log.WithField("actuator", "awsactuator"),
+		amiID:               testAMI,
+		privateSubnetsCache: cache.NewLRUExpireCache(privateSubnetsCacheSize),
+	}
+	cd := testExistingNetworkClusterDeployment()
+	cd.UID = "cd-uid"
+	otherCD := testExistingNetworkClusterDeployment()
+	otherCD.UID = "other-cd-uid"
+	for _, cd := range []*hivev1.ClusterDeployment{cd, cd, otherCD, otherCD} {
+		machineSets, err := actuator.GenerateMachineSets(cd, testMachinePool("worker", 2, nil), actuator.logger)
+		if assert.NoError(t, err, "unexpected error generating machinesets") {
+			assert.Len(t, machineSets, 2, "expected a machineset in each zone with a private subnet")
+		}
+	}
+}
+
 func testMachinePool(name string, replicas int, zones []string) *hivev1.MachinePool {
 	return &hivev1.MachinePool{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace:  testNamespace,
 			Name:       fmt.Sprintf("%s-%s", testName, name),
 			Finalizers: []string{finalizer},
+			Generation: 1,
 		},
 		Spec: hivev1.MachinePoolSpec{
 			ClusterDeploymentRef: corev1.LocalObjectReference{
@@ -553,6 +1389,54 @@ func testMachinePool(name string, replicas int, zones []string) *hivev1.MachineP
 	}
 }
 
+func testAutoscalingMachinePool(minReplicas, maxReplicas int) *hivev1.MachinePool {
+	pool := testMachinePool("worker", 0, []string{})
+	pool.Spec.Replicas = nil
+	pool.Spec.Autoscaling = &hivev1.MachinePoolAutoscaling{
+		MinReplicas: int32(minReplicas),
+		MaxReplicas: int32(maxReplicas),
+	}
+	return pool
+}
+
+func testMachineAutoscaler(name string, minReplicas, maxReplicas int) *unstructured.Unstructured {
+	ma := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"minReplicas": int64(minReplicas),
+			"maxReplicas": int64(maxReplicas),
+		},
+	}}
+	ma.SetGroupVersionKind(machineAutoscalerGVK)
+	ma.SetNamespace(machineAPINamespace)
+	ma.SetName(name)
+	return ma
+}
+
+func testMachineHealthCheck(spec map[string]interface{}) *unstructured.Unstructured {
+	mhc := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	mhc.SetGroupVersionKind(machineHealthCheckGVK)
+	mhc.SetNamespace(machineAPINamespace)
+	mhc.SetName("foo-12345-worker")
+	return mhc
+}
+
+func testMachineHealthCheckSpec(unhealthyConditions []interface{}, maxUnhealthy interface{}, nodeStartupTimeout string) map[string]interface{} {
+	return map[string]interface{}{
+		"selector": map[string]interface{}{
+			"matchExpressions": []interface{}{
+				map[string]interface{}{
+					"key":      machineSetNameLabel,
+					"operator": "In",
+					"values":   []interface{}{"foo-12345-worker-us-east-1a", "foo-12345-worker-us-east-1b", "foo-12345-worker-us-east-1c"},
+				},
+			},
+		},
+		"unhealthyConditions": unhealthyConditions,
+		"maxUnhealthy":        maxUnhealthy,
+		"nodeStartupTimeout":  nodeStartupTimeout,
+	}
+}
+
 func testMachineSet(name string, machineType string, unstompedAnnotation bool, replicas int, generation int) *machineapi.MachineSet {
 	return testMachineSetWithAMI(name, machineType, testAMI, unstompedAnnotation, replicas, generation)
 }
@@ -618,6 +1502,12 @@ func testMachineSetWithAMI(name, machineType, ami string, unstompedAnnotation bo
 	return &ms
 }
 
+func testMachineSetWithReadyReplicas(name string, replicas, readyReplicas int) *machineapi.MachineSet {
+	ms := testMachineSet(name, "worker", true, replicas, 0)
+	ms.Status.ReadyReplicas = int32(readyReplicas)
+	return ms
+}
+
 func testClusterDeployment() *hivev1.ClusterDeployment {
 	return &hivev1.ClusterDeployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -646,6 +1536,15 @@ func testClusterDeployment() *hivev1.ClusterDeployment {
 	}
 }
 
+// testExistingNetworkClusterDeployment returns a cluster deployment installed in the existing subnets mocked by
+// mockTestExistingSubnets, with an additional security group
+func testExistingNetworkClusterDeployment() *hivev1.ClusterDeployment {
+	cd := testClusterDeployment()
+	cd.Spec.Platform.AWS.Subnets = []string{"subnet-public-a", "subnet-private-a", "subnet-public-b", "subnet-private-b"}
+	cd.Spec.Platform.AWS.AdditionalSecurityGroupIDs = []string{"sg-additional"}
+	return cd
+}
+
 func mockTestAvailabilityZones(mockAWSClient *mockaws.MockClient, region string, zones []string) {
 	availabilityZones := []*ec2.AvailabilityZone{}
 
@@ -662,6 +1561,149 @@ func mockTestAvailabilityZones(mockAWSClient *mockaws.MockClient, region string,
 		}, nil).AnyTimes()
 }
 
+// mockTestSubnets mocks the private subnets of the test cluster in all the test zones except for the missing zones
+func mockTestSubnets(mockAWSClient *mockaws.MockClient, missingZones ...string) {
+	subnets := []*ec2.Subnet{}
+	for _, zone := range []string{"us-east-1a", "us-east-1b", "us-east-1c"} {
+		missing := false
+		for _, missingZone := range missingZones {
+			if zone == missingZone {
+				missing = true
+			}
+		}
+		if !missing {
+			subnets = append(subnets, &ec2.Subnet{
+				AvailabilityZone: aws.String(zone),
+				Tags: []*ec2.Tag{{
+					Key:   aws.String("Name"),
+					Value: aws.String(fmt.Sprintf("%s-private-%s", testInfraID, zone)),
+				}},
+			})
+		}
+	}
+
+	mockAWSClient.EXPECT().DescribeSubnets(gomock.Any()).Return(
+		&ec2.DescribeSubnetsOutput{
+			Subnets: subnets,
+		}, nil).AnyTimes()
+}
+
+// mockTestExistingSubnets mocks the subnets of testExistingNetworkClusterDeployment: a public and a private subnet in
+// us-east-1a and us-east-1b. The public subnets are associated with a route table routing to an internet gateway,
+// and the private subnets use the main route table of the VPC.
+func mockTestExistingSubnets(mockAWSClient *mockaws.MockClient) {
+	subnetsOutput, routeTablesOutput := testExistingSubnetsOutputs()
+	mockAWSClient.EXPECT().DescribeSubnets(gomock.Any()).Return(subnetsOutput, nil).AnyTimes()
+	mockAWSClient.EXPECT().DescribeRouteTables(gomock.Any()).Return(routeTablesOutput, nil).AnyTimes()
+}
+
+func testExistingSubnetsOutputs() (*ec2.DescribeSubnetsOutput, *ec2.DescribeRouteTablesOutput) {
+	subnets := []*ec2.Subnet{}
+	for _, zone := range []string{"a", "b"} {
+		for _, kind := range []string{"public", "private"} {
+			subnets = append(subnets, &ec2.Subnet{
+				SubnetId:         aws.String(fmt.Sprintf("subnet-%s-%s", kind, zone)),
+				AvailabilityZone: aws.String("us-east-1" + zone),
+				VpcId:            aws.String("vpc-1"),
+			})
+		}
+	}
+	routeTables := []*ec2.RouteTable{
+		{
+			Routes:       []*ec2.Route{{GatewayId: aws.String("local")}},
+			Associations: []*ec2.RouteTableAssociation{{Main: aws.Bool(true)}},
+		},
+		{
+			Routes: []*ec2.Route{{GatewayId: aws.String("local")}, {GatewayId: aws.String("igw-1")}},
+			Associations: []*ec2.RouteTableAssociation{
+				{SubnetId: aws.String("subnet-public-a")},
+				{SubnetId: aws.String("subnet-public-b")},
+			},
+		},
+	}
+	return &ec2.DescribeSubnetsOutput{Subnets: subnets}, &ec2.DescribeRouteTablesOutput{RouteTables: routeTables}
+}
+
+// mockTestInterruptedInstances mocks spot instances terminated by AWS
+func mockTestInterruptedInstances(mockAWSClient *mockaws.MockClient, names []string) {
+	instances := []*ec2.Instance{}
+	for _, name := range names {
+		instances = append(instances, &ec2.Instance{
+			InstanceLifecycle: aws.String(ec2.InstanceLifecycleTypeSpot),
+			StateReason:       &ec2.StateReason{Code: aws.String(awsSpotTerminationReason)},
+			Tags: []*ec2.Tag{{
+				Key:   aws.String("Name"),
+				Value: aws.String(name),
+			}},
+		})
+	}
+
+	mockAWSClient.EXPECT().DescribeInstances(gomock.Any()).Return(
+		&ec2.DescribeInstancesOutput{
+			Reservations: []*ec2.Reservation{{Instances: instances}},
+		}, nil).AnyTimes()
+}
+
+// mockTestPreemptedInstances mocks preemptible instances stopped by GCP, along with a running preemptible instance
+func mockTestPreemptedInstances(mockGCPClient *mockgcp.MockClient, names []string) {
+	instances := []*compute.Instance{{
+		Name:       "foo-12345-w-c-running",
+		Status:     "RUNNING",
+		Scheduling: &compute.Scheduling{Preemptible: true},
+	}}
+	for _, name := range names {
+		instances = append(instances, &compute.Instance{
+			Name:       name,
+			Status:     gcpTerminatedStatus,
+			Scheduling: &compute.Scheduling{Preemptible: true},
+		})
+	}
+
+	mockGCPClient.EXPECT().ListComputeInstances(gomock.Any()).Return(
+		&compute.InstanceAggregatedList{
+			Items: map[string]compute.InstancesScopedList{
+				"zones/us-east1-b": {Instances: instances},
+			},
+		}, nil).AnyTimes()
+}
+
+// testLegacyMachineSetsSyncSet returns a syncset named like the machinesets syncset of the former syncmachineset
+// controller with the given resource apply mode, controlled by the cluster deployment when controlled is set
+func testLegacyMachineSetsSyncSet(controlled bool, mode hivev1.SyncSetResourceApplyMode) *hivev1.SyncSet {
+	ss := &hivev1.SyncSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo-machinesets",
+			Namespace: testNamespace,
+		},
+		Spec: hivev1.SyncSetSpec{
+			SyncSetCommonSpec: hivev1.SyncSetCommonSpec{
+				ResourceApplyMode: mode,
+			},
+			ClusterDeploymentRefs: []corev1.LocalObjectReference{{Name: testName}},
+		},
+	}
+	if controlled {
+		ss.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(testGCPClusterDeployment(), hivev1.SchemeGroupVersion.WithKind("ClusterDeployment"))}
+	}
+	return ss
+}
+
+// testLegacyMachineSetsSyncSetInstance returns the syncset instance of the legacy machinesets syncset with the given
+// resource apply mode
+func testLegacyMachineSetsSyncSetInstance(mode hivev1.SyncSetResourceApplyMode) *hivev1.SyncSetInstance {
+	return &hivev1.SyncSetInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo-foo-machinesets-syncset",
+			Namespace: testNamespace,
+		},
+		Spec: hivev1.SyncSetInstanceSpec{
+			ClusterDeploymentRef: corev1.LocalObjectReference{Name: testName},
+			SyncSetRef:           &corev1.LocalObjectReference{Name: "foo-machinesets"},
+			ResourceApplyMode:    mode,
+		},
+	}
+}
+
 func testSecret(name, key, value string) *corev1.Secret {
 	s := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
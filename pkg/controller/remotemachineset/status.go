@@ -0,0 +1,222 @@
+package remotemachineset
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	gcpprovider "github.com/openshift/cluster-api-provider-gcp/pkg/apis/gcpprovider/v1beta1"
+	machineapi "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+	awsprovider "sigs.k8s.io/cluster-api-provider-aws/pkg/apis/awsproviderconfig/v1beta1"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+// checkSupportedConfiguration sets the UnsupportedConfiguration condition of the machine pool. It returns false
+// when the machine sets of the pool cannot be managed on its cluster.
+func checkSupportedConfiguration(pool *hivev1.MachinePool, cd *hivev1.ClusterDeployment) bool {
+	status, reason, message := corev1.ConditionFalse, "ConfigurationSupported", "machine pool configuration is supported"
+	switch {
+	case cd.Spec.Platform.AWS == nil && cd.Spec.Platform.GCP == nil && cd.Spec.Platform.Azure == nil:
+		status, reason, message = corev1.ConditionTrue, "UnsupportedPlatform", "machine pools are only supported for clusters on AWS, Azure and GCP"
+	case cd.Spec.Platform.AWS != nil && (pool.Spec.Platform.GCP != nil || pool.Spec.Platform.Azure != nil),
+		cd.Spec.Platform.GCP != nil && (pool.Spec.Platform.AWS != nil || pool.Spec.Platform.Azure != nil),
+		cd.Spec.Platform.Azure != nil && (pool.Spec.Platform.AWS != nil || pool.Spec.Platform.GCP != nil):
+		status, reason, message = corev1.ConditionTrue, "PlatformMismatch", "machine pool platform does not match the platform of the cluster"
+	case cd.Spec.Platform.Azure != nil && (pool.Spec.Platform.Azure == nil || len(pool.Spec.Platform.Azure.Zones) == 0):
+		status, reason, message = corev1.ConditionTrue, "NoAvailabilityZones", "machine pools on Azure must list their availability zones"
+	}
+	pool.Status.Conditions, _ = controllerutils.SetMachinePoolConditionWithChangeCheck(
+		pool.Status.Conditions,
+		hivev1.UnsupportedConfigurationMachinePoolCondition,
+		status,
+		reason,
+		message,
+		controllerutils.UpdateConditionIfReasonOrMessageChange,
+	)
+	return status == corev1.ConditionFalse
+}
+
+// checkEnoughReplicas sets the NotEnoughReplicas condition of the machine pool. It returns false when the pool is
+// autoscaled and its maximum replicas are too few for each of its machine sets to have a replica.
+func checkEnoughReplicas(pool *hivev1.MachinePool, machineSetCount int) bool {
+	status, reason, message := corev1.ConditionFalse, "EnoughReplicas", "machine pool has enough replicas for its machine sets"
+	if pool.Spec.Autoscaling != nil && int(pool.Spec.Autoscaling.MaxReplicas) < machineSetCount {
+		status, reason = corev1.ConditionTrue, "MaxReplicasTooLow"
+		message = fmt.Sprintf("maximum replicas of autoscaled machine pool must be at least the number of its machine sets (%d)", machineSetCount)
+	}
+	pool.Status.Conditions, _ = controllerutils.SetMachinePoolConditionWithChangeCheck(
+		pool.Status.Conditions,
+		hivev1.NotEnoughReplicasMachinePoolCondition,
+		status,
+		reason,
+		message,
+		controllerutils.UpdateConditionIfReasonOrMessageChange,
+	)
+	return status == corev1.ConditionFalse
+}
+
+// checkSubnets sets the InvalidSubnets condition of the machine pool. It returns false when the cluster does not
+// have the private subnets that the machine sets of the pool are placed in. Subnets that were found are not looked up
+// again until the spec of the pool changes, while missing subnets are looked up until they are found.
+func (r *ReconcileRemoteMachineSet) checkSubnets(
+	pool *hivev1.MachinePool,
+	cd *hivev1.ClusterDeployment,
+	machineSets []*machineapi.MachineSet,
+	logger log.FieldLogger) (bool, error) {
+
+	if pool.Status.SubnetsCheckedGeneration == pool.Generation {
+		cond := controllerutils.FindMachinePoolCondition(pool.Status.Conditions, hivev1.InvalidSubnetsMachinePoolCondition)
+		if cond == nil || cond.Status != corev1.ConditionTrue {
+			logger.Debug("subnets already found for the generation of the machine pool")
+			return true, nil
+		}
+	}
+
+	var missing []string
+	if len(cd.Spec.Platform.AWS.Subnets) > 0 {
+		// The private subnets of clusters installed in existing subnets are looked up when generating the machine sets
+		for _, ms := range machineSets {
+			providerConfig, ok := ms.Spec.Template.Spec.ProviderSpec.Value.Object.(*awsprovider.AWSMachineProviderConfig)
+			if ok && providerConfig.Subnet.ID == nil {
+				missing = append(missing, fmt.Sprintf("private subnet in zone %s", providerConfig.Placement.AvailabilityZone))
+			}
+		}
+	} else {
+		var err error
+		if missing, err = r.findMissingSubnets(cd, machineSets, logger); err != nil {
+			return false, err
+		}
+	}
+
+	status, reason, message := corev1.ConditionFalse, "ValidSubnets", "subnets found for all the zones of the machine pool"
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		status, reason = corev1.ConditionTrue, "SubnetsNotFound"
+		message = fmt.Sprintf("subnets not found: %s", strings.Join(missing, ", "))
+		logger.WithField("subnets", missing).Warn("subnets not found for machine pool")
+	}
+	pool.Status.Conditions, _ = controllerutils.SetMachinePoolConditionWithChangeCheck(
+		pool.Status.Conditions,
+		hivev1.InvalidSubnetsMachinePoolCondition,
+		status,
+		reason,
+		message,
+		controllerutils.UpdateConditionIfReasonOrMessageChange,
+	)
+	pool.Status.SubnetsCheckedGeneration = pool.Generation
+	return status == corev1.ConditionFalse, nil
+}
+
+// findMissingSubnets returns the names of the private subnets created by the installer for the zones of the machine
+// sets that are not found in the cluster.
+func (r *ReconcileRemoteMachineSet) findMissingSubnets(
+	cd *hivev1.ClusterDeployment,
+	machineSets []*machineapi.MachineSet,
+	logger log.FieldLogger) ([]string, error) {
+
+	subnetNames := []string{}
+	for _, ms := range machineSets {
+		if zone := machineSetZone(ms); zone != "" {
+			subnetNames = append(subnetNames, fmt.Sprintf("%s-private-%s", cd.Spec.ClusterMetadata.InfraID, zone))
+		}
+	}
+	if len(subnetNames) == 0 {
+		return nil, nil
+	}
+
+	awsClient, err := r.getAWSClient(cd)
+	if err != nil {
+		logger.WithError(err).Error("unable to create AWS client")
+		return nil, err
+	}
+	resp, err := awsClient.DescribeSubnets(&ec2.DescribeSubnetsInput{
+		Filters: []*ec2.Filter{{
+			Name:   aws.String("tag:Name"),
+			Values: aws.StringSlice(subnetNames),
+		}},
+	})
+	if err != nil {
+		logger.WithError(err).Error("unable to describe subnets")
+		return nil, err
+	}
+	found := map[string]bool{}
+	for _, subnet := range resp.Subnets {
+		for _, tag := range subnet.Tags {
+			if aws.StringValue(tag.Key) == "Name" {
+				found[aws.StringValue(tag.Value)] = true
+			}
+		}
+	}
+	missing := []string{}
+	for _, name := range subnetNames {
+		if !found[name] {
+			missing = append(missing, name)
+		}
+	}
+	return missing, nil
+}
+
+// setMachineSetsStatus sets the replicas of the machine pool and the status of each of its machine sets from the
+// generated machine sets and the machine sets observed in the remote cluster.
+func setMachineSetsStatus(pool *hivev1.MachinePool, machineSets []*machineapi.MachineSet, remoteMachineSets []machineapi.MachineSet) {
+	pool.Status.Replicas = 0
+	pool.Status.ReadyReplicas = 0
+	pool.Status.MachineSets = nil
+	for i, ms := range machineSets {
+		msStatus := hivev1.MachineSetStatus{
+			Name:     ms.Name,
+			Zone:     machineSetZone(ms),
+			Replicas: *ms.Spec.Replicas,
+		}
+		for _, rMS := range remoteMachineSets {
+			if rMS.Name == ms.Name {
+				msStatus.ReadyReplicas = rMS.Status.ReadyReplicas
+				break
+			}
+		}
+		if pool.Spec.Autoscaling != nil {
+			msStatus.MinReplicas, msStatus.MaxReplicas = machineSetReplicaRange(pool.Spec.Autoscaling, i, len(machineSets))
+		}
+		pool.Status.Replicas += msStatus.Replicas
+		pool.Status.ReadyReplicas += msStatus.ReadyReplicas
+		pool.Status.MachineSets = append(pool.Status.MachineSets, msStatus)
+	}
+}
+
+// machineSetZone returns the availability zone of a generated machine set
+func machineSetZone(ms *machineapi.MachineSet) string {
+	if ms.Spec.Template.Spec.ProviderSpec.Value == nil {
+		return ""
+	}
+	switch providerConfig := ms.Spec.Template.Spec.ProviderSpec.Value.Object.(type) {
+	case *awsprovider.AWSMachineProviderConfig:
+		return providerConfig.Placement.AvailabilityZone
+	case *gcpprovider.GCPMachineProviderSpec:
+		return providerConfig.Zone
+	case nil:
+		return azureMachineSetZone(ms)
+	}
+	return ""
+}
+
+func (r *ReconcileRemoteMachineSet) updatePoolStatus(pool *hivev1.MachinePool, originalStatus *hivev1.MachinePoolStatus, logger log.FieldLogger) error {
+	if reflect.DeepEqual(&pool.Status, originalStatus) {
+		return nil
+	}
+	if err := r.Status().Update(context.Background(), pool); err != nil {
+		logger.WithError(err).Log(controllerutils.LogLevel(err), "could not update machine pool status")
+		return err
+	}
+	return nil
+}
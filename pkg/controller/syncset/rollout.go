@@ -0,0 +1,194 @@
+package syncset
+
+import (
+	"context"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	"github.com/openshift/hive/pkg/syncsetmatch"
+)
+
+// canRollOut determines whether the syncset instance of a cluster deployment can be updated to the selectorsyncset
+// spec with the given hash, according to the rollout strategy of the selectorsyncset. An instance that has been updated
+// to the hash is updating until it has applied the spec with that hash without failures.
+//
+// Since the syncset instance updates that follow take a while to show up in the cache, the clusters allowed to update
+// are recorded in the rollout status of the selectorsyncset and counted as updating until their instance is found
+// updated. Recording a cluster fails with a conflict when the selectorsyncset was changed by another rollout decision
+// in the meantime, so that rollout decisions for a selectorsyncset are made one at a time.
+func (r *ReconcileSyncSet) canRollOut(cd *hivev1.ClusterDeployment, selectorSyncSet *hivev1.SelectorSyncSet, hash string, cdLog log.FieldLogger) (bool, error) {
+	rollout := selectorSyncSet.Spec.Rollout
+	rolloutLog := cdLog.WithField("selectorSyncSet", selectorSyncSet.Name)
+
+	allowed := map[types.NamespacedName]bool{}
+	if status := selectorSyncSet.Status.Rollout; status != nil && status.SyncSetHash == hash {
+		for _, cluster := range status.AllowedClusters {
+			allowed[types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}] = true
+		}
+	}
+	cdName := types.NamespacedName{Namespace: cd.Namespace, Name: cd.Name}
+	if allowed[cdName] {
+		// The cluster has already been allowed to update, but its syncset instance has not been updated yet
+		return true, nil
+	}
+
+	var canarySelector labels.Selector
+	if rollout.CanarySelector != nil {
+		var err error
+		canarySelector, err = metav1.LabelSelectorAsSelector(rollout.CanarySelector)
+		if err != nil {
+			return false, err
+		}
+	}
+	cdSelector, err := metav1.LabelSelectorAsSelector(&selectorSyncSet.Spec.ClusterDeploymentSelector)
+	if err != nil {
+		return false, err
+	}
+
+	cdList := &hivev1.ClusterDeploymentList{}
+	if err := r.List(context.TODO(), cdList, client.UseListOptions(&client.ListOptions{LabelSelector: cdSelector})); err != nil {
+		return false, err
+	}
+	ssiList := &hivev1.SyncSetInstanceList{}
+	if err := r.List(context.TODO(), ssiList, client.MatchingField(controllerutils.SyncSetInstanceSelectorSyncSetIndex, selectorSyncSet.Name)); err != nil {
+		return false, err
+	}
+	syncSetInstances := map[types.NamespacedName]*hivev1.SyncSetInstance{}
+	for i, ssi := range ssiList.Items {
+		if ssi.Spec.SelectorSyncSetRef != nil && ssi.Spec.SelectorSyncSetRef.Name == selectorSyncSet.Name {
+			syncSetInstances[types.NamespacedName{Namespace: ssi.Namespace, Name: ssi.Spec.ClusterDeploymentRef.Name}] = &ssiList.Items[i]
+		}
+	}
+	if err := r.pruneRolloutSnapshots(selectorSyncSet, hash, syncSetInstances, rolloutLog); err != nil {
+		return false, err
+	}
+
+	isCanary := canarySelector != nil && canarySelector.Matches(labels.Set(cd.Labels))
+	targeted, updating := 0, 0
+	for i, targetCD := range cdList.Items {
+		if !targetCD.Spec.Installed || !targetCD.DeletionTimestamp.IsZero() {
+			continue
+		}
+		matches, err := syncsetmatch.Matches(selectorSyncSet, &cdList.Items[i])
+		if err != nil {
+			return false, err
+		}
+		if !matches {
+			continue
+		}
+		targeted++
+		targetName := types.NamespacedName{Namespace: targetCD.Namespace, Name: targetCD.Name}
+		ssi := syncSetInstances[targetName]
+		updated := ssi != nil && ssi.Spec.SyncSetHash == hash
+		applied := updated && ssi.Status.AppliedSyncSetHash == hash
+		switch {
+		case updated:
+			delete(allowed, targetName)
+			_, failed := controllerutils.SyncSetInstanceFailure(ssi)
+			if failed && rollout.PauseOnFailure {
+				rolloutLog.WithField("failedClusterDeployment", targetName.String()).Info("rollout is paused after a failure")
+				return false, nil
+			}
+			if failed || !applied {
+				updating++
+			}
+		case allowed[targetName]:
+			updating++
+		}
+		if canarySelector != nil && !isCanary && !applied && canarySelector.Matches(labels.Set(targetCD.Labels)) {
+			rolloutLog.Debug("waiting for the rollout to complete on canary clusters")
+			return false, nil
+		}
+	}
+
+	if maxUnavailable := maxUnavailableClusters(rollout.MaxUnavailablePercentage, targeted); updating >= maxUnavailable {
+		rolloutLog.WithField("updating", updating).WithField("maxUnavailable", maxUnavailable).Debug("waiting for clusters to finish updating")
+		return false, nil
+	}
+
+	allowed[cdName] = true
+	status := &hivev1.SelectorSyncSetRolloutStatus{SyncSetHash: hash}
+	for name := range allowed {
+		status.AllowedClusters = append(status.AllowedClusters, hivev1.SyncSetClusterReference{Namespace: name.Namespace, Name: name.Name})
+	}
+	sort.Slice(status.AllowedClusters, func(i, j int) bool {
+		a, b := status.AllowedClusters[i], status.AllowedClusters[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	selectorSyncSet.Status.Rollout = status
+	if err := r.Status().Update(context.TODO(), selectorSyncSet); err != nil {
+		rolloutLog.WithError(err).Log(controllerutils.LogLevel(err), "cannot record the cluster allowed to update in the rollout status")
+		return false, err
+	}
+	return true, nil
+}
+
+// ensureRolloutSnapshot keeps the spec with the given hash of a selectorsyncset with a rollout strategy, before a
+// syncset instance is updated to it, so that the instance can keep applying it once the selectorsyncset changes again.
+func (r *ReconcileSyncSet) ensureRolloutSnapshot(selectorSyncSet *hivev1.SelectorSyncSet, hash string) error {
+	snapshot := &corev1.ConfigMap{}
+	name := types.NamespacedName{Namespace: constants.HiveNamespace, Name: controllerutils.RolloutSnapshotName(selectorSyncSet.Name, hash)}
+	err := r.Get(context.TODO(), name, snapshot)
+	if err == nil || !errors.IsNotFound(err) {
+		return err
+	}
+	snapshot, err = controllerutils.NewRolloutSnapshot(selectorSyncSet, hash)
+	if err != nil {
+		return err
+	}
+	if err := r.Create(context.TODO(), snapshot); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// pruneRolloutSnapshots deletes the rollout snapshots of a selectorsyncset that neither keep the spec being rolled out
+// nor a spec that a syncset instance has been updated to.
+func (r *ReconcileSyncSet) pruneRolloutSnapshots(selectorSyncSet *hivev1.SelectorSyncSet, hash string, syncSetInstances map[types.NamespacedName]*hivev1.SyncSetInstance, rolloutLog log.FieldLogger) error {
+	inUse := map[string]bool{hash: true}
+	for _, ssi := range syncSetInstances {
+		inUse[ssi.Spec.SyncSetHash] = true
+	}
+	snapshots := &corev1.ConfigMapList{}
+	if err := r.List(context.TODO(), snapshots, client.InNamespace(constants.HiveNamespace), client.MatchingLabels(map[string]string{constants.SelectorSyncSetUIDLabel: string(selectorSyncSet.UID)})); err != nil {
+		return err
+	}
+	for i, snapshot := range snapshots.Items {
+		if inUse[snapshot.Labels[constants.SyncSetHashLabel]] {
+			continue
+		}
+		rolloutLog.WithField("snapshot", snapshot.Name).Debug("deleting rollout snapshot that is no longer used")
+		if err := r.Delete(context.TODO(), &snapshots.Items[i]); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// maxUnavailableClusters returns the number of clusters that can be updating at the same time. The percentage of the
+// targeted clusters is rounded up, and at least one cluster can always be updating.
+func maxUnavailableClusters(percentage, targeted int) int {
+	if percentage <= 0 || percentage > 100 {
+		percentage = 100
+	}
+	maxUnavailable := (targeted*percentage + 99) / 100
+	if maxUnavailable < 1 {
+		return 1
+	}
+	return maxUnavailable
+}
@@ -0,0 +1,274 @@
+package syncset
+
+import (
+	"context"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+func TestSelectorSyncSetRollout(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	up := hivev1.UpsertResourceApplyMode
+	sy := hivev1.SyncResourceApplyMode
+
+	tests := []struct {
+		name          string
+		rollout       *hivev1.SelectorSyncSetRolloutStrategy
+		canary        bool
+		existing      []runtime.Object
+		expectUpdated bool
+	}{
+		{
+			name:          "no rollout strategy",
+			existing:      []runtime.Object{otherClusterDeployment("other1", false), otherInstance("other1", sy, "")},
+			expectUpdated: true,
+		},
+		{
+			name:          "max unavailable reached",
+			rollout:       &hivev1.SelectorSyncSetRolloutStrategy{MaxUnavailablePercentage: 50},
+			existing:      []runtime.Object{otherClusterDeployment("other1", false), otherInstance("other1", sy, "")},
+			expectUpdated: false,
+		},
+		{
+			name:          "max unavailable not reached",
+			rollout:       &hivev1.SelectorSyncSetRolloutStrategy{MaxUnavailablePercentage: 50},
+			existing:      []runtime.Object{otherClusterDeployment("other1", false), otherInstance("other1", sy, string(sy))},
+			expectUpdated: true,
+		},
+		{
+			name:    "failed clusters count as unavailable",
+			rollout: &hivev1.SelectorSyncSetRolloutStrategy{MaxUnavailablePercentage: 50},
+			existing: []runtime.Object{
+				otherClusterDeployment("other1", false), failedInstance(otherInstance("other1", sy, string(sy))),
+			},
+			expectUpdated: false,
+		},
+		{
+			name:    "pause on failure",
+			rollout: &hivev1.SelectorSyncSetRolloutStrategy{PauseOnFailure: true},
+			existing: []runtime.Object{
+				otherClusterDeployment("other1", false), failedInstance(otherInstance("other1", sy, "")),
+			},
+			expectUpdated: false,
+		},
+		{
+			name:          "failure without pause on failure",
+			rollout:       &hivev1.SelectorSyncSetRolloutStrategy{},
+			existing:      []runtime.Object{otherClusterDeployment("other1", false), failedInstance(otherInstance("other1", sy, ""))},
+			expectUpdated: true,
+		},
+		{
+			name:          "waiting for canary clusters",
+			rollout:       &hivev1.SelectorSyncSetRolloutStrategy{CanarySelector: canarySelector()},
+			existing:      []runtime.Object{otherClusterDeployment("other1", true), otherInstance("other1", up, "")},
+			expectUpdated: false,
+		},
+		{
+			name:          "canary clusters updated",
+			rollout:       &hivev1.SelectorSyncSetRolloutStrategy{CanarySelector: canarySelector()},
+			existing:      []runtime.Object{otherClusterDeployment("other1", true), otherInstance("other1", sy, string(sy))},
+			expectUpdated: true,
+		},
+		{
+			name:          "canary cluster does not wait for other canaries",
+			rollout:       &hivev1.SelectorSyncSetRolloutStrategy{CanarySelector: canarySelector()},
+			canary:        true,
+			existing:      []runtime.Object{otherClusterDeployment("other1", true), otherInstance("other1", up, "")},
+			expectUpdated: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cd := testClusterDeployment()
+			if test.canary {
+				cd.Labels["canary"] = "true"
+			}
+			sss := testMatchingSelectorSyncSet("aa", sy)
+			sss.Spec.Rollout = test.rollout
+			objs := append(test.existing, cd, sss, testSyncSetInstanceForSelectorSyncSet("aa", up))
+			fakeClient := fake.NewFakeClient(objs...)
+			rss := &ReconcileSyncSet{
+				Client:      fakeClient,
+				scheme:      scheme.Scheme,
+				logger:      log.WithField("controller", "syncset"),
+				computeHash: testHashCompute,
+			}
+			result, err := rss.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testName,
+					Namespace: testNamespace,
+				},
+			})
+			require.NoError(t, err, "unexpected error from reconcile")
+
+			ssi := &hivev1.SyncSetInstance{}
+			expected := testSyncSetInstanceForSelectorSyncSet("aa", up)
+			require.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: expected.Name}, ssi))
+			if test.expectUpdated {
+				assert.Equal(t, string(sy), ssi.Spec.SyncSetHash, "expected syncset instance to be updated")
+				assert.Zero(t, result.RequeueAfter, "unexpected requeue")
+				snapshot := &corev1.ConfigMap{}
+				err := fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: constants.HiveNamespace, Name: controllerutils.RolloutSnapshotName("aa", string(sy))}, snapshot)
+				if test.rollout != nil {
+					require.NoError(t, err, "expected rollout snapshot to be kept")
+					spec, err := controllerutils.RolloutSnapshotSpec(snapshot)
+					require.NoError(t, err, "unexpected error reading rollout snapshot")
+					assert.Equal(t, &sss.Spec.SyncSetCommonSpec, spec, "unexpected rolled out spec")
+				} else {
+					assert.True(t, errors.IsNotFound(err), "unexpected rollout snapshot")
+				}
+			} else {
+				assert.Equal(t, string(up), ssi.Spec.SyncSetHash, "expected syncset instance not to be updated")
+				assert.Equal(t, rolloutRequeueInterval, result.RequeueAfter, "expected requeue while waiting for rollout")
+			}
+		})
+	}
+}
+
+func TestRolloutDecisionsBeforeCacheUpdate(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	up := hivev1.UpsertResourceApplyMode
+	sy := hivev1.SyncResourceApplyMode
+	cd := testClusterDeployment()
+	other := otherClusterDeployment("other1", false)
+	sss := testMatchingSelectorSyncSet("aa", sy)
+	sss.Spec.Rollout = &hivev1.SelectorSyncSetRolloutStrategy{MaxUnavailablePercentage: 50}
+	rss := &ReconcileSyncSet{
+		Client: fake.NewFakeClient(
+			cd, other, sss,
+			testSyncSetInstanceForSelectorSyncSet("aa", up), otherInstance("other1", up, string(up)),
+		),
+		scheme:      scheme.Scheme,
+		logger:      log.WithField("controller", "syncset"),
+		computeHash: testHashCompute,
+	}
+	getSelectorSyncSet := func() *hivev1.SelectorSyncSet {
+		current := &hivev1.SelectorSyncSet{}
+		require.NoError(t, rss.Get(context.TODO(), types.NamespacedName{Name: sss.Name}, current))
+		return current
+	}
+
+	// The syncset instances are not updated, as if the updates had not reached the cache yet
+	proceed, err := rss.canRollOut(cd, getSelectorSyncSet(), string(sy), rss.logger)
+	require.NoError(t, err)
+	assert.True(t, proceed, "expected first cluster to be allowed to update")
+	status := getSelectorSyncSet().Status.Rollout
+	if assert.NotNil(t, status, "expected rollout status to be recorded") {
+		assert.Equal(t, string(sy), status.SyncSetHash, "unexpected hash in rollout status")
+		assert.Equal(t, []hivev1.SyncSetClusterReference{{Namespace: cd.Namespace, Name: cd.Name}}, status.AllowedClusters, "unexpected allowed clusters")
+	}
+	proceed, err = rss.canRollOut(other, getSelectorSyncSet(), string(sy), rss.logger)
+	require.NoError(t, err)
+	assert.False(t, proceed, "expected second cluster to wait for the first one")
+	proceed, err = rss.canRollOut(cd, getSelectorSyncSet(), string(sy), rss.logger)
+	require.NoError(t, err)
+	assert.True(t, proceed, "expected first cluster to remain allowed to update")
+}
+
+func TestPruneRolloutSnapshots(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	up := hivev1.UpsertResourceApplyMode
+	sy := hivev1.SyncResourceApplyMode
+	cd := testClusterDeployment()
+	sss := testMatchingSelectorSyncSet("aa", sy)
+	sss.UID = "aa-uid"
+	sss.Spec.Rollout = &hivev1.SelectorSyncSetRolloutStrategy{}
+	otherSSS := testMatchingSelectorSyncSet("bb", sy)
+	otherSSS.UID = "bb-uid"
+	snapshot := func(owner *hivev1.SelectorSyncSet, hash string) runtime.Object {
+		cm, err := controllerutils.NewRolloutSnapshot(owner, hash)
+		require.NoError(t, err)
+		return cm
+	}
+	fakeClient := fake.NewFakeClient(
+		cd, sss, testSyncSetInstanceForSelectorSyncSet("aa", up),
+		snapshot(sss, string(up)), snapshot(sss, string(sy)), snapshot(sss, "stale"), snapshot(otherSSS, "stale"),
+	)
+	rss := &ReconcileSyncSet{
+		Client:      fakeClient,
+		scheme:      scheme.Scheme,
+		logger:      log.WithField("controller", "syncset"),
+		computeHash: testHashCompute,
+	}
+
+	_, err := rss.canRollOut(cd, sss, string(sy), rss.logger)
+	require.NoError(t, err)
+
+	snapshots := &corev1.ConfigMapList{}
+	require.NoError(t, fakeClient.List(context.TODO(), snapshots, client.InNamespace(constants.HiveNamespace)))
+	names := []string{}
+	for _, cm := range snapshots.Items {
+		names = append(names, cm.Name)
+	}
+	assert.ElementsMatch(t, []string{
+		controllerutils.RolloutSnapshotName("aa", string(up)),
+		controllerutils.RolloutSnapshotName("aa", string(sy)),
+		controllerutils.RolloutSnapshotName("bb", "stale"),
+	}, names, "expected only the unused snapshot of the selectorsyncset to be deleted")
+}
+
+func TestMaxUnavailableClusters(t *testing.T) {
+	assert.Equal(t, 10, maxUnavailableClusters(0, 10))
+	assert.Equal(t, 3, maxUnavailableClusters(25, 10))
+	assert.Equal(t, 1, maxUnavailableClusters(1, 10))
+	assert.Equal(t, 1, maxUnavailableClusters(50, 0))
+}
+
+func canarySelector() *metav1.LabelSelector {
+	return &metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}}
+}
+
+func otherClusterDeployment(name string, canary bool) *hivev1.ClusterDeployment {
+	cd := testClusterDeployment()
+	cd.Name = name
+	if canary {
+		cd.Labels["canary"] = "true"
+	}
+	return cd
+}
+
+func otherInstance(cdName string, mode hivev1.SyncSetResourceApplyMode, appliedHash string) *hivev1.SyncSetInstance {
+	ssi := testSyncSetInstanceForSelectorSyncSet("aa", mode)
+	ssi.Name = cdName + "-aa"
+	ssi.Spec.ClusterDeploymentRef.Name = cdName
+	ssi.Status.AppliedSyncSetHash = appliedHash
+	return ssi
+}
+
+func failedInstance(ssi *hivev1.SyncSetInstance) *hivev1.SyncSetInstance {
+	ssi.Status.Resources = []hivev1.SyncStatus{
+		{
+			Name: "foo",
+			Conditions: []hivev1.SyncCondition{
+				{
+					Type:   hivev1.ApplyFailureSyncCondition,
+					Status: corev1.ConditionTrue,
+				},
+			},
+		},
+	}
+	return ssi
+}
@@ -3,13 +3,13 @@ package syncset
 import (
 	"context"
 	"fmt"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation"
@@ -24,10 +24,14 @@ import (
 	"github.com/openshift/hive/pkg/apis/helpers"
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
 	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	"github.com/openshift/hive/pkg/syncsetmatch"
 )
 
 const (
 	controllerName = "syncset"
+
+	// rolloutRequeueInterval is how often a cluster waiting for the rollout of a SelectorSyncSet change is checked again
+	rolloutRequeueInterval = 30 * time.Second
 )
 
 // Add creates a new SyncSet Controller and adds it to the Manager with default RBAC. The Manager will set fields on the
@@ -55,6 +59,11 @@ func AddToManager(mgr manager.Manager, r reconcile.Reconciler) error {
 		return fmt.Errorf("cannot create new syncset-controller: %v", err)
 	}
 
+	// Index syncset instances by selectorsyncset to look up the clusters a selectorsyncset is rolled out to
+	if err := controllerutils.IndexSyncSetInstances(mgr); err != nil {
+		return err
+	}
+
 	// Watch for changes to ClusterDeployment
 	err = c.Watch(&source.Kind{Type: &hivev1.ClusterDeployment{}}, &handler.EnqueueRequestForObject{})
 	if err != nil {
@@ -87,13 +96,6 @@ func (r *ReconcileSyncSet) selectorSyncSetHandlerFunc(a handler.MapObject) []rec
 		r.logger.Warning("unexpected object, expected SelectorSyncSet")
 		return []reconcile.Request{}
 	}
-	clusterDeployments := &hivev1.ClusterDeploymentList{}
-	err := r.List(context.TODO(), clusterDeployments)
-	if err != nil {
-		r.logger.WithError(err).Error("cannot list cluster deployments for selector syncset")
-		return []reconcile.Request{}
-	}
-
 	labelSelector, err := metav1.LabelSelectorAsSelector(&selectorSyncSet.Spec.ClusterDeploymentSelector)
 	if err != nil {
 		r.logger.WithError(err).
@@ -103,14 +105,21 @@ func (r *ReconcileSyncSet) selectorSyncSetHandlerFunc(a handler.MapObject) []rec
 		return []reconcile.Request{}
 	}
 
+	// Clusters that do not meet the cluster match conditions are enqueued too, so that syncset instances are removed
+	// from clusters that no longer match
+	clusterDeployments := &hivev1.ClusterDeploymentList{}
+	err = r.List(context.TODO(), clusterDeployments, client.UseListOptions(&client.ListOptions{LabelSelector: labelSelector}))
+	if err != nil {
+		r.logger.WithError(err).Error("cannot list cluster deployments for selector syncset")
+		return []reconcile.Request{}
+	}
+
 	retval := []reconcile.Request{}
 	for _, clusterDeployment := range clusterDeployments.Items {
-		if labelSelector.Matches(labels.Set(clusterDeployment.Labels)) {
-			retval = append(retval, reconcile.Request{NamespacedName: types.NamespacedName{
-				Name:      clusterDeployment.Name,
-				Namespace: clusterDeployment.Namespace,
-			}})
-		}
+		retval = append(retval, reconcile.Request{NamespacedName: types.NamespacedName{
+			Name:      clusterDeployment.Name,
+			Namespace: clusterDeployment.Namespace,
+		}})
 	}
 
 	return retval
@@ -195,7 +204,7 @@ func (r *ReconcileSyncSet) Reconcile(request reconcile.Request) (reconcile.Resul
 		return reconcile.Result{}, err
 	}
 
-	toAdd, toUpdate, toDelete, err := r.reconcileSyncSetInstances(cd, syncSets, selectorSyncSets, syncSetInstances)
+	toAdd, toUpdate, toDelete, waiting, err := r.reconcileSyncSetInstances(cd, syncSets, selectorSyncSets, syncSetInstances, cdLog)
 	if err != nil {
 		cdLog.WithError(err).Error("unable to reconcile sync set instances for cluster deployment")
 	}
@@ -227,13 +236,19 @@ func (r *ReconcileSyncSet) Reconcile(request reconcile.Request) (reconcile.Resul
 		}
 	}
 	cdLog.Info("done reconciling sync sets for cluster deployment")
+	if waiting {
+		return reconcile.Result{RequeueAfter: rolloutRequeueInterval}, nil
+	}
 	return reconcile.Result{}, nil
 }
 
-func (r *ReconcileSyncSet) reconcileSyncSetInstances(cd *hivev1.ClusterDeployment, syncSets []*hivev1.SyncSet, selectorSyncSets []*hivev1.SelectorSyncSet, syncSetInstances []*hivev1.SyncSetInstance) (
+// reconcileSyncSetInstances determines the syncset instances to add, update and delete for a cluster deployment. It also
+// returns true if a selectorsyncset change is waiting to be rolled out to the cluster deployment.
+func (r *ReconcileSyncSet) reconcileSyncSetInstances(cd *hivev1.ClusterDeployment, syncSets []*hivev1.SyncSet, selectorSyncSets []*hivev1.SelectorSyncSet, syncSetInstances []*hivev1.SyncSetInstance, cdLog log.FieldLogger) (
 	toAdd []*hivev1.SyncSetInstance,
 	toUpdate []*hivev1.SyncSetInstance,
 	toDelete []*hivev1.SyncSetInstance,
+	waiting bool,
 	err error) {
 
 	var computedHash string
@@ -263,7 +278,31 @@ func (r *ReconcileSyncSet) reconcileSyncSetInstances(cd *hivev1.ClusterDeploymen
 	}
 
 	for _, selectorSyncSet := range selectorSyncSets {
+		computedHash, err = r.computeHash(controllerutils.SelectorSyncSetContentSpec(selectorSyncSet.Spec))
+		if err != nil {
+			err = fmt.Errorf("cannot comput selectorsyncset hash: %v", err)
+			return
+		}
 		matchingSyncSetInstance := findSyncSetInstanceForSelectorSyncSet(selectorSyncSet.Name, syncSetInstances)
+		if matchingSyncSetInstance != nil && computedHash != matchingSyncSetInstance.Spec.SyncSetHash && selectorSyncSet.Spec.Rollout != nil {
+			var proceed bool
+			proceed, err = r.canRollOut(cd, selectorSyncSet, computedHash, cdLog)
+			if err != nil {
+				err = fmt.Errorf("cannot determine rollout of selectorsyncset: %v", err)
+				return
+			}
+			if !proceed {
+				waiting = true
+				continue
+			}
+		}
+		if selectorSyncSet.Spec.Rollout != nil {
+			// Keep the spec the syncset instance applies, for when the selectorsyncset changes again
+			if err = r.ensureRolloutSnapshot(selectorSyncSet, computedHash); err != nil {
+				err = fmt.Errorf("cannot keep rollout snapshot of selectorsyncset: %v", err)
+				return
+			}
+		}
 		if matchingSyncSetInstance == nil {
 			syncSetInstance, err = r.syncSetInstanceForSelectorSyncSet(cd, selectorSyncSet)
 			if err != nil {
@@ -273,11 +312,6 @@ func (r *ReconcileSyncSet) reconcileSyncSetInstances(cd *hivev1.ClusterDeploymen
 			toAdd = append(toAdd, syncSetInstance)
 			continue
 		}
-		computedHash, err = r.computeHash(selectorSyncSet.Spec)
-		if err != nil {
-			err = fmt.Errorf("cannot comput selectorsyncset hash: %v", err)
-			return
-		}
 		if computedHash != matchingSyncSetInstance.Spec.SyncSetHash {
 			matchingSyncSetInstance.Spec.SyncSetHash = computedHash
 			matchingSyncSetInstance.Spec.ResourceApplyMode = selectorSyncSet.Spec.ResourceApplyMode
@@ -307,19 +341,18 @@ func (r *ReconcileSyncSet) getRelatedSelectorSyncSets(cd *hivev1.ClusterDeployme
 		return nil, err
 	}
 
-	cdLabels := labels.Set(cd.Labels)
 	selectorSyncSets := []*hivev1.SelectorSyncSet{}
 	for i, selectorSyncSet := range list.Items {
 		if !selectorSyncSet.DeletionTimestamp.IsZero() {
 			continue
 		}
-		labelSelector, err := metav1.LabelSelectorAsSelector(&selectorSyncSet.Spec.ClusterDeploymentSelector)
+		matches, err := syncsetmatch.Matches(&list.Items[i], cd)
 		if err != nil {
-			r.logger.WithError(err).Error("unable to convert selector")
+			r.logger.WithError(err).WithField("selectorsyncset", selectorSyncSet.Name).Error("unable to match selectorsyncset")
 			continue
 		}
 
-		if labelSelector.Matches(cdLabels) {
+		if matches {
 			selectorSyncSets = append(selectorSyncSets, &list.Items[i])
 		}
 	}
@@ -412,7 +445,7 @@ func (r *ReconcileSyncSet) syncSetInstanceForSyncSet(cd *hivev1.ClusterDeploymen
 
 func (r *ReconcileSyncSet) syncSetInstanceForSelectorSyncSet(cd *hivev1.ClusterDeployment, selectorSyncSet *hivev1.SelectorSyncSet) (*hivev1.SyncSetInstance, error) {
 	cdRef := metav1.NewControllerRef(cd, hivev1.SchemeGroupVersion.WithKind("ClusterDeployment"))
-	hash, err := r.computeHash(selectorSyncSet.Spec)
+	hash, err := r.computeHash(controllerutils.SelectorSyncSetContentSpec(selectorSyncSet.Spec))
 	if err != nil {
 		return nil, err
 	}
@@ -45,9 +45,10 @@ func TestReconcileSyncSet(t *testing.T) {
 	sy := hivev1.SyncResourceApplyMode
 
 	tests := []struct {
-		name     string
-		existing []runtime.Object
-		expected []*hivev1.SyncSetInstance
+		name           string
+		clusterVersion string
+		existing       []runtime.Object
+		expected       []*hivev1.SyncSetInstance
 	}{
 		{
 			name: "add syncsetinstances",
@@ -114,11 +115,25 @@ func TestReconcileSyncSet(t *testing.T) {
 				ssi("aa", sy), ssi("bb", up), ssi("cc", up),
 			},
 		},
+		{
+			name:           "selectorsyncsets with cluster match",
+			clusterVersion: "4.4.1",
+			existing: []runtime.Object{
+				withClusterMatch(sss("aa"), &hivev1.SelectorSyncSetClusterMatch{VersionRange: ">=4.4"}),
+				withClusterMatch(sss("bb"), &hivev1.SelectorSyncSetClusterMatch{VersionRange: "<4.4"}),
+				withClusterMatch(sss("cc"), &hivev1.SelectorSyncSetClusterMatch{Platforms: []string{"azure"}}),
+				ssi("bb"),
+			},
+			expected: []*hivev1.SyncSetInstance{
+				ssi("aa"),
+			},
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			cd := testClusterDeployment()
+			cd.Status.ClusterVersionStatus.Desired.Version = test.clusterVersion
 			objs := append(test.existing, cd)
 			fakeClient := fake.NewFakeClient(objs...)
 			rss := &ReconcileSyncSet{
@@ -190,6 +205,11 @@ func testClusterDeployment() *hivev1.ClusterDeployment {
 	return cd
 }
 
+func withClusterMatch(selectorSyncSet *hivev1.SelectorSyncSet, match *hivev1.SelectorSyncSetClusterMatch) *hivev1.SelectorSyncSet {
+	selectorSyncSet.Spec.ClusterMatch = match
+	return selectorSyncSet
+}
+
 func getMode(mode []hivev1.SyncSetResourceApplyMode) hivev1.SyncSetResourceApplyMode {
 	if len(mode) == 0 {
 		return hivev1.UpsertResourceApplyMode
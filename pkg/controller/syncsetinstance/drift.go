@@ -0,0 +1,132 @@
+package syncsetinstance
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	hiveresource "github.com/openshift/hive/pkg/resource"
+)
+
+const (
+	// maxDriftDifferences is the maximum number of differences included in a drift summary
+	maxDriftDifferences = 5
+)
+
+// detectsDrift returns true if remote objects need to be checked for drift with the given policy
+func detectsDrift(policy hivev1.SyncSetDriftPolicy) bool {
+	return policy == hivev1.ReportDriftPolicy || policy == hivev1.RevertDriftPolicy
+}
+
+// resourceDrift compares the live remote object for a resource with the content that was applied from the SyncSet.
+// Only the fields present in the applied content are compared, so fields defaulted or added by the remote cluster
+// are not considered drift. It returns a summary of the differences, or an empty string if the object has not drifted.
+func resourceDrift(dynamicClient dynamic.Interface, info hiveresource.Info, raw []byte) (string, error) {
+	gv, err := schema.ParseGroupVersion(info.APIVersion)
+	if err != nil {
+		return "", err
+	}
+	obj, err := dynamicClient.Resource(gv.WithResource(info.Resource)).Namespace(info.Namespace).Get(info.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return "resource does not exist", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var desired map[string]interface{}
+	if err := json.Unmarshal(raw, &desired); err != nil {
+		return "", err
+	}
+	// Round trip the live object through JSON so that values have the same types as the desired content
+	liveJSON, err := json.Marshal(obj.Object)
+	if err != nil {
+		return "", err
+	}
+	var live map[string]interface{}
+	if err := json.Unmarshal(liveJSON, &live); err != nil {
+		return "", err
+	}
+
+	differences := []string{}
+	for _, key := range sortedKeys(desired) {
+		switch key {
+		case "apiVersion", "kind", "status":
+			continue
+		case "metadata":
+			desiredMetadata, _ := desired[key].(map[string]interface{})
+			liveMetadata, _ := live[key].(map[string]interface{})
+			for _, field := range []string{"labels", "annotations"} {
+				if value, ok := desiredMetadata[field]; ok {
+					differences = appendDifferences(differences, "metadata."+field, value, liveMetadata[field], liveMetadata != nil && liveMetadata[field] != nil)
+				}
+			}
+		default:
+			liveValue, found := live[key]
+			differences = appendDifferences(differences, key, desired[key], liveValue, found)
+		}
+	}
+	return summarizeDifferences(differences), nil
+}
+
+// appendDifferences appends the paths at which the live value does not match the desired value to differences
+func appendDifferences(differences []string, path string, desired, live interface{}, found bool) []string {
+	if desired == nil {
+		return differences
+	}
+	if !found {
+		return append(differences, fmt.Sprintf("%s: missing", path))
+	}
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			return append(differences, fmt.Sprintf("%s: expected an object", path))
+		}
+		for _, key := range sortedKeys(d) {
+			liveValue, found := l[key]
+			differences = appendDifferences(differences, path+"."+key, d[key], liveValue, found)
+		}
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok {
+			return append(differences, fmt.Sprintf("%s: expected a list", path))
+		}
+		if len(d) != len(l) {
+			return append(differences, fmt.Sprintf("%s: expected %d items, found %d", path, len(d), len(l)))
+		}
+		for i := range d {
+			differences = appendDifferences(differences, fmt.Sprintf("%s[%d]", path, i), d[i], l[i], true)
+		}
+	default:
+		if !reflect.DeepEqual(desired, live) {
+			return append(differences, fmt.Sprintf("%s: expected %v, found %v", path, desired, live))
+		}
+	}
+	return differences
+}
+
+// summarizeDifferences joins the first maxDriftDifferences differences into a single summary
+func summarizeDifferences(differences []string) string {
+	if len(differences) <= maxDriftDifferences {
+		return strings.Join(differences, "; ")
+	}
+	return fmt.Sprintf("%s; and %d more", strings.Join(differences[:maxDriftDifferences], "; "), len(differences)-maxDriftDifferences)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,184 @@
+package syncsetinstance
+
+import (
+	"crypto/md5"
+	"fmt"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+)
+
+// syncSetOwnerName returns a description of the SyncSet or SelectorSyncSet that ssi was created for
+func syncSetOwnerName(ssi *hivev1.SyncSetInstance) string {
+	switch {
+	case ssi.Spec.SyncSetRef != nil:
+		return fmt.Sprintf("SyncSet %s/%s", ssi.Namespace, ssi.Spec.SyncSetRef.Name)
+	case ssi.Spec.SelectorSyncSetRef != nil:
+		return fmt.Sprintf("SelectorSyncSet %s", ssi.Spec.SelectorSyncSetRef.Name)
+	default:
+		return fmt.Sprintf("SyncSetInstance %s/%s", ssi.Namespace, ssi.Name)
+	}
+}
+
+// syncSetOwnerLabelValue returns the value of the owner label of remote objects applied for ssi. Names of SyncSets and
+// ClusterDeployments can exceed the maximum length of a label value, so the value is a hash of them. It does not depend
+// on the status or UID of ssi, so that objects are still found when the syncset instance is recreated.
+func syncSetOwnerLabelValue(ssi *hivev1.SyncSetInstance) string {
+	owner := fmt.Sprintf("%s ClusterDeployment %s/%s", syncSetOwnerName(ssi), ssi.Namespace, ssi.Spec.ClusterDeploymentRef.Name)
+	return fmt.Sprintf("%x", md5.Sum([]byte(owner)))
+}
+
+// setSyncSetOwner labels and annotates obj as owned by the SyncSet and ClusterDeployment of ssi
+func setSyncSetOwner(obj metav1.Object, ssi *hivev1.SyncSetInstance) {
+	objLabels := obj.GetLabels()
+	if objLabels == nil {
+		objLabels = map[string]string{}
+	}
+	objLabels[constants.SyncSetOwnerLabel] = syncSetOwnerLabelValue(ssi)
+	obj.SetLabels(objLabels)
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[constants.SyncSetOwnerAnnotation] = syncSetOwnerName(ssi)
+	annotations[constants.SyncSetOwnerClusterDeploymentAnnotation] = fmt.Sprintf("%s/%s", ssi.Namespace, ssi.Spec.ClusterDeploymentRef.Name)
+	obj.SetAnnotations(annotations)
+}
+
+// withSyncSetOwner returns the raw resource with the owner label and annotations of ssi added
+func withSyncSetOwner(raw []byte, ssi *hivev1.SyncSetInstance) ([]byte, error) {
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(raw); err != nil {
+		return nil, err
+	}
+	setSyncSetOwner(obj, ssi)
+	return obj.MarshalJSON()
+}
+
+// checkExistingObject returns an error if policy is Refuse and the remote object exists without being owned by ssi.
+// Objects in the status of ssi were created by it before objects were labeled, and are not refused.
+func checkExistingObject(ssi *hivev1.SyncSetInstance, policy hivev1.SyncSetExistingObjectPolicy, syncStatus hivev1.SyncStatus, inStatus bool, dynamicClient dynamic.Interface) error {
+	if policy != hivev1.RefuseExistingObjectPolicy || inStatus {
+		return nil
+	}
+	gv, err := schema.ParseGroupVersion(syncStatus.APIVersion)
+	if err != nil {
+		return err
+	}
+	obj, err := dynamicClient.Resource(gv.WithResource(syncStatus.Resource)).Namespace(syncStatus.Namespace).Get(syncStatus.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if obj.GetLabels()[constants.SyncSetOwnerLabel] != syncSetOwnerLabelValue(ssi) {
+		return fmt.Errorf("%s %s/%s already exists and is not owned by %s", syncStatus.Kind, syncStatus.Namespace, syncStatus.Name, syncSetOwnerName(ssi))
+	}
+	return nil
+}
+
+// syncStatusResources returns the group version resources of the objects in the status lists, sorted by name
+func syncStatusResources(statusLists ...[]hivev1.SyncStatus) []schema.GroupVersionResource {
+	found := map[schema.GroupVersionResource]bool{}
+	for _, statusList := range statusLists {
+		for _, syncStatus := range statusList {
+			gv, err := schema.ParseGroupVersion(syncStatus.APIVersion)
+			if err != nil || syncStatus.Resource == "" {
+				continue
+			}
+			found[gv.WithResource(syncStatus.Resource)] = true
+		}
+	}
+	resources := make([]schema.GroupVersionResource, 0, len(found))
+	for gvr := range found {
+		resources = append(resources, gvr)
+	}
+	sort.Slice(resources, func(i, j int) bool { return resources[i].String() < resources[j].String() })
+	return resources
+}
+
+// ownedObjectResources returns the resources searched for remote objects owned by ssi. These are all the listable
+// resources of the remote cluster, so that owned objects are found even when their kind is in neither the status nor
+// the spec of ssi, along with the resources of the objects in the status lists in case discovery left them out.
+func (r *ReconcileSyncSetInstance) ownedObjectResources(kubeConfig []byte, statusLists [][]hivev1.SyncStatus, ssiLog log.FieldLogger) []schema.GroupVersionResource {
+	resources := syncStatusResources(statusLists...)
+	discovered, err := r.resourcesBuilder(string(kubeConfig))
+	if err != nil {
+		ssiLog.WithError(err).Warn("unable to discover resources of the cluster, only searching resources in status for owned objects")
+		return resources
+	}
+	found := map[schema.GroupVersionResource]bool{}
+	for _, gvr := range resources {
+		found[gvr] = true
+	}
+	for _, gvr := range discovered {
+		if !found[gvr] {
+			found[gvr] = true
+			resources = append(resources, gvr)
+		}
+	}
+	sort.Slice(resources, func(i, j int) bool { return resources[i].String() < resources[j].String() })
+	return resources
+}
+
+// pruneSyncSetObjects deletes the remote objects that are labeled as owned by ssi and are not in the keep status lists.
+// Unlike reconcileDeleted, this does not rely on the status of ssi to know which objects were applied before, so
+// objects are also cleaned up after the status was lost.
+func (r *ReconcileSyncSetInstance) pruneSyncSetObjects(ssi *hivev1.SyncSetInstance, previous, keep [][]hivev1.SyncStatus, kubeConfig []byte, dynamicClient dynamic.Interface, ssiLog log.FieldLogger) error {
+	resources := r.ownedObjectResources(kubeConfig, append(previous, keep...), ssiLog)
+	kept := map[string]bool{}
+	for _, statusList := range keep {
+		for _, syncStatus := range statusList {
+			gv, err := schema.ParseGroupVersion(syncStatus.APIVersion)
+			if err != nil {
+				continue
+			}
+			kept[pruneKey(gv.WithResource(syncStatus.Resource).GroupResource(), syncStatus.Namespace, syncStatus.Name)] = true
+		}
+	}
+
+	selector := labels.SelectorFromSet(labels.Set{constants.SyncSetOwnerLabel: syncSetOwnerLabelValue(ssi)}).String()
+	var lastError error
+	for _, gvr := range resources {
+		list, err := dynamicClient.Resource(gvr).List(metav1.ListOptions{LabelSelector: selector})
+		if errors.IsNotFound(err) {
+			// The resource no longer exists on the cluster, and neither do its objects
+			continue
+		}
+		if err != nil {
+			ssiLog.WithError(err).WithField("resource", gvr.String()).Warn("unable to list owned objects")
+			lastError = err
+			continue
+		}
+		for _, obj := range list.Items {
+			if kept[pruneKey(gvr.GroupResource(), obj.GetNamespace(), obj.GetName())] {
+				continue
+			}
+			itemLog := ssiLog.WithField("object", fmt.Sprintf("%s/%s", obj.GetNamespace(), obj.GetName())).
+				WithField("resource", gvr.String())
+			itemLog.Info("pruning object that is no longer part of the syncset")
+			err := dynamicClient.Resource(gvr).Namespace(obj.GetNamespace()).Delete(obj.GetName(), &metav1.DeleteOptions{})
+			if err != nil && !errors.IsNotFound(err) {
+				itemLog.WithError(err).Warn("error pruning object")
+				lastError = err
+			}
+		}
+	}
+	return lastError
+}
+
+func pruneKey(gr schema.GroupResource, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s", gr.String(), namespace, name)
+}
@@ -0,0 +1,111 @@
+package syncsetinstance
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/openshift/hive/pkg/constants"
+	hiveresource "github.com/openshift/hive/pkg/resource"
+	"github.com/openshift/hive/pkg/syncsetreadiness"
+)
+
+// resourceAnnotations returns the annotations found in the metadata of a raw resource
+func resourceAnnotations(raw []byte) (map[string]string, error) {
+	obj := &struct {
+		Metadata metav1.ObjectMeta `json:"metadata"`
+	}{}
+	if err := json.Unmarshal(raw, obj); err != nil {
+		return nil, err
+	}
+	return obj.Metadata.Annotations, nil
+}
+
+// resourceApplyWave returns the apply wave set on a resource with the apply wave annotation, or 0 if not set
+func resourceApplyWave(annotations map[string]string) (int, error) {
+	value, ok := annotations[constants.SyncSetApplyWaveAnnotation]
+	if !ok {
+		return 0, nil
+	}
+	wave, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s annotation %q: %v", constants.SyncSetApplyWaveAnnotation, value, err)
+	}
+	return wave, nil
+}
+
+// isResourceReady determines whether a resource applied to the remote cluster is ready for the next apply wave to
+// proceed. It returns a message describing why the resource is not ready.
+func isResourceReady(dynamicClient dynamic.Interface, info hiveresource.Info, readinessCheck string) (bool, string, error) {
+	gv, err := schema.ParseGroupVersion(info.APIVersion)
+	if err != nil {
+		return false, "", err
+	}
+	obj, err := dynamicClient.Resource(gv.WithResource(info.Resource)).Namespace(info.Namespace).Get(info.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return false, "resource does not exist", nil
+	}
+	if err != nil {
+		return false, "", err
+	}
+
+	switch {
+	case readinessCheck != "":
+		return checkCustomReadiness(obj, readinessCheck)
+	case gv.Group == "apiextensions.k8s.io" && info.Kind == "CustomResourceDefinition":
+		if !hasTrueStatusCondition(obj, "Established") {
+			return false, "CustomResourceDefinition is not established", nil
+		}
+	case (gv.Group == "apps" || gv.Group == "extensions") && info.Kind == "Deployment":
+		generation := obj.GetGeneration()
+		observedGeneration, _, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+		if observedGeneration < generation {
+			return false, "Deployment has not observed the latest generation", nil
+		}
+		if !hasTrueStatusCondition(obj, "Available") {
+			return false, "Deployment is not available", nil
+		}
+	}
+	return true, "", nil
+}
+
+func checkCustomReadiness(obj *unstructured.Unstructured, readinessCheck string) (bool, string, error) {
+	path, expected, hasExpected, err := syncsetreadiness.ParseCheck(readinessCheck)
+	if err != nil {
+		return false, "", err
+	}
+	buf := &bytes.Buffer{}
+	if err := path.Execute(buf, obj.Object); err != nil {
+		return false, fmt.Sprintf("readiness check %s cannot be evaluated: %v", readinessCheck, err), nil
+	}
+	actual := strings.TrimSpace(buf.String())
+	switch {
+	case hasExpected && actual != expected:
+		return false, fmt.Sprintf("readiness check %s not met, current value: %q", readinessCheck, actual), nil
+	case !hasExpected && actual == "":
+		return false, fmt.Sprintf("readiness check %s not met", readinessCheck), nil
+	}
+	return true, "", nil
+}
+
+func hasTrueStatusCondition(obj *unstructured.Unstructured, conditionType string) bool {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if condition["type"] == conditionType {
+			return condition["status"] == "True"
+		}
+	}
+	return false
+}
@@ -21,7 +21,10 @@ import (
 	"crypto/md5"
 	"encoding/json"
 	"fmt"
+	"os"
 	"reflect"
+	"sort"
+	"strings"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -47,6 +50,7 @@ import (
 	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
 	controllerutils "github.com/openshift/hive/pkg/controller/utils"
 	hiveresource "github.com/openshift/hive/pkg/resource"
+	"github.com/openshift/hive/pkg/syncsettemplate"
 )
 
 const (
@@ -55,10 +59,25 @@ const (
 	applySucceededReason     = "ApplySucceeded"
 	applyFailedReason        = "ApplyFailed"
 	deletionFailedReason     = "DeletionFailed"
+	deletionSucceededReason  = "DeletionSucceeded"
+	templateFailedReason     = "TemplateExpansionFailed"
+	templateSucceededReason  = "TemplateExpansionSucceeded"
 	reapplyInterval          = 2 * time.Hour
+	readinessRequeueInterval = 15 * time.Second
+	driftCheckInterval       = 10 * time.Minute
+	driftDetectedReason      = "DriftDetected"
+	driftRevertedReason      = "DriftReverted"
+	noDriftReason            = "NoDrift"
+	notReadyReason           = "NotReady"
+	readyReason              = "Ready"
+	conflictDetectedReason   = "ConflictDetected"
+	noConflictReason         = "NoConflict"
 	secretsResource          = "secrets"
 	secretKind               = "Secret"
 	secretAPIVersion         = "v1"
+	configMapsResource       = "configmaps"
+	configMapKind            = "ConfigMap"
+	configMapAPIVersion      = "v1"
 )
 
 // Applier knows how to Apply, Patch and return Info for []byte arrays describing objects and patches.
@@ -67,6 +86,8 @@ type Applier interface {
 	Info(obj []byte) (*hiveresource.Info, error)
 	Patch(name types.NamespacedName, kind, apiVersion string, patch []byte, patchType string) error
 	ApplyRuntimeObject(obj runtime.Object, scheme *runtime.Scheme) (hiveresource.ApplyResult, error)
+	// ResetRESTMapper lets kinds added to the cluster since its resources were last discovered be found by Info
+	ResetRESTMapper()
 }
 
 // Add creates a new SyncSet controller and adds it to the manager with default RBAC. The manager will set fields on the
@@ -81,17 +102,33 @@ func NewReconciler(mgr manager.Manager) reconcile.Reconciler {
 		Client:               controllerutils.NewClientWithMetricsOrDie(mgr, controllerName),
 		scheme:               mgr.GetScheme(),
 		logger:               log.WithField("controller", controllerName),
-		applierBuilder:       applierBuilderFunc,
-		dynamicClientBuilder: controllerutils.BuildDynamicClientFromKubeconfig,
+		applierBuilder:       newApplierBuilder(),
+		dynamicClientBuilder: controllerutils.RemoteClusters.DynamicClient,
+		resourcesBuilder:     controllerutils.RemoteClusters.Resources,
 	}
 	r.hash = r.resourceHash
 	return r
 }
 
 // applierBuilderFunc returns an Applier which implements Info, Apply and Patch
-func applierBuilderFunc(kubeConfig []byte, logger log.FieldLogger) Applier {
-	var helper Applier = hiveresource.NewHelper(kubeConfig, logger)
-	return helper
+func applierBuilderFunc(kubeConfig []byte, logger log.FieldLogger) (Applier, error) {
+	var helper Applier = hiveresource.NewHelperWithMetrics(kubeConfig, controllerName, true, logger)
+	return helper, nil
+}
+
+// newApplierBuilder returns the function used to build Appliers, using server-side apply if configured in HiveConfig
+func newApplierBuilder() func([]byte, log.FieldLogger) (Applier, error) {
+	if hivev1.SyncSetApplierType(os.Getenv(constants.SyncSetApplierEnvVar)) != hivev1.ServerSideSyncSetApplierType {
+		return applierBuilderFunc
+	}
+	options := hiveresource.ServerSideApplyOptions{
+		FieldManager:   os.Getenv(constants.SyncSetApplierFieldManagerEnvVar),
+		ForceConflicts: os.Getenv(constants.SyncSetApplierForceConflictsEnvVar) == "true",
+		ControllerName: controllerName,
+	}
+	return func(kubeConfig []byte, logger log.FieldLogger) (Applier, error) {
+		return hiveresource.NewServerSideApplier(kubeConfig, options, logger)
+	}
 }
 
 // AddToManager adds a new Controller to mgr with r as the reconcile.Reconciler
@@ -153,9 +190,11 @@ type ReconcileSyncSetInstance struct {
 	scheme *runtime.Scheme
 
 	logger               log.FieldLogger
-	applierBuilder       func([]byte, log.FieldLogger) Applier
+	applierBuilder       func([]byte, log.FieldLogger) (Applier, error)
 	hash                 func([]byte) string
 	dynamicClientBuilder func(string, string) (dynamic.Interface, error)
+	// resourcesBuilder returns the resources of the remote cluster of a kubeconfig that are searched for owned objects
+	resourcesBuilder func(string) ([]schema.GroupVersionResource, error)
 }
 
 // Reconcile applies SyncSet or SelectorSyncSets associated with SyncSetInstances to the owning cluster.
@@ -186,7 +225,17 @@ func (r *ReconcileSyncSetInstance) Reconcile(request reconcile.Request) (reconci
 		if !controllerutils.HasFinalizer(ssi, hivev1.FinalizerSyncSetInstance) {
 			return reconcile.Result{}, nil
 		}
-		return r.syncDeletedSyncSetInstance(ssi, ssiLog)
+		result, err := r.syncDeletedSyncSetInstance(ssi, ssiLog)
+		if err != nil || controllerutils.HasFinalizer(ssi, hivev1.FinalizerSyncSetInstance) {
+			return result, err
+		}
+		// Objects of the deleted syncset instance no longer conflict with other syncset instances
+		otherSyncSetInstances, err := r.getOtherSyncSetInstances(ssi)
+		if err != nil {
+			ssiLog.WithError(err).Error("unable to list syncset instances of the clusterdeployment")
+			return result, err
+		}
+		return result, r.updateConflictingSyncSetInstances(ssi, otherSyncSetInstances, ssiLog)
 	}
 
 	if !controllerutils.HasFinalizer(ssi, hivev1.FinalizerSyncSetInstance) {
@@ -244,6 +293,15 @@ func (r *ReconcileSyncSetInstance) Reconcile(request reconcile.Request) (reconci
 		return reconcile.Result{}, err
 	}
 
+	original := ssi.DeepCopy()
+	spec, err = r.expandSyncSetTemplates(ssi, spec, cd, ssiLog)
+	if err != nil {
+		if updateErr := r.updateSyncSetInstanceStatus(ssi, original, ssiLog); updateErr != nil {
+			return reconcile.Result{}, updateErr
+		}
+		return reconcile.Result{}, err
+	}
+
 	// get kubeconfig for the cluster
 	adminKubeconfigSecret, err := r.getKubeconfigSecret(cd, ssiLog)
 	if err != nil {
@@ -260,15 +318,37 @@ func (r *ReconcileSyncSetInstance) Reconcile(request reconcile.Request) (reconci
 		return reconcile.Result{}, err
 	}
 	ssiLog.Debug("applying sync set")
-	original := ssi.DeepCopy()
-	applier := r.applierBuilder(kubeConfig, ssiLog)
-	applyErr := r.applySyncSet(ssi, spec, dynamicClient, applier, kubeConfig, ssiLog)
+	applier, err := r.applierBuilder(kubeConfig, ssiLog)
+	if err != nil {
+		ssiLog.WithError(err).Error("unable to build applier")
+		return reconcile.Result{}, err
+	}
+	waiting, applyErr := r.applySyncSet(ssi, spec, dynamicClient, applier, kubeConfig, ssiLog)
+	if applyErr == nil && !waiting {
+		ssi.Status.AppliedSyncSetHash = ssi.Spec.SyncSetHash
+	}
+	otherSyncSetInstances, err := r.getOtherSyncSetInstances(ssi)
+	if err != nil {
+		ssiLog.WithError(err).Error("unable to list syncset instances of the clusterdeployment")
+		return reconcile.Result{}, err
+	}
+	ssi.Status.Conditions = r.setResourceConflictSyncCondition(ssi.Status.Conditions, controllerutils.SyncSetInstanceConflicts(ssi, otherSyncSetInstances))
 	err = r.updateSyncSetInstanceStatus(ssi, original, ssiLog)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
+	if err := r.updateConflictingSyncSetInstances(ssi, otherSyncSetInstances, ssiLog); err != nil {
+		return reconcile.Result{}, err
+	}
 
 	ssiLog.Info("done reconciling syncsetinstance")
+	if waiting && applyErr == nil {
+		return reconcile.Result{RequeueAfter: readinessRequeueInterval}, nil
+	}
+	if detectsDrift(spec.DriftPolicy) && applyErr == nil {
+		// Periodically check remote objects for drift
+		return reconcile.Result{RequeueAfter: driftCheckInterval}, nil
+	}
 	return reconcile.Result{}, applyErr
 }
 
@@ -371,12 +451,25 @@ func (r *ReconcileSyncSetInstance) syncDeletedSyncSetInstance(ssi *hivev1.SyncSe
 	if err != nil {
 		return reconcile.Result{}, err
 	}
+	err = r.deleteSyncSetConfigMapReferences(ssi, dynamicClient, ssiLog)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	// Also delete owned objects that are missing from the status
+	previous := [][]hivev1.SyncStatus{ssi.Status.Resources, ssi.Status.SecretReferences, ssi.Status.ConfigMapReferences}
+	err = r.pruneSyncSetObjects(ssi, previous, nil, kubeConfig, dynamicClient, ssiLog)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
 	return reconcile.Result{}, r.removeSyncSetInstanceFinalizer(ssi, ssiLog)
 }
 
-func (r *ReconcileSyncSetInstance) applySyncSet(ssi *hivev1.SyncSetInstance, spec *hivev1.SyncSetCommonSpec, dynamicClient dynamic.Interface, h Applier, kubeConfig []byte, ssiLog log.FieldLogger) error {
+// applySyncSet applies the resources, patches, secret references, configmap references and deletions of a syncset. It returns
+// true if resources in later apply waves are waiting for earlier ones to become ready, in which case patches, references and
+// deletions are not applied yet.
+func (r *ReconcileSyncSetInstance) applySyncSet(ssi *hivev1.SyncSetInstance, spec *hivev1.SyncSetCommonSpec, dynamicClient dynamic.Interface, h Applier, kubeConfig []byte, ssiLog log.FieldLogger) (bool, error) {
 	defer func() {
-		// Temporary fix for status hot loop: do not update ssi.Status.{Patches,Resources,SecretReferences} with empty slice.
+		// Temporary fix for status hot loop: do not update ssi.Status.{Patches,Resources,SecretReferences,ConfigMapReferences,Deletions} with empty slice.
 		if len(ssi.Status.Resources) == 0 {
 			ssi.Status.Resources = nil
 		}
@@ -386,15 +479,37 @@ func (r *ReconcileSyncSetInstance) applySyncSet(ssi *hivev1.SyncSetInstance, spe
 		if len(ssi.Status.SecretReferences) == 0 {
 			ssi.Status.SecretReferences = nil
 		}
+		if len(ssi.Status.ConfigMapReferences) == 0 {
+			ssi.Status.ConfigMapReferences = nil
+		}
+		if len(ssi.Status.Deletions) == 0 {
+			ssi.Status.Deletions = nil
+		}
 	}()
 
-	if err := r.applySyncSetResources(ssi, spec.Resources, dynamicClient, h, ssiLog); err != nil {
-		return err
+	// Objects applied before, whose resources are searched for owned objects to prune
+	previous := [][]hivev1.SyncStatus{ssi.Status.Resources, ssi.Status.SecretReferences, ssi.Status.ConfigMapReferences}
+
+	waiting, err := r.applySyncSetResources(ssi, spec.Resources, spec.DriftPolicy, spec.ExistingObjectPolicy, dynamicClient, h, ssiLog)
+	if err != nil || waiting {
+		return waiting, err
 	}
-	if err := r.applySyncSetPatches(ssi, spec.Patches, kubeConfig, ssiLog); err != nil {
-		return err
+	if err := r.applySyncSetPatches(ssi, spec.Patches, h, ssiLog); err != nil {
+		return false, err
+	}
+	if err := r.applySyncSetSecretReferences(ssi, spec.SecretReferences, spec.ExistingObjectPolicy, dynamicClient, h, ssiLog); err != nil {
+		return false, err
+	}
+	if err := r.applySyncSetConfigMapReferences(ssi, spec.ConfigMapReferences, spec.ExistingObjectPolicy, dynamicClient, h, ssiLog); err != nil {
+		return false, err
 	}
-	return r.applySyncSetSecretReferences(ssi, spec.SecretReferences, dynamicClient, h, ssiLog)
+	if ssi.Spec.ResourceApplyMode == hivev1.SyncResourceApplyMode {
+		keep := [][]hivev1.SyncStatus{ssi.Status.Resources, ssi.Status.SecretReferences, ssi.Status.ConfigMapReferences}
+		if err := r.pruneSyncSetObjects(ssi, previous, keep, kubeConfig, dynamicClient, ssiLog); err != nil {
+			return false, err
+		}
+	}
+	return false, r.applySyncSetDeletions(ssi, spec.Deletions, dynamicClient, h, ssiLog)
 }
 
 func (r *ReconcileSyncSetInstance) deleteSyncSetResources(ssi *hivev1.SyncSetInstance, dynamicClient dynamic.Interface, ssiLog log.FieldLogger) error {
@@ -457,8 +572,40 @@ func (r *ReconcileSyncSetInstance) deleteSyncSetSecretReferences(ssi *hivev1.Syn
 	return lastError
 }
 
+func (r *ReconcileSyncSetInstance) deleteSyncSetConfigMapReferences(ssi *hivev1.SyncSetInstance, dynamicClient dynamic.Interface, ssiLog log.FieldLogger) error {
+	var lastError error
+	for index, configMapStatus := range ssi.Status.ConfigMapReferences {
+		configMapLog := ssiLog.WithField("configmap", fmt.Sprintf("%s/%s", configMapStatus.Namespace, configMapStatus.Name)).
+			WithField("apiVersion", configMapStatus.APIVersion).
+			WithField("kind", configMapStatus.Kind)
+		gv, err := schema.ParseGroupVersion(configMapStatus.APIVersion)
+		if err != nil {
+			configMapLog.WithError(err).Error("cannot parse configmap apiVersion, skipping deletion")
+			continue
+		}
+		gvr := gv.WithResource(configMapStatus.Resource)
+		configMapLog.Debug("deleting configmap")
+		err = dynamicClient.Resource(gvr).Namespace(configMapStatus.Namespace).Delete(configMapStatus.Name, &metav1.DeleteOptions{})
+		if err != nil {
+			switch {
+			case errors.IsNotFound(err):
+				configMapLog.Debug("configmap not found, nothing to do")
+			case errors.IsForbidden(err):
+				configMapLog.WithError(err).Error("forbidden configmap deletion, skipping")
+			default:
+				lastError = err
+				configMapLog.WithError(err).Error("error deleting configmap")
+				ssi.Status.ConfigMapReferences[index].Conditions = r.setDeletionFailedSyncCondition(ssi.Status.ConfigMapReferences[index].Conditions, fmt.Errorf("failed to delete configmap: %v", err))
+			}
+		}
+	}
+	return lastError
+}
+
 // getSyncSetCommonSpec returns the common spec of the associated syncset or selectorsyncset. It returns a boolean indicating
-// whether the source object (syncset or selectorsyncset) has been deleted or is in the process of being deleted.
+// whether the source object (syncset or selectorsyncset) has been deleted or is in the process of being deleted. While a
+// selectorsyncset change is waiting to be rolled out to the syncsetinstance, the spec that has been rolled out to it is
+// read from the rollout snapshot for the hash of the syncsetinstance, or no spec is returned if there is no snapshot.
 func (r *ReconcileSyncSetInstance) getSyncSetCommonSpec(ssi *hivev1.SyncSetInstance, ssiLog log.FieldLogger) (*hivev1.SyncSetCommonSpec, bool, error) {
 	if ssi.Spec.SyncSetRef != nil {
 		syncSet := &hivev1.SyncSet{}
@@ -485,12 +632,70 @@ func (r *ReconcileSyncSetInstance) getSyncSetCommonSpec(ssi *hivev1.SyncSetInsta
 			ssiLog.WithField("selectorsyncset", selectorSyncSetName).Error("cannot get associated selectorsyncset")
 			return nil, false, err
 		}
+		if selectorSyncSet.Spec.Rollout != nil {
+			// With a rollout strategy, the current spec is only applied once it has been rolled out to this instance
+			hash, err := controllerutils.GetChecksumOfObject(controllerutils.SelectorSyncSetContentSpec(selectorSyncSet.Spec))
+			if err != nil {
+				ssiLog.WithError(err).WithField("selectorsyncset", selectorSyncSetName).Error("cannot compute selectorsyncset hash")
+				return nil, false, err
+			}
+			if hash != ssi.Spec.SyncSetHash {
+				snapshot := &corev1.ConfigMap{}
+				snapshotName := types.NamespacedName{
+					Namespace: constants.HiveNamespace,
+					Name:      controllerutils.RolloutSnapshotName(selectorSyncSet.Name, ssi.Spec.SyncSetHash),
+				}
+				err := r.Get(context.TODO(), snapshotName, snapshot)
+				if errors.IsNotFound(err) {
+					ssiLog.WithField("selectorsyncset", selectorSyncSetName).Info("selectorsyncset change has not been rolled out to this instance yet")
+					return nil, false, nil
+				}
+				if err != nil {
+					ssiLog.WithError(err).WithField("snapshot", snapshotName).Error("cannot get rollout snapshot of selectorsyncset")
+					return nil, false, err
+				}
+				ssiLog.WithField("selectorsyncset", selectorSyncSetName).Debug("selectorsyncset change has not been rolled out to this instance yet, applying the rolled out spec")
+				spec, err := controllerutils.RolloutSnapshotSpec(snapshot)
+				if err != nil {
+					ssiLog.WithError(err).WithField("snapshot", snapshotName).Error("cannot read rollout snapshot of selectorsyncset")
+					return nil, false, err
+				}
+				return spec, false, nil
+			}
+		}
 		return &selectorSyncSet.Spec.SyncSetCommonSpec, false, nil
 	}
 	ssiLog.Error("invalid syncsetinstance, no reference found to syncset or selectorsyncset")
 	return nil, false, nil
 }
 
+// expandSyncSetTemplates expands the templates in spec for the given clusterdeployment and records the outcome
+// in the syncsetinstance status.
+func (r *ReconcileSyncSetInstance) expandSyncSetTemplates(ssi *hivev1.SyncSetInstance, spec *hivev1.SyncSetCommonSpec, cd *hivev1.ClusterDeployment, ssiLog log.FieldLogger) (*hivev1.SyncSetCommonSpec, error) {
+	expanded, err := syncsettemplate.Expand(spec, cd)
+	if err != nil {
+		ssiLog.WithError(err).Warn("unable to expand syncset templates")
+		ssi.Status.Conditions = controllerutils.SetSyncCondition(
+			ssi.Status.Conditions,
+			hivev1.TemplateExpansionFailureSyncCondition,
+			corev1.ConditionTrue,
+			templateFailedReason,
+			fmt.Sprintf("Unable to expand SyncSet templates: %v", err),
+			controllerutils.UpdateConditionIfReasonOrMessageChange,
+		)
+		return nil, err
+	}
+	ssi.Status.Conditions = controllerutils.SetSyncCondition(
+		ssi.Status.Conditions,
+		hivev1.TemplateExpansionFailureSyncCondition,
+		corev1.ConditionFalse,
+		templateSucceededReason,
+		"SyncSet templates expanded successfully",
+		controllerutils.UpdateConditionIfReasonOrMessageChange,
+	)
+	return expanded, nil
+}
+
 // findSyncStatus returns a SyncStatus matching the provided status from a list of SyncStatus
 func findSyncStatus(status hivev1.SyncStatus, statusList []hivev1.SyncStatus) *hivev1.SyncStatus {
 	for _, ss := range statusList {
@@ -529,77 +734,192 @@ func needToReApply(applyTerm string, newStatus, existingStatus hivev1.SyncStatus
 	return false
 }
 
-// applySyncSetResources evaluates resource objects from RawExtension and applies them to the cluster identified by kubeConfig
-func (r *ReconcileSyncSetInstance) applySyncSetResources(ssi *hivev1.SyncSetInstance, resources []runtime.RawExtension, dynamicClient dynamic.Interface, h Applier, ssiLog log.FieldLogger) error {
-	// determine if we can gather info for all resources
-	infos := []hiveresource.Info{}
+// applySyncSetResources evaluates resource objects from RawExtension and applies them to the cluster identified by kubeConfig.
+// Resources are applied in order of their apply wave, and the resources of a wave must be ready before the next wave is
+// applied. The info of the resources of a wave is only gathered once the earlier waves are ready, as their kinds may be
+// added by the resources of an earlier wave. It returns true if a later wave is waiting for the resources of an earlier
+// wave to become ready.
+func (r *ReconcileSyncSetInstance) applySyncSetResources(ssi *hivev1.SyncSetInstance, resources []runtime.RawExtension, driftPolicy hivev1.SyncSetDriftPolicy, existingObjectPolicy hivev1.SyncSetExistingObjectPolicy, dynamicClient dynamic.Interface, h Applier, ssiLog log.FieldLogger) (bool, error) {
+	waves := []int{}
+	readinessChecks := []string{}
 	for i, resource := range resources {
-		info, err := h.Info(resource.Raw)
+		annotations, err := resourceAnnotations(resource.Raw)
+		var wave int
+		if err == nil {
+			wave, err = resourceApplyWave(annotations)
+		}
 		if err != nil {
 			ssi.Status.Conditions = r.setUnknownObjectSyncCondition(ssi.Status.Conditions, err, i)
 			ssiLog.WithError(err).Warn("unable to parse resource")
-			return err
+			return false, err
 		}
-		infos = append(infos, *info)
+		waves = append(waves, wave)
+		readinessChecks = append(readinessChecks, annotations[constants.SyncSetReadinessCheckAnnotation])
 	}
 
-	ssi.Status.Conditions = r.clearUnknownObjectSyncCondition(ssi.Status.Conditions)
 	syncStatusList := []hivev1.SyncStatus{}
+	infos := make([]hiveresource.Info, len(resources))
 
-	var applyErr error
-	for i, resource := range resources {
-		resourceSyncStatus := hivev1.SyncStatus{
-			APIVersion: infos[i].APIVersion,
-			Kind:       infos[i].Kind,
-			Resource:   infos[i].Resource,
-			Name:       infos[i].Name,
-			Namespace:  infos[i].Namespace,
-			Hash:       r.hash(resource.Raw),
-		}
-
-		if rss := findSyncStatus(resourceSyncStatus, ssi.Status.Resources); rss == nil || needToReApply("resource", resourceSyncStatus, *rss, ssiLog) {
-			// Apply resource
-			ssiLog.Debugf("applying resource: %s/%s (%s)", resourceSyncStatus.Namespace, resourceSyncStatus.Name, resourceSyncStatus.Kind)
-			var applyResult hiveresource.ApplyResult
-			applyResult, applyErr = h.Apply(resource.Raw)
-
-			var resourceSyncConditions []hivev1.SyncCondition
-			if rss != nil {
-				resourceSyncConditions = rss.Conditions
+	order := make([]int, len(resources))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return waves[order[i]] < waves[order[j]] })
+
+	var applyErr, infoErr error
+	var waitingFor []string
+	for start := 0; start < len(order) && applyErr == nil && len(waitingFor) == 0; {
+		wave := waves[order[start]]
+		end := start
+		for end < len(order) && waves[order[end]] == wave {
+			end++
+		}
+		if start > 0 {
+			// The resources of earlier waves may have added kinds to the cluster
+			h.ResetRESTMapper()
+		}
+		for _, i := range order[start:end] {
+			var info *hiveresource.Info
+			info, infoErr = h.Info(resources[i].Raw)
+			if infoErr != nil {
+				ssi.Status.Conditions = r.setUnknownObjectSyncCondition(ssi.Status.Conditions, infoErr, i)
+				ssiLog.WithError(infoErr).Warn("unable to parse resource")
+				break
 			}
-			resourceSyncStatus.Conditions = r.setApplySyncConditions(resourceSyncConditions, applyErr)
+			infos[i] = *info
+		}
+		if infoErr != nil {
+			applyErr = infoErr
+			break
+		}
+
+		waveStart := len(syncStatusList)
+		for _, i := range order[start:end] {
+			var resourceSyncStatus hivev1.SyncStatus
+			resourceSyncStatus, applyErr = r.applySyncSetResource(ssi, resources[i], infos[i], driftPolicy, existingObjectPolicy, dynamicClient, h, ssiLog)
+			syncStatusList = append(syncStatusList, resourceSyncStatus)
 
+			// If an error applying occurred, stop processing right here
 			if applyErr != nil {
-				ssiLog.WithError(applyErr).Warnf("error applying resource %s/%s (%s)", resourceSyncStatus.Namespace, resourceSyncStatus.Name, resourceSyncStatus.Kind)
-			} else {
-				ssiLog.Debugf("resource %s/%s (%s): %s", resourceSyncStatus.Namespace, resourceSyncStatus.Name, resourceSyncStatus.Kind, applyResult)
+				break
 			}
-		} else {
-			// Do not apply resource
-			ssiLog.Debugf("resource %s/%s (%s) has not changed, will not apply", resourceSyncStatus.Namespace, resourceSyncStatus.Name, resourceSyncStatus.Kind)
-			resourceSyncStatus.Conditions = rss.Conditions
 		}
-
-		syncStatusList = append(syncStatusList, resourceSyncStatus)
-
-		// If an error applying occurred, stop processing right here
-		if applyErr != nil {
+		if applyErr != nil || end == len(order) {
 			break
 		}
+
+		// Resources in later waves are only applied once all resources in this wave are ready
+		for statusIndex, i := range order[start:end] {
+			resourceSyncStatus := &syncStatusList[waveStart+statusIndex]
+			ready, message, err := isResourceReady(dynamicClient, infos[i], readinessChecks[i])
+			if err != nil {
+				ssiLog.WithError(err).Warnf("cannot determine readiness of resource %s/%s (%s)", infos[i].Namespace, infos[i].Name, infos[i].Kind)
+				message = fmt.Sprintf("cannot determine readiness: %v", err)
+			}
+			resourceSyncStatus.Conditions = r.setReadySyncCondition(resourceSyncStatus.Conditions, ready, message)
+			if !ready {
+				waitingFor = append(waitingFor, fmt.Sprintf("%s/%s (%s)", infos[i].Namespace, infos[i].Name, infos[i].Kind))
+			}
+		}
+		if len(waitingFor) > 0 {
+			ssiLog.Infof("waiting for resources in apply wave %d to become ready: %s", wave, strings.Join(waitingFor, ", "))
+			ssi.Status.Conditions = r.setWaitingForReadinessSyncCondition(ssi.Status.Conditions, true,
+				fmt.Sprintf("Waiting for resources in apply wave %d to become ready: %s", wave, strings.Join(waitingFor, ", ")))
+		}
+		start = end
+	}
+	if infoErr == nil {
+		ssi.Status.Conditions = r.clearUnknownObjectSyncCondition(ssi.Status.Conditions)
+	}
+	if len(waitingFor) == 0 {
+		ssi.Status.Conditions = r.setWaitingForReadinessSyncCondition(ssi.Status.Conditions, false, "All apply waves are ready")
 	}
 
-	ssi.Status.Resources = r.reconcileDeleted("resource", ssi.Spec.ResourceApplyMode, dynamicClient, ssi.Status.Resources, syncStatusList, applyErr, ssiLog)
+	// Resources in waves that were not reached are kept in the status so that they are not deleted
+	ssi.Status.Resources = r.reconcileDeleted("resource", ssi.Spec.ResourceApplyMode, dynamicClient, ssi.Status.Resources, syncStatusList, applyErr != nil || len(waitingFor) > 0, ssiLog)
 
 	// Return applyErr for the controller to trigger retries and go into exponential backoff
 	// if the problem does not resolve itself.
 	if applyErr != nil {
-		return applyErr
+		return false, applyErr
 	}
 
-	return nil
+	return len(waitingFor) > 0, nil
+}
+
+// applySyncSetResource applies a single resource if it has changed or needs to be re-applied, and returns its updated status.
+// Unchanged resources are checked for drift according to driftPolicy. Applied resources are labeled as owned by the syncset,
+// and existing objects not owned by it are handled according to existingObjectPolicy.
+func (r *ReconcileSyncSetInstance) applySyncSetResource(ssi *hivev1.SyncSetInstance, resource runtime.RawExtension, info hiveresource.Info, driftPolicy hivev1.SyncSetDriftPolicy, existingObjectPolicy hivev1.SyncSetExistingObjectPolicy, dynamicClient dynamic.Interface, h Applier, ssiLog log.FieldLogger) (hivev1.SyncStatus, error) {
+	resourceSyncStatus := hivev1.SyncStatus{
+		APIVersion: info.APIVersion,
+		Kind:       info.Kind,
+		Resource:   info.Resource,
+		Name:       info.Name,
+		Namespace:  info.Namespace,
+		Hash:       r.hash(resource.Raw),
+	}
+
+	rss := findSyncStatus(resourceSyncStatus, ssi.Status.Resources)
+	drift := ""
+	if rss != nil && rss.Hash == resourceSyncStatus.Hash && detectsDrift(driftPolicy) {
+		var err error
+		drift, err = resourceDrift(dynamicClient, info, resource.Raw)
+		if err != nil {
+			ssiLog.WithError(err).Warnf("cannot determine drift of resource %s/%s (%s)", resourceSyncStatus.Namespace, resourceSyncStatus.Name, resourceSyncStatus.Kind)
+		} else {
+			rss.Conditions = r.setDriftedSyncCondition(rss.Conditions, drift, driftDetectedReason)
+		}
+		if drift != "" {
+			ssiLog.Infof("resource %s/%s (%s) has drifted: %s", resourceSyncStatus.Namespace, resourceSyncStatus.Name, resourceSyncStatus.Kind, drift)
+		}
+		if drift != "" && driftPolicy == hivev1.ReportDriftPolicy {
+			// Drift is only reported, the resource must not be reverted by a periodic re-apply either
+			resourceSyncStatus.Conditions = rss.Conditions
+			return resourceSyncStatus, nil
+		}
+	}
+	if rss != nil && drift == "" && !needToReApply("resource", resourceSyncStatus, *rss, ssiLog) {
+		// Do not apply resource
+		ssiLog.Debugf("resource %s/%s (%s) has not changed, will not apply", resourceSyncStatus.Namespace, resourceSyncStatus.Name, resourceSyncStatus.Kind)
+		resourceSyncStatus.Conditions = rss.Conditions
+		return resourceSyncStatus, nil
+	}
+
+	var resourceSyncConditions []hivev1.SyncCondition
+	if rss != nil {
+		resourceSyncConditions = rss.Conditions
+	}
+	owned, err := withSyncSetOwner(resource.Raw, ssi)
+	if err == nil {
+		err = checkExistingObject(ssi, existingObjectPolicy, resourceSyncStatus, rss != nil, dynamicClient)
+	}
+	if err != nil {
+		ssiLog.WithError(err).Warnf("will not apply resource %s/%s (%s)", resourceSyncStatus.Namespace, resourceSyncStatus.Name, resourceSyncStatus.Kind)
+		resourceSyncStatus.Conditions = r.setApplySyncConditions(resourceSyncConditions, err)
+		return resourceSyncStatus, err
+	}
+
+	// Apply resource
+	ssiLog.Debugf("applying resource: %s/%s (%s)", resourceSyncStatus.Namespace, resourceSyncStatus.Name, resourceSyncStatus.Kind)
+	applyResult, applyErr := h.Apply(owned)
+	resourceSyncStatus.Conditions = r.setApplySyncConditions(resourceSyncConditions, applyErr)
+	if applyErr == nil {
+		// A successful apply brings the remote object back in line with the applied content
+		resourceSyncStatus.Conditions = r.setDriftedSyncCondition(resourceSyncStatus.Conditions, drift, driftRevertedReason)
+	}
+
+	if applyErr != nil {
+		ssiLog.WithError(applyErr).Warnf("error applying resource %s/%s (%s)", resourceSyncStatus.Namespace, resourceSyncStatus.Name, resourceSyncStatus.Kind)
+	} else {
+		ssiLog.Debugf("resource %s/%s (%s): %s", resourceSyncStatus.Namespace, resourceSyncStatus.Name, resourceSyncStatus.Kind, applyResult)
+	}
+	return resourceSyncStatus, applyErr
 }
 
-func (r *ReconcileSyncSetInstance) reconcileDeleted(deleteTerm string, applyMode hivev1.SyncSetResourceApplyMode, dynamicClient dynamic.Interface, existingStatusList, newStatusList []hivev1.SyncStatus, err error, ssiLog log.FieldLogger) []hivev1.SyncStatus {
+// reconcileDeleted deletes the remote objects in existingStatusList that are no longer in newStatusList when the apply
+// mode is Sync. If incomplete is true, not all objects were processed and nothing is deleted.
+func (r *ReconcileSyncSetInstance) reconcileDeleted(deleteTerm string, applyMode hivev1.SyncSetResourceApplyMode, dynamicClient dynamic.Interface, existingStatusList, newStatusList []hivev1.SyncStatus, incomplete bool, ssiLog log.FieldLogger) []hivev1.SyncStatus {
 	ssiLog.Debugf("reconciling syncset %ss, existing: %d, actual: %d", deleteTerm, len(existingStatusList), len(newStatusList))
 	if applyMode == "" || applyMode == hivev1.UpsertResourceApplyMode {
 		ssiLog.Debugf("apply mode is upsert, remote %ss will not be deleted", deleteTerm)
@@ -616,9 +936,9 @@ func (r *ReconcileSyncSetInstance) reconcileDeleted(deleteTerm string, applyMode
 		}
 	}
 
-	// If an error occurred applying resources, do not delete yet
-	if err != nil {
-		ssiLog.Debugf("an error occurred applying %ss, will preserve all syncset status items", deleteTerm)
+	// If an error occurred applying resources or not all were applied yet, do not delete yet
+	if incomplete {
+		ssiLog.Debugf("not all %ss were applied, will preserve all syncset status items", deleteTerm)
 		return append(newStatusList, deletedStatusList...)
 	}
 
@@ -650,10 +970,8 @@ func (r *ReconcileSyncSetInstance) reconcileDeleted(deleteTerm string, applyMode
 	return newStatusList
 }
 
-// applySyncSetPatches applies patches to cluster identified by kubeConfig
-func (r *ReconcileSyncSetInstance) applySyncSetPatches(ssi *hivev1.SyncSetInstance, ssPatches []hivev1.SyncObjectPatch, kubeConfig []byte, ssiLog log.FieldLogger) error {
-	h := r.applierBuilder(kubeConfig, r.logger)
-
+// applySyncSetPatches applies patches to the cluster of the applier
+func (r *ReconcileSyncSetInstance) applySyncSetPatches(ssi *hivev1.SyncSetInstance, ssPatches []hivev1.SyncObjectPatch, h Applier, ssiLog log.FieldLogger) error {
 	for _, ssPatch := range ssPatches {
 
 		b, err := json.Marshal(ssPatch)
@@ -699,7 +1017,7 @@ func (r *ReconcileSyncSetInstance) applySyncSetPatches(ssi *hivev1.SyncSetInstan
 }
 
 // applySyncSetSecretReferences evaluates secret references and applies them to the cluster identified by kubeConfig
-func (r *ReconcileSyncSetInstance) applySyncSetSecretReferences(ssi *hivev1.SyncSetInstance, secretReferences []hivev1.SecretReference, dynamicClient dynamic.Interface, h Applier, ssiLog log.FieldLogger) error {
+func (r *ReconcileSyncSetInstance) applySyncSetSecretReferences(ssi *hivev1.SyncSetInstance, secretReferences []hivev1.SecretReference, existingObjectPolicy hivev1.SyncSetExistingObjectPolicy, dynamicClient dynamic.Interface, h Applier, ssiLog log.FieldLogger) error {
 	syncStatusList := []hivev1.SyncStatus{}
 
 	var applyErr error
@@ -754,8 +1072,17 @@ func (r *ReconcileSyncSetInstance) applySyncSetSecretReferences(ssi *hivev1.Sync
 		secretReferenceSyncStatus.Hash = hash
 
 		if rss == nil || needToReApply("secret", secretReferenceSyncStatus, *rss, ssiLog) {
+			applyErr = checkExistingObject(ssi, existingObjectPolicy, secretReferenceSyncStatus, rss != nil, dynamicClient)
+			if applyErr != nil {
+				ssiLog.WithError(applyErr).Warnf("will not apply secret %s/%s (%s)", secret.Namespace, secret.Name, secret.Kind)
+				secretReferenceSyncStatus.Conditions = r.setApplySyncConditions(secretReferenceSyncConditions, applyErr)
+				syncStatusList = append(syncStatusList, secretReferenceSyncStatus)
+				break
+			}
+
 			// Apply secret
 			ssiLog.Debugf("applying secret: %s/%s (%s)", secret.Namespace, secret.Name, secret.Kind)
+			setSyncSetOwner(secret, ssi)
 			var result hiveresource.ApplyResult
 			result, applyErr = h.ApplyRuntimeObject(secret, scheme.Scheme)
 
@@ -784,7 +1111,7 @@ func (r *ReconcileSyncSetInstance) applySyncSetSecretReferences(ssi *hivev1.Sync
 		}
 	}
 
-	ssi.Status.SecretReferences = r.reconcileDeleted("secret", ssi.Spec.ResourceApplyMode, dynamicClient, ssi.Status.SecretReferences, syncStatusList, applyErr, ssiLog)
+	ssi.Status.SecretReferences = r.reconcileDeleted("secret", ssi.Spec.ResourceApplyMode, dynamicClient, ssi.Status.SecretReferences, syncStatusList, applyErr != nil, ssiLog)
 
 	// Return applyErr for the controller to trigger retries nd go into exponential backoff
 	// if the problem does not resolve itself.
@@ -795,6 +1122,245 @@ func (r *ReconcileSyncSetInstance) applySyncSetSecretReferences(ssi *hivev1.Sync
 	return nil
 }
 
+// applySyncSetConfigMapReferences evaluates configmap references and applies them to the cluster identified by kubeConfig
+func (r *ReconcileSyncSetInstance) applySyncSetConfigMapReferences(ssi *hivev1.SyncSetInstance, configMapReferences []hivev1.ConfigMapReference, existingObjectPolicy hivev1.SyncSetExistingObjectPolicy, dynamicClient dynamic.Interface, h Applier, ssiLog log.FieldLogger) error {
+	syncStatusList := []hivev1.SyncStatus{}
+
+	var applyErr error
+	for _, configMapReference := range configMapReferences {
+		apiVersion := configMapAPIVersion
+		if configMapReference.Target.APIVersion != "" {
+			apiVersion = configMapReference.Target.APIVersion
+		}
+		configMapReferenceSyncStatus := hivev1.SyncStatus{
+			APIVersion: apiVersion,
+			Kind:       configMapKind,
+			Name:       configMapReference.Target.Name,
+			Namespace:  configMapReference.Target.Namespace,
+			Resource:   configMapsResource,
+		}
+
+		rss := findSyncStatus(configMapReferenceSyncStatus, ssi.Status.ConfigMapReferences)
+		var configMapReferenceSyncConditions []hivev1.SyncCondition
+		if rss != nil {
+			configMapReferenceSyncConditions = rss.Conditions
+		}
+
+		configMap := &corev1.ConfigMap{}
+		applyErr = r.Get(context.Background(), types.NamespacedName{Name: configMapReference.Source.Name, Namespace: configMapReference.Source.Namespace}, configMap)
+		if applyErr != nil {
+			logLevel := log.ErrorLevel
+			if errors.IsNotFound(applyErr) {
+				logLevel = log.InfoLevel
+			}
+			ssiLog.WithError(applyErr).WithField("configmap", fmt.Sprintf("%s/%s", configMapReference.Source.Namespace, configMapReference.Source.Name)).Log(logLevel, "cannot read configmap")
+			configMapReferenceSyncStatus.Conditions = r.setApplySyncConditions(configMapReferenceSyncConditions, applyErr)
+			syncStatusList = append(syncStatusList, configMapReferenceSyncStatus)
+			break
+		}
+
+		configMap.Name = configMapReference.Target.Name
+		configMap.Namespace = configMapReference.Target.Namespace
+		// These pieces of metadata need to be set to nil values to perform an update from the original configmap
+		configMap.Generation = 0
+		configMap.ResourceVersion = ""
+		configMap.UID = ""
+		configMap.OwnerReferences = nil
+
+		var hash string
+		hash, applyErr = controllerutils.GetChecksumOfObject(configMap)
+		if applyErr != nil {
+			ssiLog.WithError(applyErr).WithField("configmap", fmt.Sprintf("%s/%s", configMapReference.Source.Namespace, configMapReference.Source.Name)).Error("unable to compute configmap hash")
+			configMapReferenceSyncStatus.Conditions = r.setApplySyncConditions(configMapReferenceSyncConditions, applyErr)
+			syncStatusList = append(syncStatusList, configMapReferenceSyncStatus)
+			break
+		}
+		configMapReferenceSyncStatus.Hash = hash
+
+		if rss == nil || needToReApply("configmap", configMapReferenceSyncStatus, *rss, ssiLog) {
+			applyErr = checkExistingObject(ssi, existingObjectPolicy, configMapReferenceSyncStatus, rss != nil, dynamicClient)
+			if applyErr != nil {
+				ssiLog.WithError(applyErr).Warnf("will not apply configmap %s/%s (%s)", configMap.Namespace, configMap.Name, configMap.Kind)
+				configMapReferenceSyncStatus.Conditions = r.setApplySyncConditions(configMapReferenceSyncConditions, applyErr)
+				syncStatusList = append(syncStatusList, configMapReferenceSyncStatus)
+				break
+			}
+
+			// Apply configmap
+			ssiLog.Debugf("applying configmap: %s/%s (%s)", configMap.Namespace, configMap.Name, configMap.Kind)
+			setSyncSetOwner(configMap, ssi)
+			var result hiveresource.ApplyResult
+			result, applyErr = h.ApplyRuntimeObject(configMap, scheme.Scheme)
+			configMapReferenceSyncStatus.Conditions = r.setApplySyncConditions(configMapReferenceSyncConditions, applyErr)
+
+			if applyErr != nil {
+				ssiLog.WithError(applyErr).Warnf("error applying configmap %s/%s (%s)", configMap.Namespace, configMap.Name, configMap.Kind)
+			} else {
+				ssiLog.Debugf("resource %s/%s (%s): %s", configMap.Namespace, configMap.Name, configMap.Kind, result)
+			}
+		} else {
+			// Do not apply configmap
+			ssiLog.Debugf("resource %s/%s (%s) has not changed, will not apply", configMap.Namespace, configMap.Name, configMap.Kind)
+			configMapReferenceSyncStatus.Conditions = rss.Conditions
+		}
+
+		syncStatusList = append(syncStatusList, configMapReferenceSyncStatus)
+
+		// If an error applying occurred, stop processing right here
+		if applyErr != nil {
+			break
+		}
+	}
+
+	ssi.Status.ConfigMapReferences = r.reconcileDeleted("configmap", ssi.Spec.ResourceApplyMode, dynamicClient, ssi.Status.ConfigMapReferences, syncStatusList, applyErr != nil, ssiLog)
+
+	// Return applyErr for the controller to trigger retries and go into exponential backoff
+	// if the problem does not resolve itself.
+	return applyErr
+}
+
+// applySyncSetDeletions deletes the objects listed in deletions from the cluster. Like patches, a deletion is only submitted
+// again when it changed, when it failed last time, or after the reapply interval, so that objects are removed again if they
+// are recreated. Objects that do not exist are considered deleted.
+func (r *ReconcileSyncSetInstance) applySyncSetDeletions(ssi *hivev1.SyncSetInstance, deletions []hivev1.SyncObjectDeletion, dynamicClient dynamic.Interface, h Applier, ssiLog log.FieldLogger) error {
+	syncStatusList := []hivev1.SyncStatus{}
+
+	var lastError error
+	for _, deletion := range deletions {
+		deletionLog := ssiLog.WithField("object", fmt.Sprintf("%s/%s", deletion.Namespace, deletion.Name)).
+			WithField("apiVersion", deletion.APIVersion).
+			WithField("kind", deletion.Kind)
+		b, err := json.Marshal(deletion)
+		if err != nil {
+			deletionLog.WithError(err).Error("cannot serialize syncset deletion")
+			return err
+		}
+		deletionSyncStatus := hivev1.SyncStatus{
+			APIVersion: deletion.APIVersion,
+			Kind:       deletion.Kind,
+			Name:       deletion.Name,
+			Namespace:  deletion.Namespace,
+			Hash:       r.hash(b),
+		}
+		dss := findSyncStatus(deletionSyncStatus, ssi.Status.Deletions)
+		if dss != nil && !needToReDelete(deletionSyncStatus, *dss, deletionLog) {
+			// Do not delete object
+			deletionLog.Debug("deletion has not changed, will not delete")
+			deletionSyncStatus.Resource = dss.Resource
+			deletionSyncStatus.Conditions = dss.Conditions
+			syncStatusList = append(syncStatusList, deletionSyncStatus)
+			continue
+		}
+		var deletionSyncConditions []hivev1.SyncCondition
+		if dss != nil {
+			deletionSyncConditions = dss.Conditions
+		}
+
+		err = r.deleteSyncSetObject(deletion, &deletionSyncStatus, dynamicClient, h, deletionLog)
+		deletionSyncStatus.Conditions = r.setDeletionSyncConditions(deletionSyncConditions, err)
+		syncStatusList = append(syncStatusList, deletionSyncStatus)
+		if err != nil {
+			deletionLog.WithError(err).Warn("error deleting object")
+			lastError = err
+		}
+	}
+	ssi.Status.Deletions = syncStatusList
+
+	// Return lastError for the controller to trigger retries and go into exponential backoff
+	// if the problem does not resolve itself.
+	return lastError
+}
+
+// needToReDelete returns true if an object listed in the deletions of a syncset must be deleted again. In addition to the
+// cases in which resources and patches are re-applied, an object is deleted again if its last deletion failed.
+func needToReDelete(newStatus, existingStatus hivev1.SyncStatus, deletionLog log.FieldLogger) bool {
+	if failedCondition := controllerutils.FindSyncCondition(existingStatus.Conditions, hivev1.DeletionFailedSyncCondition); failedCondition != nil {
+		if failedCondition.Status == corev1.ConditionTrue {
+			deletionLog.Debug("deletion failed last time, will delete again")
+			return true
+		}
+	}
+	return needToReApply("deletion", newStatus, existingStatus, deletionLog)
+}
+
+// deleteSyncSetObject deletes the remote object described by deletion, recording its resource in deletionSyncStatus.
+func (r *ReconcileSyncSetInstance) deleteSyncSetObject(deletion hivev1.SyncObjectDeletion, deletionSyncStatus *hivev1.SyncStatus, dynamicClient dynamic.Interface, h Applier, deletionLog log.FieldLogger) error {
+	// The applier determines the resource of the object from a minimal definition of it
+	obj := map[string]interface{}{
+		"apiVersion": deletion.APIVersion,
+		"kind":       deletion.Kind,
+		"metadata": map[string]interface{}{
+			"name":      deletion.Name,
+			"namespace": deletion.Namespace,
+		},
+	}
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	info, err := h.Info(b)
+	if err != nil {
+		return fmt.Errorf("cannot determine resource of object: %v", err)
+	}
+	deletionSyncStatus.Resource = info.Resource
+	// Whether the kind is namespaced is only known to the cluster, so the namespace of deletions is checked here
+	if info.Namespaced && len(deletion.Namespace) == 0 {
+		return fmt.Errorf("namespace is required for namespaced kind %s", deletion.Kind)
+	}
+	if !info.Namespaced && len(deletion.Namespace) > 0 {
+		return fmt.Errorf("namespace must be empty for cluster-scoped kind %s", deletion.Kind)
+	}
+
+	gv, err := schema.ParseGroupVersion(deletion.APIVersion)
+	if err != nil {
+		return err
+	}
+	options := &metav1.DeleteOptions{PropagationPolicy: deletion.PropagationPolicy}
+	deletionLog.Debug("deleting object")
+	err = dynamicClient.Resource(gv.WithResource(info.Resource)).Namespace(deletion.Namespace).Delete(deletion.Name, options)
+	if errors.IsNotFound(err) {
+		deletionLog.Debug("object not found, nothing to do")
+		return nil
+	}
+	return err
+}
+
+// getOtherSyncSetInstances returns the syncset instances of the clusterdeployment of ssi, except ssi itself
+func (r *ReconcileSyncSetInstance) getOtherSyncSetInstances(ssi *hivev1.SyncSetInstance) ([]*hivev1.SyncSetInstance, error) {
+	list := &hivev1.SyncSetInstanceList{}
+	if err := r.List(context.TODO(), list, client.InNamespace(ssi.Namespace)); err != nil {
+		return nil, err
+	}
+	syncSetInstances := []*hivev1.SyncSetInstance{}
+	for i, other := range list.Items {
+		if other.Name != ssi.Name && other.Spec.ClusterDeploymentRef.Name == ssi.Spec.ClusterDeploymentRef.Name {
+			syncSetInstances = append(syncSetInstances, &list.Items[i])
+		}
+	}
+	return syncSetInstances, nil
+}
+
+// updateConflictingSyncSetInstances updates the ResourceConflict condition of the other syncset instances of the
+// clusterdeployment of ssi, so that conflicts are reported on both syncset instances as soon as they are detected or
+// resolved. Objects of ssi are not taken into account once it has been deleted.
+func (r *ReconcileSyncSetInstance) updateConflictingSyncSetInstances(ssi *hivev1.SyncSetInstance, others []*hivev1.SyncSetInstance, ssiLog log.FieldLogger) error {
+	all := others
+	if controllerutils.HasFinalizer(ssi, hivev1.FinalizerSyncSetInstance) {
+		all = append([]*hivev1.SyncSetInstance{ssi}, others...)
+	}
+	for _, other := range others {
+		if !other.DeletionTimestamp.IsZero() {
+			continue
+		}
+		original := other.DeepCopy()
+		other.Status.Conditions = r.setResourceConflictSyncCondition(other.Status.Conditions, controllerutils.SyncSetInstanceConflicts(other, all))
+		if err := r.updateSyncSetInstanceStatus(other, original, ssiLog.WithField("conflictingSyncSetInstance", other.Name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func appendOrUpdateSyncStatus(statusList []hivev1.SyncStatus, syncStatus hivev1.SyncStatus) []hivev1.SyncStatus {
 	for i, ss := range statusList {
 		if ss.Name == syncStatus.Name && ss.Namespace == syncStatus.Namespace && ss.Kind == syncStatus.Kind {
@@ -887,6 +1453,110 @@ func (r *ReconcileSyncSetInstance) setApplySyncConditions(resourceSyncConditions
 	return resourceSyncConditions
 }
 
+// setDriftedSyncCondition sets the Drifted condition for a resource. A drift summary with driftDetectedReason reports
+// the resource as drifted, with driftRevertedReason it records that the drift has been reverted. An empty drift summary
+// reports that the resource matches the applied content.
+func (r *ReconcileSyncSetInstance) setDriftedSyncCondition(resourceSyncConditions []hivev1.SyncCondition, drift, reason string) []hivev1.SyncCondition {
+	switch {
+	case drift == "":
+		// Keep the message of a previously reverted drift until the resource drifts again
+		return controllerutils.SetSyncCondition(
+			resourceSyncConditions,
+			hivev1.DriftedSyncCondition,
+			corev1.ConditionFalse,
+			noDriftReason,
+			"Resource matches the applied content",
+			controllerutils.UpdateConditionNever)
+	case reason == driftRevertedReason:
+		return r.addOrSetSyncCondition(
+			resourceSyncConditions,
+			hivev1.DriftedSyncCondition,
+			corev1.ConditionFalse,
+			reason,
+			fmt.Sprintf("Reverted drift: %s", drift))
+	default:
+		return r.addOrSetSyncCondition(
+			resourceSyncConditions,
+			hivev1.DriftedSyncCondition,
+			corev1.ConditionTrue,
+			reason,
+			drift)
+	}
+}
+
+func (r *ReconcileSyncSetInstance) setReadySyncCondition(resourceSyncConditions []hivev1.SyncCondition, ready bool, message string) []hivev1.SyncCondition {
+	status := corev1.ConditionTrue
+	reason := readyReason
+	if !ready {
+		status = corev1.ConditionFalse
+		reason = notReadyReason
+	} else {
+		message = "Resource is ready"
+	}
+	return r.addOrSetSyncCondition(resourceSyncConditions, hivev1.ReadySyncCondition, status, reason, message)
+}
+
+// addOrSetSyncCondition sets a sync condition, adding it if it does not exist yet regardless of its status
+func (r *ReconcileSyncSetInstance) addOrSetSyncCondition(conditions []hivev1.SyncCondition, conditionType hivev1.SyncConditionType, status corev1.ConditionStatus, reason, message string) []hivev1.SyncCondition {
+	if controllerutils.FindSyncCondition(conditions, conditionType) == nil {
+		// SetSyncCondition only adds conditions with a true status
+		now := metav1.Now()
+		return append(conditions, hivev1.SyncCondition{
+			Type:               conditionType,
+			Status:             status,
+			Reason:             reason,
+			Message:            message,
+			LastTransitionTime: now,
+			LastProbeTime:      now,
+		})
+	}
+	return controllerutils.SetSyncCondition(
+		conditions,
+		conditionType,
+		status,
+		reason,
+		message,
+		controllerutils.UpdateConditionIfReasonOrMessageChange)
+}
+
+func (r *ReconcileSyncSetInstance) setWaitingForReadinessSyncCondition(syncSetConditions []hivev1.SyncCondition, waiting bool, message string) []hivev1.SyncCondition {
+	status := corev1.ConditionFalse
+	reason := readyReason
+	if waiting {
+		status = corev1.ConditionTrue
+		reason = notReadyReason
+	}
+	return controllerutils.SetSyncCondition(
+		syncSetConditions,
+		hivev1.WaitingForReadinessSyncCondition,
+		status,
+		reason,
+		message,
+		controllerutils.UpdateConditionIfReasonOrMessageChange,
+	)
+}
+
+// setResourceConflictSyncCondition sets the ResourceConflict condition of a syncset instance from the list of conflicts
+// returned by controllerutils.SyncSetInstanceConflicts.
+func (r *ReconcileSyncSetInstance) setResourceConflictSyncCondition(syncSetConditions []hivev1.SyncCondition, conflicts []string) []hivev1.SyncCondition {
+	status := corev1.ConditionFalse
+	reason := noConflictReason
+	message := "No objects are managed by other syncset instances"
+	if len(conflicts) > 0 {
+		status = corev1.ConditionTrue
+		reason = conflictDetectedReason
+		message = strings.Join(conflicts, "; ")
+	}
+	return controllerutils.SetSyncCondition(
+		syncSetConditions,
+		hivev1.ResourceConflictSyncCondition,
+		status,
+		reason,
+		message,
+		controllerutils.UpdateConditionIfReasonOrMessageChange,
+	)
+}
+
 func (r *ReconcileSyncSetInstance) setDeletionFailedSyncCondition(resourceSyncConditions []hivev1.SyncCondition, err error) []hivev1.SyncCondition {
 	if err == nil {
 		return resourceSyncConditions
@@ -900,6 +1570,36 @@ func (r *ReconcileSyncSetInstance) setDeletionFailedSyncCondition(resourceSyncCo
 		controllerutils.UpdateConditionAlways)
 }
 
+// setDeletionSyncConditions sets the conditions of an object listed in the deletions of a syncset. A successful deletion
+// is reported with the ApplySuccess condition.
+func (r *ReconcileSyncSetInstance) setDeletionSyncConditions(deletionSyncConditions []hivev1.SyncCondition, err error) []hivev1.SyncCondition {
+	if err != nil {
+		deletionSyncConditions = controllerutils.SetSyncCondition(
+			deletionSyncConditions,
+			hivev1.ApplySuccessSyncCondition,
+			corev1.ConditionFalse,
+			deletionFailedReason,
+			"Deletion failed",
+			controllerutils.UpdateConditionIfReasonOrMessageChange)
+		return r.setDeletionFailedSyncCondition(deletionSyncConditions, err)
+	}
+	deletionSyncConditions = controllerutils.SetSyncCondition(
+		deletionSyncConditions,
+		hivev1.ApplySuccessSyncCondition,
+		corev1.ConditionTrue,
+		deletionSucceededReason,
+		"Deletion successful",
+		// The probe time of a successful deletion is always updated, it is used to determine when to delete again
+		controllerutils.UpdateConditionAlways)
+	return controllerutils.SetSyncCondition(
+		deletionSyncConditions,
+		hivev1.DeletionFailedSyncCondition,
+		corev1.ConditionFalse,
+		deletionSucceededReason,
+		"Deletion successful",
+		controllerutils.UpdateConditionIfReasonOrMessageChange)
+}
+
 func (r *ReconcileSyncSetInstance) resourceHash(data []byte) string {
 	return fmt.Sprintf("%x", md5.Sum(data))
 }
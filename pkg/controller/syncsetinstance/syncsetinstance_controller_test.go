@@ -21,6 +21,9 @@ import (
 	"crypto/md5"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"strings"
 	"testing"
 	"time"
@@ -32,9 +35,11 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/dynamic"
@@ -76,10 +81,16 @@ func TestSyncSetReconcile(t *testing.T) {
 		existingObjs           []runtime.Object
 		clusterDeployment      *hivev1.ClusterDeployment
 		validate               func(*testing.T, *hivev1.SyncSetInstance)
+		validateOthers         func(*testing.T, []hivev1.SyncSetInstance)
 		isDeleted              bool
 		expectDeleted          []deletedItemInfo
 		expectSSIDeleted       bool
-		expectErr              bool
+		remoteObjs             []runtime.Object
+		syncSetHash            string
+		// kindsAddedBy are kinds that are unknown to the cluster until the resource with the given name has been
+		// applied, like a custom resource kind and its CustomResourceDefinition
+		kindsAddedBy map[string]string
+		expectErr    bool
 	}{
 		{
 			name:    "Create single resource successfully",
@@ -208,6 +219,288 @@ func TestSyncSetReconcile(t *testing.T) {
 				))
 			},
 		},
+		{
+			name: "selectorsyncset: apply change rolled out to instance",
+			selectorSyncSet: func() *hivev1.SelectorSyncSet {
+				sss := testSelectorSyncSetWithResources("foo", testCM("cm1", "key1", "value1"))
+				sss.Spec.Rollout = &hivev1.SelectorSyncSetRolloutStrategy{MaxUnavailablePercentage: 10}
+				return sss
+			}(),
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				validateSyncSetInstanceStatus(t, ssi.Status, successfulResourceStatus(
+					testCM("cm1", "key1", "value1"),
+				))
+				if ssi.Status.AppliedSyncSetHash != ssi.Spec.SyncSetHash {
+					t.Errorf("unexpected applied syncset hash: %q", ssi.Status.AppliedSyncSetHash)
+				}
+			},
+		},
+		{
+			name: "selectorsyncset: change not rolled out to instance yet",
+			selectorSyncSet: func() *hivev1.SelectorSyncSet {
+				sss := testSelectorSyncSetWithResources("foo", testCM("cm1", "key1", "value1"))
+				sss.Spec.Rollout = &hivev1.SelectorSyncSetRolloutStrategy{MaxUnavailablePercentage: 10}
+				return sss
+			}(),
+			syncSetHash: "previous-hash",
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				validateSyncSetInstanceStatus(t, ssi.Status, hivev1.SyncSetInstanceStatus{})
+				if ssi.Status.AppliedSyncSetHash != "" {
+					t.Errorf("unexpected applied syncset hash: %q", ssi.Status.AppliedSyncSetHash)
+				}
+			},
+		},
+		{
+			name: "selectorsyncset: apply rolled out spec while change is not rolled out yet",
+			selectorSyncSet: func() *hivev1.SelectorSyncSet {
+				sss := testSelectorSyncSetWithResources("foo", testCM("cm1", "key1", "value2"))
+				sss.Spec.Rollout = &hivev1.SelectorSyncSetRolloutStrategy{MaxUnavailablePercentage: 10}
+				return sss
+			}(),
+			syncSetHash: "previous-hash",
+			existingObjs: []runtime.Object{
+				testRolloutSnapshot(testSelectorSyncSetWithResources("foo", testCM("cm1", "key1", "value1")), "previous-hash"),
+			},
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				validateSyncSetInstanceStatus(t, ssi.Status, successfulResourceStatus(
+					testCM("cm1", "key1", "value1"),
+				))
+				if ssi.Status.AppliedSyncSetHash != "previous-hash" {
+					t.Errorf("unexpected applied syncset hash: %q", ssi.Status.AppliedSyncSetHash)
+				}
+			},
+		},
+		{
+			name: "selectorsyncset: expand resource templates",
+			selectorSyncSet: func() *hivev1.SelectorSyncSet {
+				sss := testSelectorSyncSetWithResources("foo",
+					testCM("cm-{{ .ClusterDeploymentName }}", "region", `{{ index .Labels "region" }}`),
+				)
+				sss.Spec.EnableResourceTemplates = true
+				return sss
+			}(),
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				validateSyncSetInstanceStatus(t, ssi.Status, successfulResourceStatus(
+					testCM("cm-foo", "region", "us-east-1"),
+				))
+			},
+		},
+		{
+			name: "selectorsyncset: fail to expand resource templates",
+			selectorSyncSet: func() *hivev1.SelectorSyncSet {
+				sss := testSelectorSyncSetWithResources("foo",
+					testCM("cm1", "key1", "{{ .Labels.missing }}"),
+				)
+				sss.Spec.EnableResourceTemplates = true
+				return sss
+			}(),
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				if len(ssi.Status.Resources) != 0 {
+					t.Errorf("expected no resources to be applied")
+				}
+				condition := controllerutils.FindSyncCondition(ssi.Status.Conditions, hivev1.TemplateExpansionFailureSyncCondition)
+				if condition == nil || condition.Status != corev1.ConditionTrue {
+					t.Errorf("expected template expansion failure condition")
+				}
+			},
+			expectErr: true,
+		},
+		{
+			name: "selectorsyncset: clear template expansion failure once templates expand",
+			selectorSyncSet: func() *hivev1.SelectorSyncSet {
+				sss := testSelectorSyncSetWithResources("foo",
+					testCM("cm-{{ .ClusterDeploymentName }}", "region", `{{ index .Labels "region" }}`),
+				)
+				sss.Spec.EnableResourceTemplates = true
+				return sss
+			}(),
+			status: hivev1.SyncSetInstanceStatus{
+				Conditions: []hivev1.SyncCondition{{
+					Type:   hivev1.TemplateExpansionFailureSyncCondition,
+					Status: corev1.ConditionTrue,
+					Reason: templateFailedReason,
+				}},
+			},
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				condition := controllerutils.FindSyncCondition(ssi.Status.Conditions, hivev1.TemplateExpansionFailureSyncCondition)
+				if condition == nil || condition.Status != corev1.ConditionFalse || condition.Reason != templateSucceededReason {
+					t.Errorf("expected template expansion failure condition to be cleared, got %v", condition)
+				}
+			},
+		},
+		{
+			name: "apply waves: wait for earlier wave to become ready",
+			syncSet: testSyncSetWithResources("ss1",
+				testCMWithAnnotations("cm-wave1", "key1", "value1", map[string]string{constants.SyncSetApplyWaveAnnotation: "1"}),
+				testCM("cm-wave0", "key0", "value0"),
+			),
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				if len(ssi.Status.Resources) != 1 || ssi.Status.Resources[0].Name != "cm-wave0" {
+					t.Fatalf("expected only the first wave to be applied, got %v", ssi.Status.Resources)
+				}
+				validateReadyCondition(t, ssi.Status.Resources[0], corev1.ConditionFalse)
+				validateWaitingForReadinessCondition(t, ssi.Status, corev1.ConditionTrue)
+			},
+		},
+		{
+			name: "apply waves: apply later wave when earlier wave is ready",
+			syncSet: testSyncSetWithResources("ss1",
+				testCMWithAnnotations("cm-wave1", "key1", "value1", map[string]string{constants.SyncSetApplyWaveAnnotation: "1"}),
+				testCM("cm-wave0", "key0", "value0"),
+			),
+			remoteObjs: []runtime.Object{testCM("cm-wave0", "key0", "value0")},
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				if len(ssi.Status.Resources) != 2 {
+					t.Fatalf("expected both waves to be applied, got %v", ssi.Status.Resources)
+				}
+				validateReadyCondition(t, ssi.Status.Resources[0], corev1.ConditionTrue)
+				if ssi.Status.Resources[1].Name != "cm-wave1" {
+					t.Errorf("expected later wave to be applied last")
+				}
+				if condition := controllerutils.FindSyncCondition(ssi.Status.Conditions, hivev1.WaitingForReadinessSyncCondition); condition != nil && condition.Status != corev1.ConditionFalse {
+					t.Errorf("unexpected waiting for readiness condition")
+				}
+			},
+		},
+		{
+			name: "apply waves: gather info of later wave once its kind was added by an earlier wave",
+			syncSet: testSyncSetWithResources("ss1",
+				func() runtime.Object {
+					secret := testSecret("widget", "value1")
+					secret.Annotations = map[string]string{constants.SyncSetApplyWaveAnnotation: "1"}
+					return secret
+				}(),
+				testCM("widget-crd", "key0", "value0"),
+			),
+			remoteObjs:   []runtime.Object{testCM("widget-crd", "key0", "value0")},
+			kindsAddedBy: map[string]string{"Secret": "widget-crd"},
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				if len(ssi.Status.Resources) != 2 || ssi.Status.Resources[1].Name != "widget" {
+					t.Fatalf("expected both waves to be applied, got %v", ssi.Status.Resources)
+				}
+				if condition := controllerutils.FindSyncCondition(ssi.Status.Conditions, hivev1.UnknownObjectSyncCondition); condition != nil && condition.Status == corev1.ConditionTrue {
+					t.Errorf("unexpected unknown object condition: %v", condition)
+				}
+			},
+		},
+		{
+			name: "apply waves: do not gather info of later wave before earlier wave is ready",
+			syncSet: testSyncSetWithResources("ss1",
+				func() runtime.Object {
+					secret := testSecret("widget", "value1")
+					secret.Annotations = map[string]string{constants.SyncSetApplyWaveAnnotation: "1"}
+					return secret
+				}(),
+				testCM("widget-crd", "key0", "value0"),
+			),
+			kindsAddedBy: map[string]string{"Secret": "widget-crd"},
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				if len(ssi.Status.Resources) != 1 || ssi.Status.Resources[0].Name != "widget-crd" {
+					t.Fatalf("expected only the first wave to be applied, got %v", ssi.Status.Resources)
+				}
+				if condition := controllerutils.FindSyncCondition(ssi.Status.Conditions, hivev1.UnknownObjectSyncCondition); condition != nil && condition.Status == corev1.ConditionTrue {
+					t.Errorf("unexpected unknown object condition: %v", condition)
+				}
+				validateWaitingForReadinessCondition(t, ssi.Status, corev1.ConditionTrue)
+			},
+		},
+		{
+			name: "apply waves: custom readiness check not met",
+			syncSet: testSyncSetWithResources("ss1",
+				testCMWithAnnotations("cm-wave0", "key0", "value0", map[string]string{constants.SyncSetReadinessCheckAnnotation: "{.data.state}=done"}),
+				testCMWithAnnotations("cm-wave1", "key1", "value1", map[string]string{constants.SyncSetApplyWaveAnnotation: "1"}),
+			),
+			remoteObjs: []runtime.Object{testCM("cm-wave0", "state", "inprogress")},
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				if len(ssi.Status.Resources) != 1 {
+					t.Fatalf("expected only the first wave to be applied, got %v", ssi.Status.Resources)
+				}
+				validateReadyCondition(t, ssi.Status.Resources[0], corev1.ConditionFalse)
+				validateWaitingForReadinessCondition(t, ssi.Status, corev1.ConditionTrue)
+			},
+		},
+		{
+			name:   "apply waves: preserve resources of later waves in sync mode",
+			status: successfulResourceStatus(testCMWithAnnotations("cm-wave1", "key1", "value1", map[string]string{constants.SyncSetApplyWaveAnnotation: "1"})),
+			syncSet: func() *hivev1.SyncSet {
+				ss := testSyncSetWithResources("ss1",
+					testCMWithAnnotations("cm-wave1", "key1", "value1", map[string]string{constants.SyncSetApplyWaveAnnotation: "1"}),
+					testCM("cm-wave0", "key0", "value0"),
+				)
+				ss.Spec.ResourceApplyMode = hivev1.SyncResourceApplyMode
+				return ss
+			}(),
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				if len(ssi.Status.Resources) != 2 {
+					t.Fatalf("expected status of later wave to be preserved, got %v", ssi.Status.Resources)
+				}
+			},
+		},
+		{
+			name:   "drift: report drifted resource without reverting",
+			status: successfulResourceStatusWithTime([]runtime.Object{testCM("cm1", "key1", "value1")}, metav1.NewTime(tenMinutesAgo)),
+			syncSet: func() *hivev1.SyncSet {
+				ss := testSyncSetWithResources("ss1", testCM("cm1", "key1", "value1"))
+				ss.Spec.DriftPolicy = hivev1.ReportDriftPolicy
+				return ss
+			}(),
+			remoteObjs: []runtime.Object{testCMWithAnnotations("cm1", "key1", "changed", map[string]string{"hash": "key1=value1"})},
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				condition := validateDriftedCondition(t, ssi.Status.Resources[0], corev1.ConditionTrue)
+				if condition != nil && !strings.Contains(condition.Message, "data.key1: expected value1, found changed") {
+					t.Errorf("unexpected drifted condition message: %s", condition.Message)
+				}
+				applied := controllerutils.FindSyncCondition(ssi.Status.Resources[0].Conditions, hivev1.ApplySuccessSyncCondition)
+				if applied.LastProbeTime.Time.Unix() != tenMinutesAgo.Unix() {
+					t.Errorf("expected drifted resource not to be re-applied")
+				}
+			},
+		},
+		{
+			name:   "drift: revert drifted resource",
+			status: successfulResourceStatusWithTime([]runtime.Object{testCM("cm1", "key1", "value1")}, metav1.NewTime(tenMinutesAgo)),
+			syncSet: func() *hivev1.SyncSet {
+				ss := testSyncSetWithResources("ss1", testCM("cm1", "key1", "value1"))
+				ss.Spec.DriftPolicy = hivev1.RevertDriftPolicy
+				return ss
+			}(),
+			remoteObjs: []runtime.Object{testCMWithAnnotations("cm1", "key1", "changed", map[string]string{"hash": "key1=value1"})},
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				condition := validateDriftedCondition(t, ssi.Status.Resources[0], corev1.ConditionFalse)
+				if condition != nil && condition.Reason != driftRevertedReason {
+					t.Errorf("unexpected drifted condition reason: %s", condition.Reason)
+				}
+				applied := controllerutils.FindSyncCondition(ssi.Status.Resources[0].Conditions, hivev1.ApplySuccessSyncCondition)
+				if applied.LastProbeTime.Time.Unix() <= tenMinutesAgo.Unix() {
+					t.Errorf("expected drifted resource to be re-applied")
+				}
+			},
+		},
+		{
+			name:   "drift: report deleted resource",
+			status: successfulResourceStatus(testCM("cm1", "key1", "value1")),
+			syncSet: func() *hivev1.SyncSet {
+				ss := testSyncSetWithResources("ss1", testCM("cm1", "key1", "value1"))
+				ss.Spec.DriftPolicy = hivev1.ReportDriftPolicy
+				return ss
+			}(),
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				validateDriftedCondition(t, ssi.Status.Resources[0], corev1.ConditionTrue)
+			},
+		},
+		{
+			name:   "drift: resource matches applied content",
+			status: successfulResourceStatus(testCM("cm1", "key1", "value1")),
+			syncSet: func() *hivev1.SyncSet {
+				ss := testSyncSetWithResources("ss1", testCM("cm1", "key1", "value1"))
+				ss.Spec.DriftPolicy = hivev1.ReportDriftPolicy
+				return ss
+			}(),
+			remoteObjs: []runtime.Object{testCMWithAnnotations("cm1", "key1", "value1", map[string]string{"extra": "added-by-cluster"})},
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				validateSyncSetInstanceStatus(t, ssi.Status, successfulResourceStatus(testCM("cm1", "key1", "value1")))
+			},
+		},
 		{
 			name:    "Apply single patch successfully",
 			syncSet: testSyncSetWithPatches("ss1", testSyncObjectPatch("foo", "bar", "baz", "v1", "AlwaysApply", "value1")),
@@ -349,6 +642,168 @@ func TestSyncSetReconcile(t *testing.T) {
 				deletedItem("cm2", "ConfigMap"),
 			},
 		},
+		{
+			name: "keep resources of deleted syncset instance switched from sync to upsert",
+			deletedSyncSet: testSyncSetWithResources("aaa",
+				testCM("cm1", "key1", "value1"),
+			),
+			isDeleted: true,
+			status: successfulResourceStatus(
+				testCM("cm1", "key1", "value1"),
+			),
+			remoteObjs: []runtime.Object{
+				testOwnedCM("aaa", "cm1", "key1", "value1"),
+			},
+		},
+		{
+			name: "cleanup owned resources missing from status of deleted syncset instance",
+			deletedSyncSet: func() *hivev1.SyncSet {
+				ss := testSyncSetWithResources("aaa",
+					testCM("cm1", "key1", "value1"),
+				)
+				ss.Spec.ResourceApplyMode = hivev1.SyncResourceApplyMode
+				return ss
+			}(),
+			isDeleted: true,
+			status: successfulResourceStatus(
+				testCM("cm1", "key1", "value1"),
+			),
+			remoteObjs: []runtime.Object{
+				testOwnedCM("aaa", "cm2", "key2", "value2"),
+			},
+			expectDeleted: []deletedItemInfo{
+				deletedItem("cm1", "ConfigMap"),
+				deletedItem("cm2", "ConfigMap"),
+			},
+		},
+		{
+			name: "resource sync mode, prune owned resources missing from status",
+			syncSet: func() *hivev1.SyncSet {
+				ss := testSyncSetWithResources("aaa",
+					testCM("cm1", "key1", "value1"),
+				)
+				ss.Spec.ResourceApplyMode = hivev1.SyncResourceApplyMode
+				return ss
+			}(),
+			remoteObjs: []runtime.Object{
+				testOwnedCM("aaa", "cm1", "key1", "value1"),
+				testOwnedCM("aaa", "cm2", "key2", "value2"),
+				testOwnedCM("bbb", "cm3", "key3", "value3"),
+				testCM("cm4", "key4", "value4"),
+			},
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				validateSyncSetInstanceStatus(t, ssi.Status, successfulResourceStatus(
+					testCM("cm1", "key1", "value1"),
+				))
+			},
+			expectDeleted: []deletedItemInfo{
+				deletedItem("cm2", "ConfigMap"),
+			},
+		},
+		{
+			name: "resource sync mode, prune owned objects of kinds removed from syncset after status was lost",
+			syncSet: func() *hivev1.SyncSet {
+				ss := testSyncSetWithResources("aaa",
+					testCM("cm1", "key1", "value1"),
+				)
+				ss.Spec.ResourceApplyMode = hivev1.SyncResourceApplyMode
+				return ss
+			}(),
+			remoteObjs: []runtime.Object{
+				testOwnedCM("aaa", "cm1", "key1", "value1"),
+				testOwnedSecret("aaa", "s1"),
+				testOwnedSecret("bbb", "s2"),
+			},
+			expectDeleted: []deletedItemInfo{
+				deletedItem("s1", "Secret"),
+			},
+		},
+		{
+			name: "resource sync mode, delete owned objects of deleted syncset instance without status",
+			syncSet: func() *hivev1.SyncSet {
+				ss := testSyncSetWithResources("aaa",
+					testCM("cm1", "key1", "value1"),
+				)
+				ss.Spec.ResourceApplyMode = hivev1.SyncResourceApplyMode
+				return ss
+			}(),
+			isDeleted: true,
+			remoteObjs: []runtime.Object{
+				testOwnedCM("aaa", "cm1", "key1", "value1"),
+				testOwnedSecret("aaa", "s1"),
+			},
+			expectDeleted: []deletedItemInfo{
+				deletedItem("cm1", "ConfigMap"),
+				deletedItem("s1", "Secret"),
+			},
+		},
+		{
+			name: "resource upsert mode, do not prune owned resources",
+			syncSet: testSyncSetWithResources("aaa",
+				testCM("cm1", "key1", "value1"),
+			),
+			remoteObjs: []runtime.Object{
+				testOwnedCM("aaa", "cm2", "key2", "value2"),
+			},
+		},
+		{
+			name: "existing object policy refuse, unowned object exists",
+			syncSet: func() *hivev1.SyncSet {
+				ss := testSyncSetWithResources("aaa",
+					testCM("cm1", "key1", "value1"),
+				)
+				ss.Spec.ExistingObjectPolicy = hivev1.RefuseExistingObjectPolicy
+				return ss
+			}(),
+			remoteObjs: []runtime.Object{
+				testCM("cm1", "key1", "other"),
+			},
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				validateSyncSetInstanceStatus(t, ssi.Status, hivev1.SyncSetInstanceStatus{
+					Resources: applyFailedResourceStatus("aaa", testCM("cm1", "key1", "value1")).Resources,
+				})
+			},
+			expectErr: true,
+		},
+		{
+			name: "existing object policy refuse, owned object exists",
+			syncSet: func() *hivev1.SyncSet {
+				ss := testSyncSetWithResources("aaa",
+					testCM("cm1", "key1", "value1"),
+				)
+				ss.Spec.ExistingObjectPolicy = hivev1.RefuseExistingObjectPolicy
+				return ss
+			}(),
+			remoteObjs: []runtime.Object{
+				testOwnedCM("aaa", "cm1", "key1", "other"),
+			},
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				validateSyncSetInstanceStatus(t, ssi.Status, successfulResourceStatus(
+					testCM("cm1", "key1", "value1"),
+				))
+			},
+		},
+		{
+			name: "existing object policy refuse, previously applied object exists",
+			syncSet: func() *hivev1.SyncSet {
+				ss := testSyncSetWithResources("aaa",
+					testCM("cm1", "key1", "value2"),
+				)
+				ss.Spec.ExistingObjectPolicy = hivev1.RefuseExistingObjectPolicy
+				return ss
+			}(),
+			status: successfulResourceStatus(
+				testCM("cm1", "key1", "value1"),
+			),
+			remoteObjs: []runtime.Object{
+				testCM("cm1", "key1", "value1"),
+			},
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				validateSyncSetInstanceStatus(t, ssi.Status, successfulResourceStatus(
+					testCM("cm1", "key1", "value2"),
+				))
+			},
+		},
 		{
 			name: "Apply single SecretReference successfully",
 			existingObjs: []runtime.Object{
@@ -563,6 +1018,284 @@ func TestSyncSetReconcile(t *testing.T) {
 				deletedItem("bar", secretsResource),
 			},
 		},
+		{
+			name: "Apply single ConfigMapReference successfully",
+			existingObjs: []runtime.Object{
+				testConfigMap("foo", "bar"),
+			},
+			syncSet: testSyncSetWithConfigMapReferences("ss1", testConfigMapRef("foo")),
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				validateSyncSetInstanceStatus(t, ssi.Status,
+					successfulConfigMapReferenceStatus(testConfigMap("foo", "bar")))
+			},
+		},
+		{
+			name:      "Local ConfigMapReference configmap does not exist",
+			syncSet:   testSyncSetWithConfigMapReferences("ss1", testConfigMapRef("foo")),
+			expectErr: true,
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				status := hivev1.SyncSetInstanceStatus{}
+				status.ConfigMapReferences = append(status.ConfigMapReferences, applyFailedConfigMapReferenceStatus("ss1",
+					testConfigMap("foo", "bar"),
+				).ConfigMapReferences...)
+				validateSyncSetInstanceStatus(t, ssi.Status, status)
+			},
+		},
+		{
+			name: "Reapply ConfigMapReference when source changes",
+			existingObjs: []runtime.Object{
+				testConfigMap("foo", "bar***changed"),
+			},
+			status:  successfulConfigMapReferenceStatus(testConfigMap("foo", "bar")),
+			syncSet: testSyncSetWithConfigMapReferences("ss1", testConfigMapRef("foo")),
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				validateSyncSetInstanceStatus(t, ssi.Status,
+					successfulConfigMapReferenceStatus(testConfigMap("foo", "bar***changed")))
+			},
+		},
+		{
+			name: "Apply SecretReferences and ConfigMapReferences successfully",
+			existingObjs: []runtime.Object{
+				testSecret("foo", "bar"),
+				testConfigMap("foo", "bar"),
+			},
+			syncSet: func() *hivev1.SyncSet {
+				ss := testSyncSetWithSecretReferences("ss1", testSecretRef("foo"))
+				ss.Spec.ConfigMapReferences = []hivev1.ConfigMapReference{testConfigMapRef("foo")}
+				return ss
+			}(),
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				status := successfulSecretReferenceStatus(testSecret("foo", "bar"))
+				status.ConfigMapReferences = successfulConfigMapReferenceStatus(testConfigMap("foo", "bar")).ConfigMapReferences
+				validateSyncSetInstanceStatus(t, ssi.Status, status)
+			},
+		},
+		{
+			name: "syncset: delete removed configmap reference",
+			existingObjs: []runtime.Object{
+				testConfigMap("foo", "bar"),
+			},
+			status: successfulConfigMapReferenceStatus(
+				testConfigMap("foo", "bar"),
+				testConfigMap("baz", "bar"),
+			),
+			syncSet: func() *hivev1.SyncSet {
+				ss := testSyncSetWithConfigMapReferences("aaa", testConfigMapRef("foo"))
+				ss.Spec.ResourceApplyMode = hivev1.SyncResourceApplyMode
+				return ss
+			}(),
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				validateSyncSetInstanceStatus(t, ssi.Status,
+					successfulConfigMapReferenceStatus(testConfigMap("foo", "bar")))
+			},
+			expectDeleted: []deletedItemInfo{
+				deletedItem("baz", configMapsResource),
+			},
+		},
+		{
+			name: "syncset: fail to delete configmap reference configmap",
+			existingObjs: []runtime.Object{
+				testConfigMap("foo", "bar"),
+			},
+			status: successfulConfigMapReferenceStatus(
+				testConfigMap("foo", "bar"),
+				testConfigMap("delete-error", "baz"),
+			),
+			syncSet: func() *hivev1.SyncSet {
+				ss := testSyncSetWithConfigMapReferences("aaa", testConfigMapRef("foo"))
+				ss.Spec.ResourceApplyMode = hivev1.SyncResourceApplyMode
+				return ss
+			}(),
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				status := successfulConfigMapReferenceStatus(testConfigMap("foo", "bar"))
+				status.ConfigMapReferences = append(status.ConfigMapReferences, deleteFailedConfigMapReferenceStatus("aaa",
+					testConfigMap("delete-error", "baz"),
+				).ConfigMapReferences...)
+				validateSyncSetInstanceStatus(t, ssi.Status, status)
+			},
+		},
+		{
+			name: "cleanup deleted syncset configmaps",
+			deletedSyncSet: func() *hivev1.SyncSet {
+				ss := testSyncSetWithConfigMapReferences("aaa",
+					testConfigMapRef("foo"),
+					testConfigMapRef("bar"),
+				)
+				ss.Spec.ResourceApplyMode = hivev1.SyncResourceApplyMode
+				return ss
+			}(),
+			isDeleted: true,
+			status: successfulConfigMapReferenceStatus(
+				testConfigMap("foo", "bar"),
+				testConfigMap("bar", "baz"),
+			),
+			expectDeleted: []deletedItemInfo{
+				deletedItem("foo", configMapsResource),
+				deletedItem("bar", configMapsResource),
+			},
+		},
+		{
+			name:    "Delete objects listed in deletions",
+			syncSet: testSyncSetWithDeletions("ss1", testDeletion("foo"), testDeletion("bar")),
+			expectDeleted: []deletedItemInfo{
+				deletedItem("foo", "ConfigMap"),
+				deletedItem("bar", "ConfigMap"),
+			},
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				validateSyncSetInstanceStatus(t, ssi.Status,
+					deletionStatus(false, testDeletion("foo"), testDeletion("bar")))
+			},
+		},
+		{
+			name:    "Deletion of object that does not exist succeeds",
+			syncSet: testSyncSetWithDeletions("ss1", testDeletion("delete-not-found")),
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				validateSyncSetInstanceStatus(t, ssi.Status,
+					deletionStatus(false, testDeletion("delete-not-found")))
+			},
+		},
+		{
+			name:      "Failed deletion is reported and other deletions continue",
+			syncSet:   testSyncSetWithDeletions("ss1", testDeletion("delete-error"), testDeletion("foo")),
+			expectErr: true,
+			expectDeleted: []deletedItemInfo{
+				deletedItem("foo", "ConfigMap"),
+			},
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				status := deletionStatus(true, testDeletion("delete-error"))
+				status.Deletions = append(status.Deletions, deletionStatus(false, testDeletion("foo")).Deletions...)
+				validateSyncSetInstanceStatus(t, ssi.Status, status)
+			},
+		},
+		{
+			name: "Deletion with a namespace that does not match the scope of its kind fails",
+			syncSet: func() *hivev1.SyncSet {
+				namespaced := testDeletion("foo")
+				namespaced.Namespace = ""
+				clusterScoped := testDeletion("bar")
+				clusterScoped.Kind = "Namespace"
+				return testSyncSetWithDeletions("ss1", namespaced, clusterScoped)
+			}(),
+			expectErr: true,
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				namespaced := testDeletion("foo")
+				namespaced.Namespace = ""
+				clusterScoped := testDeletion("bar")
+				clusterScoped.Kind = "Namespace"
+				validateSyncSetInstanceStatus(t, ssi.Status, deletionStatus(true, namespaced, clusterScoped))
+			},
+		},
+		{
+			name:   "Removed deletion is dropped from status",
+			status: deletionStatus(false, testDeletion("foo"), testDeletion("bar")),
+			syncSet: func() *hivev1.SyncSet {
+				ss := testSyncSetWithDeletions("ss1", testDeletion("foo"))
+				ss.Spec.ResourceApplyMode = hivev1.SyncResourceApplyMode
+				return ss
+			}(),
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				validateSyncSetInstanceStatus(t, ssi.Status,
+					deletionStatus(false, testDeletion("foo")))
+			},
+		},
+		{
+			name:    "Unchanged deletion is not submitted again",
+			status:  deletionStatusWithTime(false, metav1.NewTime(tenMinutesAgo), testDeletion("foo")),
+			syncSet: testSyncSetWithDeletions("ss1", testDeletion("foo")),
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				validateSyncSetInstanceStatus(t, ssi.Status,
+					deletionStatus(false, testDeletion("foo")))
+				deleted := controllerutils.FindSyncCondition(ssi.Status.Deletions[0].Conditions, hivev1.ApplySuccessSyncCondition)
+				if deleted.LastProbeTime.Time.Unix() != tenMinutesAgo.Unix() {
+					t.Errorf("unexpected condition last probe time. Got: %v, Expected: %v", deleted.LastProbeTime.Time, tenMinutesAgo)
+				}
+			},
+		},
+		{
+			name: "Changed deletion is submitted again",
+			status: func() hivev1.SyncSetInstanceStatus {
+				status := deletionStatusWithTime(false, metav1.NewTime(tenMinutesAgo), testDeletion("foo"))
+				status.Deletions[0].Hash = "previous-hash"
+				return status
+			}(),
+			syncSet: testSyncSetWithDeletions("ss1", testDeletion("foo")),
+			expectDeleted: []deletedItemInfo{
+				deletedItem("foo", "ConfigMap"),
+			},
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				validateSyncSetInstanceStatus(t, ssi.Status,
+					deletionStatus(false, testDeletion("foo")))
+			},
+		},
+		{
+			name:    "Deletion is submitted again after the reapply interval",
+			status:  deletionStatusWithTime(false, metav1.NewTime(time.Now().Add(-reapplyInterval-time.Minute)), testDeletion("foo")),
+			syncSet: testSyncSetWithDeletions("ss1", testDeletion("foo")),
+			expectDeleted: []deletedItemInfo{
+				deletedItem("foo", "ConfigMap"),
+			},
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				validateSyncSetInstanceStatus(t, ssi.Status,
+					deletionStatus(false, testDeletion("foo")))
+				deleted := controllerutils.FindSyncCondition(ssi.Status.Deletions[0].Conditions, hivev1.ApplySuccessSyncCondition)
+				if time.Since(deleted.LastProbeTime.Time) > time.Minute {
+					t.Errorf("expected condition last probe time to be updated. Got: %v", deleted.LastProbeTime.Time)
+				}
+			},
+		},
+		{
+			name:    "Failed deletion is submitted again",
+			status:  deletionStatusWithTime(true, metav1.NewTime(tenMinutesAgo), testDeletion("foo")),
+			syncSet: testSyncSetWithDeletions("ss1", testDeletion("foo")),
+			expectDeleted: []deletedItemInfo{
+				deletedItem("foo", "ConfigMap"),
+			},
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				if len(ssi.Status.Deletions) != 1 {
+					t.Fatalf("unexpected number of deletion statuses: %d", len(ssi.Status.Deletions))
+				}
+				conditions := ssi.Status.Deletions[0].Conditions
+				if c := controllerutils.FindSyncCondition(conditions, hivev1.ApplySuccessSyncCondition); c == nil || c.Status != corev1.ConditionTrue {
+					t.Errorf("expected successful deletion condition, got %v", c)
+				}
+				if c := controllerutils.FindSyncCondition(conditions, hivev1.DeletionFailedSyncCondition); c == nil || c.Status != corev1.ConditionFalse {
+					t.Errorf("expected deletion failed condition to be cleared, got %v", c)
+				}
+			},
+		},
+		{
+			name:         "Conflict with another syncset instance is reported on both",
+			syncSet:      testSyncSetWithResources("ss1", testCM("cm1", "key1", "value1")),
+			existingObjs: []runtime.Object{otherSyncSetInstance("other", testCM("cm1", "key1", "other"))},
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				validateResourceConflictCondition(t, ssi, corev1.ConditionTrue)
+			},
+			validateOthers: func(t *testing.T, others []hivev1.SyncSetInstance) {
+				validateResourceConflictCondition(t, &others[0], corev1.ConditionTrue)
+			},
+		},
+		{
+			name:    "Conflict resolved on the other syncset instance",
+			syncSet: testSyncSetWithResources("ss1", testCM("cm2", "key1", "value1")),
+			existingObjs: []runtime.Object{
+				func() runtime.Object {
+					ssi := otherSyncSetInstance("other", testCM("cm1", "key1", "other"))
+					ssi.Status.Conditions = []hivev1.SyncCondition{{
+						Type:   hivev1.ResourceConflictSyncCondition,
+						Status: corev1.ConditionTrue,
+					}}
+					return ssi
+				}(),
+			},
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				if controllerutils.FindSyncCondition(ssi.Status.Conditions, hivev1.ResourceConflictSyncCondition) != nil {
+					t.Errorf("unexpected resource conflict condition")
+				}
+			},
+			validateOthers: func(t *testing.T, others []hivev1.SyncSetInstance) {
+				validateResourceConflictCondition(t, &others[0], corev1.ConditionFalse)
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -590,6 +1323,9 @@ func TestSyncSetReconcile(t *testing.T) {
 			}
 			controllerutils.AddFinalizer(ssi, hivev1.FinalizerSyncSetInstance)
 			ssi.Status = test.status
+			if test.syncSetHash != "" {
+				ssi.Spec.SyncSetHash = test.syncSetHash
+			}
 			if test.isDeleted {
 				now := metav1.Now()
 				ssi.DeletionTimestamp = &now
@@ -597,9 +1333,9 @@ func TestSyncSetReconcile(t *testing.T) {
 
 			runtimeObjs = append(runtimeObjs, ssi)
 			fakeClient := fake.NewFakeClient(runtimeObjs...)
-			dynamicClient := &fakeDynamicClient{}
+			dynamicClient := &fakeDynamicClient{remoteObjects: test.remoteObjs}
 
-			helper := &fakeHelper{t: t}
+			helper := &fakeHelper{t: t, kindsAddedBy: test.kindsAddedBy}
 			r := &ReconcileSyncSetInstance{
 				Client:         fakeClient,
 				scheme:         scheme.Scheme,
@@ -609,6 +1345,9 @@ func TestSyncSetReconcile(t *testing.T) {
 				dynamicClientBuilder: func(string, string) (dynamic.Interface, error) {
 					return dynamicClient, nil
 				},
+				resourcesBuilder: func(string) ([]schema.GroupVersionResource, error) {
+					return testRemoteResources(test.remoteObjs), nil
+				},
 			}
 			_, err := r.Reconcile(reconcile.Request{
 				NamespacedName: types.NamespacedName{
@@ -638,10 +1377,76 @@ func TestSyncSetReconcile(t *testing.T) {
 				}
 				test.validate(t, result)
 			}
+			if test.validateOthers != nil {
+				list := &hivev1.SyncSetInstanceList{}
+				if err := fakeClient.List(context.TODO(), list); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				others := []hivev1.SyncSetInstance{}
+				for _, other := range list.Items {
+					if other.Name != ssi.Name {
+						others = append(others, other)
+					}
+				}
+				test.validateOthers(t, others)
+			}
 		})
 	}
 }
 
+func TestNewApplierBuilder(t *testing.T) {
+	defer os.Unsetenv(constants.SyncSetApplierEnvVar)
+
+	// The server-side applier discovers the resources of the cluster through the cached connection to it
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch req.URL.Path {
+		case "/api":
+			json.NewEncoder(w).Encode(&metav1.APIVersions{Versions: []string{"v1"}})
+		case "/apis":
+			json.NewEncoder(w).Encode(&metav1.APIGroupList{})
+		default:
+			json.NewEncoder(w).Encode(&metav1.APIResourceList{GroupVersion: "v1"})
+		}
+	}))
+	defer server.Close()
+	kubeconfig := []byte(fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: %s
+  name: cluster
+contexts:
+- context:
+    cluster: cluster
+    user: admin
+  name: admin
+current-context: admin
+users:
+- name: admin
+  user:
+    token: secret
+`, server.URL))
+
+	os.Unsetenv(constants.SyncSetApplierEnvVar)
+	applier, err := newApplierBuilder()(kubeconfig, log.StandardLogger())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := applier.(*resource.Helper); !ok {
+		t.Errorf("expected kubectl applier by default")
+	}
+
+	os.Setenv(constants.SyncSetApplierEnvVar, string(hivev1.ServerSideSyncSetApplierType))
+	applier, err = newApplierBuilder()(kubeconfig, log.StandardLogger())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := applier.(*resource.ServerSideApplier); !ok {
+		t.Errorf("expected server-side applier when configured")
+	}
+}
+
 func sameDeletedItem(a, b deletedItemInfo) bool {
 	return a.name == b.name &&
 		a.namespace == b.namespace &&
@@ -777,6 +1582,24 @@ func testSyncSetWithSecretReferences(name string, refs ...hivev1.SecretReference
 	return ss
 }
 
+func testSyncSetWithConfigMapReferences(name string, refs ...hivev1.ConfigMapReference) *hivev1.SyncSet {
+	ss := &hivev1.SyncSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+		},
+		Spec: hivev1.SyncSetSpec{
+			ClusterDeploymentRefs: []corev1.LocalObjectReference{
+				{
+					Name: testName,
+				},
+			},
+		},
+	}
+	ss.Spec.ConfigMapReferences = append(ss.Spec.ConfigMapReferences, refs...)
+	return ss
+}
+
 func testSyncObjectPatch(name, namespace, kind, apiVersion string, applyMode hivev1.SyncSetPatchApplyMode, value string) hivev1.SyncObjectPatch {
 	patch := fmt.Sprintf("{'spec': {'key: '%v'}}", value)
 	return hivev1.SyncObjectPatch{
@@ -809,6 +1632,23 @@ func testSelectorSyncSetWithResources(name string, resources ...runtime.Object)
 	return ss
 }
 
+// testRolloutSnapshot returns the rollout snapshot of the selectorsyncset spec for the given hash. The resources are
+// serialized the way they are when read from the API server.
+func testRolloutSnapshot(sss *hivev1.SelectorSyncSet, hash string) *corev1.ConfigMap {
+	for i, r := range sss.Spec.Resources {
+		raw, err := json.Marshal(r.Object)
+		if err != nil {
+			panic(err)
+		}
+		sss.Spec.Resources[i] = runtime.RawExtension{Raw: raw}
+	}
+	snapshot, err := controllerutils.NewRolloutSnapshot(sss, hash)
+	if err != nil {
+		panic(err)
+	}
+	return snapshot
+}
+
 func testMatchingSelectorSyncSetWithSecretReferences(name string, refs ...hivev1.SecretReference) *hivev1.SelectorSyncSet {
 	return testSelectorSyncSetWithSecretReferences(name, map[string]string{"region": "us-east-1"}, refs...)
 }
@@ -847,6 +1687,27 @@ func testCM(name, key, value string) runtime.Object {
 	}
 }
 
+func testCMWithAnnotations(name, key, value string, annotations map[string]string) runtime.Object {
+	cm := testCM(name, key, value).(*corev1.ConfigMap)
+	for k, v := range annotations {
+		cm.Annotations[k] = v
+	}
+	return cm
+}
+
+// testOwnedCM returns a remote configmap labeled as owned by the syncset with the given name
+func testOwnedCM(syncSetName, name, key, value string) runtime.Object {
+	cm := testCM(name, key, value).(*corev1.ConfigMap)
+	setSyncSetOwner(cm, syncSetInstanceForSyncSet(testClusterDeployment(), testSyncSetWithResources(syncSetName)))
+	return cm
+}
+
+func testOwnedSecret(syncSetName, name string) runtime.Object {
+	secret := testSecret(name, "value")
+	setSyncSetOwner(secret, syncSetInstanceForSyncSet(testClusterDeployment(), testSyncSetWithResources(syncSetName)))
+	return secret
+}
+
 func deletedItem(name, resource string) deletedItemInfo {
 	return deletedItemInfo{
 		name:      name,
@@ -909,6 +1770,64 @@ func testSecretWithOwner(name, data string) *corev1.Secret {
 	return secret
 }
 
+func testSyncSetWithDeletions(name string, deletions ...hivev1.SyncObjectDeletion) *hivev1.SyncSet {
+	ss := &hivev1.SyncSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+		},
+		Spec: hivev1.SyncSetSpec{
+			ClusterDeploymentRefs: []corev1.LocalObjectReference{
+				{
+					Name: testName,
+				},
+			},
+		},
+	}
+	ss.Spec.Deletions = append(ss.Spec.Deletions, deletions...)
+	return ss
+}
+
+func testDeletion(name string) hivev1.SyncObjectDeletion {
+	return hivev1.SyncObjectDeletion{
+		APIVersion: "v1",
+		Kind:       "ConfigMap",
+		Name:       name,
+		Namespace:  testNamespace,
+	}
+}
+
+func testConfigMapRef(name string) hivev1.ConfigMapReference {
+	return hivev1.ConfigMapReference{
+		Source: corev1.ObjectReference{
+			Name:       name,
+			Namespace:  testNamespace,
+			APIVersion: "v1",
+		},
+		Target: corev1.ObjectReference{
+			Name:       name,
+			Namespace:  testNamespace,
+			APIVersion: "v1",
+		},
+	}
+}
+
+func testConfigMap(name, data string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+		},
+		Data: map[string]string{
+			testName: data,
+		},
+	}
+}
+
 func kubeconfigSecret() *corev1.Secret {
 	s := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -976,6 +1895,33 @@ func applyFailedSecretReferenceStatus(name string, secrets ...runtime.Object) hi
 	return status
 }
 
+func applyFailedConfigMapReferenceStatus(name string, configMaps ...runtime.Object) hivev1.SyncSetObjectStatus {
+	conditionTime := metav1.Now()
+	status := hivev1.SyncSetObjectStatus{
+		Name: name,
+	}
+	for _, c := range configMaps {
+		obj, _ := meta.Accessor(c)
+		status.ConfigMapReferences = append(status.ConfigMapReferences, hivev1.SyncStatus{
+			APIVersion: c.GetObjectKind().GroupVersionKind().GroupVersion().String(),
+			Kind:       c.GetObjectKind().GroupVersionKind().Kind,
+			Resource:   configMapsResource,
+			Name:       obj.GetName(),
+			Namespace:  obj.GetNamespace(),
+			Hash:       "",
+			Conditions: []hivev1.SyncCondition{
+				{
+					Type:               hivev1.ApplyFailureSyncCondition,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: conditionTime,
+					LastProbeTime:      conditionTime,
+				},
+			},
+		})
+	}
+	return status
+}
+
 func deleteFailedResourceStatus(name string, resources ...runtime.Object) hivev1.SyncSetObjectStatus {
 	conditionTime := metav1.Now()
 	status := hivev1.SyncSetObjectStatus{
@@ -1043,6 +1989,40 @@ func deleteFailedSecretReferenceStatus(name string, secrets ...runtime.Object) h
 	return status
 }
 
+func deleteFailedConfigMapReferenceStatus(name string, configMaps ...runtime.Object) hivev1.SyncSetObjectStatus {
+	conditionTime := metav1.Now()
+	status := hivev1.SyncSetObjectStatus{
+		Name: name,
+	}
+	for _, c := range configMaps {
+		obj, _ := meta.Accessor(c)
+		hash, _ := controllerutils.GetChecksumOfObject(c)
+		status.ConfigMapReferences = append(status.ConfigMapReferences, hivev1.SyncStatus{
+			APIVersion: c.GetObjectKind().GroupVersionKind().GroupVersion().String(),
+			Kind:       c.GetObjectKind().GroupVersionKind().Kind,
+			Resource:   configMapsResource,
+			Name:       obj.GetName(),
+			Namespace:  obj.GetNamespace(),
+			Hash:       hash,
+			Conditions: []hivev1.SyncCondition{
+				{
+					Type:               hivev1.ApplySuccessSyncCondition,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: conditionTime,
+					LastProbeTime:      conditionTime,
+				},
+				{
+					Type:               hivev1.DeletionFailedSyncCondition,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: conditionTime,
+					LastProbeTime:      conditionTime,
+				},
+			},
+		})
+	}
+	return status
+}
+
 func failedPatchStatus(name string, patches []hivev1.SyncObjectPatch) hivev1.SyncSetObjectStatus {
 	conditionTime := metav1.Now()
 	status := hivev1.SyncSetObjectStatus{
@@ -1126,6 +2106,64 @@ func successfulSecretReferenceStatusWithTime(secrets []runtime.Object, condition
 	return status
 }
 
+func deletionStatus(failed bool, deletions ...hivev1.SyncObjectDeletion) hivev1.SyncSetInstanceStatus {
+	return deletionStatusWithTime(failed, metav1.Now(), deletions...)
+}
+
+func deletionStatusWithTime(failed bool, conditionTime metav1.Time, deletions ...hivev1.SyncObjectDeletion) hivev1.SyncSetInstanceStatus {
+	conditionType := hivev1.ApplySuccessSyncCondition
+	if failed {
+		conditionType = hivev1.DeletionFailedSyncCondition
+	}
+	status := hivev1.SyncSetInstanceStatus{}
+	for _, d := range deletions {
+		b, _ := json.Marshal(d)
+		status.Deletions = append(status.Deletions, hivev1.SyncStatus{
+			APIVersion: d.APIVersion,
+			Kind:       d.Kind,
+			Resource:   d.Kind,
+			Name:       d.Name,
+			Namespace:  d.Namespace,
+			Hash:       fakeHashFunc(nil)(b),
+			Conditions: []hivev1.SyncCondition{
+				{
+					Type:               conditionType,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: conditionTime,
+					LastProbeTime:      conditionTime,
+				},
+			},
+		})
+	}
+	return status
+}
+
+func successfulConfigMapReferenceStatus(configMaps ...runtime.Object) hivev1.SyncSetInstanceStatus {
+	conditionTime := metav1.Now()
+	status := hivev1.SyncSetInstanceStatus{}
+	for _, c := range configMaps {
+		obj, _ := meta.Accessor(c)
+		hash, _ := controllerutils.GetChecksumOfObject(c)
+		status.ConfigMapReferences = append(status.ConfigMapReferences, hivev1.SyncStatus{
+			APIVersion: c.GetObjectKind().GroupVersionKind().GroupVersion().String(),
+			Kind:       c.GetObjectKind().GroupVersionKind().Kind,
+			Resource:   configMapsResource,
+			Name:       obj.GetName(),
+			Namespace:  obj.GetNamespace(),
+			Hash:       hash,
+			Conditions: []hivev1.SyncCondition{
+				{
+					Type:               hivev1.ApplySuccessSyncCondition,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: conditionTime,
+					LastProbeTime:      conditionTime,
+				},
+			},
+		})
+	}
+	return status
+}
+
 func successfulPatchStatus(patches []hivev1.SyncObjectPatch) hivev1.SyncSetInstanceStatus {
 	return successfulPatchStatusWithTime(patches, metav1.Now())
 }
@@ -1155,10 +2193,15 @@ func successfulPatchStatusWithTime(patches []hivev1.SyncObjectPatch, conditionTi
 
 type fakeHelper struct {
 	t *testing.T
+	// kindsAddedBy are kinds that Info does not find until the resource with the given name has been applied and the
+	// REST mapper has been reset
+	kindsAddedBy map[string]string
+	applied      sets.String
+	addedKinds   sets.String
 }
 
-func (f *fakeHelper) newHelper(kubeconfig []byte, logger log.FieldLogger) Applier {
-	return f
+func (f *fakeHelper) newHelper(kubeconfig []byte, logger log.FieldLogger) (Applier, error) {
+	return f, nil
 }
 
 func (f *fakeHelper) ApplyRuntimeObject(object runtime.Object, scheme *runtime.Scheme) (resource.ApplyResult, error) {
@@ -1177,6 +2220,10 @@ func (f *fakeHelper) Apply(data []byte) (resource.ApplyResult, error) {
 	if info.Name == "apply-error" {
 		return "", fmt.Errorf("cannot apply resource")
 	}
+	if f.applied == nil {
+		f.applied = sets.NewString()
+	}
+	f.applied.Insert(info.Name)
 	return resource.UnknownApplyResult, nil
 }
 
@@ -1189,6 +2236,10 @@ func (f *fakeHelper) Info(data []byte) (*resource.Info, error) {
 	if obj.GetName() == "info-error" {
 		return nil, fmt.Errorf("cannot determine info")
 	}
+	kind := r.GetObjectKind().GroupVersionKind().Kind
+	if _, ok := f.kindsAddedBy[kind]; ok && !f.addedKinds.Has(kind) {
+		return nil, fmt.Errorf("no matches for kind %q", kind)
+	}
 
 	return &resource.Info{
 		Name:       obj.GetName(),
@@ -1196,9 +2247,21 @@ func (f *fakeHelper) Info(data []byte) (*resource.Info, error) {
 		Kind:       r.GetObjectKind().GroupVersionKind().Kind,
 		APIVersion: r.GetObjectKind().GroupVersionKind().GroupVersion().String(),
 		Resource:   r.GetObjectKind().GroupVersionKind().Kind,
+		Namespaced: kind != "Namespace",
 	}, nil
 }
 
+func (f *fakeHelper) ResetRESTMapper() {
+	if f.addedKinds == nil {
+		f.addedKinds = sets.NewString()
+	}
+	for kind, name := range f.kindsAddedBy {
+		if f.applied.Has(name) {
+			f.addedKinds.Insert(kind)
+		}
+	}
+}
+
 func (f *fakeHelper) Patch(name types.NamespacedName, kind, apiVersion string, patch []byte, patchType string) error {
 	p := string(patch)
 	if strings.Contains(p, "patch-error") {
@@ -1218,6 +2281,12 @@ func validateSyncSetInstanceStatus(t *testing.T, actual, expected hivev1.SyncSet
 	if len(actual.SecretReferences) != len(expected.SecretReferences) {
 		t.Errorf("number of secret reference statuses does not match, actual %d, expected: %d", len(actual.SecretReferences), len(expected.SecretReferences))
 	}
+	if len(actual.ConfigMapReferences) != len(expected.ConfigMapReferences) {
+		t.Errorf("number of configmap reference statuses does not match, actual %d, expected: %d", len(actual.ConfigMapReferences), len(expected.ConfigMapReferences))
+	}
+	if len(actual.Deletions) != len(expected.Deletions) {
+		t.Errorf("number of deletion statuses does not match, actual %d, expected: %d", len(actual.Deletions), len(expected.Deletions))
+	}
 
 	for _, actualResource := range actual.Resources {
 		found := false
@@ -1263,6 +2332,36 @@ func validateSyncSetInstanceStatus(t *testing.T, actual, expected hivev1.SyncSet
 				actualSecretReference.Namespace, actualSecretReference.Name, actualSecretReference.Kind, actualSecretReference.APIVersion)
 		}
 	}
+
+	for _, actualConfigMapReference := range actual.ConfigMapReferences {
+		found := false
+		for _, expectedConfigMapReference := range expected.ConfigMapReferences {
+			if matchesConfigMapReferenceStatus(actualConfigMapReference, expectedConfigMapReference) {
+				found = true
+				validateSyncStatus(t, actualConfigMapReference, expectedConfigMapReference)
+				break
+			}
+		}
+		if !found {
+			t.Errorf("got unexpected configmap reference status: %s/%s (kind: %s, apiVersion: %s)",
+				actualConfigMapReference.Namespace, actualConfigMapReference.Name, actualConfigMapReference.Kind, actualConfigMapReference.APIVersion)
+		}
+	}
+
+	for _, actualDeletion := range actual.Deletions {
+		found := false
+		for _, expectedDeletion := range expected.Deletions {
+			if matchesResourceStatus(actualDeletion, expectedDeletion) {
+				found = true
+				validateSyncStatus(t, actualDeletion, expectedDeletion)
+				break
+			}
+		}
+		if !found {
+			t.Errorf("got unexpected deletion status: %s/%s (kind: %s, apiVersion: %s)",
+				actualDeletion.Namespace, actualDeletion.Name, actualDeletion.Kind, actualDeletion.APIVersion)
+		}
+	}
 }
 
 func matchesResourceStatus(a, b hivev1.SyncStatus) bool {
@@ -1286,6 +2385,13 @@ func matchesSecretReferenceStatus(a, b hivev1.SyncStatus) bool {
 		a.APIVersion == b.APIVersion
 }
 
+func matchesConfigMapReferenceStatus(a, b hivev1.SyncStatus) bool {
+	return a.Name == b.Name &&
+		a.Namespace == b.Namespace &&
+		a.Kind == b.Kind &&
+		a.APIVersion == b.APIVersion
+}
+
 func validateSyncStatus(t *testing.T, actual, expected hivev1.SyncStatus) {
 	if len(actual.Conditions) != len(expected.Conditions) {
 		t.Errorf("number of conditions do not match for resource %s/%s (kind: %s, apiVersion: %s). Expected: %d, Actual: %d",
@@ -1315,6 +2421,17 @@ func validateSyncStatus(t *testing.T, actual, expected hivev1.SyncStatus) {
 	}
 }
 
+func validateResourceConflictCondition(t *testing.T, ssi *hivev1.SyncSetInstance, status corev1.ConditionStatus) {
+	condition := controllerutils.FindSyncCondition(ssi.Status.Conditions, hivev1.ResourceConflictSyncCondition)
+	if condition == nil {
+		t.Errorf("missing resource conflict condition on syncset instance %s", ssi.Name)
+		return
+	}
+	if condition.Status != status {
+		t.Errorf("unexpected resource conflict condition status on syncset instance %s: %s", ssi.Name, condition.Status)
+	}
+}
+
 func validateUnknownObjectCondition(t *testing.T, status hivev1.SyncSetInstanceStatus) {
 	if len(status.Conditions) != 1 {
 		t.Errorf("did not get the expected number of syncset level conditions (1)")
@@ -1329,6 +2446,40 @@ func validateUnknownObjectCondition(t *testing.T, status hivev1.SyncSetInstanceS
 	}
 }
 
+func validateReadyCondition(t *testing.T, status hivev1.SyncStatus, expected corev1.ConditionStatus) {
+	condition := controllerutils.FindSyncCondition(status.Conditions, hivev1.ReadySyncCondition)
+	if condition == nil {
+		t.Errorf("ready condition not found for resource %s/%s", status.Namespace, status.Name)
+		return
+	}
+	if condition.Status != expected {
+		t.Errorf("unexpected ready condition status for resource %s/%s. Expected: %s, Actual: %s", status.Namespace, status.Name, expected, condition.Status)
+	}
+}
+
+func validateDriftedCondition(t *testing.T, status hivev1.SyncStatus, expected corev1.ConditionStatus) *hivev1.SyncCondition {
+	condition := controllerutils.FindSyncCondition(status.Conditions, hivev1.DriftedSyncCondition)
+	if condition == nil {
+		t.Errorf("drifted condition not found for resource %s/%s", status.Namespace, status.Name)
+		return nil
+	}
+	if condition.Status != expected {
+		t.Errorf("unexpected drifted condition status for resource %s/%s. Expected: %s, Actual: %s", status.Namespace, status.Name, expected, condition.Status)
+	}
+	return condition
+}
+
+func validateWaitingForReadinessCondition(t *testing.T, status hivev1.SyncSetInstanceStatus, expected corev1.ConditionStatus) {
+	condition := controllerutils.FindSyncCondition(status.Conditions, hivev1.WaitingForReadinessSyncCondition)
+	if condition == nil {
+		t.Errorf("waiting for readiness condition not found")
+		return
+	}
+	if condition.Status != expected {
+		t.Errorf("unexpected waiting for readiness condition status. Expected: %s, Actual: %s", expected, condition.Status)
+	}
+}
+
 func decode(t *testing.T, data []byte) (runtime.Object, metav1.Object, error) {
 	decoder := scheme.Codecs.UniversalDecoder(corev1.SchemeGroupVersion)
 	r, _, err := decoder.Decode(data, nil, nil)
@@ -1371,7 +2522,8 @@ type deletedItemInfo struct {
 }
 
 type fakeDynamicClient struct {
-	deletedItems []deletedItemInfo
+	deletedItems  []deletedItemInfo
+	remoteObjects []runtime.Object
 }
 
 type fakeNamespaceableClient struct {
@@ -1411,6 +2563,9 @@ func (c *fakeNamespaceableClient) Delete(name string, options *metav1.DeleteOpti
 	if name == "delete-error" {
 		return fmt.Errorf("cannot delete resource")
 	}
+	if name == "delete-not-found" {
+		return errors.NewNotFound(c.resource.GroupResource(), name)
+	}
 
 	c.client.deletedItems = append(c.client.deletedItems, deletedItemInfo{
 		name:      name,
@@ -1427,11 +2582,41 @@ func (c *fakeNamespaceableClient) DeleteCollection(options *metav1.DeleteOptions
 }
 
 func (c *fakeNamespaceableClient) Get(name string, options metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error) {
-	return nil, nil
+	for _, obj := range c.client.remoteObjects {
+		accessor, _ := meta.Accessor(obj)
+		if accessor.GetName() != name || accessor.GetNamespace() != c.namespace {
+			continue
+		}
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, err
+		}
+		return &unstructured.Unstructured{Object: content}, nil
+	}
+	return nil, errors.NewNotFound(c.resource.GroupResource(), name)
 }
 
 func (c *fakeNamespaceableClient) List(opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
-	return nil, nil
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+	list := &unstructured.UnstructuredList{}
+	for _, obj := range c.client.remoteObjects {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, err
+		}
+		item := unstructured.Unstructured{Object: content}
+		// The fake helper uses the kind of objects as their resource
+		if item.GetKind() != c.resource.Resource || (c.namespace != "" && item.GetNamespace() != c.namespace) {
+			continue
+		}
+		if selector.Matches(labels.Set(item.GetLabels())) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, nil
 }
 
 func (c *fakeNamespaceableClient) Watch(opts metav1.ListOptions) (watch.Interface, error) {
@@ -1442,6 +2627,22 @@ func (c *fakeNamespaceableClient) Patch(name string, pt types.PatchType, data []
 	return nil, nil
 }
 
+// testRemoteResources returns the resources discovered on a cluster with the remote objects. The fake dynamic client
+// uses the kind of objects as their resource.
+func testRemoteResources(remoteObjs []runtime.Object) []schema.GroupVersionResource {
+	resources := []schema.GroupVersionResource{}
+	found := map[schema.GroupVersionResource]bool{}
+	for _, obj := range remoteObjs {
+		gvk := obj.GetObjectKind().GroupVersionKind()
+		gvr := gvk.GroupVersion().WithResource(gvk.Kind)
+		if !found[gvr] {
+			found[gvr] = true
+			resources = append(resources, gvr)
+		}
+	}
+	return resources
+}
+
 func syncSetInstanceForSyncSet(cd *hivev1.ClusterDeployment, syncSet *hivev1.SyncSet) *hivev1.SyncSetInstance {
 	ownerRef := metav1.NewControllerRef(cd, hivev1.SchemeGroupVersion.WithKind("ClusterDeployment"))
 	hash := computeHash(syncSet.Spec)
@@ -1466,7 +2667,7 @@ func syncSetInstanceForSyncSet(cd *hivev1.ClusterDeployment, syncSet *hivev1.Syn
 
 func syncSetInstanceForSelectorSyncSet(cd *hivev1.ClusterDeployment, selectorSyncSet *hivev1.SelectorSyncSet) *hivev1.SyncSetInstance {
 	ownerRef := metav1.NewControllerRef(cd, hivev1.SchemeGroupVersion.WithKind("ClusterDeployment"))
-	hash := computeHash(selectorSyncSet.Spec)
+	hash := computeHash(controllerutils.SelectorSyncSetContentSpec(selectorSyncSet.Spec))
 	return &hivev1.SyncSetInstance{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            syncSetInstanceNameForSelectorSyncSet(cd, selectorSyncSet),
@@ -1486,6 +2687,20 @@ func syncSetInstanceForSelectorSyncSet(cd *hivev1.ClusterDeployment, selectorSyn
 	}
 }
 
+func otherSyncSetInstance(name string, resources ...runtime.Object) *hivev1.SyncSetInstance {
+	ssi := &hivev1.SyncSetInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+		},
+		Spec: hivev1.SyncSetInstanceSpec{
+			ClusterDeploymentRef: corev1.LocalObjectReference{Name: testName},
+		},
+	}
+	ssi.Status = successfulResourceStatus(resources...)
+	return ssi
+}
+
 func syncSetInstanceNameForSyncSet(cd *hivev1.ClusterDeployment, syncSet *hivev1.SyncSet) string {
 	syncSetPart := helpers.GetName(syncSet.Name, "syncset", validation.DNS1123SubdomainMaxLength-validation.DNS1123LabelMaxLength)
 	return fmt.Sprintf("%s-%s", cd.Name, syncSetPart)
@@ -0,0 +1,306 @@
+package syncsetstatus
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	"github.com/openshift/hive/pkg/syncsetmatch"
+)
+
+const (
+	controllerName = "syncsetstatus"
+)
+
+// Add creates a new SyncSetStatus controller and adds it to the manager with default RBAC.
+func Add(mgr manager.Manager) error {
+	return AddToManager(mgr, NewReconciler(mgr))
+}
+
+// NewReconciler returns a new reconcile.Reconciler
+func NewReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileSyncSetStatus{
+		Client:      controllerutils.NewClientWithMetricsOrDie(mgr, controllerName),
+		scheme:      mgr.GetScheme(),
+		logger:      log.WithField("controller", controllerName),
+		computeHash: controllerutils.GetChecksumOfObject,
+	}
+}
+
+// AddToManager adds a new Controller to mgr with r as the reconcile.Reconciler
+func AddToManager(mgr manager.Manager, r reconcile.Reconciler) error {
+	c, err := controller.New("syncsetstatus-controller", mgr, controller.Options{Reconciler: r, MaxConcurrentReconciles: controllerutils.GetConcurrentReconciles()})
+	if err != nil {
+		return fmt.Errorf("cannot create new syncsetstatus-controller: %v", err)
+	}
+
+	// Index syncset instances by syncset and selectorsyncset to look up the instances of a syncset
+	if err := controllerutils.IndexSyncSetInstances(mgr); err != nil {
+		return err
+	}
+
+	// SyncSets are namespaced and SelectorSyncSets are cluster scoped, so requests for
+	// both can be enqueued without ambiguity.
+	err = c.Watch(&source.Kind{Type: &hivev1.SyncSet{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return fmt.Errorf("cannot start watch on syncsets: %v", err)
+	}
+
+	err = c.Watch(&source.Kind{Type: &hivev1.SelectorSyncSet{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return fmt.Errorf("cannot start watch on selectorsyncsets: %v", err)
+	}
+
+	err = c.Watch(&source.Kind{Type: &hivev1.SyncSetInstance{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(syncSetInstanceHandlerFunc),
+	})
+	if err != nil {
+		return fmt.Errorf("cannot start watch on syncsetinstances: %v", err)
+	}
+
+	return nil
+}
+
+// syncSetInstanceHandlerFunc enqueues the SyncSet or SelectorSyncSet that a SyncSetInstance was created for
+func syncSetInstanceHandlerFunc(a handler.MapObject) []reconcile.Request {
+	syncSetInstance, ok := a.Object.(*hivev1.SyncSetInstance)
+	if !ok {
+		return []reconcile.Request{}
+	}
+	switch {
+	case syncSetInstance.Spec.SyncSetRef != nil:
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{
+			Namespace: syncSetInstance.Namespace,
+			Name:      syncSetInstance.Spec.SyncSetRef.Name,
+		}}}
+	case syncSetInstance.Spec.SelectorSyncSetRef != nil:
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{
+			Name: syncSetInstance.Spec.SelectorSyncSetRef.Name,
+		}}}
+	}
+	return []reconcile.Request{}
+}
+
+var _ reconcile.Reconciler = &ReconcileSyncSetStatus{}
+
+// ReconcileSyncSetStatus rolls up the results of the SyncSetInstances of a SyncSet or SelectorSyncSet
+// into the status of the SyncSet or SelectorSyncSet
+type ReconcileSyncSetStatus struct {
+	client.Client
+	scheme      *runtime.Scheme
+	logger      log.FieldLogger
+	computeHash func(interface{}) (string, error)
+}
+
+// Reconcile updates the status of a SyncSet, when the request has a namespace, or a SelectorSyncSet, when it does not
+func (r *ReconcileSyncSetStatus) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	start := time.Now()
+	var logger log.FieldLogger
+	if request.Namespace == "" {
+		logger = r.logger.WithField("selectorSyncSet", request.Name)
+	} else {
+		logger = r.logger.WithField("syncSet", request.NamespacedName)
+	}
+	defer func() {
+		dur := time.Since(start)
+		hivemetrics.MetricControllerReconcileTime.WithLabelValues(controllerName).Observe(dur.Seconds())
+		logger.WithField("elapsed", dur).Debug("reconcile complete")
+	}()
+
+	if request.Namespace == "" {
+		return reconcile.Result{}, r.reconcileSelectorSyncSet(request.Name, logger)
+	}
+	return reconcile.Result{}, r.reconcileSyncSet(request.NamespacedName, logger)
+}
+
+func (r *ReconcileSyncSetStatus) reconcileSyncSet(name types.NamespacedName, logger log.FieldLogger) error {
+	syncSet := &hivev1.SyncSet{}
+	err := r.Get(context.TODO(), name, syncSet)
+	if errors.IsNotFound(err) || (err == nil && !syncSet.DeletionTimestamp.IsZero()) {
+		logger.Debug("syncset not found or being deleted")
+		return nil
+	}
+	if err != nil {
+		logger.WithError(err).Error("error looking up syncset")
+		return err
+	}
+
+	clusterDeployments := []*hivev1.ClusterDeployment{}
+	referenced := map[string]bool{}
+	for _, ref := range syncSet.Spec.ClusterDeploymentRefs {
+		if referenced[ref.Name] {
+			continue
+		}
+		referenced[ref.Name] = true
+		cd := &hivev1.ClusterDeployment{}
+		err := r.Get(context.TODO(), types.NamespacedName{Namespace: syncSet.Namespace, Name: ref.Name}, cd)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			logger.WithError(err).WithField("clusterDeployment", ref.Name).Error("cannot get cluster deployment")
+			return err
+		}
+		clusterDeployments = append(clusterDeployments, cd)
+	}
+
+	ssiList := &hivev1.SyncSetInstanceList{}
+	if err := r.List(context.TODO(), ssiList, client.InNamespace(syncSet.Namespace), client.MatchingField(controllerutils.SyncSetInstanceSyncSetIndex, syncSet.Name)); err != nil {
+		logger.WithError(err).Error("cannot list syncset instances")
+		return err
+	}
+	syncSetInstances := map[types.NamespacedName]*hivev1.SyncSetInstance{}
+	for i, ssi := range ssiList.Items {
+		if ssi.Spec.SyncSetRef != nil && ssi.Spec.SyncSetRef.Name == syncSet.Name {
+			syncSetInstances[clusterDeploymentName(&ssiList.Items[i])] = &ssiList.Items[i]
+		}
+	}
+
+	hash, err := r.computeHash(syncSet.Spec)
+	if err != nil {
+		logger.WithError(err).Error("cannot compute syncset hash")
+		return err
+	}
+
+	original := syncSet.DeepCopy()
+	syncSet.Status.SyncSetCommonStatus = aggregateStatus(syncSet.Generation, hash, clusterDeployments, syncSetInstances)
+	if reflect.DeepEqual(original.Status, syncSet.Status) {
+		return nil
+	}
+	logger.Debug("syncset status has changed, updating")
+	if err := r.Status().Update(context.TODO(), syncSet); err != nil {
+		logger.WithError(err).Log(controllerutils.LogLevel(err), "error updating syncset status")
+		return err
+	}
+	return nil
+}
+
+func (r *ReconcileSyncSetStatus) reconcileSelectorSyncSet(name string, logger log.FieldLogger) error {
+	selectorSyncSet := &hivev1.SelectorSyncSet{}
+	err := r.Get(context.TODO(), types.NamespacedName{Name: name}, selectorSyncSet)
+	if errors.IsNotFound(err) || (err == nil && !selectorSyncSet.DeletionTimestamp.IsZero()) {
+		logger.Debug("selectorsyncset not found or being deleted")
+		return nil
+	}
+	if err != nil {
+		logger.WithError(err).Error("error looking up selectorsyncset")
+		return err
+	}
+
+	cdSelector, err := metav1.LabelSelectorAsSelector(&selectorSyncSet.Spec.ClusterDeploymentSelector)
+	if err != nil {
+		logger.WithError(err).Error("invalid cluster deployment selector")
+		return err
+	}
+	cdList := &hivev1.ClusterDeploymentList{}
+	if err := r.List(context.TODO(), cdList, client.UseListOptions(&client.ListOptions{LabelSelector: cdSelector})); err != nil {
+		logger.WithError(err).Error("cannot list cluster deployments")
+		return err
+	}
+	clusterDeployments := []*hivev1.ClusterDeployment{}
+	for i := range cdList.Items {
+		matches, err := syncsetmatch.Matches(selectorSyncSet, &cdList.Items[i])
+		if err != nil {
+			logger.WithError(err).Error("cannot match selectorsyncset with cluster deployments")
+			return err
+		}
+		if matches {
+			clusterDeployments = append(clusterDeployments, &cdList.Items[i])
+		}
+	}
+
+	ssiList := &hivev1.SyncSetInstanceList{}
+	if err := r.List(context.TODO(), ssiList, client.MatchingField(controllerutils.SyncSetInstanceSelectorSyncSetIndex, selectorSyncSet.Name)); err != nil {
+		logger.WithError(err).Error("cannot list syncset instances")
+		return err
+	}
+	syncSetInstances := map[types.NamespacedName]*hivev1.SyncSetInstance{}
+	for i, ssi := range ssiList.Items {
+		if ssi.Spec.SelectorSyncSetRef != nil && ssi.Spec.SelectorSyncSetRef.Name == selectorSyncSet.Name {
+			syncSetInstances[clusterDeploymentName(&ssiList.Items[i])] = &ssiList.Items[i]
+		}
+	}
+
+	hash, err := r.computeHash(controllerutils.SelectorSyncSetContentSpec(selectorSyncSet.Spec))
+	if err != nil {
+		logger.WithError(err).Error("cannot compute selectorsyncset hash")
+		return err
+	}
+
+	original := selectorSyncSet.DeepCopy()
+	selectorSyncSet.Status.SyncSetCommonStatus = aggregateStatus(selectorSyncSet.Generation, hash, clusterDeployments, syncSetInstances)
+	if reflect.DeepEqual(original.Status, selectorSyncSet.Status) {
+		return nil
+	}
+	logger.Debug("selectorsyncset status has changed, updating")
+	if err := r.Status().Update(context.TODO(), selectorSyncSet); err != nil {
+		logger.WithError(err).Log(controllerutils.LogLevel(err), "error updating selectorsyncset status")
+		return err
+	}
+	return nil
+}
+
+// aggregateStatus computes the status of a syncset from the SyncSetInstances of the installed clusters it targets.
+// Clusters with a failure are counted as failed, clusters that applied the spec with the given hash as applied and
+// all other clusters as pending.
+func aggregateStatus(generation int64, hash string, clusterDeployments []*hivev1.ClusterDeployment, syncSetInstances map[types.NamespacedName]*hivev1.SyncSetInstance) hivev1.SyncSetCommonStatus {
+	status := hivev1.SyncSetCommonStatus{
+		ObservedGeneration: generation,
+	}
+	for _, cd := range clusterDeployments {
+		if !cd.Spec.Installed || !cd.DeletionTimestamp.IsZero() {
+			continue
+		}
+		status.TargetedClusters++
+		ssi, ok := syncSetInstances[types.NamespacedName{Namespace: cd.Namespace, Name: cd.Name}]
+		if !ok {
+			status.PendingClusters++
+			continue
+		}
+		if message, failed := controllerutils.SyncSetInstanceFailure(ssi); failed {
+			status.FailedClusters++
+			status.FailingClusters = append(status.FailingClusters, hivev1.SyncSetClusterFailure{
+				Namespace: cd.Namespace,
+				Name:      cd.Name,
+				Message:   message,
+			})
+			continue
+		}
+		if ssi.Status.AppliedSyncSetHash == hash {
+			status.AppliedClusters++
+		} else {
+			status.PendingClusters++
+		}
+	}
+	sort.Slice(status.FailingClusters, func(i, j int) bool {
+		a, b := status.FailingClusters[i], status.FailingClusters[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return status
+}
+
+func clusterDeploymentName(ssi *hivev1.SyncSetInstance) types.NamespacedName {
+	return types.NamespacedName{Namespace: ssi.Namespace, Name: ssi.Spec.ClusterDeploymentRef.Name}
+}
@@ -0,0 +1,282 @@
+package syncsetstatus
+
+import (
+	"context"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+)
+
+const (
+	testNamespace = "test-namespace"
+	testSyncSet   = "test-syncset"
+	testHash      = "current-hash"
+)
+
+func init() {
+	log.SetLevel(log.DebugLevel)
+}
+
+func TestReconcileSyncSetStatus(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	tests := []struct {
+		name           string
+		selector       bool
+		versionRange   string
+		existing       []runtime.Object
+		expectErr      bool
+		expectedStatus hivev1.SyncSetCommonStatus
+	}{
+		{
+			name: "no installed clusters",
+			existing: []runtime.Object{
+				testClusterDeployment("cd1", false),
+			},
+			expectedStatus: hivev1.SyncSetCommonStatus{ObservedGeneration: 2},
+		},
+		{
+			name: "syncset applied, failed and pending clusters",
+			existing: []runtime.Object{
+				testClusterDeployment("cd1", true),
+				testClusterDeployment("cd2", true),
+				testClusterDeployment("cd3", true),
+				testClusterDeployment("cd4", true),
+				testClusterDeployment("cd5", false),
+				testSyncSetInstance("cd1", false, testHash),
+				testSyncSetInstance("cd2", false, "old-hash"),
+				withFailedResource(testSyncSetInstance("cd3", false, "old-hash"), "apply failed"),
+			},
+			expectedStatus: hivev1.SyncSetCommonStatus{
+				ObservedGeneration: 2,
+				TargetedClusters:   4,
+				AppliedClusters:    1,
+				FailedClusters:     1,
+				PendingClusters:    2,
+				FailingClusters: []hivev1.SyncSetClusterFailure{
+					{
+						Namespace: testNamespace,
+						Name:      "cd3",
+						Message:   "ConfigMap test-namespace/cm: apply failed",
+					},
+				},
+			},
+		},
+		{
+			name:     "selectorsyncset applied and failed clusters",
+			selector: true,
+			existing: []runtime.Object{
+				testClusterDeployment("cd1", true),
+				testClusterDeployment("cd2", true),
+				testSyncSetInstance("cd1", true, testHash),
+				withFailedInstance(testSyncSetInstance("cd2", true, testHash), "template failed"),
+				testSyncSetInstance("cd3", false, testHash),
+			},
+			expectedStatus: hivev1.SyncSetCommonStatus{
+				ObservedGeneration: 2,
+				TargetedClusters:   2,
+				AppliedClusters:    1,
+				FailedClusters:     1,
+				FailingClusters: []hivev1.SyncSetClusterFailure{
+					{
+						Namespace: testNamespace,
+						Name:      "cd2",
+						Message:   "template failed",
+					},
+				},
+			},
+		},
+		{
+			name:     "selectorsyncset ignores non-matching clusters",
+			selector: true,
+			existing: []runtime.Object{
+				testClusterDeployment("cd1", true),
+				func() runtime.Object {
+					cd := testClusterDeployment("cd2", true)
+					cd.Labels = nil
+					return cd
+				}(),
+				testSyncSetInstance("cd1", true, testHash),
+			},
+			expectedStatus: hivev1.SyncSetCommonStatus{
+				ObservedGeneration: 2,
+				TargetedClusters:   1,
+				AppliedClusters:    1,
+			},
+		},
+		{
+			name:         "selectorsyncset with invalid version range",
+			selector:     true,
+			versionRange: ">=4.2.x",
+			existing: []runtime.Object{
+				testClusterDeployment("cd1", true),
+				testSyncSetInstance("cd1", true, testHash),
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			existing := test.existing
+			request := reconcile.Request{}
+			if test.selector {
+				selectorSyncSet := testSelectorSyncSet()
+				if test.versionRange != "" {
+					selectorSyncSet.Spec.ClusterMatch = &hivev1.SelectorSyncSetClusterMatch{VersionRange: test.versionRange}
+				}
+				existing = append(existing, selectorSyncSet)
+				request.NamespacedName = types.NamespacedName{Name: testSyncSet}
+			} else {
+				existing = append(existing, testSyncSetWithRefs("cd1", "cd2", "cd3", "cd4", "cd5"))
+				request.NamespacedName = types.NamespacedName{Namespace: testNamespace, Name: testSyncSet}
+			}
+			fakeClient := fake.NewFakeClient(existing...)
+			r := &ReconcileSyncSetStatus{
+				Client: fakeClient,
+				scheme: scheme.Scheme,
+				logger: log.WithField("controller", controllerName),
+				computeHash: func(interface{}) (string, error) {
+					return testHash, nil
+				},
+			}
+
+			_, err := r.Reconcile(request)
+			if test.expectErr {
+				assert.Error(t, err, "expected error from reconcile")
+				return
+			}
+			require.NoError(t, err, "unexpected error from reconcile")
+
+			if test.selector {
+				selectorSyncSet := &hivev1.SelectorSyncSet{}
+				require.NoError(t, fakeClient.Get(context.TODO(), request.NamespacedName, selectorSyncSet))
+				assert.Equal(t, test.expectedStatus, selectorSyncSet.Status.SyncSetCommonStatus, "unexpected selectorsyncset status")
+			} else {
+				syncSet := &hivev1.SyncSet{}
+				require.NoError(t, fakeClient.Get(context.TODO(), request.NamespacedName, syncSet))
+				assert.Equal(t, test.expectedStatus, syncSet.Status.SyncSetCommonStatus, "unexpected syncset status")
+			}
+		})
+	}
+}
+
+func TestSyncSetInstanceHandlerFunc(t *testing.T) {
+	requests := syncSetInstanceHandlerFunc(handlerObject(testSyncSetInstance("cd1", false, "")))
+	assert.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: testSyncSet}}}, requests)
+
+	requests = syncSetInstanceHandlerFunc(handlerObject(testSyncSetInstance("cd1", true, "")))
+	assert.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Name: testSyncSet}}}, requests)
+}
+
+func testClusterDeployment(name string, installed bool) *hivev1.ClusterDeployment {
+	return &hivev1.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+			Labels:    map[string]string{"region": "us-east-1"},
+		},
+		Spec: hivev1.ClusterDeploymentSpec{
+			Installed: installed,
+		},
+	}
+}
+
+func testSyncSetWithRefs(clusterDeployments ...string) *hivev1.SyncSet {
+	syncSet := &hivev1.SyncSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       testSyncSet,
+			Namespace:  testNamespace,
+			Generation: 2,
+		},
+	}
+	for _, name := range clusterDeployments {
+		syncSet.Spec.ClusterDeploymentRefs = append(syncSet.Spec.ClusterDeploymentRefs, corev1.LocalObjectReference{Name: name})
+	}
+	return syncSet
+}
+
+func testSelectorSyncSet() *hivev1.SelectorSyncSet {
+	return &hivev1.SelectorSyncSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       testSyncSet,
+			Generation: 2,
+		},
+		Spec: hivev1.SelectorSyncSetSpec{
+			ClusterDeploymentSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{"region": "us-east-1"},
+			},
+		},
+	}
+}
+
+func testSyncSetInstance(clusterDeployment string, selector bool, appliedHash string) *hivev1.SyncSetInstance {
+	ssi := &hivev1.SyncSetInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      clusterDeployment + "-" + testSyncSet,
+			Namespace: testNamespace,
+		},
+		Spec: hivev1.SyncSetInstanceSpec{
+			ClusterDeploymentRef: corev1.LocalObjectReference{Name: clusterDeployment},
+			SyncSetHash:          testHash,
+		},
+		Status: hivev1.SyncSetInstanceStatus{
+			AppliedSyncSetHash: appliedHash,
+		},
+	}
+	if selector {
+		ssi.Spec.SelectorSyncSetRef = &hivev1.SelectorSyncSetReference{Name: testSyncSet}
+	} else {
+		ssi.Spec.SyncSetRef = &corev1.LocalObjectReference{Name: testSyncSet}
+	}
+	return ssi
+}
+
+func withFailedResource(ssi *hivev1.SyncSetInstance, message string) *hivev1.SyncSetInstance {
+	ssi.Status.Resources = []hivev1.SyncStatus{
+		{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+			Name:       "cm",
+			Namespace:  testNamespace,
+			Conditions: []hivev1.SyncCondition{
+				{
+					Type:    hivev1.ApplyFailureSyncCondition,
+					Status:  corev1.ConditionTrue,
+					Message: message,
+				},
+			},
+		},
+	}
+	return ssi
+}
+
+func withFailedInstance(ssi *hivev1.SyncSetInstance, message string) *hivev1.SyncSetInstance {
+	ssi.Status.Conditions = []hivev1.SyncCondition{
+		{
+			Type:    hivev1.TemplateExpansionFailureSyncCondition,
+			Status:  corev1.ConditionTrue,
+			Message: message,
+		},
+	}
+	return ssi
+}
+
+func handlerObject(ssi *hivev1.SyncSetInstance) handler.MapObject {
+	return handler.MapObject{Meta: ssi, Object: ssi}
+}
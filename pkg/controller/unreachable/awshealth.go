@@ -0,0 +1,137 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unreachable
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/awsclient"
+)
+
+type awsClientBuilderType func(secret *corev1.Secret, region string) (awsclient.Client, error)
+
+// checkAWSHealth gets the state of the instances and the health of the load balancers of the cluster with the
+// infra ID. AWS keeps terminated instances visible for about an hour, so all terminated instances are recent.
+func checkAWSHealth(awsClientBuilder awsClientBuilderType, secret *corev1.Secret, region, infraID string) (*hivev1.CloudHealthStatus, error) {
+	awsClient, err := awsClientBuilder(secret, region)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create AWS client")
+	}
+	health := &hivev1.CloudHealthStatus{}
+
+	input := &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{{
+			Name:   aws.String("tag:kubernetes.io/cluster/" + infraID),
+			Values: aws.StringSlice([]string{"owned"}),
+		}},
+	}
+	for {
+		out, err := awsClient.DescribeInstances(input)
+		if err != nil {
+			return health, errors.Wrap(err, "failed to describe instances")
+		}
+		for _, reservation := range out.Reservations {
+			for _, instance := range reservation.Instances {
+				state := awsInstanceState(instance)
+				if isControlPlaneInstance(state.Name, infraID) {
+					health.ControlPlaneInstances = append(health.ControlPlaneInstances, state)
+				}
+				if isAWSStoppedInstanceState(state.State) {
+					health.RecentTerminations = append(health.RecentTerminations, state)
+				}
+			}
+		}
+		if aws.StringValue(out.NextToken) == "" {
+			break
+		}
+		input.NextToken = out.NextToken
+	}
+
+	// The installer creates an internal load balancer for the API and machine config server, and an external one
+	// for the API of clusters that are not private
+	for _, lbName := range []string{infraID + "-int", infraID + "-ext"} {
+		lbs, err := awsClient.DescribeLoadBalancers(&elbv2.DescribeLoadBalancersInput{Names: aws.StringSlice([]string{lbName})})
+		if err != nil {
+			if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == elbv2.ErrCodeLoadBalancerNotFoundException {
+				continue
+			}
+			return health, errors.Wrapf(err, "failed to describe load balancer %s", lbName)
+		}
+		for _, lb := range lbs.LoadBalancers {
+			targetGroups, err := awsClient.DescribeTargetGroups(&elbv2.DescribeTargetGroupsInput{LoadBalancerArn: lb.LoadBalancerArn})
+			if err != nil {
+				return health, errors.Wrapf(err, "failed to describe target groups of load balancer %s", lbName)
+			}
+			for _, tg := range targetGroups.TargetGroups {
+				targetHealth, err := awsClient.DescribeTargetHealth(&elbv2.DescribeTargetHealthInput{TargetGroupArn: tg.TargetGroupArn})
+				if err != nil {
+					return health, errors.Wrapf(err, "failed to describe target health of target group %s", aws.StringValue(tg.TargetGroupName))
+				}
+				lbHealth := hivev1.CloudLoadBalancerHealth{Name: aws.StringValue(tg.TargetGroupName)}
+				for _, target := range targetHealth.TargetHealthDescriptions {
+					if target.TargetHealth != nil && aws.StringValue(target.TargetHealth.State) == elbv2.TargetHealthStateEnumHealthy {
+						lbHealth.HealthyTargets++
+					} else {
+						lbHealth.UnhealthyTargets++
+					}
+				}
+				health.LoadBalancers = append(health.LoadBalancers, lbHealth)
+			}
+		}
+	}
+	return health, nil
+}
+
+func awsInstanceState(instance *ec2.Instance) hivev1.CloudInstanceState {
+	state := hivev1.CloudInstanceState{
+		ID:     aws.StringValue(instance.InstanceId),
+		Reason: aws.StringValue(instance.StateTransitionReason),
+	}
+	for _, tag := range instance.Tags {
+		if aws.StringValue(tag.Key) == "Name" {
+			state.Name = aws.StringValue(tag.Value)
+		}
+	}
+	if state.Name == "" {
+		state.Name = state.ID
+	}
+	if instance.Placement != nil {
+		state.Zone = aws.StringValue(instance.Placement.AvailabilityZone)
+	}
+	if instance.State != nil {
+		state.State = aws.StringValue(instance.State.Name)
+	}
+	if instance.StateReason != nil && aws.StringValue(instance.StateReason.Message) != "" {
+		state.Reason = aws.StringValue(instance.StateReason.Message)
+	}
+	return state
+}
+
+func isAWSStoppedInstanceState(state string) bool {
+	switch state {
+	case ec2.InstanceStateNameShuttingDown, ec2.InstanceStateNameTerminated, ec2.InstanceStateNameStopping, ec2.InstanceStateNameStopped:
+		return true
+	}
+	return false
+}
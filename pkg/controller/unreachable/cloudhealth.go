@@ -0,0 +1,112 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unreachable
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+)
+
+// checkCloudHealth queries the cloud provider of an unreachable cluster for the state of its control plane
+// instances, the health of its load balancers and its recently terminated instances. It returns nil for platforms
+// that are not supported.
+func (r *ReconcileRemoteMachineSet) checkCloudHealth(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) *hivev1.CloudHealthStatus {
+	infraID := cd.Spec.ClusterMetadata.InfraID
+	var health *hivev1.CloudHealthStatus
+	var err error
+	switch {
+	case cd.Spec.Platform.AWS != nil:
+		var secret *corev1.Secret
+		secret, err = r.loadCredentialsSecret(cd.Spec.Platform.AWS.CredentialsSecretRef.Name, cd.Namespace)
+		if err == nil {
+			health, err = checkAWSHealth(r.awsClientBuilder, secret, cd.Spec.Platform.AWS.Region, infraID)
+		}
+	case cd.Spec.Platform.GCP != nil:
+		var secret *corev1.Secret
+		secret, err = r.loadCredentialsSecret(cd.Spec.Platform.GCP.CredentialsSecretRef.Name, cd.Namespace)
+		if err == nil {
+			health, err = checkGCPHealth(r.gcpClientBuilder, secret, cd.Spec.Platform.GCP.Region, infraID)
+		}
+	default:
+		return nil
+	}
+	if health == nil {
+		health = &hivev1.CloudHealthStatus{}
+	}
+	if err != nil {
+		cdLog.WithError(err).Warn("failed to check the health of the cluster in the cloud provider")
+		health.Error = err.Error()
+	}
+	sort.Slice(health.ControlPlaneInstances, func(i, j int) bool {
+		return health.ControlPlaneInstances[i].Name < health.ControlPlaneInstances[j].Name
+	})
+	sort.Slice(health.LoadBalancers, func(i, j int) bool { return health.LoadBalancers[i].Name < health.LoadBalancers[j].Name })
+	sort.Slice(health.RecentTerminations, func(i, j int) bool {
+		return health.RecentTerminations[i].Name < health.RecentTerminations[j].Name
+	})
+	health.Time = metav1.Now()
+	return health
+}
+
+func (r *ReconcileRemoteMachineSet) loadCredentialsSecret(secretName, namespace string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: secretName, Namespace: namespace}, secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// summarizeCloudHealth describes the cloud health of a cluster in a sentence for the unreachable condition message
+func summarizeCloudHealth(health *hivev1.CloudHealthStatus) string {
+	if health.Error != "" {
+		return fmt.Sprintf("unable to check the cluster in the cloud provider: %s", health.Error)
+	}
+	running := 0
+	for _, instance := range health.ControlPlaneInstances {
+		if isRunningInstanceState(instance.State) {
+			running++
+		}
+	}
+	parts := []string{fmt.Sprintf("%d/%d control plane instances running", running, len(health.ControlPlaneInstances))}
+	for _, lb := range health.LoadBalancers {
+		parts = append(parts, fmt.Sprintf("%d/%d %s targets healthy", lb.HealthyTargets, lb.HealthyTargets+lb.UnhealthyTargets, lb.Name))
+	}
+	if len(health.RecentTerminations) > 0 {
+		parts = append(parts, fmt.Sprintf("%d instances recently terminated or stopped", len(health.RecentTerminations)))
+	}
+	return "cloud provider reports " + strings.Join(parts, ", ")
+}
+
+// isRunningInstanceState returns true for the running state of instances of all cloud providers
+func isRunningInstanceState(state string) bool {
+	return strings.EqualFold(state, "running")
+}
+
+// isControlPlaneInstance returns true for instances named like the control plane instances created by the installer
+func isControlPlaneInstance(name, infraID string) bool {
+	return strings.HasPrefix(name, infraID+"-master-")
+}
@@ -0,0 +1,328 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unreachable
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/golang/mock/gomock"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	compute "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/awsclient"
+	mockaws "github.com/openshift/hive/pkg/awsclient/mock"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	"github.com/openshift/hive/pkg/gcpclient"
+	mockgcp "github.com/openshift/hive/pkg/gcpclient/mock"
+)
+
+const testInfraID = "bar-abcde"
+
+func TestCheckAWSHealth(t *testing.T) {
+	tests := []struct {
+		name          string
+		setup         func(*mockaws.MockClientMockRecorder)
+		expectError   bool
+		expectedState *hivev1.CloudHealthStatus
+	}{
+		{
+			name: "instances and load balancers",
+			setup: func(m *mockaws.MockClientMockRecorder) {
+				m.DescribeInstances(gomock.Any()).Return(&ec2.DescribeInstancesOutput{
+					Reservations: []*ec2.Reservation{{
+						Instances: []*ec2.Instance{
+							testAWSInstance("i-1", testInfraID+"-master-0", ec2.InstanceStateNameRunning, ""),
+							testAWSInstance("i-2", testInfraID+"-master-1", ec2.InstanceStateNameStopped, "Client.UserInitiatedShutdown"),
+							testAWSInstance("i-3", testInfraID+"-worker-a-xyz", ec2.InstanceStateNameTerminated, "Server.SpotInstanceTermination"),
+							testAWSInstance("i-4", testInfraID+"-worker-b-xyz", ec2.InstanceStateNameRunning, ""),
+						},
+					}},
+				}, nil)
+				m.DescribeLoadBalancers(&elbv2.DescribeLoadBalancersInput{Names: aws.StringSlice([]string{testInfraID + "-int"})}).Return(&elbv2.DescribeLoadBalancersOutput{
+					LoadBalancers: []*elbv2.LoadBalancer{{LoadBalancerArn: aws.String("int-arn")}},
+				}, nil)
+				m.DescribeLoadBalancers(&elbv2.DescribeLoadBalancersInput{Names: aws.StringSlice([]string{testInfraID + "-ext"})}).Return(nil,
+					awserr.New(elbv2.ErrCodeLoadBalancerNotFoundException, "not found", nil))
+				m.DescribeTargetGroups(gomock.Any()).Return(&elbv2.DescribeTargetGroupsOutput{
+					TargetGroups: []*elbv2.TargetGroup{{TargetGroupArn: aws.String("aint-arn"), TargetGroupName: aws.String(testInfraID + "-aint")}},
+				}, nil)
+				m.DescribeTargetHealth(gomock.Any()).Return(&elbv2.DescribeTargetHealthOutput{
+					TargetHealthDescriptions: []*elbv2.TargetHealthDescription{
+						{TargetHealth: &elbv2.TargetHealth{State: aws.String(elbv2.TargetHealthStateEnumHealthy)}},
+						{TargetHealth: &elbv2.TargetHealth{State: aws.String(elbv2.TargetHealthStateEnumUnhealthy)}},
+					},
+				}, nil)
+			},
+			expectedState: &hivev1.CloudHealthStatus{
+				ControlPlaneInstances: []hivev1.CloudInstanceState{
+					{Name: testInfraID + "-master-0", ID: "i-1", Zone: "us-east-1a", State: "running"},
+					{Name: testInfraID + "-master-1", ID: "i-2", Zone: "us-east-1a", State: "stopped", Reason: "Client.UserInitiatedShutdown"},
+				},
+				LoadBalancers: []hivev1.CloudLoadBalancerHealth{
+					{Name: testInfraID + "-aint", HealthyTargets: 1, UnhealthyTargets: 1},
+				},
+				RecentTerminations: []hivev1.CloudInstanceState{
+					{Name: testInfraID + "-master-1", ID: "i-2", Zone: "us-east-1a", State: "stopped", Reason: "Client.UserInitiatedShutdown"},
+					{Name: testInfraID + "-worker-a-xyz", ID: "i-3", Zone: "us-east-1a", State: "terminated", Reason: "Server.SpotInstanceTermination"},
+				},
+			},
+		},
+		{
+			name: "describe instances fails",
+			setup: func(m *mockaws.MockClientMockRecorder) {
+				m.DescribeInstances(gomock.Any()).Return(nil, awserr.New("UnauthorizedOperation", "not authorized", nil))
+			},
+			expectError:   true,
+			expectedState: &hivev1.CloudHealthStatus{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockAWSClient := mockaws.NewMockClient(mockCtrl)
+			test.setup(mockAWSClient.EXPECT())
+
+			health, err := checkAWSHealth(fakeAWSClientBuilder(mockAWSClient), testSecret("aws-credentials", "aws_access_key_id", "key"), "us-east-1", testInfraID)
+			if test.expectError {
+				assert.Error(t, err, "expected error")
+			} else {
+				assert.NoError(t, err, "unexpected error")
+			}
+			assert.Equal(t, test.expectedState, health, "unexpected cloud health")
+		})
+	}
+}
+
+func TestCheckGCPHealth(t *testing.T) {
+	tests := []struct {
+		name          string
+		setup         func(*mockgcp.MockClientMockRecorder)
+		expectedState *hivev1.CloudHealthStatus
+	}{
+		{
+			name: "instances and target pool",
+			setup: func(m *mockgcp.MockClientMockRecorder) {
+				m.ListComputeInstances(gomock.Any()).Return(&compute.InstanceAggregatedList{
+					Items: map[string]compute.InstancesScopedList{
+						"zones/us-east1-b": {Instances: []*compute.Instance{
+							{Name: testInfraID + "-master-0", Id: 1, Zone: "https://compute/zones/us-east1-b", Status: "RUNNING"},
+							{Name: testInfraID + "-worker-b-xyz", Id: 2, Zone: "https://compute/zones/us-east1-b", Status: "TERMINATED", StatusMessage: "preempted"},
+						}},
+					},
+				}, nil)
+				m.GetTargetPool("us-east1", testInfraID+"-api").Return(&compute.TargetPool{
+					Name:      testInfraID + "-api",
+					Instances: []string{"https://compute/instances/" + testInfraID + "-master-0"},
+				}, nil)
+				m.GetTargetPoolHealth("us-east1", testInfraID+"-api", gomock.Any()).Return(&compute.TargetPoolInstanceHealth{
+					HealthStatus: []*compute.HealthStatus{{HealthState: "UNHEALTHY"}},
+				}, nil)
+			},
+			expectedState: &hivev1.CloudHealthStatus{
+				ControlPlaneInstances: []hivev1.CloudInstanceState{
+					{Name: testInfraID + "-master-0", ID: "1", Zone: "us-east1-b", State: "RUNNING"},
+				},
+				LoadBalancers: []hivev1.CloudLoadBalancerHealth{
+					{Name: testInfraID + "-api", UnhealthyTargets: 1},
+				},
+				RecentTerminations: []hivev1.CloudInstanceState{
+					{Name: testInfraID + "-worker-b-xyz", ID: "2", Zone: "us-east1-b", State: "TERMINATED", Reason: "preempted"},
+				},
+			},
+		},
+		{
+			name: "private cluster without target pool",
+			setup: func(m *mockgcp.MockClientMockRecorder) {
+				m.ListComputeInstances(gomock.Any()).Return(&compute.InstanceAggregatedList{}, nil)
+				m.GetTargetPool(gomock.Any(), gomock.Any()).Return(nil, &googleapi.Error{Code: gcpclient.ErrCodeNotFound})
+			},
+			expectedState: &hivev1.CloudHealthStatus{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockGCPClient := mockgcp.NewMockClient(mockCtrl)
+			test.setup(mockGCPClient.EXPECT())
+
+			health, err := checkGCPHealth(fakeGCPClientBuilder(mockGCPClient), testSecret("gcp-credentials", "osServiceAccount.json", "{}"), "us-east1", testInfraID)
+			assert.NoError(t, err, "unexpected error")
+			assert.Equal(t, test.expectedState, health, "unexpected cloud health")
+		})
+	}
+}
+
+func TestUnreachableClusterCloudHealth(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+	configv1.Install(scheme.Scheme)
+	tests := []struct {
+		name                    string
+		cd                      *hivev1.ClusterDeployment
+		reachable               bool
+		expectCloudHealth       bool
+		expectedMessageContains string
+	}{
+		{
+			name:                    "unreachable AWS cluster",
+			cd:                      testCloudHealthClusterDeployment(),
+			expectCloudHealth:       true,
+			expectedMessageContains: "cloud provider reports 0/1 control plane instances running",
+		},
+		{
+			name: "unreachable cluster on unsupported platform",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := testCloudHealthClusterDeployment()
+				cd.Spec.Platform.AWS = nil
+				return cd
+			}(),
+		},
+		{
+			name: "reachable cluster clears cloud health",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := testCloudHealthClusterDeployment()
+				cd.Status.CloudHealth = &hivev1.CloudHealthStatus{}
+				return cd
+			}(),
+			reachable: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockAWSClient := mockaws.NewMockClient(mockCtrl)
+			if test.expectCloudHealth {
+				mockAWSClient.EXPECT().DescribeInstances(gomock.Any()).Return(&ec2.DescribeInstancesOutput{
+					Reservations: []*ec2.Reservation{{
+						Instances: []*ec2.Instance{testAWSInstance("i-1", testInfraID+"-master-0", ec2.InstanceStateNameStopped, "")},
+					}},
+				}, nil)
+				mockAWSClient.EXPECT().DescribeLoadBalancers(gomock.Any()).Return(nil,
+					awserr.New(elbv2.ErrCodeLoadBalancerNotFoundException, "not found", nil)).Times(2)
+			}
+			existing := []runtime.Object{
+				test.cd,
+				getKubeconfigSecret(),
+				testSecret("aws-credentials", "aws_access_key_id", "key"),
+			}
+			fakeClient := fake.NewFakeClient(existing...)
+			rcd := &ReconcileRemoteMachineSet{
+				Client:                             fakeClient,
+				scheme:                             scheme.Scheme,
+				logger:                             log.WithField("controller", "unreachable"),
+				remoteClusterAPIClientBuilder:      mockUnreachableClusterAPIClientBuilder,
+				invalidateRemoteClusterConnections: func(string) {},
+				schedule:                           defaultProbeSchedule(),
+				awsClientBuilder:                   fakeAWSClientBuilder(mockAWSClient),
+			}
+			if test.reachable {
+				rcd.remoteClusterAPIClientBuilder = mockReachableClusterAPIClientBuilder
+			}
+
+			namespacedName := types.NamespacedName{Name: testName, Namespace: testNamespace}
+			_, err := rcd.Reconcile(reconcile.Request{NamespacedName: namespacedName})
+			require.NoError(t, err, "unexpected error from reconcile")
+
+			cd := &hivev1.ClusterDeployment{}
+			require.NoError(t, fakeClient.Get(context.TODO(), namespacedName, cd), "unexpected error getting cluster deployment")
+			if test.expectCloudHealth {
+				if assert.NotNil(t, cd.Status.CloudHealth, "expected cloud health") {
+					assert.Len(t, cd.Status.CloudHealth.ControlPlaneInstances, 1, "unexpected control plane instances")
+				}
+			} else {
+				assert.Nil(t, cd.Status.CloudHealth, "expected no cloud health")
+			}
+			if !test.reachable {
+				cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.UnreachableCondition)
+				require.NotNil(t, cond, "expected unreachable condition")
+				assert.Contains(t, cond.Message, test.expectedMessageContains, "unexpected unreachable condition message")
+			}
+		})
+	}
+}
+
+func TestSummarizeCloudHealth(t *testing.T) {
+	health := &hivev1.CloudHealthStatus{
+		ControlPlaneInstances: []hivev1.CloudInstanceState{
+			{Name: "master-0", State: "running"},
+			{Name: "master-1", State: "RUNNING"},
+			{Name: "master-2", State: "stopped"},
+		},
+		LoadBalancers: []hivev1.CloudLoadBalancerHealth{
+			{Name: "aint", HealthyTargets: 2, UnhealthyTargets: 1},
+		},
+		RecentTerminations: []hivev1.CloudInstanceState{{Name: "master-2", State: "stopped"}},
+	}
+	assert.Equal(t,
+		"cloud provider reports 2/3 control plane instances running, 2/3 aint targets healthy, 1 instances recently terminated or stopped",
+		summarizeCloudHealth(health))
+
+	health = &hivev1.CloudHealthStatus{Error: "access denied"}
+	assert.Equal(t, "unable to check the cluster in the cloud provider: access denied", summarizeCloudHealth(health))
+}
+
+func testCloudHealthClusterDeployment() *hivev1.ClusterDeployment {
+	cd := getClusterDeployment()
+	cd.Spec.ClusterMetadata.InfraID = testInfraID
+	return cd
+}
+
+func testAWSInstance(id, name, state, reason string) *ec2.Instance {
+	instance := &ec2.Instance{
+		InstanceId: aws.String(id),
+		Tags:       []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String(name)}},
+		Placement:  &ec2.Placement{AvailabilityZone: aws.String("us-east-1a")},
+		State:      &ec2.InstanceState{Name: aws.String(state)},
+	}
+	if reason != "" {
+		instance.StateReason = &ec2.StateReason{Message: aws.String(reason)}
+	}
+	return instance
+}
+
+func fakeAWSClientBuilder(mockAWSClient *mockaws.MockClient) awsClientBuilderType {
+	return func(secret *corev1.Secret, region string) (awsclient.Client, error) {
+		return mockAWSClient, nil
+	}
+}
+
+func fakeGCPClientBuilder(mockGCPClient *mockgcp.MockClient) gcpClientBuilderType {
+	return func(secret *corev1.Secret) (gcpclient.Client, error) {
+		return mockGCPClient, nil
+	}
+}
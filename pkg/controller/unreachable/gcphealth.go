@@ -0,0 +1,116 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unreachable
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/pkg/errors"
+	compute "google.golang.org/api/compute/v1"
+	googleapi "google.golang.org/api/googleapi"
+
+	corev1 "k8s.io/api/core/v1"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/gcpclient"
+)
+
+const gcpHealthyState = "HEALTHY"
+
+type gcpClientBuilderType func(secret *corev1.Secret) (gcpclient.Client, error)
+
+// checkGCPHealth gets the state of the instances and the health of the API target pool of the cluster with the
+// infra ID. GCP keeps stopped instances, so stopped instances are reported whenever they were stopped.
+func checkGCPHealth(gcpClientBuilder gcpClientBuilderType, secret *corev1.Secret, region, infraID string) (*hivev1.CloudHealthStatus, error) {
+	gcpClient, err := gcpClientBuilder(secret)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create GCP client")
+	}
+	health := &hivev1.CloudHealthStatus{}
+
+	opts := gcpclient.ListComputeInstancesOptions{Filter: fmt.Sprintf("name eq \"%s-.*\"", infraID)}
+	for {
+		list, err := gcpClient.ListComputeInstances(opts)
+		if err != nil {
+			return health, errors.Wrap(err, "failed to list instances")
+		}
+		for _, scopedList := range list.Items {
+			for _, instance := range scopedList.Instances {
+				state := gcpInstanceState(instance)
+				if isControlPlaneInstance(state.Name, infraID) {
+					health.ControlPlaneInstances = append(health.ControlPlaneInstances, state)
+				}
+				if isGCPStoppedInstanceState(state.State) {
+					health.RecentTerminations = append(health.RecentTerminations, state)
+				}
+			}
+		}
+		if list.NextPageToken == "" {
+			break
+		}
+		opts.PageToken = list.NextPageToken
+	}
+
+	// The installer creates a target pool for the external load balancer of the API of clusters that are not private
+	poolName := infraID + "-api"
+	pool, err := gcpClient.GetTargetPool(region, poolName)
+	if err != nil {
+		if gerr, ok := err.(*googleapi.Error); ok && gerr.Code == gcpclient.ErrCodeNotFound {
+			return health, nil
+		}
+		return health, errors.Wrapf(err, "failed to get target pool %s", poolName)
+	}
+	lbHealth := hivev1.CloudLoadBalancerHealth{Name: poolName}
+	for _, instance := range pool.Instances {
+		instanceHealth, err := gcpClient.GetTargetPoolHealth(region, poolName, instance)
+		if err != nil {
+			return health, errors.Wrapf(err, "failed to get health of %s in target pool %s", path.Base(instance), poolName)
+		}
+		healthy := false
+		for _, status := range instanceHealth.HealthStatus {
+			if status.HealthState == gcpHealthyState {
+				healthy = true
+			}
+		}
+		if healthy {
+			lbHealth.HealthyTargets++
+		} else {
+			lbHealth.UnhealthyTargets++
+		}
+	}
+	health.LoadBalancers = append(health.LoadBalancers, lbHealth)
+	return health, nil
+}
+
+func gcpInstanceState(instance *compute.Instance) hivev1.CloudInstanceState {
+	return hivev1.CloudInstanceState{
+		Name:   instance.Name,
+		ID:     fmt.Sprint(instance.Id),
+		Zone:   path.Base(instance.Zone),
+		State:  instance.Status,
+		Reason: instance.StatusMessage,
+	}
+}
+
+func isGCPStoppedInstanceState(state string) bool {
+	switch state {
+	case "STOPPING", "STOPPED", "SUSPENDING", "SUSPENDED", "TERMINATED":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,268 @@
+/*
+Copyright (C) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package unreachable
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+const (
+	defaultReachableProbeInterval        = 10 * time.Minute
+	defaultUnreachableProbeBackoffFactor = 4
+	defaultMaxUnreachableProbeInterval   = 2 * time.Hour
+	defaultProbeHistoryLimit             = 5
+
+	// minUnreachableProbeInterval is the shortest time between probes of an unreachable cluster
+	minUnreachableProbeInterval = time.Minute
+
+	probeTimeout = 10 * time.Second
+)
+
+var (
+	metricClusterReachable = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "hive_cluster_deployment_reachable",
+		Help: "Whether the last reachability probe of a cluster succeeded (1) or failed (0), by probe.",
+	},
+		[]string{"cluster_deployment", "namespace", "probe"},
+	)
+	metricProbeSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "hive_cluster_reachability_probe_seconds",
+		Help:    "Distribution of the length of time reachability probes of clusters take.",
+		Buckets: []float64{0.1, 0.5, 1, 2, 5, 10, 30},
+	},
+		[]string{"probe", "reachable"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(metricClusterReachable)
+	metrics.Registry.MustRegister(metricProbeSeconds)
+}
+
+// probe checks whether a part of the cluster of a ClusterDeployment can be reached with its admin kubeconfig. It
+// returns errNoProbeTarget if the part to check is not known yet, in which case the probe is not recorded.
+type probe func(cd *hivev1.ClusterDeployment, kubeconfig string) error
+
+var errNoProbeTarget = fmt.Errorf("nothing to probe")
+
+type namedProbe struct {
+	name hivev1.ClusterReachabilityProbe
+	run  probe
+}
+
+// probeSchedule controls how often clusters are probed and how many probe results are kept
+type probeSchedule struct {
+	reachableInterval      time.Duration
+	backoffFactor          int
+	maxUnreachableInterval time.Duration
+	historyLimit           int
+}
+
+func defaultProbeSchedule() probeSchedule {
+	return probeSchedule{
+		reachableInterval:      defaultReachableProbeInterval,
+		backoffFactor:          defaultUnreachableProbeBackoffFactor,
+		maxUnreachableInterval: defaultMaxUnreachableProbeInterval,
+		historyLimit:           defaultProbeHistoryLimit,
+	}
+}
+
+// loadReachabilityConfig reads the cluster reachability config passed by the operator from the HiveConfig, and
+// returns the probe schedule with defaults for unset fields along with the additional probes to run.
+func loadReachabilityConfig(logger log.FieldLogger) (probeSchedule, []namedProbe) {
+	schedule := defaultProbeSchedule()
+	config := hivev1.ClusterReachabilityConfig{}
+	if configJSON := os.Getenv(constants.ClusterReachabilityEnvVar); configJSON != "" {
+		if err := json.Unmarshal([]byte(configJSON), &config); err != nil {
+			logger.WithError(err).Errorf("Couldn't parse environment variable %v, using defaults", constants.ClusterReachabilityEnvVar)
+			return schedule, nil
+		}
+	}
+	if config.ReachableProbeInterval != nil && config.ReachableProbeInterval.Duration > 0 {
+		schedule.reachableInterval = config.ReachableProbeInterval.Duration
+	}
+	if config.UnreachableProbeBackoffFactor != nil && *config.UnreachableProbeBackoffFactor > 0 {
+		schedule.backoffFactor = *config.UnreachableProbeBackoffFactor
+	}
+	if config.MaxUnreachableProbeInterval != nil && config.MaxUnreachableProbeInterval.Duration > 0 {
+		schedule.maxUnreachableInterval = config.MaxUnreachableProbeInterval.Duration
+	}
+	if config.ProbeHistoryLimit != nil && *config.ProbeHistoryLimit >= 0 {
+		schedule.historyLimit = *config.ProbeHistoryLimit
+	}
+
+	var probes []namedProbe
+	for _, name := range config.AdditionalProbes {
+		var run probe
+		switch name {
+		case hivev1.APIReadyzReachabilityProbe:
+			run = apiReadyzProbe
+		case hivev1.ConsoleReachabilityProbe:
+			run = consoleProbe
+		case hivev1.IngressCanaryReachabilityProbe:
+			run = ingressCanaryProbe
+		default:
+			logger.WithField("probe", name).Warn("ignoring unknown cluster reachability probe")
+			continue
+		}
+		probes = append(probes, namedProbe{name: name, run: run})
+	}
+	return schedule, probes
+}
+
+// isAPIProbe returns true for probes of the API server of a cluster, whose failure marks the cluster unreachable
+func isAPIProbe(name hivev1.ClusterReachabilityProbe) bool {
+	return name == hivev1.APIClientReachabilityProbe || name == hivev1.APIReadyzReachabilityProbe
+}
+
+// apiReadyzProbe checks that the API server of the cluster reports itself ready
+func apiReadyzProbe(cd *hivev1.ClusterDeployment, kubeconfig string) error {
+	kubeClient, err := controllerutils.BuildKubeClientFromKubeconfig(kubeconfig, controllerName)
+	if err != nil {
+		return err
+	}
+	_, err = kubeClient.Discovery().RESTClient().Get().AbsPath("/readyz").Timeout(probeTimeout).DoRaw()
+	return err
+}
+
+// consoleProbe checks that the web console of the cluster responds
+func consoleProbe(cd *hivev1.ClusterDeployment, kubeconfig string) error {
+	if cd.Status.WebConsoleURL == "" {
+		return errNoProbeTarget
+	}
+	return httpProbe(cd.Status.WebConsoleURL)
+}
+
+// ingressCanaryProbe checks that the ingress canary route of the cluster responds
+func ingressCanaryProbe(cd *hivev1.ClusterDeployment, kubeconfig string) error {
+	if cd.Spec.ClusterName == "" || cd.Spec.BaseDomain == "" {
+		return errNoProbeTarget
+	}
+	return httpProbe(fmt.Sprintf("https://canary-openshift-ingress-canary.apps.%s.%s", cd.Spec.ClusterName, cd.Spec.BaseDomain))
+}
+
+// httpProbe checks that a GET of the URL gets a response without a server error. The certificate of the ingress of
+// a cluster is usually signed by a CA of the cluster that is not in the kubeconfig, and trusting it is irrelevant to
+// whether the ingress can be reached, so it is not verified.
+func httpProbe(url string) error {
+	httpClient := &http.Client{
+		Timeout: probeTimeout,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("%s responded with %s", url, resp.Status)
+	}
+	return nil
+}
+
+// runProbes runs the probes against the cluster and records their results in the metrics. It returns whether any
+// probe that is not an API probe succeeded, and the error of the first API probe that failed.
+func runProbes(cd *hivev1.ClusterDeployment, kubeconfig string, probes []namedProbe) (hivev1.ReachabilityProbeRun, bool, error) {
+	run := hivev1.ReachabilityProbeRun{}
+	var apiErr error
+	otherReachable := false
+	for _, p := range probes {
+		start := time.Now()
+		err := p.run(cd, kubeconfig)
+		if err == errNoProbeTarget {
+			continue
+		}
+		latency := time.Since(start)
+		result := hivev1.ReachabilityProbeResult{
+			Probe:     p.name,
+			Reachable: err == nil,
+			Latency:   metav1.Duration{Duration: latency.Round(time.Millisecond)},
+		}
+		reachable := 0.0
+		if err == nil {
+			reachable = 1
+			if !isAPIProbe(p.name) {
+				otherReachable = true
+			}
+		} else {
+			result.Error = err.Error()
+			if apiErr == nil && isAPIProbe(p.name) {
+				apiErr = err
+			}
+		}
+		metricClusterReachable.WithLabelValues(cd.Name, cd.Namespace, string(p.name)).Set(reachable)
+		metricProbeSeconds.WithLabelValues(string(p.name), fmt.Sprint(err == nil)).Observe(latency.Seconds())
+		run.Results = append(run.Results, result)
+	}
+	run.Time = metav1.Now()
+	return run, otherReachable, apiErr
+}
+
+// probeOutcomeChanged returns whether the probes of a run had another outcome than those of the previous run,
+// regardless of when the runs happened and how long the probes took.
+func probeOutcomeChanged(previous []hivev1.ReachabilityProbeRun, run hivev1.ReachabilityProbeRun) bool {
+	if len(previous) == 0 || len(previous[0].Results) != len(run.Results) {
+		return true
+	}
+	for i, result := range run.Results {
+		last := previous[0].Results[i]
+		if last.Probe != result.Probe || last.Reachable != result.Reachable || last.Error != result.Error {
+			return true
+		}
+	}
+	return false
+}
+
+// recordProbeRun adds a run to the front of the probe history, which is kept to the limit of the schedule
+func recordProbeRun(history []hivev1.ReachabilityProbeRun, run hivev1.ReachabilityProbeRun, schedule probeSchedule) []hivev1.ReachabilityProbeRun {
+	history = append([]hivev1.ReachabilityProbeRun{run}, history...)
+	if len(history) > schedule.historyLimit {
+		history = history[:schedule.historyLimit]
+	}
+	return history
+}
+
+// clearReachableMetrics stops reporting the reachability of a cluster that is gone
+func clearReachableMetrics(cd, namespace string) {
+	for _, name := range []hivev1.ClusterReachabilityProbe{
+		hivev1.APIClientReachabilityProbe,
+		hivev1.APIReadyzReachabilityProbe,
+		hivev1.ConsoleReachabilityProbe,
+		hivev1.IngressCanaryReachabilityProbe,
+	} {
+		metricClusterReachable.DeleteLabelValues(cd, namespace, string(name))
+	}
+}
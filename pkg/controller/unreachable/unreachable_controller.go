@@ -16,12 +16,15 @@ limitations under the License.
 
 // Package unreachable provides a controller which periodically checks if a remote cluster is reachable
 // and maintains a condition on the cluster as a result. If the unreachable condition is true, other controllers
-// can skip attempts to reach the cluster which require a 30 second timeout.
+// can skip attempts to reach the cluster which require a 30 second timeout. The results of the probes of a cluster
+// are kept in its status and exposed as metrics, to tell an unreachable API server from an unreachable ingress.
 package unreachable
 
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"sync"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -29,6 +32,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	kapi "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 
@@ -40,16 +44,16 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/awsclient"
 	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
 	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	"github.com/openshift/hive/pkg/gcpclient"
 )
 
 const (
 	controllerName = "unreachable"
 
-	adminKubeConfigKey          = "kubeconfig"
-	maxUnreachableDuration      = 2 * time.Hour
-	noOfAttemptsWhenUnreachable = 4
+	adminKubeConfigKey = "kubeconfig"
 )
 
 // Add creates a new Unreachable Controller and adds it to the Manager with default RBAC. The Manager will set fields on the
@@ -60,11 +64,18 @@ func Add(mgr manager.Manager) error {
 
 // NewReconciler returns a new reconcile.Reconciler
 func NewReconciler(mgr manager.Manager) reconcile.Reconciler {
+	logger := log.WithField("controller", controllerName)
+	schedule, additionalProbes := loadReachabilityConfig(logger)
 	return &ReconcileRemoteMachineSet{
-		Client:                        controllerutils.NewClientWithMetricsOrDie(mgr, controllerName),
-		scheme:                        mgr.GetScheme(),
-		logger:                        log.WithField("controller", controllerName),
-		remoteClusterAPIClientBuilder: controllerutils.BuildClusterAPIClientFromKubeconfig,
+		Client:                             controllerutils.NewClientWithMetricsOrDie(mgr, controllerName),
+		scheme:                             mgr.GetScheme(),
+		logger:                             logger,
+		remoteClusterAPIClientBuilder:      controllerutils.BuildClusterAPIClientFromKubeconfig,
+		invalidateRemoteClusterConnections: controllerutils.RemoteClusters.Invalidate,
+		schedule:                           schedule,
+		additionalProbes:                   additionalProbes,
+		awsClientBuilder:                   awsclient.NewClientFromSecret,
+		gcpClientBuilder:                   gcpclient.NewClientFromSecret,
 	}
 }
 
@@ -95,8 +106,56 @@ type ReconcileRemoteMachineSet struct {
 	logger log.FieldLogger
 
 	// remoteClusterAPIClientBuilder is a function pointer to the function that builds a client for the
-	// remote cluster's cluster-api
+	// remote cluster's cluster-api. It must not return cached clients, as it is used to check connectivity.
 	remoteClusterAPIClientBuilder func(string, string) (client.Client, error)
+
+	// invalidateRemoteClusterConnections drops the cached connections of other controllers to the remote cluster of
+	// a kubeconfig
+	invalidateRemoteClusterConnections func(string)
+
+	// schedule controls how often clusters are probed
+	schedule probeSchedule
+
+	// additionalProbes are run along with building an API client for the remote cluster
+	additionalProbes []namedProbe
+
+	// awsClientBuilder builds the AWS client used to check the health of unreachable AWS clusters
+	awsClientBuilder awsClientBuilderType
+
+	// gcpClientBuilder builds the GCP client used to check the health of unreachable GCP clusters
+	gcpClientBuilder gcpClientBuilderType
+
+	// lastProbes records when clusters were last probed
+	lastProbes probeTimes
+}
+
+// probeTimes records when clusters were last probed. The times are kept in memory rather than in the status of the
+// ClusterDeployments, so that probes which do not change the reachability of a cluster do not update it.
+type probeTimes struct {
+	mutex sync.Mutex
+	times map[types.NamespacedName]time.Time
+}
+
+func (p *probeTimes) get(name types.NamespacedName) (time.Time, bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	t, ok := p.times[name]
+	return t, ok
+}
+
+func (p *probeTimes) set(name types.NamespacedName, t time.Time) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.times == nil {
+		p.times = map[types.NamespacedName]time.Time{}
+	}
+	p.times[name] = t
+}
+
+func (p *probeTimes) delete(name types.NamespacedName) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	delete(p.times, name)
 }
 
 // Reconcile checks if we can establish an API client connection to the remote cluster and maintains the unreachable condition as a result.
@@ -120,6 +179,8 @@ func (r *ReconcileRemoteMachineSet) Reconcile(request reconcile.Request) (reconc
 	err := r.Get(context.TODO(), request.NamespacedName, cd)
 	if err != nil {
 		if errors.IsNotFound(err) {
+			clearReachableMetrics(request.Name, request.Namespace)
+			r.lastProbes.delete(request.NamespacedName)
 			return reconcile.Result{}, nil
 		}
 
@@ -131,6 +192,8 @@ func (r *ReconcileRemoteMachineSet) Reconcile(request reconcile.Request) (reconc
 	// If the clusterdeployment is deleted, do not reconcile.
 	if cd.DeletionTimestamp != nil {
 		cdLog.Debug("cluster has deletion timestamp")
+		clearReachableMetrics(cd.Name, cd.Namespace)
+		r.lastProbes.delete(request.NamespacedName)
 		return reconcile.Result{}, nil
 	}
 
@@ -145,15 +208,9 @@ func (r *ReconcileRemoteMachineSet) Reconcile(request reconcile.Request) (reconc
 	}
 
 	// Check if we're due for rechecking cluster's connectivity
-	cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.UnreachableCondition)
-	if cond != nil {
-		if !isTimeForConnectivityCheck(cond) {
-			cdLog.WithFields(log.Fields{
-				"sinceLastTransition": time.Since(cond.LastTransitionTime.Time),
-				"sinceLastProbe":      time.Since(cond.LastProbeTime.Time),
-			}).Debug("skipping unreachable check")
-			return reconcile.Result{}, nil
-		}
+	if wait := r.nextProbeWait(cd); wait > 0 {
+		cdLog.WithField("nextProbe", wait).Debug("skipping unreachable check")
+		return reconcile.Result{RequeueAfter: wait}, nil
 	}
 
 	secretName := cd.Spec.ClusterMetadata.AdminKubeconfigSecretRef.Name
@@ -164,33 +221,103 @@ func (r *ReconcileRemoteMachineSet) Reconcile(request reconcile.Request) (reconc
 	}
 
 	cdLog.Info("checking if cluster is reachable")
+	probes := append([]namedProbe{{name: hivev1.APIClientReachabilityProbe, run: r.apiClientProbe}}, r.additionalProbes...)
+	run, otherReachable, apiErr := runProbes(cd, secretData, probes)
+	r.lastProbes.set(request.NamespacedName, time.Now())
+	for _, result := range run.Results {
+		if !result.Reachable {
+			cdLog.WithField("probe", result.Probe).WithField("error", result.Error).Warn("cluster reachability probe failed")
+		}
+	}
+
 	status := corev1.ConditionFalse
 	reason := "ClusterReachable"
 	message := "cluster is reachable"
 	updateCheck := controllerutils.UpdateConditionNever
-	if _, err := r.remoteClusterAPIClientBuilder(secretData, controllerName); err != nil {
-		cdLog.Warn("unable to create remote API client, marking cluster unreachable")
+	cloudHealth := cd.Status.CloudHealth
+	if apiErr != nil {
+		cdLog.Warn("unable to reach remote API server, marking cluster unreachable")
+		// Drop the cached connections to the cluster so that other controllers reconnect once it is reachable again
+		r.invalidateRemoteClusterConnections(secretData)
 		status = corev1.ConditionTrue
 		reason = "ErrorConnectingToCluster"
-		message = err.Error()
+		if otherReachable {
+			// The ingress of the cluster can be reached, so the cluster is not cut off from the hub
+			reason = "ErrorConnectingToAPI"
+		}
+		message = apiErr.Error()
+		// Query the cloud provider to tell whether the instances of the cluster are even running
+		cloudHealth = r.checkCloudHealth(cd, cdLog)
+		if cloudHealth != nil {
+			message = fmt.Sprintf("%s; %s", message, summarizeCloudHealth(cloudHealth))
+		}
+		// Update the condition when the error changes, to record the time of the probe that found it
 		updateCheck = controllerutils.UpdateConditionIfReasonOrMessageChange
+	} else {
+		cloudHealth = nil
 	}
 	conds, changed := controllerutils.SetClusterDeploymentConditionWithChangeCheck(cd.Status.Conditions, hivev1.UnreachableCondition, status, reason, message, updateCheck)
-
-	if !changed {
-		return reconcile.Result{}, nil
+	// Only record the probe run when its outcome changed, so that probes finding the same do not update the status
+	outcomeChanged := r.schedule.historyLimit > 0 && probeOutcomeChanged(cd.Status.ReachabilityProbes, run)
+	if !changed && !outcomeChanged && !cloudHealthChanged(cd.Status.CloudHealth, cloudHealth) {
+		cdLog.Debug("cluster reachability has not changed")
+		return reconcile.Result{RequeueAfter: r.nextProbeWait(cd)}, nil
 	}
-
-	if status == corev1.ConditionFalse {
+	if changed && status == corev1.ConditionFalse {
 		cdLog.Info("cluster is reachable now")
 	}
 
 	cd.Status.Conditions = conds
+	cd.Status.CloudHealth = cloudHealth
+	if outcomeChanged {
+		cd.Status.ReachabilityProbes = recordProbeRun(cd.Status.ReachabilityProbes, run, r.schedule)
+	}
 	err = r.Status().Update(context.TODO(), cd)
 	if err != nil {
 		cdLog.WithError(err).Logf(controllerutils.LogLevel(err), "error updating cluster deployment with unreachable condition (= %v)", status)
+		return reconcile.Result{}, err
 	}
-	return reconcile.Result{}, err
+	return reconcile.Result{RequeueAfter: r.nextProbeWait(cd)}, nil
+}
+
+// apiClientProbe checks that an API client can be built for the cluster, which requires discovering its API
+// resources. The client is not cached, so that the API server is actually reached.
+func (r *ReconcileRemoteMachineSet) apiClientProbe(cd *hivev1.ClusterDeployment, kubeconfig string) error {
+	_, err := r.remoteClusterAPIClientBuilder(kubeconfig, controllerName)
+	return err
+}
+
+// nextProbeWait returns how long until the cluster is due to be probed again, or zero if it is due now. Reachable
+// clusters are probed at the reachable probe interval, and unreachable clusters less often the longer they have
+// been unreachable. Clusters that have not been probed since the controller started are due now, unless they are
+// unreachable, in which case the last probe that changed their unreachable condition is taken into account.
+func (r *ReconcileRemoteMachineSet) nextProbeWait(cd *hivev1.ClusterDeployment) time.Duration {
+	lastProbe, probed := r.lastProbes.get(types.NamespacedName{Namespace: cd.Namespace, Name: cd.Name})
+	cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.UnreachableCondition)
+	if cond != nil && cond.Status == corev1.ConditionTrue {
+		if !probed {
+			lastProbe = cond.LastProbeTime.Time
+		}
+		return unreachableProbeWait(cond.LastTransitionTime.Time, lastProbe, r.schedule)
+	}
+	if !probed {
+		return 0
+	}
+	if wait := r.schedule.reachableInterval - time.Since(lastProbe); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// cloudHealthChanged returns whether the cloud provider reports something else about the instances and load
+// balancers of a cluster than it did before, regardless of when it was queried.
+func cloudHealthChanged(previous, current *hivev1.CloudHealthStatus) bool {
+	if previous == nil || current == nil {
+		return previous != current
+	}
+	p, c := *previous, *current
+	p.Time, c.Time = metav1.Time{}, metav1.Time{}
+	return !reflect.DeepEqual(p, c)
 }
 
 func (r *ReconcileRemoteMachineSet) loadSecretData(secretName, namespace, dataKey string) (string, error) {
@@ -206,13 +333,19 @@ func (r *ReconcileRemoteMachineSet) loadSecretData(secretName, namespace, dataKe
 	return string(retStr), nil
 }
 
-// isTimeForisTimeForConnectivityCheck returns true as per the condition to check if cluster is reachable/unreachable
-// else it returns false
-func isTimeForConnectivityCheck(condition *hivev1.ClusterDeploymentCondition) bool {
-	sinceLastTransition := time.Since(condition.LastTransitionTime.Time)
-	sinceLastProbe := time.Since(condition.LastProbeTime.Time)
-	if sinceLastProbe < (sinceLastTransition/noOfAttemptsWhenUnreachable) && sinceLastProbe < maxUnreachableDuration {
-		return false
+// unreachableProbeWait returns how long until an unreachable cluster is due to be probed again, or zero if it is
+// due now. The time between probes is the time since the cluster became unreachable divided by the backoff factor,
+// between the min and max unreachable probe intervals.
+func unreachableProbeWait(unreachableSince, lastProbe time.Time, schedule probeSchedule) time.Duration {
+	interval := time.Since(unreachableSince) / time.Duration(schedule.backoffFactor)
+	if interval < minUnreachableProbeInterval {
+		interval = minUnreachableProbeInterval
+	}
+	if interval > schedule.maxUnreachableInterval {
+		interval = schedule.maxUnreachableInterval
+	}
+	if wait := interval - time.Since(lastProbe); wait > 0 {
+		return wait
 	}
-	return true
+	return 0
 }
@@ -5,7 +5,7 @@ Licensed under the Apache License, Version 2.0 (the "License");
 you may not use this file except in compliance with the License.
 You may obtain a copy of the License at
 
-    http://www.apache.org/licenses/LICENSE-2.0
+	http://www.apache.org/licenses/LICENSE-2.0
 
 Unless required by applicable law or agreed to in writing, software
 distributed under the License is distributed on an "AS IS" BASIS,
@@ -18,12 +18,16 @@ package unreachable
 import (
 	"context"
 	"errors"
+	"os"
 	"testing"
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/openshift/hive/pkg/apis"
+	"github.com/openshift/hive/pkg/constants"
 	controllerutils "github.com/openshift/hive/pkg/controller/utils"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -112,11 +116,16 @@ func TestUnreachablClusterStatusCondition(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			fakeClient := fake.NewFakeClient(test.existing...)
+			invalidated := false
 			rcd := &ReconcileRemoteMachineSet{
 				Client:                        fakeClient,
 				scheme:                        scheme.Scheme,
 				logger:                        log.WithField("controller", "unreachable"),
 				remoteClusterAPIClientBuilder: mockUnreachableClusterAPIClientBuilder,
+				invalidateRemoteClusterConnections: func(string) {
+					invalidated = true
+				},
+				schedule: defaultProbeSchedule(),
 			}
 
 			namespacedName := types.NamespacedName{
@@ -136,6 +145,10 @@ func TestUnreachablClusterStatusCondition(t *testing.T) {
 				test.validate(t, cd)
 			}
 
+			if !invalidated {
+				t.Errorf("Expected cached connections to the unreachable cluster to be invalidated")
+			}
+
 			if err != nil && !test.expectError {
 				t.Errorf("Unexpected error: %v", err)
 			}
@@ -204,11 +217,16 @@ func TestReachableClusterStatusCondition(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			fakeClient := fake.NewFakeClient(test.existing...)
+			invalidated := false
 			rcd := &ReconcileRemoteMachineSet{
 				Client:                        fakeClient,
 				scheme:                        scheme.Scheme,
 				logger:                        log.WithField("controller", "unreachable"),
 				remoteClusterAPIClientBuilder: mockReachableClusterAPIClientBuilder,
+				invalidateRemoteClusterConnections: func(string) {
+					invalidated = true
+				},
+				schedule: defaultProbeSchedule(),
 			}
 
 			namespacedName := types.NamespacedName{
@@ -228,6 +246,10 @@ func TestReachableClusterStatusCondition(t *testing.T) {
 				test.validate(t, cd)
 			}
 
+			if invalidated {
+				t.Errorf("Unexpected invalidation of cached connections to the reachable cluster")
+			}
+
 			if err != nil && !test.expectError {
 				t.Errorf("Unexpected error: %v", err)
 			}
@@ -238,70 +260,269 @@ func TestReachableClusterStatusCondition(t *testing.T) {
 	}
 }
 
-func TestIsTimeForConnectivityCheck(t *testing.T) {
+func TestReachabilityProbes(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+	configv1.Install(scheme.Scheme)
+
+	succeeding := func(*hivev1.ClusterDeployment, string) error { return nil }
+	failing := func(*hivev1.ClusterDeployment, string) error { return errors.New("probe failed") }
+	noTarget := func(*hivev1.ClusterDeployment, string) error { return errNoProbeTarget }
+	withProbeHistory := func(cd *hivev1.ClusterDeployment, runs int, results ...hivev1.ReachabilityProbeResult) *hivev1.ClusterDeployment {
+		for i := 0; i < runs; i++ {
+			cd.Status.ReachabilityProbes = append(cd.Status.ReachabilityProbes, hivev1.ReachabilityProbeRun{
+				Time:    metav1.NewTime(time.Now().Add(-time.Duration(i+1) * time.Hour)),
+				Results: results,
+			})
+		}
+		return cd
+	}
+	result := func(probe hivev1.ClusterReachabilityProbe, err string) hivev1.ReachabilityProbeResult {
+		return hivev1.ReachabilityProbeResult{
+			Probe:     probe,
+			Reachable: err == "",
+			Latency:   metav1.Duration{Duration: time.Second},
+			Error:     err,
+		}
+	}
+
 	tests := []struct {
-		name     string
-		cd       *hivev1.ClusterDeployment
-		expected bool
+		name              string
+		cd                *hivev1.ClusterDeployment
+		apiClientBuilder  func(string, string) (client.Client, error)
+		additionalProbes  []namedProbe
+		lastProbe         time.Duration
+		expectUnreachable bool
+		expectReason      string
+		expectNoUpdate    bool
+		expectProbes      []hivev1.ReachabilityProbeResult
+		expectHistory     int
 	}{
 		{
-			name: "lastProbeTime, lastTransitionTime is same",
-			cd: func() *hivev1.ClusterDeployment {
-				lastProbeTime := metav1.NewTime(time.Now().Add(-(time.Hour * 1)))
-				lastTransitionTime := lastProbeTime
-				return getCDWithProbeAndTransitionTime(lastProbeTime, lastTransitionTime, corev1.ConditionFalse)
-			}(),
-			expected: true,
+			name:              "API unreachable, ingress reachable",
+			cd:                getClusterDeployment(),
+			apiClientBuilder:  mockUnreachableClusterAPIClientBuilder,
+			additionalProbes:  []namedProbe{{name: hivev1.ConsoleReachabilityProbe, run: succeeding}},
+			expectUnreachable: true,
+			expectReason:      "ErrorConnectingToAPI",
+			expectProbes: []hivev1.ReachabilityProbeResult{
+				result(hivev1.APIClientReachabilityProbe, "cluster not reachable"),
+				result(hivev1.ConsoleReachabilityProbe, ""),
+			},
+			expectHistory: 1,
 		},
 		{
-			name: "lastProbeTime 20Mins ago and lastTransitionTime 100Mins ago",
-			cd: func() *hivev1.ClusterDeployment {
-				lastProbeTime := metav1.NewTime(time.Now().Add(-(time.Minute * 20)))
-				lastTransitionTime := metav1.NewTime(time.Now().Add(-(time.Minute * 100)))
-				return getCDWithProbeAndTransitionTime(lastProbeTime, lastTransitionTime, corev1.ConditionFalse)
-			}(),
-			expected: false,
+			name:             "API and ingress unreachable",
+			cd:               getClusterDeployment(),
+			apiClientBuilder: mockUnreachableClusterAPIClientBuilder,
+			additionalProbes: []namedProbe{
+				{name: hivev1.ConsoleReachabilityProbe, run: failing},
+				{name: hivev1.IngressCanaryReachabilityProbe, run: failing},
+			},
+			expectUnreachable: true,
+			expectReason:      "ErrorConnectingToCluster",
+			expectHistory:     1,
 		},
 		{
-			name: "lastProbeTime 1hour ago lastTransitionTime 5hour ago",
-			cd: func() *hivev1.ClusterDeployment {
-				lastProbeTime := metav1.NewTime(time.Now().Add(-(time.Hour * 1)))
-				lastTransitionTime := metav1.NewTime(time.Now().Add(-(time.Hour * 5)))
-				return getCDWithProbeAndTransitionTime(lastProbeTime, lastTransitionTime, corev1.ConditionFalse)
-			}(),
-			expected: false,
+			name:              "API not ready",
+			cd:                getClusterDeployment(),
+			apiClientBuilder:  mockReachableClusterAPIClientBuilder,
+			additionalProbes:  []namedProbe{{name: hivev1.APIReadyzReachabilityProbe, run: failing}},
+			expectUnreachable: true,
+			expectReason:      "ErrorConnectingToCluster",
+			expectHistory:     1,
 		},
 		{
-			name: "lastProbeTime more than 2hour ago lastTransitionTime 12hour ago",
-			cd: func() *hivev1.ClusterDeployment {
-				lastProbeTime := metav1.NewTime(time.Now().Add(-(time.Minute * 121)))
-				lastTransitionTime := metav1.NewTime(time.Now().Add(-(time.Hour * 12)))
-				return getCDWithProbeAndTransitionTime(lastProbeTime, lastTransitionTime, corev1.ConditionFalse)
-			}(),
-			expected: true,
+			name:             "ingress unreachable only",
+			cd:               getClusterDeployment(),
+			apiClientBuilder: mockReachableClusterAPIClientBuilder,
+			additionalProbes: []namedProbe{{name: hivev1.IngressCanaryReachabilityProbe, run: failing}},
+			expectProbes: []hivev1.ReachabilityProbeResult{
+				result(hivev1.APIClientReachabilityProbe, ""),
+				result(hivev1.IngressCanaryReachabilityProbe, "probe failed"),
+			},
+			expectHistory: 1,
+		},
+		{
+			name: "ingress unreachable only with the same outcome",
+			cd: withProbeHistory(getClusterDeployment(), 1,
+				result(hivev1.APIClientReachabilityProbe, ""),
+				result(hivev1.IngressCanaryReachabilityProbe, "probe failed"),
+			),
+			apiClientBuilder: mockReachableClusterAPIClientBuilder,
+			additionalProbes: []namedProbe{{name: hivev1.IngressCanaryReachabilityProbe, run: failing}},
+			expectNoUpdate:   true,
+			expectHistory:    1,
+		},
+		{
+			name: "ingress reachable again",
+			cd: withProbeHistory(getClusterDeployment(), defaultProbeHistoryLimit,
+				result(hivev1.APIClientReachabilityProbe, ""),
+				result(hivev1.IngressCanaryReachabilityProbe, "probe failed"),
+			),
+			apiClientBuilder: mockReachableClusterAPIClientBuilder,
+			additionalProbes: []namedProbe{{name: hivev1.IngressCanaryReachabilityProbe, run: succeeding}},
+			expectProbes: []hivev1.ReachabilityProbeResult{
+				result(hivev1.APIClientReachabilityProbe, ""),
+				result(hivev1.IngressCanaryReachabilityProbe, ""),
+			},
+			expectHistory: defaultProbeHistoryLimit,
+		},
+		{
+			name:             "probe without target",
+			cd:               withProbeHistory(getClusterDeployment(), 1, result(hivev1.APIClientReachabilityProbe, "")),
+			apiClientBuilder: mockReachableClusterAPIClientBuilder,
+			additionalProbes: []namedProbe{{name: hivev1.ConsoleReachabilityProbe, run: noTarget}},
+			expectNoUpdate:   true,
+			expectHistory:    1,
 		},
 		{
-			name: "lastProbeTime 60min ago lastTransitionTime 160min ago",
+			name:             "reachable cluster probed recently",
+			cd:               getClusterDeployment(),
+			apiClientBuilder: mockUnreachableClusterAPIClientBuilder,
+			lastProbe:        time.Minute,
+			expectNoUpdate:   true,
+		},
+		{
+			name:              "reachable cluster due for a probe",
+			cd:                getClusterDeployment(),
+			apiClientBuilder:  mockUnreachableClusterAPIClientBuilder,
+			lastProbe:         time.Hour,
+			expectUnreachable: true,
+			expectReason:      "ErrorConnectingToCluster",
+			expectHistory:     1,
+		},
+		{
+			name: "unreachable cluster with the same error",
 			cd: func() *hivev1.ClusterDeployment {
-				lastProbeTime := metav1.NewTime(time.Now().Add(-(time.Minute * 60)))
-				lastTransitionTime := metav1.NewTime(time.Now().Add(-(time.Minute * 160)))
-				return getCDWithProbeAndTransitionTime(lastProbeTime, lastTransitionTime, corev1.ConditionFalse)
+				cd := withProbeHistory(getClusterDeployment(), 1, result(hivev1.APIClientReachabilityProbe, "cluster not reachable"))
+				cd.Status.CloudHealth = &hivev1.CloudHealthStatus{
+					Time:  metav1.NewTime(time.Now().Add(-3 * time.Hour)),
+					Error: `secrets "aws-credentials" not found`,
+				}
+				cd.Status.Conditions = []hivev1.ClusterDeploymentCondition{{
+					Type:               hivev1.UnreachableCondition,
+					Status:             corev1.ConditionTrue,
+					Reason:             "ErrorConnectingToCluster",
+					Message:            "cluster not reachable; " + summarizeCloudHealth(cd.Status.CloudHealth),
+					LastProbeTime:      metav1.NewTime(time.Now().Add(-3 * time.Hour)),
+					LastTransitionTime: metav1.NewTime(time.Now().Add(-3 * time.Hour)),
+				}}
+				return cd
 			}(),
-			expected: true,
+			apiClientBuilder:  mockUnreachableClusterAPIClientBuilder,
+			expectUnreachable: true,
+			expectReason:      "ErrorConnectingToCluster",
+			expectNoUpdate:    true,
+			expectHistory:     1,
 		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			cond := controllerutils.FindClusterDeploymentCondition(test.cd.Status.Conditions, hivev1.UnreachableCondition)
-			actual := isTimeForConnectivityCheck(cond)
-			if test.expected != actual {
-				t.Errorf("expected: %t actual: %t", test.expected, actual)
+			fakeClient := &statusUpdateCounter{Client: fake.NewFakeClient(test.cd, getKubeconfigSecret())}
+			rcd := &ReconcileRemoteMachineSet{
+				Client:                             fakeClient,
+				scheme:                             scheme.Scheme,
+				logger:                             log.WithField("controller", "unreachable"),
+				remoteClusterAPIClientBuilder:      test.apiClientBuilder,
+				invalidateRemoteClusterConnections: func(string) {},
+				schedule:                           defaultProbeSchedule(),
+				additionalProbes:                   test.additionalProbes,
+			}
+			namespacedName := types.NamespacedName{Name: testName, Namespace: testNamespace}
+			if test.lastProbe > 0 {
+				rcd.lastProbes.set(namespacedName, time.Now().Add(-test.lastProbe))
+			}
+			result, err := rcd.Reconcile(reconcile.Request{NamespacedName: namespacedName})
+			require.NoError(t, err, "unexpected error")
+			assert.True(t, result.RequeueAfter > 0, "expected the next probe to be scheduled")
+
+			cd := &hivev1.ClusterDeployment{}
+			require.NoError(t, fakeClient.Get(context.TODO(), namespacedName, cd), "unexpected error getting cluster deployment")
+			if test.expectNoUpdate {
+				assert.Zero(t, fakeClient.statusUpdates, "unexpected update of cluster deployment status")
+			} else {
+				assert.Equal(t, 1, fakeClient.statusUpdates, "expected update of cluster deployment status")
+			}
+			if lastProbe, ok := rcd.lastProbes.get(namespacedName); assert.True(t, ok, "expected probe time to be recorded") && test.lastProbe == time.Minute {
+				assert.True(t, time.Since(lastProbe) >= time.Minute, "expected no new probe")
+			}
+
+			if assert.Len(t, cd.Status.ReachabilityProbes, test.expectHistory, "unexpected number of probe runs kept") && test.expectProbes != nil {
+				run := cd.Status.ReachabilityProbes[0]
+				assert.True(t, time.Since(run.Time.Time) < time.Minute, "expected the latest probe run first")
+				for i := range run.Results {
+					run.Results[i].Latency = metav1.Duration{Duration: time.Second}
+				}
+				assert.Equal(t, test.expectProbes, run.Results, "unexpected probe results")
+			}
+
+			cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.UnreachableCondition)
+			if test.expectUnreachable {
+				if assert.NotNil(t, cond, "expected unreachable condition") {
+					assert.Equal(t, corev1.ConditionTrue, cond.Status, "unexpected unreachable condition status")
+					assert.Equal(t, test.expectReason, cond.Reason, "unexpected unreachable condition reason")
+				}
+			} else {
+				assert.Nil(t, cond, "unexpected unreachable condition")
 			}
 		})
 	}
 }
 
+// statusUpdateCounter counts the status updates made through a client
+type statusUpdateCounter struct {
+	client.Client
+	statusUpdates int
+}
+
+func (c *statusUpdateCounter) Status() client.StatusWriter {
+	return &countingStatusWriter{StatusWriter: c.Client.Status(), counter: c}
+}
+
+type countingStatusWriter struct {
+	client.StatusWriter
+	counter *statusUpdateCounter
+}
+
+func (w *countingStatusWriter) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOptionFunc) error {
+	w.counter.statusUpdates++
+	return w.StatusWriter.Update(ctx, obj, opts...)
+}
+
+func TestCloudHealthChanged(t *testing.T) {
+	health := func(age time.Duration, states ...string) *hivev1.CloudHealthStatus {
+		h := &hivev1.CloudHealthStatus{Time: metav1.NewTime(time.Now().Add(-age))}
+		for _, state := range states {
+			h.ControlPlaneInstances = append(h.ControlPlaneInstances, hivev1.CloudInstanceState{Name: "master-0", State: state})
+		}
+		return h
+	}
+	assert.False(t, cloudHealthChanged(nil, nil), "expected no change without health")
+	assert.True(t, cloudHealthChanged(nil, health(0, "running")), "expected change when health is found")
+	assert.True(t, cloudHealthChanged(health(0, "running"), nil), "expected change when health is cleared")
+	assert.False(t, cloudHealthChanged(health(time.Hour, "running"), health(0, "running")), "expected no change when only the time differs")
+	assert.True(t, cloudHealthChanged(health(time.Hour, "running"), health(0, "stopped")), "expected change when an instance state differs")
+}
+
+func TestLoadReachabilityConfig(t *testing.T) {
+	os.Setenv(constants.ClusterReachabilityEnvVar, `{"additionalProbes":["APIReadyz","Unknown","IngressCanary"],"reachableProbeInterval":"5m","unreachableProbeBackoffFactor":2,"probeHistoryLimit":3}`)
+	defer os.Unsetenv(constants.ClusterReachabilityEnvVar)
+
+	schedule, probes := loadReachabilityConfig(log.WithField("controller", "unreachable"))
+	assert.Equal(t, probeSchedule{
+		reachableInterval:      5 * time.Minute,
+		backoffFactor:          2,
+		maxUnreachableInterval: defaultMaxUnreachableProbeInterval,
+		historyLimit:           3,
+	}, schedule, "unexpected probe schedule")
+	names := []hivev1.ClusterReachabilityProbe{}
+	for _, p := range probes {
+		names = append(names, p.name)
+	}
+	assert.Equal(t, []hivev1.ClusterReachabilityProbe{hivev1.APIReadyzReachabilityProbe, hivev1.IngressCanaryReachabilityProbe}, names, "unexpected additional probes")
+}
+
 func mockUnreachableClusterAPIClientBuilder(secretData, controllerName string) (client.Client, error) {
 	err := errors.New("cluster not reachable")
 	return nil, err
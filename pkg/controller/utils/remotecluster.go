@@ -0,0 +1,406 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// remoteClusterConnectionTTL is how long the connection to a remote cluster is cached after it was last used
+	remoteClusterConnectionTTL = time.Hour
+
+	// restMapperRefreshInterval is the minimum time between rediscovering the resources of a remote cluster when a
+	// kind is not found
+	restMapperRefreshInterval = 30 * time.Second
+
+	// resourceDiscoveryInterval is how long the resources of a remote cluster returned by Resources are cached
+	resourceDiscoveryInterval = 10 * time.Minute
+)
+
+var (
+	metricRemoteClusterConnections = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "hive_remote_cluster_connections",
+		Help: "Number of remote clusters with cached connections.",
+	})
+	metricRemoteClusterClientsBuilt = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "hive_remote_cluster_clients_built_total",
+		Help: "Counter incremented each time a client for a remote cluster is built because it was not cached.",
+	},
+		[]string{"controller", "type"},
+	)
+	metricRemoteClusterClientsCached = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "hive_remote_cluster_clients_cached_total",
+		Help: "Counter incremented each time a cached client for a remote cluster is used.",
+	},
+		[]string{"controller", "type"},
+	)
+	metricRemoteClusterConnectionsInvalidated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "hive_remote_cluster_connections_invalidated_total",
+		Help: "Counter incremented each time the cached connection to a remote cluster is dropped.",
+	},
+		[]string{"reason"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(metricRemoteClusterConnections)
+	metrics.Registry.MustRegister(metricRemoteClusterClientsBuilt)
+	metrics.Registry.MustRegister(metricRemoteClusterClientsCached)
+	metrics.Registry.MustRegister(metricRemoteClusterConnectionsInvalidated)
+}
+
+// RemoteClusters caches the connections to remote clusters for all controllers of the hub. Its ClusterAPIClient and
+// DynamicClient methods can be used in place of BuildClusterAPIClientFromKubeconfig and
+// BuildDynamicClientFromKubeconfig.
+var RemoteClusters = NewRemoteClusterManager()
+
+// RemoteClusterManager caches clients for remote clusters, so that TLS connections and the discovery of the resources
+// of a cluster are reused across reconciles. Connections are cached per kubeconfig, so a changed kubeconfig secret
+// results in a new connection, and connections that were not used for an hour are dropped. Clients are built per
+// controller to keep the client metrics of each controller.
+type RemoteClusterManager struct {
+	mutex    sync.Mutex
+	clusters map[string]*remoteCluster
+
+	// now, newRESTMapper and discoverResources are exposed for testing
+	now               func() time.Time
+	newRESTMapper     func(*rest.Config) (meta.RESTMapper, error)
+	discoverResources func(*rest.Config) ([]schema.GroupVersionResource, error)
+}
+
+// remoteCluster holds the connection to a remote cluster. Its mutex guards building clients, so that building the
+// clients of a slow cluster does not block the other clusters.
+type remoteCluster struct {
+	mutex          sync.Mutex
+	config         *rest.Config
+	mapper         meta.RESTMapper
+	apiClients     map[string]client.Client
+	dynamicClients map[string]dynamic.Interface
+	lastUsed       time.Time
+
+	resources           []schema.GroupVersionResource
+	resourcesDiscovered time.Time
+}
+
+// NewRemoteClusterManager returns a new RemoteClusterManager without cached connections.
+func NewRemoteClusterManager() *RemoteClusterManager {
+	return &RemoteClusterManager{
+		clusters:          map[string]*remoteCluster{},
+		now:               time.Now,
+		newRESTMapper:     newRefreshingRESTMapper,
+		discoverResources: discoverListableResources,
+	}
+}
+
+// ClusterAPIClient returns a kubeclient with metrics for the remote cluster of the kubeconfig, reusing the client
+// built for the controller before if there is one. Controller name is required for metrics purposes.
+func (m *RemoteClusterManager) ClusterAPIClient(kubeconfigData, controllerName string) (client.Client, error) {
+	cluster, err := m.cluster(kubeconfigData)
+	if err != nil {
+		return nil, err
+	}
+	cluster.mutex.Lock()
+	defer cluster.mutex.Unlock()
+	if c, ok := cluster.apiClients[controllerName]; ok {
+		metricRemoteClusterClientsCached.WithLabelValues(controllerName, "clusterapi").Inc()
+		return c, nil
+	}
+	mapper, err := m.restMapper(cluster)
+	if err != nil {
+		return nil, err
+	}
+	cfg := rest.CopyConfig(cluster.config)
+	AddControllerMetricsTransportWrapper(cfg, controllerName, true)
+	c, err := newClusterAPIClient(cfg, mapper)
+	if err != nil {
+		return nil, err
+	}
+	metricRemoteClusterClientsBuilt.WithLabelValues(controllerName, "clusterapi").Inc()
+	cluster.apiClients[controllerName] = c
+	return c, nil
+}
+
+// DynamicClient returns a dynamic client with metrics for the remote cluster of the kubeconfig, reusing the client
+// built for the controller before if there is one. Controller name is required for metrics purposes.
+func (m *RemoteClusterManager) DynamicClient(kubeconfigData, controllerName string) (dynamic.Interface, error) {
+	cluster, err := m.cluster(kubeconfigData)
+	if err != nil {
+		return nil, err
+	}
+	cluster.mutex.Lock()
+	defer cluster.mutex.Unlock()
+	if c, ok := cluster.dynamicClients[controllerName]; ok {
+		metricRemoteClusterClientsCached.WithLabelValues(controllerName, "dynamic").Inc()
+		return c, nil
+	}
+	cfg := rest.CopyConfig(cluster.config)
+	AddControllerMetricsTransportWrapper(cfg, controllerName, true)
+	c, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	metricRemoteClusterClientsBuilt.WithLabelValues(controllerName, "dynamic").Inc()
+	cluster.dynamicClients[controllerName] = c
+	return c, nil
+}
+
+// RESTMapper returns the REST mapper of the remote cluster of the kubeconfig. The mapper is shared by all clients of
+// the cluster and discovers the resources of the cluster again when a kind or resource is not found.
+func (m *RemoteClusterManager) RESTMapper(kubeconfigData string) (meta.RESTMapper, error) {
+	cluster, err := m.cluster(kubeconfigData)
+	if err != nil {
+		return nil, err
+	}
+	cluster.mutex.Lock()
+	defer cluster.mutex.Unlock()
+	return m.restMapper(cluster)
+}
+
+// Resources returns the preferred versions of the resources of the remote cluster of the kubeconfig that can be listed
+// and deleted. The resources are discovered again once they were cached for resourceDiscoveryInterval.
+func (m *RemoteClusterManager) Resources(kubeconfigData string) ([]schema.GroupVersionResource, error) {
+	cluster, err := m.cluster(kubeconfigData)
+	if err != nil {
+		return nil, err
+	}
+	cluster.mutex.Lock()
+	defer cluster.mutex.Unlock()
+	now := m.now()
+	if cluster.resources != nil && now.Sub(cluster.resourcesDiscovered) < resourceDiscoveryInterval {
+		return cluster.resources, nil
+	}
+	resources, err := m.discoverResources(cluster.config)
+	if err != nil {
+		return nil, err
+	}
+	cluster.resources = resources
+	cluster.resourcesDiscovered = now
+	return resources, nil
+}
+
+// Invalidate drops the cached connections to the API server of the kubeconfig, e.g. when the cluster became
+// unreachable. Connections built from other kubeconfigs for the same API server are dropped as well.
+func (m *RemoteClusterManager) Invalidate(kubeconfigData string) {
+	cfg, err := restConfigFromKubeconfig(kubeconfigData)
+	if err != nil {
+		return
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for key, cluster := range m.clusters {
+		if cluster.config.Host == cfg.Host {
+			delete(m.clusters, key)
+			metricRemoteClusterConnectionsInvalidated.WithLabelValues("invalidated").Inc()
+		}
+	}
+	metricRemoteClusterConnections.Set(float64(len(m.clusters)))
+}
+
+// cluster returns the cached connection for the kubeconfig, creating it if needed. Connections that expired are
+// dropped.
+func (m *RemoteClusterManager) cluster(kubeconfigData string) (*remoteCluster, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := m.now()
+	for key, cluster := range m.clusters {
+		if now.Sub(cluster.lastUsed) > remoteClusterConnectionTTL {
+			delete(m.clusters, key)
+			metricRemoteClusterConnectionsInvalidated.WithLabelValues("expired").Inc()
+		}
+	}
+	defer func() {
+		metricRemoteClusterConnections.Set(float64(len(m.clusters)))
+	}()
+
+	key := fmt.Sprintf("%x", sha256.Sum256([]byte(kubeconfigData)))
+	cluster, ok := m.clusters[key]
+	if !ok {
+		cfg, err := restConfigFromKubeconfig(kubeconfigData)
+		if err != nil {
+			return nil, err
+		}
+		cluster = &remoteCluster{
+			config:         cfg,
+			apiClients:     map[string]client.Client{},
+			dynamicClients: map[string]dynamic.Interface{},
+		}
+		m.clusters[key] = cluster
+	}
+	cluster.lastUsed = now
+	return cluster, nil
+}
+
+// restMapper returns the REST mapper of the cluster, building it if needed. The mutex of the cluster must be held.
+func (m *RemoteClusterManager) restMapper(cluster *remoteCluster) (meta.RESTMapper, error) {
+	if cluster.mapper == nil {
+		mapper, err := m.newRESTMapper(cluster.config)
+		if err != nil {
+			return nil, err
+		}
+		cluster.mapper = mapper
+	}
+	return cluster.mapper, nil
+}
+
+// discoverListableResources returns the preferred versions of the resources of a cluster that can be listed and
+// deleted. Resources of API groups that fail discovery are left out.
+func discoverListableResources(cfg *rest.Config) ([]schema.GroupVersionResource, error) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	resourceLists, err := discoveryClient.ServerPreferredResources()
+	if err != nil && len(resourceLists) == 0 {
+		return nil, err
+	}
+	resourceLists = discovery.FilteredBy(discovery.SupportsAllVerbs{Verbs: []string{"list", "delete"}}, resourceLists)
+	found, err := discovery.GroupVersionResources(resourceLists)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]schema.GroupVersionResource, 0, len(found))
+	for gvr := range found {
+		resources = append(resources, gvr)
+	}
+	sort.Slice(resources, func(i, j int) bool { return resources[i].String() < resources[j].String() })
+	return resources, nil
+}
+
+// ResettableRESTMapper is a RESTMapper whose discovered resources can be reset, so that the resources of the cluster
+// are discovered again the next time a kind or resource is not found.
+type ResettableRESTMapper interface {
+	meta.RESTMapper
+	Reset()
+}
+
+// refreshingRESTMapper is a REST mapper for a remote cluster that discovers the resources of the cluster again when a
+// kind or resource is not found, e.g. because its CRD was created after the mapper was built.
+type refreshingRESTMapper struct {
+	mutex       sync.RWMutex
+	config      *rest.Config
+	mapper      meta.RESTMapper
+	lastRefresh time.Time
+
+	// discover and now are exposed for testing
+	discover func(*rest.Config) (meta.RESTMapper, error)
+	now      func() time.Time
+}
+
+func newRefreshingRESTMapper(cfg *rest.Config) (meta.RESTMapper, error) {
+	return newRefreshingRESTMapperWithDiscovery(cfg, apiutil.NewDiscoveryRESTMapper, time.Now)
+}
+
+func newRefreshingRESTMapperWithDiscovery(cfg *rest.Config, discover func(*rest.Config) (meta.RESTMapper, error), now func() time.Time) (*refreshingRESTMapper, error) {
+	mapper, err := discover(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &refreshingRESTMapper{
+		config:      cfg,
+		mapper:      mapper,
+		lastRefresh: now(),
+		discover:    discover,
+		now:         now,
+	}, nil
+}
+
+// withRefresh calls f with the current mapper, and calls it again after refreshing the mapper if no match was found
+func (m *refreshingRESTMapper) withRefresh(f func(meta.RESTMapper) error) error {
+	m.mutex.RLock()
+	mapper := m.mapper
+	m.mutex.RUnlock()
+	err := f(mapper)
+	if !meta.IsNoMatchError(err) {
+		return err
+	}
+
+	m.mutex.Lock()
+	if now := m.now(); now.Sub(m.lastRefresh) >= restMapperRefreshInterval {
+		if refreshed, refreshErr := m.discover(m.config); refreshErr == nil {
+			m.mapper = refreshed
+		}
+		m.lastRefresh = now
+	}
+	mapper = m.mapper
+	m.mutex.Unlock()
+	return f(mapper)
+}
+
+// Reset lets the next kind or resource that is not found discover the resources of the cluster again right away,
+// regardless of when they were last discovered
+func (m *refreshingRESTMapper) Reset() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.lastRefresh = time.Time{}
+}
+
+func (m *refreshingRESTMapper) KindFor(resource schema.GroupVersionResource) (gvk schema.GroupVersionKind, err error) {
+	err = m.withRefresh(func(mapper meta.RESTMapper) error {
+		gvk, err = mapper.KindFor(resource)
+		return err
+	})
+	return
+}
+
+func (m *refreshingRESTMapper) KindsFor(resource schema.GroupVersionResource) (gvks []schema.GroupVersionKind, err error) {
+	err = m.withRefresh(func(mapper meta.RESTMapper) error {
+		gvks, err = mapper.KindsFor(resource)
+		return err
+	})
+	return
+}
+
+func (m *refreshingRESTMapper) ResourceFor(input schema.GroupVersionResource) (gvr schema.GroupVersionResource, err error) {
+	err = m.withRefresh(func(mapper meta.RESTMapper) error {
+		gvr, err = mapper.ResourceFor(input)
+		return err
+	})
+	return
+}
+
+func (m *refreshingRESTMapper) ResourcesFor(input schema.GroupVersionResource) (gvrs []schema.GroupVersionResource, err error) {
+	err = m.withRefresh(func(mapper meta.RESTMapper) error {
+		gvrs, err = mapper.ResourcesFor(input)
+		return err
+	})
+	return
+}
+
+func (m *refreshingRESTMapper) RESTMapping(gk schema.GroupKind, versions ...string) (mapping *meta.RESTMapping, err error) {
+	err = m.withRefresh(func(mapper meta.RESTMapper) error {
+		mapping, err = mapper.RESTMapping(gk, versions...)
+		return err
+	})
+	return
+}
+
+func (m *refreshingRESTMapper) RESTMappings(gk schema.GroupKind, versions ...string) (mappings []*meta.RESTMapping, err error) {
+	err = m.withRefresh(func(mapper meta.RESTMapper) error {
+		mappings, err = mapper.RESTMappings(gk, versions...)
+		return err
+	})
+	return
+}
+
+func (m *refreshingRESTMapper) ResourceSingularizer(resource string) (singular string, err error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.mapper.ResourceSingularizer(resource)
+}
@@ -0,0 +1,213 @@
+package utils
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+)
+
+func testKubeconfig(server, user string) string {
+	return fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: %s
+  name: cluster
+contexts:
+- context:
+    cluster: cluster
+    user: %s
+  name: admin
+current-context: admin
+users:
+- name: %s
+  user:
+    token: secret
+`, server, user, user)
+}
+
+func testRemoteClusterManager(now *time.Time, mappersBuilt *int) *RemoteClusterManager {
+	m := NewRemoteClusterManager()
+	m.now = func() time.Time { return *now }
+	m.newRESTMapper = func(*rest.Config) (meta.RESTMapper, error) {
+		*mappersBuilt++
+		return meta.NewDefaultRESTMapper(nil), nil
+	}
+	return m
+}
+
+func TestRemoteClusterManager(t *testing.T) {
+	kubeconfig := testKubeconfig("https://api.cluster1.example.com:6443", "admin")
+
+	t.Run("clients are cached per controller", func(t *testing.T) {
+		now, mappersBuilt := time.Now(), 0
+		m := testRemoteClusterManager(&now, &mappersBuilt)
+
+		c1, err := m.ClusterAPIClient(kubeconfig, "controller1")
+		require.NoError(t, err, "unexpected error building client")
+		c2, err := m.ClusterAPIClient(kubeconfig, "controller1")
+		require.NoError(t, err, "unexpected error building client")
+		assert.True(t, c1 == c2, "expected cached client")
+
+		c3, err := m.ClusterAPIClient(kubeconfig, "controller2")
+		require.NoError(t, err, "unexpected error building client")
+		assert.False(t, c1 == c3, "expected a client per controller")
+		assert.Equal(t, 1, mappersBuilt, "expected the REST mapper to be shared by the controllers")
+
+		mapper1, err := m.RESTMapper(kubeconfig)
+		require.NoError(t, err, "unexpected error getting REST mapper")
+		mapper2, err := m.RESTMapper(kubeconfig)
+		require.NoError(t, err, "unexpected error getting REST mapper")
+		assert.True(t, mapper1 == mapper2, "expected cached REST mapper")
+		assert.Equal(t, 1, mappersBuilt, "expected the REST mapper of the clients to be returned")
+
+		d1, err := m.DynamicClient(kubeconfig, "controller1")
+		require.NoError(t, err, "unexpected error building dynamic client")
+		d2, err := m.DynamicClient(kubeconfig, "controller1")
+		require.NoError(t, err, "unexpected error building dynamic client")
+		assert.True(t, d1 == d2, "expected cached dynamic client")
+	})
+
+	t.Run("changed kubeconfig", func(t *testing.T) {
+		now, mappersBuilt := time.Now(), 0
+		m := testRemoteClusterManager(&now, &mappersBuilt)
+
+		c1, err := m.ClusterAPIClient(kubeconfig, "controller1")
+		require.NoError(t, err, "unexpected error building client")
+		c2, err := m.ClusterAPIClient(testKubeconfig("https://api.cluster1.example.com:6443", "admin2"), "controller1")
+		require.NoError(t, err, "unexpected error building client")
+		assert.False(t, c1 == c2, "expected a new client for the changed kubeconfig")
+		assert.Equal(t, 2, mappersBuilt, "expected a new REST mapper for the changed kubeconfig")
+	})
+
+	t.Run("invalidate", func(t *testing.T) {
+		now, mappersBuilt := time.Now(), 0
+		m := testRemoteClusterManager(&now, &mappersBuilt)
+
+		c1, err := m.ClusterAPIClient(kubeconfig, "controller1")
+		require.NoError(t, err, "unexpected error building client")
+		other, err := m.ClusterAPIClient(testKubeconfig("https://api.cluster2.example.com:6443", "admin"), "controller1")
+		require.NoError(t, err, "unexpected error building client")
+
+		// A kubeconfig for the same API server with different credentials invalidates the cached connection
+		m.Invalidate(testKubeconfig("https://api.cluster1.example.com:6443", "admin2"))
+
+		c2, err := m.ClusterAPIClient(kubeconfig, "controller1")
+		require.NoError(t, err, "unexpected error building client")
+		assert.False(t, c1 == c2, "expected a new client after invalidation")
+		otherAfter, err := m.ClusterAPIClient(testKubeconfig("https://api.cluster2.example.com:6443", "admin"), "controller1")
+		require.NoError(t, err, "unexpected error building client")
+		assert.True(t, other == otherAfter, "expected the client of the other cluster to stay cached")
+	})
+
+	t.Run("expiry", func(t *testing.T) {
+		now, mappersBuilt := time.Now(), 0
+		m := testRemoteClusterManager(&now, &mappersBuilt)
+
+		c1, err := m.ClusterAPIClient(kubeconfig, "controller1")
+		require.NoError(t, err, "unexpected error building client")
+
+		now = now.Add(remoteClusterConnectionTTL / 2)
+		c2, err := m.ClusterAPIClient(kubeconfig, "controller1")
+		require.NoError(t, err, "unexpected error building client")
+		assert.True(t, c1 == c2, "expected cached client before expiry")
+
+		now = now.Add(remoteClusterConnectionTTL + time.Minute)
+		c3, err := m.ClusterAPIClient(kubeconfig, "controller1")
+		require.NoError(t, err, "unexpected error building client")
+		assert.False(t, c1 == c3, "expected a new client after expiry")
+		assert.Equal(t, 2, mappersBuilt, "expected the REST mapper of the expired connection to be dropped")
+	})
+
+	t.Run("resources are cached", func(t *testing.T) {
+		now, mappersBuilt := time.Now(), 0
+		m := testRemoteClusterManager(&now, &mappersBuilt)
+		discoveries := 0
+		m.discoverResources = func(*rest.Config) ([]schema.GroupVersionResource, error) {
+			discoveries++
+			return []schema.GroupVersionResource{{Version: "v1", Resource: "configmaps"}}, nil
+		}
+
+		resources, err := m.Resources(kubeconfig)
+		require.NoError(t, err, "unexpected error discovering resources")
+		assert.Equal(t, []schema.GroupVersionResource{{Version: "v1", Resource: "configmaps"}}, resources, "unexpected resources")
+		_, err = m.Resources(kubeconfig)
+		require.NoError(t, err, "unexpected error discovering resources")
+		assert.Equal(t, 1, discoveries, "expected cached resources")
+
+		now = now.Add(resourceDiscoveryInterval + time.Minute)
+		_, err = m.Resources(kubeconfig)
+		require.NoError(t, err, "unexpected error discovering resources")
+		assert.Equal(t, 2, discoveries, "expected resources to be discovered again")
+	})
+
+	t.Run("invalid kubeconfig", func(t *testing.T) {
+		now, mappersBuilt := time.Now(), 0
+		m := testRemoteClusterManager(&now, &mappersBuilt)
+
+		_, err := m.ClusterAPIClient("not a kubeconfig", "controller1")
+		assert.Error(t, err, "expected error for invalid kubeconfig")
+		assert.Empty(t, m.clusters, "expected no cached connection for invalid kubeconfig")
+	})
+}
+
+func TestRefreshingRESTMapper(t *testing.T) {
+	configMapKind := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	widgetKind := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+	mapperWith := func(kinds ...schema.GroupVersionKind) meta.RESTMapper {
+		mapper := meta.NewDefaultRESTMapper(nil)
+		for _, kind := range kinds {
+			mapper.Add(kind, meta.RESTScopeNamespace)
+		}
+		return mapper
+	}
+
+	now := time.Now()
+	discoveries := 0
+	kinds := []schema.GroupVersionKind{configMapKind}
+	mapper, err := newRefreshingRESTMapperWithDiscovery(
+		&rest.Config{},
+		func(*rest.Config) (meta.RESTMapper, error) {
+			discoveries++
+			return mapperWith(kinds...), nil
+		},
+		func() time.Time { return now },
+	)
+	require.NoError(t, err, "unexpected error building REST mapper")
+
+	_, err = mapper.RESTMapping(configMapKind.GroupKind(), configMapKind.Version)
+	require.NoError(t, err, "unexpected error mapping known kind")
+	assert.Equal(t, 1, discoveries, "expected no discovery for a known kind")
+
+	// The CRD of the kind is created after the mapper was built, but the mapper was refreshed too recently
+	kinds = append(kinds, widgetKind)
+	_, err = mapper.RESTMapping(widgetKind.GroupKind(), widgetKind.Version)
+	assert.True(t, meta.IsNoMatchError(err), "expected no match before the refresh interval passed")
+	assert.Equal(t, 1, discoveries, "expected no discovery within the refresh interval")
+
+	now = now.Add(restMapperRefreshInterval)
+	mapping, err := mapper.RESTMapping(widgetKind.GroupKind(), widgetKind.Version)
+	require.NoError(t, err, "expected kind to be found after refresh")
+	assert.Equal(t, "widgets", mapping.Resource.Resource, "unexpected resource")
+	assert.Equal(t, 2, discoveries, "expected discovery for the unknown kind")
+
+	_, err = mapper.RESTMapping(widgetKind.GroupKind(), widgetKind.Version)
+	require.NoError(t, err, "unexpected error mapping refreshed kind")
+	assert.Equal(t, 2, discoveries, "expected refreshed mapper to be reused")
+
+	// A reset lets the next unknown kind be discovered within the refresh interval
+	gadgetKind := schema.GroupVersionKind{Group: widgetKind.Group, Version: widgetKind.Version, Kind: "Gadget"}
+	kinds = append(kinds, gadgetKind)
+	mapper.Reset()
+	mapping, err = mapper.RESTMapping(gadgetKind.GroupKind(), gadgetKind.Version)
+	require.NoError(t, err, "expected kind to be found after reset")
+	assert.Equal(t, "gadgets", mapping.Resource.Resource, "unexpected resource")
+	assert.Equal(t, 3, discoveries, "expected discovery for the unknown kind after reset")
+}
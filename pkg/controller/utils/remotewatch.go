@@ -0,0 +1,241 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+)
+
+var (
+	metricRemoteWatches = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "hive_remote_watches",
+		Help: "Number of remote clusters watched by a controller.",
+	},
+		[]string{"controller", "resource"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(metricRemoteWatches)
+}
+
+// remoteWatchSyncTimeout is how long the objects of a watched cluster can take to be listed before the watch fails
+const remoteWatchSyncTimeout = 2 * time.Minute
+
+// RemoteWatcher watches a resource on remote clusters, and triggers a reconcile of the ClusterDeployment of a cluster
+// when objects of the resource change on it.
+type RemoteWatcher interface {
+	// Source returns the source of events for the ClusterDeployments whose watched objects changed. Controllers
+	// using the watcher should watch it with an EnqueueRequestForObject handler.
+	Source() source.Source
+
+	// Watch starts watching the cluster of the ClusterDeployment with the kubeconfig, unless it is already watched
+	// with the same kubeconfig. It returns an error if the objects of the cluster could not be listed in time when it
+	// was last watched, in which case the watch is retried after the resync period. The ClusterDeployment is
+	// reconciled when the watch fails, so that its controller can fetch the objects in another way.
+	Watch(cd *hivev1.ClusterDeployment, kubeconfig string) error
+
+	// List returns the watched objects of the cluster of the ClusterDeployment. It returns false if the cluster is not
+	// watched, or the objects have not been listed yet.
+	List(cd types.NamespacedName) ([]*unstructured.Unstructured, bool)
+
+	// Stop stops watching the cluster of the ClusterDeployment, e.g. when it was deleted or became unreachable.
+	Stop(cd types.NamespacedName)
+}
+
+// NewRemoteWatcher returns a RemoteWatcher for the resource. A reconcile of every watched ClusterDeployment is also
+// triggered every resync period, as a fallback for missed events.
+func NewRemoteWatcher(controllerName string, resource schema.GroupVersionResource, resync time.Duration) RemoteWatcher {
+	return &remoteWatcher{
+		controllerName:       controllerName,
+		resource:             resource,
+		resync:               resync,
+		syncTimeout:          remoteWatchSyncTimeout,
+		events:               make(chan event.GenericEvent),
+		dynamicClientBuilder: RemoteClusters.DynamicClient,
+		watches:              map[types.NamespacedName]*remoteWatch{},
+		logger:               log.WithField("controller", controllerName),
+	}
+}
+
+type remoteWatcher struct {
+	controllerName       string
+	resource             schema.GroupVersionResource
+	resync               time.Duration
+	syncTimeout          time.Duration
+	events               chan event.GenericEvent
+	dynamicClientBuilder func(string, string) (dynamic.Interface, error)
+	logger               log.FieldLogger
+
+	mutex   sync.Mutex
+	watches map[types.NamespacedName]*remoteWatch
+}
+
+type remoteWatch struct {
+	kubeconfigHash string
+	informer       cache.SharedIndexInformer
+	stop           chan struct{}
+	// failed is when the watch was stopped because the objects were not listed in time
+	failed time.Time
+}
+
+func (w *remoteWatcher) Source() source.Source {
+	return &source.Channel{Source: w.events}
+}
+
+func (w *remoteWatcher) Watch(cd *hivev1.ClusterDeployment, kubeconfig string) error {
+	key := types.NamespacedName{Namespace: cd.Namespace, Name: cd.Name}
+	kubeconfigHash := fmt.Sprintf("%x", sha256.Sum256([]byte(kubeconfig)))
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if existing, ok := w.watches[key]; ok {
+		switch {
+		case existing.kubeconfigHash != kubeconfigHash:
+			// The kubeconfig changed, watch with the new one
+			if existing.failed.IsZero() {
+				close(existing.stop)
+			}
+		case existing.failed.IsZero():
+			return nil
+		case time.Since(existing.failed) < w.resync:
+			return fmt.Errorf("objects of the remote cluster were not listed within %v", w.syncTimeout)
+		}
+		delete(w.watches, key)
+	}
+
+	dynamicClient, err := w.dynamicClientBuilder(kubeconfig, w.controllerName)
+	if err != nil {
+		return err
+	}
+	resourceClient := dynamicClient.Resource(w.resource)
+	informer := cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return resourceClient.List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return resourceClient.Watch(options)
+			},
+		},
+		&unstructured.Unstructured{},
+		w.resync,
+		cache.Indexers{},
+	)
+	rw := &remoteWatch{
+		kubeconfigHash: kubeconfigHash,
+		informer:       informer,
+		stop:           make(chan struct{}),
+	}
+	notify := func(interface{}) {
+		w.notify(key, rw.stop)
+	}
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    notify,
+		UpdateFunc: func(interface{}, interface{}) { notify(nil) },
+		DeleteFunc: notify,
+	})
+	w.watches[key] = rw
+	go informer.Run(rw.stop)
+	go w.waitForSync(key, rw)
+
+	w.logger.WithField("clusterDeployment", key.String()).WithField("resource", w.resource.String()).Info("started watching remote cluster")
+	metricRemoteWatches.WithLabelValues(w.controllerName, w.resource.Resource).Set(float64(len(w.watches)))
+	return nil
+}
+
+// waitForSync stops the watch if its objects are not listed within the sync timeout
+func (w *remoteWatcher) waitForSync(cd types.NamespacedName, rw *remoteWatch) {
+	deadline := make(chan struct{})
+	go func() {
+		select {
+		case <-time.After(w.syncTimeout):
+		case <-rw.stop:
+		}
+		close(deadline)
+	}()
+	if cache.WaitForCacheSync(deadline, rw.informer.HasSynced) {
+		return
+	}
+
+	w.mutex.Lock()
+	current := w.watches[cd] == rw && rw.failed.IsZero()
+	if current {
+		close(rw.stop)
+		rw.failed = time.Now()
+	}
+	w.mutex.Unlock()
+	if !current {
+		// The watch was stopped or replaced
+		return
+	}
+	w.logger.WithField("clusterDeployment", cd.String()).WithField("resource", w.resource.String()).Warn("remote cluster objects were not listed in time, stopped watching")
+	// The watch is stopped, so the event is sent regardless
+	w.notify(cd, nil)
+}
+
+// notify sends an event for the ClusterDeployment, unless its watch was stopped
+func (w *remoteWatcher) notify(cd types.NamespacedName, stop <-chan struct{}) {
+	obj := &hivev1.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cd.Namespace,
+			Name:      cd.Name,
+		},
+	}
+	select {
+	case w.events <- event.GenericEvent{Meta: obj, Object: obj}:
+	case <-stop:
+	}
+}
+
+func (w *remoteWatcher) List(cd types.NamespacedName) ([]*unstructured.Unstructured, bool) {
+	w.mutex.Lock()
+	rw, ok := w.watches[cd]
+	failed := ok && !rw.failed.IsZero()
+	w.mutex.Unlock()
+	if !ok || failed || !rw.informer.HasSynced() {
+		return nil, false
+	}
+	items := rw.informer.GetStore().List()
+	objects := make([]*unstructured.Unstructured, 0, len(items))
+	for _, item := range items {
+		if obj, ok := item.(*unstructured.Unstructured); ok {
+			objects = append(objects, obj.DeepCopy())
+		}
+	}
+	return objects, true
+}
+
+func (w *remoteWatcher) Stop(cd types.NamespacedName) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	rw, ok := w.watches[cd]
+	if !ok {
+		return
+	}
+	if rw.failed.IsZero() {
+		close(rw.stop)
+	}
+	delete(w.watches, cd)
+	w.logger.WithField("clusterDeployment", cd.String()).WithField("resource", w.resource.String()).Info("stopped watching remote cluster")
+	metricRemoteWatches.WithLabelValues(w.controllerName, w.resource.Resource).Set(float64(len(w.watches)))
+}
@@ -0,0 +1,164 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+)
+
+type fakeResourceClient struct {
+	dynamic.NamespaceableResourceInterface
+	list    *unstructured.UnstructuredList
+	watcher *watch.FakeWatcher
+	// block, when set, blocks lists until it is closed
+	block chan struct{}
+}
+
+func (c *fakeResourceClient) List(metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	if c.block != nil {
+		<-c.block
+	}
+	return c.list.DeepCopy(), nil
+}
+
+func (c *fakeResourceClient) Watch(metav1.ListOptions) (watch.Interface, error) {
+	return c.watcher, nil
+}
+
+type fakeDynamicClient struct {
+	dynamic.Interface
+	resource *fakeResourceClient
+}
+
+func (c *fakeDynamicClient) Resource(schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return c.resource
+}
+
+func testWatchedObject(name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("config.openshift.io/v1")
+	obj.SetKind("ClusterOperator")
+	obj.SetName(name)
+	obj.SetResourceVersion("1")
+	return obj
+}
+
+func TestRemoteWatcher(t *testing.T) {
+	cd := &hivev1.ClusterDeployment{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cluster"}}
+	key := types.NamespacedName{Namespace: "ns", Name: "cluster"}
+	resource := &fakeResourceClient{
+		list:    &unstructured.UnstructuredList{Items: []unstructured.Unstructured{*testWatchedObject("a")}},
+		watcher: watch.NewFake(),
+	}
+	clientsBuilt := 0
+	w := &remoteWatcher{
+		controllerName: "test",
+		resource:       schema.GroupVersionResource{Group: "config.openshift.io", Version: "v1", Resource: "clusteroperators"},
+		events:         make(chan event.GenericEvent),
+		syncTimeout:    remoteWatchSyncTimeout,
+		dynamicClientBuilder: func(string, string) (dynamic.Interface, error) {
+			clientsBuilt++
+			return &fakeDynamicClient{resource: resource}, nil
+		},
+		watches: map[types.NamespacedName]*remoteWatch{},
+		logger:  log.WithField("controller", "test"),
+	}
+
+	nextEvent := func() event.GenericEvent {
+		select {
+		case evt := <-w.events:
+			return evt
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for event")
+		}
+		return event.GenericEvent{}
+	}
+
+	_, synced := w.List(key)
+	assert.False(t, synced, "expected cluster that is not watched to not be synced")
+
+	require.NoError(t, w.Watch(cd, "kubeconfig"), "unexpected error watching cluster")
+	require.NoError(t, w.Watch(cd, "kubeconfig"), "unexpected error watching cluster")
+	assert.Equal(t, 1, clientsBuilt, "expected cluster to be watched once")
+
+	evt := nextEvent()
+	assert.Equal(t, "ns", evt.Meta.GetNamespace(), "unexpected event namespace")
+	assert.Equal(t, "cluster", evt.Meta.GetName(), "unexpected event name")
+	objects, synced := w.List(key)
+	require.True(t, synced, "expected objects to be listed")
+	require.Len(t, objects, 1, "unexpected watched objects")
+	assert.Equal(t, "a", objects[0].GetName(), "unexpected watched object")
+
+	resource.watcher.Add(testWatchedObject("b"))
+	nextEvent()
+	objects, _ = w.List(key)
+	assert.Len(t, objects, 2, "expected added object to be watched")
+
+	w.Stop(key)
+	_, synced = w.List(key)
+	assert.False(t, synced, "expected stopped cluster to not be synced")
+
+	require.NoError(t, w.Watch(cd, "kubeconfig"), "unexpected error watching cluster")
+	assert.Equal(t, 2, clientsBuilt, "expected cluster to be watched again after stopping")
+	require.NoError(t, w.Watch(cd, "changed-kubeconfig"), "unexpected error watching cluster")
+	assert.Equal(t, 3, clientsBuilt, "expected cluster to be watched again after the kubeconfig changed")
+	w.Stop(key)
+}
+
+func TestRemoteWatcherNeverSynced(t *testing.T) {
+	cd := &hivev1.ClusterDeployment{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cluster"}}
+	key := types.NamespacedName{Namespace: "ns", Name: "cluster"}
+	resource := &fakeResourceClient{
+		list:    &unstructured.UnstructuredList{},
+		watcher: watch.NewFake(),
+		block:   make(chan struct{}),
+	}
+	defer close(resource.block)
+	clientsBuilt := 0
+	w := &remoteWatcher{
+		controllerName: "test",
+		resource:       schema.GroupVersionResource{Group: "config.openshift.io", Version: "v1", Resource: "clusteroperators"},
+		resync:         time.Hour,
+		syncTimeout:    200 * time.Millisecond,
+		events:         make(chan event.GenericEvent),
+		dynamicClientBuilder: func(string, string) (dynamic.Interface, error) {
+			clientsBuilt++
+			return &fakeDynamicClient{resource: resource}, nil
+		},
+		watches: map[types.NamespacedName]*remoteWatch{},
+		logger:  log.WithField("controller", "test"),
+	}
+
+	require.NoError(t, w.Watch(cd, "kubeconfig"), "unexpected error watching cluster")
+	select {
+	case evt := <-w.events:
+		assert.Equal(t, "cluster", evt.Meta.GetName(), "unexpected event name")
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the watch to fail")
+	}
+
+	_, synced := w.List(key)
+	assert.False(t, synced, "expected cluster that was never listed to not be synced")
+	assert.Error(t, w.Watch(cd, "kubeconfig"), "expected error watching cluster that was never listed")
+	assert.Equal(t, 1, clientsBuilt, "expected failed watch not to be retried before the resync period")
+
+	require.NoError(t, w.Watch(cd, "changed-kubeconfig"), "unexpected error watching cluster with a new kubeconfig")
+	assert.Equal(t, 2, clientsBuilt, "expected cluster to be watched again after the kubeconfig changed")
+	w.Stop(key)
+	_, synced = w.List(key)
+	assert.False(t, synced, "expected stopped cluster to not be synced")
+}
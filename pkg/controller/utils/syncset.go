@@ -0,0 +1,224 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	apihelpers "github.com/openshift/hive/pkg/apis/helpers"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+)
+
+const (
+	// SyncSetInstanceSyncSetIndex is the name of the index of syncset instances by the syncset they are for
+	SyncSetInstanceSyncSetIndex = "spec.syncSetRef.name"
+
+	// SyncSetInstanceSelectorSyncSetIndex is the name of the index of syncset instances by the selectorsyncset they
+	// are for
+	SyncSetInstanceSelectorSyncSetIndex = "spec.selectorSyncSetRef.name"
+
+	// rolloutSnapshotSpecKey is the key of the spec kept in a rollout snapshot
+	rolloutSnapshotSpecKey = "spec"
+)
+
+// indexedManagers are the managers whose cache indexes syncset instances, as an index can only be added once
+var indexedManagers = struct {
+	sync.Mutex
+	managers map[manager.Manager]bool
+}{managers: map[manager.Manager]bool{}}
+
+// syncSetInstanceFailureConditions are the conditions that indicate a SyncSetInstance failed to apply its syncset
+var syncSetInstanceFailureConditions = []hivev1.SyncConditionType{
+	hivev1.ApplyFailureSyncCondition,
+	hivev1.DeletionFailedSyncCondition,
+	hivev1.UnknownObjectSyncCondition,
+	hivev1.TemplateExpansionFailureSyncCondition,
+}
+
+// IndexSyncSetInstances indexes the syncset instances cached by a manager by the syncset and the selectorsyncset they
+// are for. The indexes are only added once to every manager, so that all controllers that look up syncset instances
+// with them can call it.
+func IndexSyncSetInstances(mgr manager.Manager) error {
+	indexedManagers.Lock()
+	defer indexedManagers.Unlock()
+	if indexedManagers.managers[mgr] {
+		return nil
+	}
+	err := mgr.GetFieldIndexer().IndexField(&hivev1.SyncSetInstance{}, SyncSetInstanceSyncSetIndex, func(o runtime.Object) []string {
+		ssi := o.(*hivev1.SyncSetInstance)
+		if ssi.Spec.SyncSetRef == nil {
+			return nil
+		}
+		return []string{ssi.Spec.SyncSetRef.Name}
+	})
+	if err != nil {
+		return fmt.Errorf("cannot index syncsetinstances by syncset: %v", err)
+	}
+	err = mgr.GetFieldIndexer().IndexField(&hivev1.SyncSetInstance{}, SyncSetInstanceSelectorSyncSetIndex, func(o runtime.Object) []string {
+		ssi := o.(*hivev1.SyncSetInstance)
+		if ssi.Spec.SelectorSyncSetRef == nil {
+			return nil
+		}
+		return []string{ssi.Spec.SelectorSyncSetRef.Name}
+	})
+	if err != nil {
+		return fmt.Errorf("cannot index syncsetinstances by selectorsyncset: %v", err)
+	}
+	indexedManagers.managers[mgr] = true
+	return nil
+}
+
+// SelectorSyncSetContentSpec returns the spec of a SelectorSyncSet without the fields that only control how it is
+// rolled out or which clusters it applies to, so that changing them does not change the hash used to detect changes
+// to its contents.
+func SelectorSyncSetContentSpec(spec hivev1.SelectorSyncSetSpec) hivev1.SelectorSyncSetSpec {
+	spec.Rollout = nil
+	spec.ClusterMatch = nil
+	return spec
+}
+
+// RolloutSnapshotName returns the name of the ConfigMap in the Hive namespace which keeps the spec with the given hash
+// of a SelectorSyncSet with a rollout strategy. Clusters keep applying the spec that was rolled out to them from its
+// snapshot while a newer spec is waiting to be rolled out.
+func RolloutSnapshotName(selectorSyncSetName, hash string) string {
+	return apihelpers.GetResourceName(selectorSyncSetName, "rollout-"+hash)
+}
+
+// NewRolloutSnapshot returns the rollout snapshot of the spec with the given hash of a SelectorSyncSet. The snapshot
+// is owned by the SelectorSyncSet, so that it is deleted along with it.
+func NewRolloutSnapshot(selectorSyncSet *hivev1.SelectorSyncSet, hash string) (*corev1.ConfigMap, error) {
+	spec, err := json.Marshal(selectorSyncSet.Spec.SyncSetCommonSpec)
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      RolloutSnapshotName(selectorSyncSet.Name, hash),
+			Namespace: constants.HiveNamespace,
+			Labels: map[string]string{
+				constants.SelectorSyncSetUIDLabel: string(selectorSyncSet.UID),
+				constants.SyncSetHashLabel:        hash,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(selectorSyncSet, hivev1.SchemeGroupVersion.WithKind("SelectorSyncSet")),
+			},
+		},
+		Data: map[string]string{
+			rolloutSnapshotSpecKey: string(spec),
+		},
+	}, nil
+}
+
+// RolloutSnapshotSpec returns the SelectorSyncSet spec kept in a rollout snapshot
+func RolloutSnapshotSpec(snapshot *corev1.ConfigMap) (*hivev1.SyncSetCommonSpec, error) {
+	data, ok := snapshot.Data[rolloutSnapshotSpecKey]
+	if !ok {
+		return nil, fmt.Errorf("rollout snapshot %s/%s has no spec", snapshot.Namespace, snapshot.Name)
+	}
+	spec := &hivev1.SyncSetCommonSpec{}
+	if err := json.Unmarshal([]byte(data), spec); err != nil {
+		return nil, fmt.Errorf("cannot parse the spec of rollout snapshot %s/%s: %v", snapshot.Namespace, snapshot.Name, err)
+	}
+	return spec, nil
+}
+
+// SyncSetInstanceFailure returns the message of the first failure condition found in the status of a
+// SyncSetInstance, and whether one was found.
+func SyncSetInstanceFailure(ssi *hivev1.SyncSetInstance) (string, bool) {
+	if message, failed := syncFailureMessage(ssi.Status.Conditions); failed {
+		return message, true
+	}
+	for _, statuses := range [][]hivev1.SyncStatus{
+		ssi.Status.Resources,
+		ssi.Status.Patches,
+		ssi.Status.SecretReferences,
+		ssi.Status.ConfigMapReferences,
+		ssi.Status.Deletions,
+	} {
+		for _, syncStatus := range statuses {
+			if message, failed := syncFailureMessage(syncStatus.Conditions); failed {
+				return fmt.Sprintf("%s %s/%s: %s", syncStatus.Kind, syncStatus.Namespace, syncStatus.Name, message), true
+			}
+		}
+	}
+	return "", false
+}
+
+func syncFailureMessage(conditions []hivev1.SyncCondition) (string, bool) {
+	for _, conditionType := range syncSetInstanceFailureConditions {
+		condition := FindSyncCondition(conditions, conditionType)
+		if condition != nil && condition.Status == corev1.ConditionTrue {
+			return condition.Message, true
+		}
+	}
+	return "", false
+}
+
+// SyncObjectKey identifies a remote object managed by a SyncSet independently of the version of its API group
+type SyncObjectKey struct {
+	Group     string
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// syncObject is a remote object managed by a SyncSetInstance
+type syncObject struct {
+	syncStatus hivev1.SyncStatus
+	deletion   bool
+}
+
+// syncSetInstanceObjects returns the remote objects that a SyncSetInstance creates, updates or deletes according to its
+// status. Patches are not included as they are meant to modify objects managed elsewhere.
+func syncSetInstanceObjects(ssi *hivev1.SyncSetInstance) map[SyncObjectKey]syncObject {
+	objects := map[SyncObjectKey]syncObject{}
+	add := func(statuses []hivev1.SyncStatus, deletion bool) {
+		for _, syncStatus := range statuses {
+			gv, _ := schema.ParseGroupVersion(syncStatus.APIVersion)
+			key := SyncObjectKey{Group: gv.Group, Kind: syncStatus.Kind, Namespace: syncStatus.Namespace, Name: syncStatus.Name}
+			objects[key] = syncObject{syncStatus: syncStatus, deletion: deletion}
+		}
+	}
+	add(ssi.Status.Resources, false)
+	add(ssi.Status.SecretReferences, false)
+	add(ssi.Status.ConfigMapReferences, false)
+	add(ssi.Status.Deletions, true)
+	return objects
+}
+
+// SyncSetInstanceConflicts returns a sorted list of messages describing the remote objects that the SyncSetInstance
+// manages and that are also managed by one of the other SyncSetInstances with different content. Other SyncSetInstances
+// are expected to target the same cluster deployment. Two SyncSetInstances deleting the same object do not conflict.
+func SyncSetInstanceConflicts(ssi *hivev1.SyncSetInstance, others []*hivev1.SyncSetInstance) []string {
+	objects := syncSetInstanceObjects(ssi)
+	if len(objects) == 0 {
+		return nil
+	}
+	var conflicts []string
+	for _, other := range others {
+		if other.Name == ssi.Name && other.Namespace == ssi.Namespace {
+			continue
+		}
+		for key, otherObject := range syncSetInstanceObjects(other) {
+			object, ok := objects[key]
+			if !ok {
+				continue
+			}
+			if object.deletion == otherObject.deletion && (object.deletion || object.syncStatus.Hash == otherObject.syncStatus.Hash) {
+				continue
+			}
+			conflicts = append(conflicts, fmt.Sprintf("%s %s/%s is also managed by SyncSetInstance %s", key.Kind, key.Namespace, key.Name, other.Name))
+		}
+	}
+	sort.Strings(conflicts)
+	return conflicts
+}
@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+)
+
+func TestSyncSetInstanceConflicts(t *testing.T) {
+	cases := []struct {
+		name     string
+		ssi      *hivev1.SyncSetInstance
+		others   []*hivev1.SyncSetInstance
+		expected []string
+	}{
+		{
+			name: "no other syncset instances",
+			ssi:  testSyncSetInstanceWithResources("ssi1", syncStatus("v1", "ConfigMap", "foo", "hash1")),
+		},
+		{
+			name: "different objects",
+			ssi:  testSyncSetInstanceWithResources("ssi1", syncStatus("v1", "ConfigMap", "foo", "hash1")),
+			others: []*hivev1.SyncSetInstance{
+				testSyncSetInstanceWithResources("ssi2", syncStatus("v1", "ConfigMap", "bar", "hash2")),
+			},
+		},
+		{
+			name: "same object with different content",
+			ssi:  testSyncSetInstanceWithResources("ssi1", syncStatus("v1", "ConfigMap", "foo", "hash1")),
+			others: []*hivev1.SyncSetInstance{
+				testSyncSetInstanceWithResources("ssi2", syncStatus("v1", "ConfigMap", "foo", "hash2")),
+			},
+			expected: []string{"ConfigMap default/foo is also managed by SyncSetInstance ssi2"},
+		},
+		{
+			name: "same object with same content",
+			ssi:  testSyncSetInstanceWithResources("ssi1", syncStatus("v1", "ConfigMap", "foo", "hash1")),
+			others: []*hivev1.SyncSetInstance{
+				testSyncSetInstanceWithResources("ssi2", syncStatus("v1", "ConfigMap", "foo", "hash1")),
+			},
+		},
+		{
+			name: "same object in different API versions",
+			ssi:  testSyncSetInstanceWithResources("ssi1", syncStatus("apps/v1", "Deployment", "foo", "hash1")),
+			others: []*hivev1.SyncSetInstance{
+				testSyncSetInstanceWithResources("ssi2", syncStatus("apps/v1beta1", "Deployment", "foo", "hash2")),
+			},
+			expected: []string{"Deployment default/foo is also managed by SyncSetInstance ssi2"},
+		},
+		{
+			name: "object deleted by another syncset instance",
+			ssi:  testSyncSetInstanceWithResources("ssi1", syncStatus("v1", "ConfigMap", "foo", "hash1")),
+			others: []*hivev1.SyncSetInstance{
+				func() *hivev1.SyncSetInstance {
+					ssi := testSyncSetInstanceWithResources("ssi2")
+					ssi.Status.Deletions = []hivev1.SyncStatus{syncStatus("v1", "ConfigMap", "foo", "hash2")}
+					return ssi
+				}(),
+			},
+			expected: []string{"ConfigMap default/foo is also managed by SyncSetInstance ssi2"},
+		},
+		{
+			name: "object deleted by both syncset instances",
+			ssi: func() *hivev1.SyncSetInstance {
+				ssi := testSyncSetInstanceWithResources("ssi1")
+				ssi.Status.Deletions = []hivev1.SyncStatus{syncStatus("v1", "ConfigMap", "foo", "hash1")}
+				return ssi
+			}(),
+			others: []*hivev1.SyncSetInstance{
+				func() *hivev1.SyncSetInstance {
+					ssi := testSyncSetInstanceWithResources("ssi2")
+					ssi.Status.Deletions = []hivev1.SyncStatus{syncStatus("v1", "ConfigMap", "foo", "hash2")}
+					return ssi
+				}(),
+			},
+		},
+		{
+			name: "secret reference and resource",
+			ssi: func() *hivev1.SyncSetInstance {
+				ssi := testSyncSetInstanceWithResources("ssi1")
+				ssi.Status.SecretReferences = []hivev1.SyncStatus{syncStatus("v1", "Secret", "foo", "hash1")}
+				return ssi
+			}(),
+			others: []*hivev1.SyncSetInstance{
+				testSyncSetInstanceWithResources("ssi3", syncStatus("v1", "Secret", "foo", "hash3")),
+				testSyncSetInstanceWithResources("ssi2", syncStatus("v1", "Secret", "foo", "hash2")),
+			},
+			expected: []string{
+				"Secret default/foo is also managed by SyncSetInstance ssi2",
+				"Secret default/foo is also managed by SyncSetInstance ssi3",
+			},
+		},
+		{
+			name: "syncset instance itself is ignored",
+			ssi:  testSyncSetInstanceWithResources("ssi1", syncStatus("v1", "ConfigMap", "foo", "hash1")),
+			others: []*hivev1.SyncSetInstance{
+				testSyncSetInstanceWithResources("ssi1", syncStatus("v1", "ConfigMap", "foo", "hash2")),
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, SyncSetInstanceConflicts(tc.ssi, tc.others))
+		})
+	}
+}
+
+func testSyncSetInstanceWithResources(name string, resources ...hivev1.SyncStatus) *hivev1.SyncSetInstance {
+	return &hivev1.SyncSetInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "test-namespace",
+		},
+		Status: hivev1.SyncSetInstanceStatus{
+			Resources: resources,
+		},
+	}
+}
+
+func syncStatus(apiVersion, kind, name, hash string) hivev1.SyncStatus {
+	return hivev1.SyncStatus{
+		APIVersion: apiVersion,
+		Kind:       kind,
+		Name:       name,
+		Namespace:  "default",
+		Hash:       hash,
+	}
+}
@@ -9,10 +9,13 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 
 	machineapi "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
@@ -29,17 +32,17 @@ import (
 // BuildClusterAPIClientFromKubeconfig will return a kubeclient with metrics using the provided kubeconfig.
 // Controller name is required for metrics purposes.
 func BuildClusterAPIClientFromKubeconfig(kubeconfigData, controllerName string) (client.Client, error) {
-	config, err := clientcmd.Load([]byte(kubeconfigData))
-	if err != nil {
-		return nil, err
-	}
-	kubeConfig := clientcmd.NewDefaultClientConfig(*config, &clientcmd.ConfigOverrides{})
-	cfg, err := kubeConfig.ClientConfig()
+	cfg, err := restConfigFromKubeconfig(kubeconfigData)
 	if err != nil {
 		return nil, err
 	}
 	AddControllerMetricsTransportWrapper(cfg, controllerName, true)
+	return newClusterAPIClient(cfg, nil)
+}
 
+// newClusterAPIClient returns a kubeclient for the remote cluster of cfg. The REST mapper is discovered from the
+// cluster if mapper is nil.
+func newClusterAPIClient(cfg *rest.Config, mapper meta.RESTMapper) (client.Client, error) {
 	scheme, err := machineapi.SchemeBuilder.Build()
 	if err != nil {
 		return nil, err
@@ -55,9 +58,19 @@ func BuildClusterAPIClientFromKubeconfig(kubeconfigData, controllerName string)
 
 	return client.New(cfg, client.Options{
 		Scheme: scheme,
+		Mapper: mapper,
 	})
 }
 
+func restConfigFromKubeconfig(kubeconfigData string) (*rest.Config, error) {
+	config, err := clientcmd.Load([]byte(kubeconfigData))
+	if err != nil {
+		return nil, err
+	}
+	kubeConfig := clientcmd.NewDefaultClientConfig(*config, &clientcmd.ConfigOverrides{})
+	return kubeConfig.ClientConfig()
+}
+
 // HasUnreachableCondition returns true if the cluster deployment has the unreachable condition set to true.
 func HasUnreachableCondition(cd *hivev1.ClusterDeployment) bool {
 	condition := FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.UnreachableCondition)
@@ -67,15 +80,23 @@ func HasUnreachableCondition(cd *hivev1.ClusterDeployment) bool {
 	return false
 }
 
-// BuildDynamicClientFromKubeconfig returns a dynamic client with metrics, using the provided kubeconfig.
+// BuildKubeClientFromKubeconfig returns a kubernetes clientset with metrics, using the provided kubeconfig.
 // Controller name is required for metrics purposes.
-func BuildDynamicClientFromKubeconfig(kubeconfigData, controllerName string) (dynamic.Interface, error) {
-	config, err := clientcmd.Load([]byte(kubeconfigData))
+func BuildKubeClientFromKubeconfig(kubeconfigData, controllerName string) (kubernetes.Interface, error) {
+	cfg, err := restConfigFromKubeconfig(kubeconfigData)
 	if err != nil {
 		return nil, err
 	}
-	kubeConfig := clientcmd.NewDefaultClientConfig(*config, &clientcmd.ConfigOverrides{})
-	cfg, err := kubeConfig.ClientConfig()
+
+	AddControllerMetricsTransportWrapper(cfg, controllerName, true)
+
+	return kubernetes.NewForConfig(cfg)
+}
+
+// BuildDynamicClientFromKubeconfig returns a dynamic client with metrics, using the provided kubeconfig.
+// Controller name is required for metrics purposes.
+func BuildDynamicClientFromKubeconfig(kubeconfigData, controllerName string) (dynamic.Interface, error) {
+	cfg, err := restConfigFromKubeconfig(kubeconfigData)
 	if err != nil {
 		return nil, err
 	}
@@ -36,6 +36,12 @@ type Client interface {
 	ListComputeZones(ListComputeZonesOptions) (*compute.ZoneList, error)
 
 	ListComputeImages(ListComputeImagesOptions) (*compute.ImageList, error)
+
+	ListComputeInstances(ListComputeInstancesOptions) (*compute.InstanceAggregatedList, error)
+
+	GetTargetPool(region, targetPool string) (*compute.TargetPool, error)
+
+	GetTargetPoolHealth(region, targetPool, instance string) (*compute.TargetPoolInstanceHealth, error)
 }
 
 // ListManagedZonesOptions are the options for listing managed zones.
@@ -199,6 +205,44 @@ func (c *gcpClient) ListComputeImages(opts ListComputeImagesOptions) (*compute.I
 	return call.Do()
 }
 
+// ListComputeInstancesOptions are the options for listing compute instances.
+type ListComputeInstancesOptions struct {
+	MaxResults int64
+	PageToken  string
+	Filter     string
+}
+
+// ListComputeInstances lists the compute instances of all zones.
+func (c *gcpClient) ListComputeInstances(opts ListComputeInstancesOptions) (*compute.InstanceAggregatedList, error) {
+	ctx, cancel := contextWithTimeout(context.TODO())
+	defer cancel()
+
+	call := c.computeClient.Instances.AggregatedList(c.projectName).Filter(opts.Filter).Context(ctx)
+
+	if opts.MaxResults > 0 {
+		call.MaxResults(opts.MaxResults)
+	}
+	if opts.PageToken != "" {
+		call.PageToken(opts.PageToken)
+	}
+	return call.Do()
+}
+
+func (c *gcpClient) GetTargetPool(region, targetPool string) (*compute.TargetPool, error) {
+	ctx, cancel := contextWithTimeout(context.TODO())
+	defer cancel()
+
+	return c.computeClient.TargetPools.Get(c.projectName, region, targetPool).Context(ctx).Do()
+}
+
+// GetTargetPoolHealth gets the health of an instance of a target pool. The instance is the URL of the instance.
+func (c *gcpClient) GetTargetPoolHealth(region, targetPool, instance string) (*compute.TargetPoolInstanceHealth, error) {
+	ctx, cancel := contextWithTimeout(context.TODO())
+	defer cancel()
+
+	return c.computeClient.TargetPools.GetHealth(c.projectName, region, targetPool, &compute.InstanceReference{Instance: instance}).Context(ctx).Do()
+}
+
 // NewClient creates our client wrapper object for interacting with GCP.
 func NewClient(projectName string, authJSON []byte) (Client, error) {
 	c, err := newClient(authJSON)
@@ -166,3 +166,48 @@ func (mr *MockClientMockRecorder) ListComputeImages(arg0 interface{}) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListComputeImages", reflect.TypeOf((*MockClient)(nil).ListComputeImages), arg0)
 }
+
+// ListComputeInstances mocks base method
+func (m *MockClient) ListComputeInstances(arg0 gcpclient.ListComputeInstancesOptions) (*v1.InstanceAggregatedList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListComputeInstances", arg0)
+	ret0, _ := ret[0].(*v1.InstanceAggregatedList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListComputeInstances indicates an expected call of ListComputeInstances
+func (mr *MockClientMockRecorder) ListComputeInstances(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListComputeInstances", reflect.TypeOf((*MockClient)(nil).ListComputeInstances), arg0)
+}
+
+// GetTargetPool mocks base method
+func (m *MockClient) GetTargetPool(region, targetPool string) (*v1.TargetPool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTargetPool", region, targetPool)
+	ret0, _ := ret[0].(*v1.TargetPool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTargetPool indicates an expected call of GetTargetPool
+func (mr *MockClientMockRecorder) GetTargetPool(region, targetPool interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTargetPool", reflect.TypeOf((*MockClient)(nil).GetTargetPool), region, targetPool)
+}
+
+// GetTargetPoolHealth mocks base method
+func (m *MockClient) GetTargetPoolHealth(region, targetPool, instance string) (*v1.TargetPoolInstanceHealth, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTargetPoolHealth", region, targetPool, instance)
+	ret0, _ := ret[0].(*v1.TargetPoolInstanceHealth)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTargetPoolHealth indicates an expected call of GetTargetPoolHealth
+func (mr *MockClientMockRecorder) GetTargetPoolHealth(region, targetPool, instance interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTargetPoolHealth", reflect.TypeOf((*MockClient)(nil).GetTargetPoolHealth), region, targetPool, instance)
+}
@@ -491,6 +491,9 @@ func completeAzureDeprovisionJob(req *hivev1.ClusterDeprovision, job *batchv1.Jo
 
 }
 
+// completeGCPDeprovisionJob sets up the GCP deprovision job. The deprovision deletes the resources of the cluster
+// project named after the infra ID, so that an existing network is left in place. For a shared VPC network the
+// firewall rules of the cluster in the host project of the network are deleted as well.
 func completeGCPDeprovisionJob(req *hivev1.ClusterDeprovision, job *batchv1.Job) {
 	volumes := []corev1.Volume{}
 	volumeMounts := []corev1.VolumeMount{}
@@ -532,6 +535,9 @@ func completeGCPDeprovisionJob(req *hivev1.ClusterDeprovision, job *batchv1.Job)
 			VolumeMounts: volumeMounts,
 		},
 	}
+	if networkProjectID := req.Spec.Platform.GCP.NetworkProjectID; networkProjectID != "" {
+		containers[0].Args = append(containers[0].Args, "--gcp-network-project-id", networkProjectID)
+	}
 	job.Spec.Template.Spec.Containers = containers
 	job.Spec.Template.Spec.Volumes = volumes
 }
@@ -6,6 +6,7 @@ import (
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -21,6 +22,56 @@ func TestGenerateDeprovision(t *testing.T) {
 	assert.NotNil(t, job)
 }
 
+func TestGenerateGCPDeprovision(t *testing.T) {
+	cases := []struct {
+		name             string
+		networkProjectID string
+		expectedArgs     []string
+	}{
+		{
+			name: "cluster project network",
+			expectedArgs: []string{
+				"deprovision", "gcp", "--loglevel", "debug",
+				"--region", "us-east1",
+				"--gcp-project-id", "test-project",
+				"test-infra-id",
+			},
+		},
+		{
+			name:             "shared VPC network",
+			networkProjectID: "test-host-project",
+			expectedArgs: []string{
+				"deprovision", "gcp", "--loglevel", "debug",
+				"--region", "us-east1",
+				"--gcp-project-id", "test-project",
+				"test-infra-id",
+				"--gcp-network-project-id", "test-host-project",
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dr := testClusterDeprovision()
+			dr.Spec.Platform.AWS = nil
+			dr.Spec.Platform.GCP = &hivev1.GCPClusterDeprovision{
+				Region:           "us-east1",
+				ProjectID:        "test-project",
+				NetworkProjectID: tc.networkProjectID,
+				CredentialsSecretRef: &corev1.LocalObjectReference{
+					Name: "gcp-creds",
+				},
+			}
+			job, err := GenerateUninstallerJobForDeprovision(dr)
+			require.NoError(t, err, "unexpected error generating job")
+			require.Len(t, job.Spec.Template.Spec.Containers, 1, "expected a single container")
+			container := job.Spec.Template.Spec.Containers[0]
+			assert.Equal(t, tc.expectedArgs, container.Args, "unexpected deprovision args")
+			require.Len(t, job.Spec.Template.Spec.Volumes, 1, "expected credentials volume")
+			assert.Equal(t, "gcp-creds", job.Spec.Template.Spec.Volumes[0].Secret.SecretName, "unexpected credentials secret")
+		})
+	}
+}
+
 func testClusterDeprovision() *hivev1.ClusterDeprovision {
 	return &hivev1.ClusterDeprovision{
 		ObjectMeta: metav1.ObjectMeta{
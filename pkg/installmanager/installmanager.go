@@ -29,6 +29,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -46,6 +47,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
 
 	azuresession "github.com/openshift/installer/pkg/asset/installconfig/azure"
 	"github.com/openshift/installer/pkg/destroy/aws"
@@ -238,6 +240,11 @@ func (m *InstallManager) Run() error {
 	}
 	m.log.Infof("copied %s to %s", m.InstallConfigMountPath, destInstallConfigPath)
 
+	if err := setInstallConfigNetwork(destInstallConfigPath, cd); err != nil {
+		m.log.WithError(err).Error("error setting network of install-config.yaml")
+		return err
+	}
+
 	// If the cluster provision has an infraID set, this implies we failed an install
 	// and are re-trying. Cleanup any resources that may have been provisioned.
 	m.log.Info("cleaning up from past install attempts")
@@ -359,6 +366,86 @@ func (m *InstallManager) Run() error {
 	return nil
 }
 
+// setInstallConfigNetwork sets the existing network, the additional security groups of the compute pools and the
+// publishing strategy of a cluster deployment in its install config. The install config is edited as YAML since the
+// vendored installer types do not have these fields.
+func setInstallConfigNetwork(installConfigPath string, cd *hivev1.ClusterDeployment) error {
+	platformFields := map[string]interface{}{}
+	var computeFields map[string]interface{}
+	publishInternal := false
+	platformName := ""
+	switch {
+	case cd.Spec.Platform.AWS != nil:
+		platformName = "aws"
+		awsPlatform := cd.Spec.Platform.AWS
+		if len(awsPlatform.Subnets) > 0 {
+			subnets := make([]interface{}, len(awsPlatform.Subnets))
+			for i, subnet := range awsPlatform.Subnets {
+				subnets[i] = subnet
+			}
+			platformFields["subnets"] = subnets
+		}
+		if len(awsPlatform.AdditionalSecurityGroupIDs) > 0 {
+			securityGroups := make([]interface{}, len(awsPlatform.AdditionalSecurityGroupIDs))
+			for i, securityGroup := range awsPlatform.AdditionalSecurityGroupIDs {
+				securityGroups[i] = securityGroup
+			}
+			computeFields = map[string]interface{}{"additionalSecurityGroupIDs": securityGroups}
+		}
+		publishInternal = awsPlatform.Internal
+	case cd.Spec.Platform.GCP != nil:
+		platformName = "gcp"
+		gcpPlatform := cd.Spec.Platform.GCP
+		if gcpPlatform.Network != "" {
+			platformFields["network"] = gcpPlatform.Network
+			platformFields["controlPlaneSubnet"] = gcpPlatform.ControlPlaneSubnet
+			platformFields["computeSubnet"] = gcpPlatform.ComputeSubnet
+			if gcpPlatform.NetworkProjectID != "" {
+				platformFields["networkProjectID"] = gcpPlatform.NetworkProjectID
+			}
+		}
+	}
+	if len(platformFields) == 0 && len(computeFields) == 0 && !publishInternal {
+		return nil
+	}
+	data, err := ioutil.ReadFile(installConfigPath)
+	if err != nil {
+		return err
+	}
+	installConfig := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &installConfig); err != nil {
+		return errors.Wrap(err, "could not parse install config")
+	}
+	for key, value := range platformFields {
+		if err := unstructured.SetNestedField(installConfig, value, "platform", platformName, key); err != nil {
+			return errors.Wrapf(err, "could not set %s in install config", key)
+		}
+	}
+	if len(computeFields) > 0 {
+		// The fields are set on the platform of every compute pool, which are the worker machines created by the
+		// installer. The pools are a list, which SetNestedField cannot traverse, so they are edited in place.
+		pools, _ := installConfig["compute"].([]interface{})
+		for i, pool := range pools {
+			poolMap, ok := pool.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("unexpected compute pool %d in install config", i)
+			}
+			for key, value := range computeFields {
+				if err := unstructured.SetNestedField(poolMap, value, "platform", platformName, key); err != nil {
+					return errors.Wrapf(err, "could not set %s of compute pool %d in install config", key, i)
+				}
+			}
+		}
+	}
+	if publishInternal {
+		installConfig["publish"] = "Internal"
+	}
+	if data, err = yaml.Marshal(installConfig); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(installConfigPath, data, 0644)
+}
+
 func (m *InstallManager) waitForFiles(files []string) {
 	m.log.Infof("waiting for files to be available: %v", files)
 
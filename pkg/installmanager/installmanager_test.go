@@ -16,6 +16,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -24,9 +25,12 @@ import (
 	installertypes "github.com/openshift/installer/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
 
 	"github.com/openshift/hive/pkg/apis"
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	hivev1aws "github.com/openshift/hive/pkg/apis/hive/v1/aws"
+	hivev1gcp "github.com/openshift/hive/pkg/apis/hive/v1/gcp"
 )
 
 const (
@@ -425,6 +429,199 @@ last line with password in text`,
 
 }
 
+func TestSetInstallConfigNetwork(t *testing.T) {
+	const installConfig = `apiVersion: v1
+baseDomain: example.com
+metadata:
+  name: test-cluster
+compute:
+- name: worker
+  replicas: 3
+platform:
+  aws:
+    region: us-east-1
+`
+	tests := []struct {
+		name                   string
+		platform               *hivev1aws.Platform
+		expectedSubnets        []interface{}
+		expectedSecurityGroups []interface{}
+		expectedPublish        interface{}
+		expectUnmodified       bool
+	}{
+		{
+			name:             "installer provided network",
+			platform:         &hivev1aws.Platform{Region: "us-east-1"},
+			expectUnmodified: true,
+		},
+		{
+			name: "existing subnets",
+			platform: &hivev1aws.Platform{
+				Region:  "us-east-1",
+				Subnets: []string{"subnet-private", "subnet-public"},
+			},
+			expectedSubnets: []interface{}{"subnet-private", "subnet-public"},
+		},
+		{
+			name: "internal cluster",
+			platform: &hivev1aws.Platform{
+				Region:   "us-east-1",
+				Subnets:  []string{"subnet-private"},
+				Internal: true,
+			},
+			expectedSubnets: []interface{}{"subnet-private"},
+			expectedPublish: "Internal",
+		},
+		{
+			name: "additional security groups",
+			platform: &hivev1aws.Platform{
+				Region:                     "us-east-1",
+				Subnets:                    []string{"subnet-private", "subnet-public"},
+				AdditionalSecurityGroupIDs: []string{"sg-additional"},
+			},
+			expectedSubnets:        []interface{}{"subnet-private", "subnet-public"},
+			expectedSecurityGroups: []interface{}{"sg-additional"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tempDir, err := ioutil.TempDir("", "setinstallconfignetwork")
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer os.RemoveAll(tempDir)
+			installConfigPath := filepath.Join(tempDir, "install-config.yaml")
+			if !assert.NoError(t, ioutil.WriteFile(installConfigPath, []byte(installConfig), 0644)) {
+				return
+			}
+			cd := testClusterDeployment()
+			cd.Spec.Platform.AWS = test.platform
+
+			if !assert.NoError(t, setInstallConfigNetwork(installConfigPath, cd)) {
+				return
+			}
+
+			data, err := ioutil.ReadFile(installConfigPath)
+			if !assert.NoError(t, err) {
+				return
+			}
+			if test.expectUnmodified {
+				assert.Equal(t, installConfig, string(data), "unexpected modification of install config")
+				return
+			}
+			ic := map[string]interface{}{}
+			if assert.NoError(t, yaml.Unmarshal(data, &ic)) {
+				subnets, _, _ := unstructured.NestedSlice(ic, "platform", "aws", "subnets")
+				assert.Equal(t, test.expectedSubnets, subnets, "unexpected subnets")
+				assert.Equal(t, test.expectedPublish, ic["publish"], "unexpected publishing strategy")
+				region, _, _ := unstructured.NestedString(ic, "platform", "aws", "region")
+				assert.Equal(t, "us-east-1", region, "unexpected region")
+				pools, _, _ := unstructured.NestedSlice(ic, "compute")
+				if assert.Len(t, pools, 1, "unexpected compute pools") {
+					pool := pools[0].(map[string]interface{})
+					securityGroups, _, _ := unstructured.NestedSlice(pool, "platform", "aws", "additionalSecurityGroupIDs")
+					assert.Equal(t, test.expectedSecurityGroups, securityGroups, "unexpected additional security groups")
+					assert.Equal(t, "worker", pool["name"], "unexpected compute pool name")
+				}
+			}
+		})
+	}
+}
+
+func TestSetInstallConfigGCPNetwork(t *testing.T) {
+	const installConfig = `apiVersion: v1
+baseDomain: example.com
+metadata:
+  name: test-cluster
+platform:
+  gcp:
+    projectID: test-project
+    region: us-east1
+`
+	tests := []struct {
+		name             string
+		platform         *hivev1gcp.Platform
+		expectedPlatform map[string]interface{}
+		expectUnmodified bool
+	}{
+		{
+			name:             "installer provided network",
+			platform:         &hivev1gcp.Platform{ProjectID: "test-project", Region: "us-east1"},
+			expectUnmodified: true,
+		},
+		{
+			name: "existing network",
+			platform: &hivev1gcp.Platform{
+				ProjectID:          "test-project",
+				Region:             "us-east1",
+				Network:            "test-network",
+				ControlPlaneSubnet: "test-master-subnet",
+				ComputeSubnet:      "test-worker-subnet",
+			},
+			expectedPlatform: map[string]interface{}{
+				"projectID":          "test-project",
+				"region":             "us-east1",
+				"network":            "test-network",
+				"controlPlaneSubnet": "test-master-subnet",
+				"computeSubnet":      "test-worker-subnet",
+			},
+		},
+		{
+			name: "shared network",
+			platform: &hivev1gcp.Platform{
+				ProjectID:          "test-project",
+				Region:             "us-east1",
+				Network:            "test-network",
+				NetworkProjectID:   "test-host-project",
+				ControlPlaneSubnet: "test-master-subnet",
+				ComputeSubnet:      "test-worker-subnet",
+			},
+			expectedPlatform: map[string]interface{}{
+				"projectID":          "test-project",
+				"region":             "us-east1",
+				"network":            "test-network",
+				"networkProjectID":   "test-host-project",
+				"controlPlaneSubnet": "test-master-subnet",
+				"computeSubnet":      "test-worker-subnet",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tempDir, err := ioutil.TempDir("", "setinstallconfignetwork")
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer os.RemoveAll(tempDir)
+			installConfigPath := filepath.Join(tempDir, "install-config.yaml")
+			if !assert.NoError(t, ioutil.WriteFile(installConfigPath, []byte(installConfig), 0644)) {
+				return
+			}
+			cd := testClusterDeployment()
+			cd.Spec.Platform.GCP = test.platform
+
+			if !assert.NoError(t, setInstallConfigNetwork(installConfigPath, cd)) {
+				return
+			}
+
+			data, err := ioutil.ReadFile(installConfigPath)
+			if !assert.NoError(t, err) {
+				return
+			}
+			if test.expectUnmodified {
+				assert.Equal(t, installConfig, string(data), "unexpected modification of install config")
+				return
+			}
+			ic := map[string]interface{}{}
+			if assert.NoError(t, yaml.Unmarshal(data, &ic)) {
+				platform, _, _ := unstructured.NestedMap(ic, "platform", "gcp")
+				assert.Equal(t, test.expectedPlatform, platform, "unexpected gcp platform")
+				assert.Nil(t, ic["publish"], "unexpected publishing strategy")
+			}
+		})
+	}
+}
+
 func TestGatherLogs(t *testing.T) {
 	fakeBootstrapIP := "1.2.3.4"
 
@@ -4,6 +4,7 @@
 // config/hiveadmission/clusterdeployment-webhook.yaml
 // config/hiveadmission/clusterimageset-webhook.yaml
 // config/hiveadmission/clusterprovision-webhook.yaml
+// config/hiveadmission/clusterresourcecollection-webhook.yaml
 // config/hiveadmission/deployment.yaml
 // config/hiveadmission/dnszones-webhook.yaml
 // config/hiveadmission/hiveadmission_rbac_role.yaml
@@ -29,6 +30,7 @@
 // config/crds/hive_v1_clusterdeprovision.yaml
 // config/crds/hive_v1_clusterimageset.yaml
 // config/crds/hive_v1_clusterprovision.yaml
+// config/crds/hive_v1_clusterresourcecollection.yaml
 // config/crds/hive_v1_clusterstate.yaml
 // config/crds/hive_v1_dnsendpoint.yaml
 // config/crds/hive_v1_dnszone.yaml
@@ -248,6 +250,47 @@ func configHiveadmissionClusterprovisionWebhookYaml() (*asset, error) {
 	return a, nil
 }
 
+var _configHiveadmissionClusterresourcecollectionWebhookYaml = []byte(`---
+apiVersion: admissionregistration.k8s.io/v1beta1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: clusterresourcecollectionvalidators.admission.hive.openshift.io
+webhooks:
+- name: clusterresourcecollectionvalidators.admission.hive.openshift.io
+  clientConfig:
+    service:
+      # reach the webhook via the registered aggregated API
+      namespace: default
+      name: kubernetes
+      path: /apis/admission.hive.openshift.io/v1/clusterresourcecollectionvalidators
+  rules:
+  - operations:
+    - CREATE
+    - UPDATE
+    apiGroups:
+    - hive.openshift.io
+    apiVersions:
+    - v1
+    resources:
+    - clusterresourcecollections
+  failurePolicy: Fail
+`)
+
+func configHiveadmissionClusterresourcecollectionWebhookYamlBytes() ([]byte, error) {
+	return _configHiveadmissionClusterresourcecollectionWebhookYaml, nil
+}
+
+func configHiveadmissionClusterresourcecollectionWebhookYaml() (*asset, error) {
+	bytes, err := configHiveadmissionClusterresourcecollectionWebhookYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/hiveadmission/clusterresourcecollection-webhook.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 var _configHiveadmissionDeploymentYaml = []byte(`---
 # to create the namespace-reservation-server
 apiVersion: apps/v1
@@ -379,6 +422,7 @@ rules:
   - ""
   resources:
   - configmaps
+  - secrets
   verbs:
   - get
 - apiGroups:
@@ -404,7 +448,16 @@ rules:
   - subjectaccessreviews
   verbs:
   - create
-
+- apiGroups:
+  - hive.openshift.io
+  resources:
+  - clusterdeployments
+  - syncsets
+  - selectorsyncsets
+  verbs:
+  - get
+  - list
+  - watch
 `)
 
 func configHiveadmissionHiveadmission_rbac_roleYamlBytes() ([]byte, error) {
@@ -803,6 +856,7 @@ rules:
   - hive.openshift.io
   resources:
   - clusterimagesets
+  - clusterresourcecollections
   - hiveconfigs
   - selectorsyncsets
   - selectorsyncidentityproviders
@@ -1078,6 +1132,17 @@ rules:
   - update
   - patch
   - delete
+- apiGroups:
+  - hive.openshift.io
+  resources:
+  - clusterresourcecollections
+  - clusterresourcecollections/status
+  verbs:
+  - get
+  - list
+  - watch
+  - update
+  - patch
 - apiGroups:
   - hive.openshift.io
   resources:
@@ -1149,6 +1214,7 @@ rules:
   - hive.openshift.io
   resources:
   - syncsets
+  - syncsets/status
   verbs:
   - get
   - create
@@ -1161,6 +1227,7 @@ rules:
   - hive.openshift.io
   resources:
   - selectorsyncsets
+  - selectorsyncsets/status
   verbs:
   - get
   - create
@@ -1429,6 +1496,7 @@ rules:
   - syncsetinstances
   - clusterdeprovisions
   - clusterstates
+  - clusterresourcecollections
   verbs:
   - get
   - list
@@ -1785,6 +1853,14 @@ spec:
                 aws:
                   description: AWS is the configuration used when installing on AWS.
                   properties:
+                    additionalSecurityGroupIDs:
+                      description: AdditionalSecurityGroupIDs are the IDs of existing
+                        security groups of the VPC of the cluster that are attached
+                        to the worker machines created by the installer and by MachinePools,
+                        in addition to the worker security group of the cluster.
+                      items:
+                        type: string
+                      type: array
                     credentialsSecretRef:
                       description: CredentialsSecretRef refers to a secret that contains
                         the AWS account access credentials.
@@ -1809,6 +1885,16 @@ spec:
                               description: Type defines the type of the storage.
                               type: string
                           type: object
+                        spotMarketOptions:
+                          description: SpotMarketOptions allows the instances of the
+                            machine pool to be run as AWS Spot instances.
+                          properties:
+                            maxPrice:
+                              description: MaxPrice is the maximum hourly price to
+                                pay for an instance, in US dollars. Defaults to the
+                                On-Demand price of the instance type.
+                              type: string
+                          type: object
                         type:
                           description: InstanceType defines the ec2 instance type.
                             eg. m4-large
@@ -1820,10 +1906,26 @@ spec:
                             type: string
                           type: array
                       type: object
+                    internal:
+                      description: 'Internal makes the endpoints of the cluster only
+                        reachable from within its network, publishing them with private
+                        DNS records and internal load balancers ("publish: Internal"
+                        in the install config). Requires Subnets.'
+                      type: boolean
                     region:
                       description: Region specifies the AWS region where the cluster
                         will be created.
                       type: string
+                    subnets:
+                      description: Subnets are the IDs of existing subnets of a single
+                        VPC the cluster is installed in, instead of the installer
+                        creating a VPC for the cluster. Both the public and the private
+                        subnets of the zones of the cluster must be listed, unless
+                        the cluster is internal, in which case only private subnets
+                        are needed.
+                      items:
+                        type: string
+                      type: array
                     userTags:
                       description: UserTags specifies additional tags for AWS resources
                         created for the cluster.
@@ -1861,7 +1963,8 @@ spec:
                           type: string
                         zones:
                           description: Zones is list of availability zones that can
-                            be used. eg. ["1", "2", "3"]
+                            be used. eg. ["1", "2", "3"] Not every Azure region has
+                            availability zones, so the zones are required for MachinePools.
                           items:
                             type: string
                           type: array
@@ -1879,6 +1982,16 @@ spec:
                   description: GCP is the configuration used when installing on Google
                     Cloud Platform.
                   properties:
+                    computeSubnet:
+                      description: ComputeSubnet is the name of the existing subnet
+                        of Network for the compute machines, including those of MachinePools.
+                        Required when Network is set.
+                      type: string
+                    controlPlaneSubnet:
+                      description: ControlPlaneSubnet is the name of the existing
+                        subnet of Network for the control plane machines. Required
+                        when Network is set.
+                      type: string
                     credentialsSecretRef:
                       description: CredentialsSecretRef refers to a secret that contains
                         the GCP account access credentials.
@@ -1888,6 +2001,10 @@ spec:
                         used when installing on GCP for machine pools which do not
                         define their own platform configuration.
                       properties:
+                        preemptible:
+                          description: Preemptible runs the instances of the machine
+                            pool as preemptible VMs.
+                          type: boolean
                         type:
                           description: InstanceType defines the GCP instance type.
                             eg. n1-standard-4
@@ -1899,6 +2016,15 @@ spec:
                             type: string
                           type: array
                       type: object
+                    network:
+                      description: Network is the name of an existing VPC network
+                        the cluster is installed in, instead of the installer creating
+                        a network for the cluster.
+                      type: string
+                    networkProjectID:
+                      description: NetworkProjectID is the ID of the host project
+                        of a shared VPC network. Defaults to ProjectID.
+                      type: string
                     projectID:
                       description: ProjectID is the the project that will be used
                         for the cluster.
@@ -1971,6 +2097,89 @@ spec:
               description: CLIImage is the name of the oc cli image to use when installing
                 the target cluster
               type: string
+            cloudHealth:
+              description: CloudHealth contains what the cloud provider reported about
+                the instances and load balancers of the cluster when it was last found
+                unreachable. It is cleared once the cluster is reachable again.
+              properties:
+                controlPlaneInstances:
+                  description: ControlPlaneInstances contains the state of the control
+                    plane instances of the cluster.
+                  items:
+                    properties:
+                      id:
+                        description: ID is the ID of the instance in the cloud provider.
+                        type: string
+                      name:
+                        description: Name is the name of the instance.
+                        type: string
+                      reason:
+                        description: Reason is the reason the cloud provider gives
+                          for the last change of state of the instance.
+                        type: string
+                      state:
+                        description: State is the state of the instance, such as running
+                          or terminated.
+                        type: string
+                      zone:
+                        description: Zone is the availability zone of the instance.
+                        type: string
+                    type: object
+                  type: array
+                error:
+                  description: Error is the error querying the cloud provider, if
+                    any.
+                  type: string
+                loadBalancers:
+                  description: LoadBalancers contains the health of the targets of
+                    the load balancers of the cluster.
+                  items:
+                    properties:
+                      healthyTargets:
+                        description: HealthyTargets is the number of targets that
+                          pass the health checks of the load balancer.
+                        format: int64
+                        type: integer
+                      name:
+                        description: Name is the name of the load balancer, or of
+                          its target group when it has several.
+                        type: string
+                      unhealthyTargets:
+                        description: UnhealthyTargets is the number of targets that
+                          do not pass the health checks of the load balancer.
+                        format: int64
+                        type: integer
+                    type: object
+                  type: array
+                recentTerminations:
+                  description: RecentTerminations contains the instances of the cluster
+                    that the cloud provider reports as recently terminated or stopped.
+                  items:
+                    properties:
+                      id:
+                        description: ID is the ID of the instance in the cloud provider.
+                        type: string
+                      name:
+                        description: Name is the name of the instance.
+                        type: string
+                      reason:
+                        description: Reason is the reason the cloud provider gives
+                          for the last change of state of the instance.
+                        type: string
+                      state:
+                        description: State is the state of the instance, such as running
+                          or terminated.
+                        type: string
+                      zone:
+                        description: Zone is the availability zone of the instance.
+                        type: string
+                    type: object
+                  type: array
+                time:
+                  description: Time is when the cloud provider was queried.
+                  format: date-time
+                  type: string
+              type: object
             clusterVersionStatus:
               description: ClusterVersionStatus will hold a copy of the remote cluster's
                 ClusterVersion.Status
@@ -2181,6 +2390,38 @@ spec:
               description: ProvisionRef is a reference to the last ClusterProvision
                 created for the deployment
               type: object
+            reachabilityProbes:
+              description: ReachabilityProbes contains the results of the most recent
+                probes of whether the cluster can be reached, most recent first. A
+                probe run is only recorded when its outcome differs from the one before
+                it.
+              items:
+                properties:
+                  results:
+                    description: Results contains the result of every probe that was
+                      run.
+                    items:
+                      properties:
+                        error:
+                          description: Error is the error of a failed probe.
+                          type: string
+                        latency:
+                          description: Latency is how long the probe took.
+                          type: string
+                        probe:
+                          description: Probe is the probe that was run.
+                          type: string
+                        reachable:
+                          description: Reachable is whether the probe succeeded.
+                          type: boolean
+                      type: object
+                    type: array
+                  time:
+                    description: Time is when the cluster was probed.
+                    format: date-time
+                    type: string
+                type: object
+              type: array
             webConsoleURL:
               description: WebConsoleURL is the URL for the cluster's web console
                 UI.
@@ -2295,6 +2536,11 @@ spec:
                       description: CredentialsSecretRef is the GCP account credentials
                         to use for deprovisioning the cluster
                       type: object
+                    networkProjectID:
+                      description: NetworkProjectID is the ID of the host project
+                        of the shared VPC network of the cluster, whose firewall rules
+                        for the cluster are deprovisioned as well.
+                      type: string
                     projectID:
                       description: ProjectID is the ID of the GCP project in which
                         the cluster exists
@@ -2564,6 +2810,154 @@ func configCrdsHive_v1_clusterprovisionYaml() (*asset, error) {
 	return a, nil
 }
 
+var _configCrdsHive_v1_clusterresourcecollectionYaml = []byte(`apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  creationTimestamp: null
+  labels:
+    controller-tools.k8s.io: "1.0"
+  name: clusterresourcecollections.hive.openshift.io
+spec:
+  group: hive.openshift.io
+  names:
+    kind: ClusterResourceCollection
+    plural: clusterresourcecollections
+    shortNames:
+    - crc
+  scope: Cluster
+  subresources:
+    status: {}
+  validation:
+    openAPIV3Schema:
+      properties:
+        apiVersion:
+          description: 'APIVersion defines the versioned schema of this representation
+            of an object. Servers should convert recognized schemas to the latest
+            internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources'
+          type: string
+        kind:
+          description: 'Kind is a string value representing the REST resource this
+            object represents. Servers may infer this from the endpoint the client
+            submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds'
+          type: string
+        metadata:
+          type: object
+        spec:
+          properties:
+            clusterDeploymentSelector:
+              description: ClusterDeploymentSelector is a LabelSelector indicating
+                which clusters objects are collected from.
+              type: object
+            countOnly:
+              description: CountOnly indicates that only the number of objects of
+                each resource is recorded in the status, and the objects themselves
+                are not stored in ConfigMaps on the hub.
+              type: boolean
+            interval:
+              description: Interval is how often objects are collected from each cluster.
+                Defaults to 1 hour.
+              type: string
+            resources:
+              description: Resources is the list of remote resources to collect from
+                each cluster.
+              items:
+                properties:
+                  apiVersion:
+                    description: APIVersion is the group and version of the objects,
+                      e.g. "operators.coreos.com/v1alpha1".
+                    type: string
+                  kind:
+                    description: Kind is the kind of the objects, e.g. "Subscription".
+                    type: string
+                  labelSelector:
+                    description: LabelSelector limits collection to objects with matching
+                      labels.
+                    type: object
+                  namespace:
+                    description: Namespace limits collection to objects in a namespace.
+                      Objects in all namespaces are collected if it is empty.
+                    type: string
+                type: object
+              type: array
+          type: object
+        status:
+          properties:
+            clusters:
+              description: Clusters contains the result of the last collection from
+                each selected cluster.
+              items:
+                properties:
+                  clusterDeploymentName:
+                    description: ClusterDeploymentName is the name of the ClusterDeployment
+                      of the cluster.
+                    type: string
+                  clusterDeploymentNamespace:
+                    description: ClusterDeploymentNamespace is the namespace of the
+                      ClusterDeployment of the cluster.
+                    type: string
+                  configMapName:
+                    description: ConfigMapName is the name of the ConfigMap in the
+                      namespace of the ClusterDeployment that the collected objects
+                      are stored in. It is empty if only counts are collected.
+                    type: string
+                  error:
+                    description: Error is the error that prevented collection from
+                      the cluster, if any.
+                    type: string
+                  lastCollectionTime:
+                    description: LastCollectionTime is the last time that objects
+                      were collected from the cluster.
+                    format: date-time
+                    type: string
+                  resources:
+                    description: Resources contains the result of collecting each
+                      resource.
+                    items:
+                      properties:
+                        apiVersion:
+                          description: APIVersion is the group and version of the
+                            objects.
+                          type: string
+                        count:
+                          description: Count is the number of objects collected.
+                          format: int64
+                          type: integer
+                        error:
+                          description: Error is the error encountered collecting the
+                            objects, if any.
+                          type: string
+                        kind:
+                          description: Kind is the kind of the objects.
+                          type: string
+                      type: object
+                    type: array
+                type: object
+              type: array
+          type: object
+  version: v1
+status:
+  acceptedNames:
+    kind: ""
+    plural: ""
+  conditions: []
+  storedVersions: []
+`)
+
+func configCrdsHive_v1_clusterresourcecollectionYamlBytes() ([]byte, error) {
+	return _configCrdsHive_v1_clusterresourcecollectionYaml, nil
+}
+
+func configCrdsHive_v1_clusterresourcecollectionYaml() (*asset, error) {
+	bytes, err := configCrdsHive_v1_clusterresourcecollectionYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/crds/hive_v1_clusterresourcecollection.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 var _configCrdsHive_v1_clusterstateYaml = []byte(`apiVersion: apiextensions.k8s.io/v1beta1
 kind: CustomResourceDefinition
 metadata:
@@ -2598,6 +2992,38 @@ spec:
           type: object
         status:
           properties:
+            capacity:
+              description: Capacity summarizes the allocatable and requested compute
+                resources of the target cluster
+              properties:
+                allocatableCPU:
+                  description: AllocatableCPU is the total allocatable CPU of the
+                    nodes
+                  type: string
+                allocatableMemory:
+                  description: AllocatableMemory is the total allocatable memory of
+                    the nodes
+                  type: string
+                allocatablePods:
+                  description: AllocatablePods is the total number of pods the nodes
+                    can run
+                  format: int64
+                  type: integer
+                pendingPods:
+                  description: PendingPods is the number of pods in the Pending phase
+                  format: int64
+                  type: integer
+                pods:
+                  description: Pods is the number of pods that have not terminated
+                  format: int64
+                  type: integer
+                requestedCPU:
+                  description: RequestedCPU is the total CPU requested by pods
+                  type: string
+                requestedMemory:
+                  description: RequestedMemory is the total memory requested by pods
+                  type: string
+              type: object
             clusterOperators:
               description: ClusterOperators contains the state for every cluster operator
                 in the target cluster
@@ -2641,6 +3067,103 @@ spec:
               description: LastUpdated is the last time that operator state was updated
               format: date-time
               type: string
+            machines:
+              description: Machines summarizes the machines and machine sets of the
+                target cluster
+              properties:
+                failed:
+                  description: Failed is the number of machines that have a terminal
+                    error
+                  format: int64
+                  type: integer
+                machineSets:
+                  description: MachineSets contains the replica counts of every machine
+                    set
+                  items:
+                    properties:
+                      availableReplicas:
+                        description: AvailableReplicas is the number of available
+                          replicas
+                        format: int32
+                        type: integer
+                      name:
+                        description: Name is the name of the machine set
+                        type: string
+                      readyReplicas:
+                        description: ReadyReplicas is the number of replicas whose
+                          node is ready
+                        format: int32
+                        type: integer
+                      replicas:
+                        description: Replicas is the desired number of replicas
+                        format: int32
+                        type: integer
+                    type: object
+                  type: array
+                running:
+                  description: Running is the number of machines in the Running phase
+                  format: int64
+                  type: integer
+                total:
+                  description: Total is the number of machines
+                  format: int64
+                  type: integer
+              type: object
+            nodes:
+              description: Nodes summarizes the nodes of the target cluster
+              properties:
+                kubeletVersions:
+                  description: KubeletVersions is the number of nodes running each
+                    kubelet version
+                  items:
+                    properties:
+                      count:
+                        description: Count is the number of nodes running the version
+                        format: int64
+                        type: integer
+                      version:
+                        description: Version is the kubelet version
+                        type: string
+                    type: object
+                  type: array
+                notReady:
+                  description: NotReady is the number of nodes without a true Ready
+                    condition
+                  format: int64
+                  type: integer
+                ready:
+                  description: Ready is the number of nodes with a true Ready condition
+                  format: int64
+                  type: integer
+                roles:
+                  description: Roles summarizes the nodes with each node role. A node
+                    with several roles is counted for each of them.
+                  items:
+                    properties:
+                      ready:
+                        description: Ready is the number of nodes with the role that
+                          are ready
+                        format: int64
+                        type: integer
+                      role:
+                        description: Role is the node role, e.g. master or worker
+                        type: string
+                      total:
+                        description: Total is the number of nodes with the role
+                        format: int64
+                        type: integer
+                    type: object
+                  type: array
+                total:
+                  description: Total is the number of nodes
+                  format: int64
+                  type: integer
+              type: object
+            resourcesLastUpdated:
+              description: ResourcesLastUpdated is the last time that the node, machine
+                and capacity summaries were updated
+              format: date-time
+              type: string
           type: object
   version: v1
 status:
@@ -2984,6 +3507,40 @@ spec:
                       type: boolean
                   type: object
               type: object
+            clusterReachability:
+              description: ClusterReachability configures how Hive probes whether
+                installed clusters can be reached.
+              properties:
+                additionalProbes:
+                  description: 'AdditionalProbes are probes run along with the APIClient
+                    probe: "APIReadyz", "Console" and "IngressCanary". A cluster is
+                    marked unreachable when one of its API probes fails. The results
+                    of the Console and IngressCanary probes are only recorded, to
+                    tell whether the ingress of the cluster can be reached as well.'
+                  items:
+                    type: string
+                  type: array
+                maxUnreachableProbeInterval:
+                  description: MaxUnreachableProbeInterval is the longest time between
+                    probes of an unreachable cluster. Defaults to 2 hours.
+                  type: string
+                probeHistoryLimit:
+                  description: ProbeHistoryLimit is the number of probe results kept
+                    in the status of every ClusterDeployment. Defaults to 5.
+                  format: int64
+                  type: integer
+                reachableProbeInterval:
+                  description: ReachableProbeInterval is the time between probes of
+                    a reachable cluster. Defaults to 10 minutes.
+                  type: string
+                unreachableProbeBackoffFactor:
+                  description: 'UnreachableProbeBackoffFactor controls how often an
+                    unreachable cluster is probed: it is probed again once the time
+                    since its last probe exceeds the time it has been unreachable
+                    divided by this factor. Defaults to 4.'
+                  format: int64
+                  type: integer
+              type: object
             externalDNS:
               description: ExternalDNS specifies configuration for external-dns if
                 it is to be deployed by Hive. If absent, external-dns will not be
@@ -3040,6 +3597,33 @@ spec:
               items:
                 type: string
               type: array
+            syncSetApplier:
+              description: SyncSetApplier configures how resources and patches from
+                SyncSets and SelectorSyncSets are applied to target clusters.
+              properties:
+                fieldManager:
+                  description: FieldManager is the name of the field manager used
+                    with server-side apply. Defaults to "hive".
+                  type: string
+                forceConflicts:
+                  description: ForceConflicts makes server-side apply take ownership
+                    of fields owned by other field managers instead of failing to
+                    apply the resource.
+                  type: boolean
+                type:
+                  description: Type is the method used to apply resources and patches,
+                    "Kubectl" (default) or "ServerSide".
+                  type: string
+              type: object
+            syncSetConflictPolicy:
+              description: 'SyncSetConflictPolicy configures how SyncSets and SelectorSyncSets
+                that manage the same objects on a cluster are handled: "Report" (default)
+                or "Reject". SyncSetConflictPolicy "Report" indicates that conflicts
+                are only reported in the status of the SyncSetInstances and ClusterDeployments.
+                SyncSetConflictPolicy "Reject" indicates that in addition, the validating
+                webhooks reject SyncSets and SelectorSyncSets that conflict with existing
+                ones of equal or higher priority.'
+              type: string
           type: object
         status:
           properties:
@@ -3091,6 +3675,12 @@ spec:
   - JSONPath: .spec.replicas
     name: Replicas
     type: integer
+  - JSONPath: .status.replicas
+    name: Current
+    type: integer
+  - JSONPath: .status.readyReplicas
+    name: Ready
+    type: integer
   group: hive.openshift.io
   names:
     kind: MachinePool
@@ -3115,10 +3705,61 @@ spec:
           type: object
         spec:
           properties:
+            autoscaling:
+              description: Autoscaling is the details for auto-scaling the machine
+                pool. Replicas and autoscaling cannot be used together.
+              properties:
+                maxReplicas:
+                  description: MaxReplicas is the maximum number of replicas for the
+                    machine pool. It is spread across the MachineSets of the zones
+                    of the machine pool.
+                  format: int32
+                  type: integer
+                minReplicas:
+                  description: MinReplicas is the minimum number of replicas for the
+                    machine pool. It is spread across the MachineSets of the zones
+                    of the machine pool.
+                  format: int32
+                  type: integer
+              type: object
             clusterDeploymentRef:
               description: ClusterDeploymentRef references the cluster deployment
                 to which this machine pool belongs.
               type: object
+            healthCheck:
+              description: HealthCheck is the configuration for remediating the unhealthy
+                machines of the machine pool.
+              properties:
+                maxUnhealthy:
+                  anyOf:
+                  - type: string
+                  - type: integer
+                  description: MaxUnhealthy is the number or percentage of the machines
+                    of the machine pool that can be unhealthy for unhealthy machines
+                    to be remediated. eg. 40%. Defaults to 100%.
+                nodeStartupTimeout:
+                  description: NodeStartupTimeout is how long a machine can go without
+                    a node before it is unhealthy. Defaults to 10 minutes.
+                  type: string
+                unhealthyConditions:
+                  description: UnhealthyConditions are the node conditions that make
+                    a machine unhealthy once they last longer than their timeout.
+                    Defaults to the Ready condition being False or Unknown for 5 minutes.
+                  items:
+                    properties:
+                      status:
+                        description: Status is the status of the node condition.
+                        type: string
+                      timeout:
+                        description: Timeout is how long the node condition must last
+                          for the machine to be unhealthy. Must be positive.
+                        type: string
+                      type:
+                        description: Type is the type of the node condition.
+                        type: string
+                    type: object
+                  type: array
+              type: object
             labels:
               description: Map of label string keys and values that will be applied
                 to the created MachineSet's MachineSpec. This list will overwrite
@@ -3149,6 +3790,16 @@ spec:
                           description: Type defines the type of the storage.
                           type: string
                       type: object
+                    spotMarketOptions:
+                      description: SpotMarketOptions allows the instances of the machine
+                        pool to be run as AWS Spot instances.
+                      properties:
+                        maxPrice:
+                          description: MaxPrice is the maximum hourly price to pay
+                            for an instance, in US dollars. Defaults to the On-Demand
+                            price of the instance type.
+                          type: string
+                      type: object
                     type:
                       description: InstanceType defines the ec2 instance type. eg.
                         m4-large
@@ -3178,7 +3829,8 @@ spec:
                       type: string
                     zones:
                       description: Zones is list of availability zones that can be
-                        used. eg. ["1", "2", "3"]
+                        used. eg. ["1", "2", "3"] Not every Azure region has availability
+                        zones, so the zones are required for MachinePools.
                       items:
                         type: string
                       type: array
@@ -3186,6 +3838,10 @@ spec:
                 gcp:
                   description: GCP is the configuration used when installing on GCP.
                   properties:
+                    preemptible:
+                      description: Preemptible runs the instances of the machine pool
+                        as preemptible VMs.
+                      type: boolean
                     type:
                       description: InstanceType defines the GCP instance type. eg.
                         n1-standard-4
@@ -3200,7 +3856,8 @@ spec:
               type: object
             replicas:
               description: Replicas is the count of machines for this machine pool.
-                Default is 1.
+                Replicas and autoscaling cannot be used together. Default is 1, if
+                autoscaling is not used.
               format: int64
               type: integer
             taints:
@@ -3243,6 +3900,61 @@ spec:
                     type: string
                 type: object
               type: array
+            machineSets:
+              description: MachineSets is the status of the machine sets for the machine
+                pool on the remote cluster.
+              items:
+                properties:
+                  maxReplicas:
+                    description: MaxReplicas is the maximum number of replicas for
+                      the machine set, when the machine pool is autoscaled.
+                    format: int32
+                    type: integer
+                  minReplicas:
+                    description: MinReplicas is the minimum number of replicas for
+                      the machine set, when the machine pool is autoscaled.
+                    format: int32
+                    type: integer
+                  name:
+                    description: Name is the name of the machine set.
+                    type: string
+                  readyReplicas:
+                    description: ReadyReplicas is the number of replicas for the machine
+                      set that are ready.
+                    format: int32
+                    type: integer
+                  replicas:
+                    description: Replicas is the current number of replicas for the
+                      machine set.
+                    format: int32
+                    type: integer
+                  zone:
+                    description: Zone is the availability zone of the machine set.
+                    type: string
+                type: object
+              type: array
+            readyReplicas:
+              description: ReadyReplicas is the number of replicas for the machine
+                pool that are ready.
+              format: int32
+              type: integer
+            recentInterruptions:
+              description: RecentInterruptions is the number of spot or preemptible
+                instances of the machine pool that were recently interrupted by the
+                cloud provider.
+              format: int32
+              type: integer
+            replicas:
+              description: Replicas is the current number of replicas for the machine
+                pool.
+              format: int32
+              type: integer
+            subnetsCheckedGeneration:
+              description: SubnetsCheckedGeneration is the generation of the machine
+                pool whose subnets were last found, the result of which is the InvalidSubnets
+                condition.
+              format: int64
+              type: integer
           type: object
   version: v1
 status:
@@ -3838,6 +4550,19 @@ metadata:
     controller-tools.k8s.io: "1.0"
   name: selectorsyncsets.hive.openshift.io
 spec:
+  additionalPrinterColumns:
+  - JSONPath: .status.targetedClusters
+    name: Targeted
+    type: integer
+  - JSONPath: .status.appliedClusters
+    name: Applied
+    type: integer
+  - JSONPath: .status.failedClusters
+    name: Failed
+    type: integer
+  - JSONPath: .status.pendingClusters
+    name: Pending
+    type: integer
   group: hive.openshift.io
   names:
     kind: SelectorSyncSet
@@ -3845,6 +4570,8 @@ spec:
     shortNames:
     - sss
   scope: Cluster
+  subresources:
+    status: {}
   validation:
     openAPIV3Schema:
       properties:
@@ -3866,6 +4593,99 @@ spec:
               description: ClusterDeploymentSelector is a LabelSelector indicating
                 which clusters the SelectorSyncSet applies to in any namespace.
               type: object
+            clusterMatch:
+              description: ClusterMatch restricts the clusters the SelectorSyncSet
+                applies to, in addition to the ClusterDeploymentSelector, based on
+                the version, platform and region of the clusters. Clusters are evaluated
+                again when they change, e.g. when they are upgraded.
+              properties:
+                platforms:
+                  description: 'Platforms is the list of platforms of the matching
+                    clusters: "aws", "azure", "gcp" or "baremetal".'
+                  items:
+                    type: string
+                  type: array
+                regions:
+                  description: Regions is the list of regions of the matching clusters.
+                  items:
+                    type: string
+                  type: array
+                versionRange:
+                  description: VersionRange is a range of OpenShift versions which
+                    is matched against the desired version in the cluster version
+                    status of the ClusterDeployment. It is a space separated list
+                    of constraints which must all be met, e.g. ">=4.2.0 <4.4.0", and
+                    alternatives can be separated with "||". Supported operators are
+                    "=", "!=", ">", ">=", "<" and "<=". Clusters whose version is
+                    not known yet do not match.
+                  type: string
+              type: object
+            configMapReferences:
+              description: ConfigMapReferences is the list of configmaps to sync from
+                existing resources.
+              items:
+                properties:
+                  source:
+                    type: object
+                  target:
+                    type: object
+                type: object
+              type: array
+            deletions:
+              description: Deletions is the list of objects to delete from the cluster.
+                Like patches, a deletion is submitted again when it changes, when
+                it failed, or every 2 hours, so objects are removed again if they
+                are recreated.
+              items:
+                properties:
+                  apiVersion:
+                    description: APIVersion is the Group and Version of the object
+                      to be deleted.
+                    type: string
+                  kind:
+                    description: Kind is the Kind of the object to be deleted.
+                    type: string
+                  name:
+                    description: Name is the name of the object to be deleted.
+                    type: string
+                  namespace:
+                    description: Namespace is the Namespace in which the object to
+                      delete exists. Must be empty for cluster-scoped objects.
+                    type: string
+                  propagationPolicy:
+                    description: 'PropagationPolicy indicates how dependents of the
+                      object are deleted: "Orphan", "Background" or "Foreground".
+                      Defaults to the default policy of the object''s resource.'
+                    type: string
+                type: object
+              type: array
+            driftPolicy:
+              description: 'DriftPolicy indicates how changes made on the remote cluster
+                to objects created from Resources are handled: "Ignore" (default),
+                "Report" or "Revert". DriftPolicy "Ignore" indicates that objects
+                are only reapplied when changed or periodically. DriftPolicy "Report"
+                indicates that drifted objects are reported with a Drifted condition
+                but not reverted. DriftPolicy "Revert" indicates that drifted objects
+                are reported and reapplied immediately.'
+              type: string
+            enableResourceTemplates:
+              description: EnableResourceTemplates indicates that string values in
+                Resources and Patches are templates that are expanded against the
+                target ClusterDeployment before being applied. Templates use the Go
+                text/template syntax, e.g. "{{ .BaseDomain }}" or "{{ index .Labels
+                \"region\" }}". Available fields are ClusterDeploymentName, ClusterDeploymentNamespace,
+                ClusterName, BaseDomain, InfraID, ClusterID, Platform, Region and
+                Labels.
+              type: boolean
+            existingObjectPolicy:
+              description: 'ExistingObjectPolicy indicates how objects that already
+                exist on the cluster without being owned by this SyncSet are handled
+                when Resources, SecretReferences or ConfigMapReferences are applied:
+                "Adopt" (default) or "Refuse". ExistingObjectPolicy "Adopt" indicates
+                that existing objects are taken over by the SyncSet. ExistingObjectPolicy
+                "Refuse" indicates that existing objects are left untouched and reported
+                as failed.'
+              type: string
             patches:
               description: Patches is the list of patches to apply.
               items:
@@ -3911,6 +4731,31 @@ spec:
               items:
                 type: object
               type: array
+            rollout:
+              description: Rollout is the strategy used to roll out changes to the
+                SelectorSyncSet across the clusters it applies to. When not set, all
+                clusters are updated at once.
+              properties:
+                canarySelector:
+                  description: CanarySelector is a LabelSelector indicating the clusters
+                    that are updated first. Other clusters are only updated once the
+                    change has been applied successfully to all canary clusters.
+                  type: object
+                maxUnavailablePercentage:
+                  description: MaxUnavailablePercentage is the maximum percentage
+                    of the targeted clusters that can be updating at the same time.
+                    Clusters that failed to apply the change count as updating until
+                    they succeed. At least one cluster is always allowed to update.
+                    Defaults to 100.
+                  format: int64
+                  type: integer
+                pauseOnFailure:
+                  description: PauseOnFailure halts the rollout when any cluster that
+                    has been updated fails to apply the change. The rollout resumes
+                    when the failure is resolved, for example by changing the SelectorSyncSet
+                    again.
+                  type: boolean
+              type: object
             secretReferences:
               description: SecretReferences is the list of secrets to sync from existing
                 resources.
@@ -3924,6 +4769,73 @@ spec:
               type: array
           type: object
         status:
+          properties:
+            appliedClusters:
+              description: AppliedClusters is the number of targeted clusters to which
+                the current spec has been applied successfully.
+              format: int64
+              type: integer
+            failedClusters:
+              description: FailedClusters is the number of targeted clusters with
+                a failure applying or deleting objects.
+              format: int64
+              type: integer
+            failingClusters:
+              description: FailingClusters is the list of targeted clusters with a
+                failure, along with their first error.
+              items:
+                properties:
+                  message:
+                    description: Message is the message of the first failure found
+                      in the SyncSetInstance for the cluster.
+                    type: string
+                  name:
+                    description: Name is the name of the ClusterDeployment.
+                    type: string
+                  namespace:
+                    description: Namespace is the namespace of the ClusterDeployment.
+                    type: string
+                type: object
+              type: array
+            observedGeneration:
+              description: ObservedGeneration is the generation of the SyncSet or
+                SelectorSyncSet that this status was computed for.
+              format: int64
+              type: integer
+            pendingClusters:
+              description: PendingClusters is the number of targeted clusters to which
+                the current spec has not been applied yet.
+              format: int64
+              type: integer
+            rollout:
+              description: Rollout is the progress of rolling out the current spec
+                of a SelectorSyncSet with a rollout strategy.
+              properties:
+                allowedClusters:
+                  description: AllowedClusters is the list of clusters that have been
+                    allowed to update to the spec being rolled out, but whose SyncSetInstance
+                    has not been found updated yet. They count as updating along with
+                    the clusters whose SyncSetInstance has been updated but has not
+                    applied the spec yet.
+                  items:
+                    properties:
+                      name:
+                        description: Name is the name of the ClusterDeployment.
+                        type: string
+                      namespace:
+                        description: Namespace is the namespace of the ClusterDeployment.
+                        type: string
+                    type: object
+                  type: array
+                syncSetHash:
+                  description: SyncSetHash is the hash of the spec being rolled out.
+                  type: string
+              type: object
+            targetedClusters:
+              description: TargetedClusters is the number of installed clusters the
+                SyncSet or SelectorSyncSet applies to.
+              format: int64
+              type: integer
           type: object
   version: v1
 status:
@@ -4523,6 +5435,19 @@ metadata:
     controller-tools.k8s.io: "1.0"
   name: syncsets.hive.openshift.io
 spec:
+  additionalPrinterColumns:
+  - JSONPath: .status.targetedClusters
+    name: Targeted
+    type: integer
+  - JSONPath: .status.appliedClusters
+    name: Applied
+    type: integer
+  - JSONPath: .status.failedClusters
+    name: Failed
+    type: integer
+  - JSONPath: .status.pendingClusters
+    name: Pending
+    type: integer
   group: hive.openshift.io
   names:
     kind: SyncSet
@@ -4530,6 +5455,8 @@ spec:
     shortNames:
     - ss
   scope: Namespaced
+  subresources:
+    status: {}
   validation:
     openAPIV3Schema:
       properties:
@@ -4554,6 +5481,72 @@ spec:
               items:
                 type: object
               type: array
+            configMapReferences:
+              description: ConfigMapReferences is the list of configmaps to sync from
+                existing resources.
+              items:
+                properties:
+                  source:
+                    type: object
+                  target:
+                    type: object
+                type: object
+              type: array
+            deletions:
+              description: Deletions is the list of objects to delete from the cluster.
+                Like patches, a deletion is submitted again when it changes, when
+                it failed, or every 2 hours, so objects are removed again if they
+                are recreated.
+              items:
+                properties:
+                  apiVersion:
+                    description: APIVersion is the Group and Version of the object
+                      to be deleted.
+                    type: string
+                  kind:
+                    description: Kind is the Kind of the object to be deleted.
+                    type: string
+                  name:
+                    description: Name is the name of the object to be deleted.
+                    type: string
+                  namespace:
+                    description: Namespace is the Namespace in which the object to
+                      delete exists. Must be empty for cluster-scoped objects.
+                    type: string
+                  propagationPolicy:
+                    description: 'PropagationPolicy indicates how dependents of the
+                      object are deleted: "Orphan", "Background" or "Foreground".
+                      Defaults to the default policy of the object''s resource.'
+                    type: string
+                type: object
+              type: array
+            driftPolicy:
+              description: 'DriftPolicy indicates how changes made on the remote cluster
+                to objects created from Resources are handled: "Ignore" (default),
+                "Report" or "Revert". DriftPolicy "Ignore" indicates that objects
+                are only reapplied when changed or periodically. DriftPolicy "Report"
+                indicates that drifted objects are reported with a Drifted condition
+                but not reverted. DriftPolicy "Revert" indicates that drifted objects
+                are reported and reapplied immediately.'
+              type: string
+            enableResourceTemplates:
+              description: EnableResourceTemplates indicates that string values in
+                Resources and Patches are templates that are expanded against the
+                target ClusterDeployment before being applied. Templates use the Go
+                text/template syntax, e.g. "{{ .BaseDomain }}" or "{{ index .Labels
+                \"region\" }}". Available fields are ClusterDeploymentName, ClusterDeploymentNamespace,
+                ClusterName, BaseDomain, InfraID, ClusterID, Platform, Region and
+                Labels.
+              type: boolean
+            existingObjectPolicy:
+              description: 'ExistingObjectPolicy indicates how objects that already
+                exist on the cluster without being owned by this SyncSet are handled
+                when Resources, SecretReferences or ConfigMapReferences are applied:
+                "Adopt" (default) or "Refuse". ExistingObjectPolicy "Adopt" indicates
+                that existing objects are taken over by the SyncSet. ExistingObjectPolicy
+                "Refuse" indicates that existing objects are left untouched and reported
+                as failed.'
+              type: string
             patches:
               description: Patches is the list of patches to apply.
               items:
@@ -4614,6 +5607,49 @@ spec:
           - clusterDeploymentRefs
           type: object
         status:
+          properties:
+            appliedClusters:
+              description: AppliedClusters is the number of targeted clusters to which
+                the current spec has been applied successfully.
+              format: int64
+              type: integer
+            failedClusters:
+              description: FailedClusters is the number of targeted clusters with
+                a failure applying or deleting objects.
+              format: int64
+              type: integer
+            failingClusters:
+              description: FailingClusters is the list of targeted clusters with a
+                failure, along with their first error.
+              items:
+                properties:
+                  message:
+                    description: Message is the message of the first failure found
+                      in the SyncSetInstance for the cluster.
+                    type: string
+                  name:
+                    description: Name is the name of the ClusterDeployment.
+                    type: string
+                  namespace:
+                    description: Namespace is the namespace of the ClusterDeployment.
+                    type: string
+                type: object
+              type: array
+            observedGeneration:
+              description: ObservedGeneration is the generation of the SyncSet or
+                SelectorSyncSet that this status was computed for.
+              format: int64
+              type: integer
+            pendingClusters:
+              description: PendingClusters is the number of targeted clusters to which
+                the current spec has not been applied yet.
+              format: int64
+              type: integer
+            targetedClusters:
+              description: TargetedClusters is the number of installed clusters the
+                SyncSet or SelectorSyncSet applies to.
+              format: int64
+              type: integer
           type: object
   version: v1
 status:
@@ -4701,6 +5737,10 @@ spec:
           type: object
         status:
           properties:
+            appliedSyncSetHash:
+              description: AppliedSyncSetHash is the SyncSetHash of the spec that
+                was last applied completely and without errors.
+              type: string
             conditions:
               description: Conditions is the list of SyncConditions used to indicate
                 UnknownObject when a resource type cannot be determined from a SyncSet
@@ -4732,6 +5772,130 @@ spec:
                     type: string
                 type: object
               type: array
+            configMapReferences:
+              description: ConfigMapReferences is the list of SyncStatus for configmaps
+                that have been synced.
+              items:
+                properties:
+                  apiVersion:
+                    description: APIVersion is the Group and Version of the object
+                      that was synced or patched.
+                    type: string
+                  conditions:
+                    description: Conditions is the list of conditions indicating success
+                      or failure of object create, update and delete as well as patch
+                      application.
+                    items:
+                      properties:
+                        lastProbeTime:
+                          description: LastProbeTime is the last time we probed the
+                            condition.
+                          format: date-time
+                          type: string
+                        lastTransitionTime:
+                          description: LastTransitionTime is the last time the condition
+                            transitioned from one status to another.
+                          format: date-time
+                          type: string
+                        message:
+                          description: Message is a human-readable message indicating
+                            details about last transition.
+                          type: string
+                        reason:
+                          description: Reason is a unique, one-word, CamelCase reason
+                            for the condition's last transition.
+                          type: string
+                        status:
+                          description: Status is the status of the condition.
+                          type: string
+                        type:
+                          description: Type is the type of the condition.
+                          type: string
+                      type: object
+                    type: array
+                  hash:
+                    description: Hash is the unique md5 hash of the resource or patch.
+                    type: string
+                  kind:
+                    description: Kind is the Kind of the object that was synced or
+                      patched.
+                    type: string
+                  name:
+                    description: Name is the name of the object that was synced or
+                      patched.
+                    type: string
+                  namespace:
+                    description: Namespace is the Namespace of the object that was
+                      synced or patched.
+                    type: string
+                  resource:
+                    description: Resource is the resource name for the object that
+                      was synced. This will be populated for resources, but not patches
+                    type: string
+                type: object
+              type: array
+            deletions:
+              description: Deletions is the list of SyncStatus for objects that have
+                been deleted.
+              items:
+                properties:
+                  apiVersion:
+                    description: APIVersion is the Group and Version of the object
+                      that was synced or patched.
+                    type: string
+                  conditions:
+                    description: Conditions is the list of conditions indicating success
+                      or failure of object create, update and delete as well as patch
+                      application.
+                    items:
+                      properties:
+                        lastProbeTime:
+                          description: LastProbeTime is the last time we probed the
+                            condition.
+                          format: date-time
+                          type: string
+                        lastTransitionTime:
+                          description: LastTransitionTime is the last time the condition
+                            transitioned from one status to another.
+                          format: date-time
+                          type: string
+                        message:
+                          description: Message is a human-readable message indicating
+                            details about last transition.
+                          type: string
+                        reason:
+                          description: Reason is a unique, one-word, CamelCase reason
+                            for the condition's last transition.
+                          type: string
+                        status:
+                          description: Status is the status of the condition.
+                          type: string
+                        type:
+                          description: Type is the type of the condition.
+                          type: string
+                      type: object
+                    type: array
+                  hash:
+                    description: Hash is the unique md5 hash of the resource or patch.
+                    type: string
+                  kind:
+                    description: Kind is the Kind of the object that was synced or
+                      patched.
+                    type: string
+                  name:
+                    description: Name is the name of the object that was synced or
+                      patched.
+                    type: string
+                  namespace:
+                    description: Namespace is the Namespace of the object that was
+                      synced or patched.
+                    type: string
+                  resource:
+                    description: Resource is the resource name for the object that
+                      was synced. This will be populated for resources, but not patches
+                    type: string
+                type: object
+              type: array
             patches:
               description: Patches is the list of SyncStatus for patches that have
                 been applied.
@@ -5044,46 +6208,48 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
-	"config/hiveadmission/apiservice.yaml":                      configHiveadmissionApiserviceYaml,
-	"config/hiveadmission/clusterdeployment-webhook.yaml":       configHiveadmissionClusterdeploymentWebhookYaml,
-	"config/hiveadmission/clusterimageset-webhook.yaml":         configHiveadmissionClusterimagesetWebhookYaml,
-	"config/hiveadmission/clusterprovision-webhook.yaml":        configHiveadmissionClusterprovisionWebhookYaml,
-	"config/hiveadmission/deployment.yaml":                      configHiveadmissionDeploymentYaml,
-	"config/hiveadmission/dnszones-webhook.yaml":                configHiveadmissionDnszonesWebhookYaml,
-	"config/hiveadmission/hiveadmission_rbac_role.yaml":         configHiveadmissionHiveadmission_rbac_roleYaml,
-	"config/hiveadmission/hiveadmission_rbac_role_binding.yaml": configHiveadmissionHiveadmission_rbac_role_bindingYaml,
-	"config/hiveadmission/machinepool-webhook.yaml":             configHiveadmissionMachinepoolWebhookYaml,
-	"config/hiveadmission/selectorsyncset-webhook.yaml":         configHiveadmissionSelectorsyncsetWebhookYaml,
-	"config/hiveadmission/service-account.yaml":                 configHiveadmissionServiceAccountYaml,
-	"config/hiveadmission/service.yaml":                         configHiveadmissionServiceYaml,
-	"config/hiveadmission/syncset-webhook.yaml":                 configHiveadmissionSyncsetWebhookYaml,
-	"config/manager/deployment.yaml":                            configManagerDeploymentYaml,
-	"config/manager/service.yaml":                               configManagerServiceYaml,
-	"config/rbac/hive_admin_role.yaml":                          configRbacHive_admin_roleYaml,
-	"config/rbac/hive_admin_role_binding.yaml":                  configRbacHive_admin_role_bindingYaml,
-	"config/rbac/hive_controllers_role.yaml":                    configRbacHive_controllers_roleYaml,
-	"config/rbac/hive_controllers_role_binding.yaml":            configRbacHive_controllers_role_bindingYaml,
-	"config/rbac/hive_frontend_role.yaml":                       configRbacHive_frontend_roleYaml,
-	"config/rbac/hive_frontend_role_binding.yaml":               configRbacHive_frontend_role_bindingYaml,
-	"config/rbac/hive_frontend_serviceaccount.yaml":             configRbacHive_frontend_serviceaccountYaml,
-	"config/rbac/hive_reader_role.yaml":                         configRbacHive_reader_roleYaml,
-	"config/rbac/hive_reader_role_binding.yaml":                 configRbacHive_reader_role_bindingYaml,
-	"config/crds/hive_v1_checkpoint.yaml":                       configCrdsHive_v1_checkpointYaml,
-	"config/crds/hive_v1_clusterdeployment.yaml":                configCrdsHive_v1_clusterdeploymentYaml,
-	"config/crds/hive_v1_clusterdeprovision.yaml":               configCrdsHive_v1_clusterdeprovisionYaml,
-	"config/crds/hive_v1_clusterimageset.yaml":                  configCrdsHive_v1_clusterimagesetYaml,
-	"config/crds/hive_v1_clusterprovision.yaml":                 configCrdsHive_v1_clusterprovisionYaml,
-	"config/crds/hive_v1_clusterstate.yaml":                     configCrdsHive_v1_clusterstateYaml,
-	"config/crds/hive_v1_dnsendpoint.yaml":                      configCrdsHive_v1_dnsendpointYaml,
-	"config/crds/hive_v1_dnszone.yaml":                          configCrdsHive_v1_dnszoneYaml,
-	"config/crds/hive_v1_hiveconfig.yaml":                       configCrdsHive_v1_hiveconfigYaml,
-	"config/crds/hive_v1_machinepool.yaml":                      configCrdsHive_v1_machinepoolYaml,
-	"config/crds/hive_v1_selectorsyncidentityprovider.yaml":     configCrdsHive_v1_selectorsyncidentityproviderYaml,
-	"config/crds/hive_v1_selectorsyncset.yaml":                  configCrdsHive_v1_selectorsyncsetYaml,
-	"config/crds/hive_v1_syncidentityprovider.yaml":             configCrdsHive_v1_syncidentityproviderYaml,
-	"config/crds/hive_v1_syncset.yaml":                          configCrdsHive_v1_syncsetYaml,
-	"config/crds/hive_v1_syncsetinstance.yaml":                  configCrdsHive_v1_syncsetinstanceYaml,
-	"config/configmaps/install-log-regexes-configmap.yaml":      configConfigmapsInstallLogRegexesConfigmapYaml,
+	"config/hiveadmission/apiservice.yaml":                        configHiveadmissionApiserviceYaml,
+	"config/hiveadmission/clusterdeployment-webhook.yaml":         configHiveadmissionClusterdeploymentWebhookYaml,
+	"config/hiveadmission/clusterimageset-webhook.yaml":           configHiveadmissionClusterimagesetWebhookYaml,
+	"config/hiveadmission/clusterprovision-webhook.yaml":          configHiveadmissionClusterprovisionWebhookYaml,
+	"config/hiveadmission/clusterresourcecollection-webhook.yaml": configHiveadmissionClusterresourcecollectionWebhookYaml,
+	"config/hiveadmission/deployment.yaml":                        configHiveadmissionDeploymentYaml,
+	"config/hiveadmission/dnszones-webhook.yaml":                  configHiveadmissionDnszonesWebhookYaml,
+	"config/hiveadmission/hiveadmission_rbac_role.yaml":           configHiveadmissionHiveadmission_rbac_roleYaml,
+	"config/hiveadmission/hiveadmission_rbac_role_binding.yaml":   configHiveadmissionHiveadmission_rbac_role_bindingYaml,
+	"config/hiveadmission/machinepool-webhook.yaml":               configHiveadmissionMachinepoolWebhookYaml,
+	"config/hiveadmission/selectorsyncset-webhook.yaml":           configHiveadmissionSelectorsyncsetWebhookYaml,
+	"config/hiveadmission/service-account.yaml":                   configHiveadmissionServiceAccountYaml,
+	"config/hiveadmission/service.yaml":                           configHiveadmissionServiceYaml,
+	"config/hiveadmission/syncset-webhook.yaml":                   configHiveadmissionSyncsetWebhookYaml,
+	"config/manager/deployment.yaml":                              configManagerDeploymentYaml,
+	"config/manager/service.yaml":                                 configManagerServiceYaml,
+	"config/rbac/hive_admin_role.yaml":                            configRbacHive_admin_roleYaml,
+	"config/rbac/hive_admin_role_binding.yaml":                    configRbacHive_admin_role_bindingYaml,
+	"config/rbac/hive_controllers_role.yaml":                      configRbacHive_controllers_roleYaml,
+	"config/rbac/hive_controllers_role_binding.yaml":              configRbacHive_controllers_role_bindingYaml,
+	"config/rbac/hive_frontend_role.yaml":                         configRbacHive_frontend_roleYaml,
+	"config/rbac/hive_frontend_role_binding.yaml":                 configRbacHive_frontend_role_bindingYaml,
+	"config/rbac/hive_frontend_serviceaccount.yaml":               configRbacHive_frontend_serviceaccountYaml,
+	"config/rbac/hive_reader_role.yaml":                           configRbacHive_reader_roleYaml,
+	"config/rbac/hive_reader_role_binding.yaml":                   configRbacHive_reader_role_bindingYaml,
+	"config/crds/hive_v1_checkpoint.yaml":                         configCrdsHive_v1_checkpointYaml,
+	"config/crds/hive_v1_clusterdeployment.yaml":                  configCrdsHive_v1_clusterdeploymentYaml,
+	"config/crds/hive_v1_clusterdeprovision.yaml":                 configCrdsHive_v1_clusterdeprovisionYaml,
+	"config/crds/hive_v1_clusterimageset.yaml":                    configCrdsHive_v1_clusterimagesetYaml,
+	"config/crds/hive_v1_clusterprovision.yaml":                   configCrdsHive_v1_clusterprovisionYaml,
+	"config/crds/hive_v1_clusterresourcecollection.yaml":          configCrdsHive_v1_clusterresourcecollectionYaml,
+	"config/crds/hive_v1_clusterstate.yaml":                       configCrdsHive_v1_clusterstateYaml,
+	"config/crds/hive_v1_dnsendpoint.yaml":                        configCrdsHive_v1_dnsendpointYaml,
+	"config/crds/hive_v1_dnszone.yaml":                            configCrdsHive_v1_dnszoneYaml,
+	"config/crds/hive_v1_hiveconfig.yaml":                         configCrdsHive_v1_hiveconfigYaml,
+	"config/crds/hive_v1_machinepool.yaml":                        configCrdsHive_v1_machinepoolYaml,
+	"config/crds/hive_v1_selectorsyncidentityprovider.yaml":       configCrdsHive_v1_selectorsyncidentityproviderYaml,
+	"config/crds/hive_v1_selectorsyncset.yaml":                    configCrdsHive_v1_selectorsyncsetYaml,
+	"config/crds/hive_v1_syncidentityprovider.yaml":               configCrdsHive_v1_syncidentityproviderYaml,
+	"config/crds/hive_v1_syncset.yaml":                            configCrdsHive_v1_syncsetYaml,
+	"config/crds/hive_v1_syncsetinstance.yaml":                    configCrdsHive_v1_syncsetinstanceYaml,
+	"config/configmaps/install-log-regexes-configmap.yaml":        configConfigmapsInstallLogRegexesConfigmapYaml,
 }
 
 // AssetDir returns the file names below a certain
@@ -5137,6 +6303,7 @@ var _bintree = &bintree{nil, map[string]*bintree{
 			"hive_v1_clusterdeprovision.yaml":           {configCrdsHive_v1_clusterdeprovisionYaml, map[string]*bintree{}},
 			"hive_v1_clusterimageset.yaml":              {configCrdsHive_v1_clusterimagesetYaml, map[string]*bintree{}},
 			"hive_v1_clusterprovision.yaml":             {configCrdsHive_v1_clusterprovisionYaml, map[string]*bintree{}},
+			"hive_v1_clusterresourcecollection.yaml":    {configCrdsHive_v1_clusterresourcecollectionYaml, map[string]*bintree{}},
 			"hive_v1_clusterstate.yaml":                 {configCrdsHive_v1_clusterstateYaml, map[string]*bintree{}},
 			"hive_v1_dnsendpoint.yaml":                  {configCrdsHive_v1_dnsendpointYaml, map[string]*bintree{}},
 			"hive_v1_dnszone.yaml":                      {configCrdsHive_v1_dnszoneYaml, map[string]*bintree{}},
@@ -5149,19 +6316,20 @@ var _bintree = &bintree{nil, map[string]*bintree{
 			"hive_v1_syncsetinstance.yaml":              {configCrdsHive_v1_syncsetinstanceYaml, map[string]*bintree{}},
 		}},
 		"hiveadmission": {nil, map[string]*bintree{
-			"apiservice.yaml":                      {configHiveadmissionApiserviceYaml, map[string]*bintree{}},
-			"clusterdeployment-webhook.yaml":       {configHiveadmissionClusterdeploymentWebhookYaml, map[string]*bintree{}},
-			"clusterimageset-webhook.yaml":         {configHiveadmissionClusterimagesetWebhookYaml, map[string]*bintree{}},
-			"clusterprovision-webhook.yaml":        {configHiveadmissionClusterprovisionWebhookYaml, map[string]*bintree{}},
-			"deployment.yaml":                      {configHiveadmissionDeploymentYaml, map[string]*bintree{}},
-			"dnszones-webhook.yaml":                {configHiveadmissionDnszonesWebhookYaml, map[string]*bintree{}},
-			"hiveadmission_rbac_role.yaml":         {configHiveadmissionHiveadmission_rbac_roleYaml, map[string]*bintree{}},
-			"hiveadmission_rbac_role_binding.yaml": {configHiveadmissionHiveadmission_rbac_role_bindingYaml, map[string]*bintree{}},
-			"machinepool-webhook.yaml":             {configHiveadmissionMachinepoolWebhookYaml, map[string]*bintree{}},
-			"selectorsyncset-webhook.yaml":         {configHiveadmissionSelectorsyncsetWebhookYaml, map[string]*bintree{}},
-			"service-account.yaml":                 {configHiveadmissionServiceAccountYaml, map[string]*bintree{}},
-			"service.yaml":                         {configHiveadmissionServiceYaml, map[string]*bintree{}},
-			"syncset-webhook.yaml":                 {configHiveadmissionSyncsetWebhookYaml, map[string]*bintree{}},
+			"apiservice.yaml":                        {configHiveadmissionApiserviceYaml, map[string]*bintree{}},
+			"clusterdeployment-webhook.yaml":         {configHiveadmissionClusterdeploymentWebhookYaml, map[string]*bintree{}},
+			"clusterimageset-webhook.yaml":           {configHiveadmissionClusterimagesetWebhookYaml, map[string]*bintree{}},
+			"clusterprovision-webhook.yaml":          {configHiveadmissionClusterprovisionWebhookYaml, map[string]*bintree{}},
+			"clusterresourcecollection-webhook.yaml": {configHiveadmissionClusterresourcecollectionWebhookYaml, map[string]*bintree{}},
+			"deployment.yaml":                        {configHiveadmissionDeploymentYaml, map[string]*bintree{}},
+			"dnszones-webhook.yaml":                  {configHiveadmissionDnszonesWebhookYaml, map[string]*bintree{}},
+			"hiveadmission_rbac_role.yaml":           {configHiveadmissionHiveadmission_rbac_roleYaml, map[string]*bintree{}},
+			"hiveadmission_rbac_role_binding.yaml":   {configHiveadmissionHiveadmission_rbac_role_bindingYaml, map[string]*bintree{}},
+			"machinepool-webhook.yaml":               {configHiveadmissionMachinepoolWebhookYaml, map[string]*bintree{}},
+			"selectorsyncset-webhook.yaml":           {configHiveadmissionSelectorsyncsetWebhookYaml, map[string]*bintree{}},
+			"service-account.yaml":                   {configHiveadmissionServiceAccountYaml, map[string]*bintree{}},
+			"service.yaml":                           {configHiveadmissionServiceYaml, map[string]*bintree{}},
+			"syncset-webhook.yaml":                   {configHiveadmissionSyncsetWebhookYaml, map[string]*bintree{}},
 		}},
 		"manager": {nil, map[string]*bintree{
 			"deployment.yaml": {configManagerDeploymentYaml, map[string]*bintree{}},
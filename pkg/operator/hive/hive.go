@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"context"
 	"crypto/md5"
+	"encoding/json"
 	"fmt"
 	"os"
+	"reflect"
 	"strconv"
 
 	log "github.com/sirupsen/logrus"
@@ -98,6 +100,39 @@ func (r *ReconcileHiveConfig) deployHive(hLog log.FieldLogger, h *resource.Helpe
 	}
 	hiveContainer.Env = append(hiveContainer.Env, logsEnvVar)
 
+	if applier := instance.Spec.SyncSetApplier; applier.Type != "" {
+		hiveContainer.Env = append(
+			hiveContainer.Env,
+			corev1.EnvVar{
+				Name:  constants.SyncSetApplierEnvVar,
+				Value: string(applier.Type),
+			},
+			corev1.EnvVar{
+				Name:  constants.SyncSetApplierFieldManagerEnvVar,
+				Value: applier.FieldManager,
+			},
+			corev1.EnvVar{
+				Name:  constants.SyncSetApplierForceConflictsEnvVar,
+				Value: strconv.FormatBool(applier.ForceConflicts),
+			},
+		)
+	}
+
+	if reachability := instance.Spec.ClusterReachability; !reflect.DeepEqual(reachability, hivev1.ClusterReachabilityConfig{}) {
+		reachabilityJSON, err := json.Marshal(reachability)
+		if err != nil {
+			hLog.WithError(err).Error("error marshalling cluster reachability config")
+			return err
+		}
+		hiveContainer.Env = append(
+			hiveContainer.Env,
+			corev1.EnvVar{
+				Name:  constants.ClusterReachabilityEnvVar,
+				Value: string(reachabilityJSON),
+			},
+		)
+	}
+
 	if zoneCheckDNSServers := os.Getenv(dnsServersEnvVar); len(zoneCheckDNSServers) > 0 {
 		dnsServersEnvVar := corev1.EnvVar{
 			Name:  dnsServersEnvVar,
@@ -149,6 +184,7 @@ func (r *ReconcileHiveConfig) deployHive(hLog log.FieldLogger, h *resource.Helpe
 		"config/crds/hive_v1_clusterdeployment.yaml",
 		"config/crds/hive_v1_clusterdeprovision.yaml",
 		"config/crds/hive_v1_clusterimageset.yaml",
+		"config/crds/hive_v1_clusterresourcecollection.yaml",
 		"config/crds/hive_v1_dnsendpoint.yaml",
 		"config/crds/hive_v1_dnszone.yaml",
 		"config/crds/hive_v1_hiveconfig.yaml",
@@ -81,6 +81,14 @@ func (r *ReconcileHiveConfig) deployHiveAdmission(hLog log.FieldLogger, h *resou
 		addManagedDomainsVolume(&hiveAdmDeployment.Spec.Template.Spec)
 	}
 
+	if instance.Spec.SyncSetConflictPolicy != "" {
+		hiveAdmContainer := &hiveAdmDeployment.Spec.Template.Spec.Containers[0]
+		hiveAdmContainer.Env = append(hiveAdmContainer.Env, corev1.EnvVar{
+			Name:  constants.SyncSetConflictPolicyEnvVar,
+			Value: string(instance.Spec.SyncSetConflictPolicy),
+		})
+	}
+
 	result, err := h.ApplyRuntimeObject(hiveAdmDeployment, scheme.Scheme)
 	if err != nil {
 		hLog.WithError(err).Error("error applying deployment")
@@ -98,6 +106,7 @@ func (r *ReconcileHiveConfig) deployHiveAdmission(hLog log.FieldLogger, h *resou
 		"config/hiveadmission/clusterdeployment-webhook.yaml",
 		"config/hiveadmission/clusterimageset-webhook.yaml",
 		"config/hiveadmission/clusterprovision-webhook.yaml",
+		"config/hiveadmission/clusterresourcecollection-webhook.yaml",
 		"config/hiveadmission/dnszones-webhook.yaml",
 		"config/hiveadmission/machinepool-webhook.yaml",
 		"config/hiveadmission/syncset-webhook.yaml",
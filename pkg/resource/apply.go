@@ -32,16 +32,35 @@ var (
 
 // Apply applies the given resource bytes to the target cluster specified by kubeconfig
 func (r *Helper) Apply(obj []byte) (ApplyResult, error) {
+	changeTracker, err := r.apply(obj, false)
+	if err != nil {
+		return "", err
+	}
+	return changeTracker.GetResult(), nil
+}
+
+// DryRunApply performs a server-side dry run of applying the given resource bytes to the target cluster specified by
+// kubeconfig. Nothing is persisted. It returns the result the apply would have and the resulting object as it would
+// be persisted.
+func (r *Helper) DryRunApply(obj []byte) (ApplyResult, runtime.Object, error) {
+	changeTracker, err := r.apply(obj, true)
+	if err != nil {
+		return "", nil, err
+	}
+	return changeTracker.GetResult(), changeTracker.GetObject(), nil
+}
+
+func (r *Helper) apply(obj []byte, serverDryRun bool) (*changeTracker, error) {
 	fileName, err := r.createTempFile("apply-", obj)
 	if err != nil {
 		r.logger.WithError(err).Error("failed to create temp file for apply")
-		return "", err
+		return nil, err
 	}
 	defer r.deleteTempFile(fileName)
 	factory, err := r.getFactory("")
 	if err != nil {
 		r.logger.WithError(err).Error("failed to obtain factory for apply")
-		return "", err
+		return nil, err
 	}
 	ioStreams := genericclioptions.IOStreams{
 		In:     &bytes.Buffer{},
@@ -51,16 +70,25 @@ func (r *Helper) Apply(obj []byte) (ApplyResult, error) {
 	applyOptions, changeTracker, err := r.setupApplyCommand(factory, fileName, ioStreams)
 	if err != nil {
 		r.logger.WithError(err).Error("failed to setup apply command")
-		return "", err
+		return nil, err
+	}
+	if serverDryRun {
+		applyOptions.ServerDryRun = true
+		// The discovery client is used to verify that the server supports dry run for each object
+		applyOptions.DiscoveryClient, err = factory.ToDiscoveryClient()
+		if err != nil {
+			r.logger.WithError(err).Error("cannot obtain discovery client from factory")
+			return nil, err
+		}
 	}
 	err = applyOptions.Run()
 	if err != nil {
 		r.logger.WithError(err).
 			WithField("stdout", ioStreams.Out.(*bytes.Buffer).String()).
 			WithField("stderr", ioStreams.ErrOut.(*bytes.Buffer).String()).Error("running the apply command failed")
-		return "", err
+		return nil, err
 	}
-	return changeTracker.GetResult(), nil
+	return changeTracker, nil
 }
 
 // ApplyRuntimeObject serializes an object and applies it to the target cluster specified by the kubeconfig.
@@ -111,6 +139,7 @@ func (r *Helper) setupApplyCommand(f cmdutil.Factory, fileName string, ioStreams
 
 type trackerPrinter struct {
 	setResult       func()
+	setObject       func(runtime.Object)
 	internalPrinter printers.ResourcePrinter
 }
 
@@ -118,11 +147,15 @@ func (p *trackerPrinter) PrintObj(o runtime.Object, w io.Writer) error {
 	if p.setResult != nil {
 		p.setResult()
 	}
+	if p.setObject != nil {
+		p.setObject(o)
+	}
 	return p.internalPrinter.PrintObj(o, w)
 }
 
 type changeTracker struct {
 	result            []ApplyResult
+	objects           []runtime.Object
 	internalToPrinter func(string) (printers.ResourcePrinter, error)
 }
 
@@ -133,6 +166,14 @@ func (t *changeTracker) GetResult() ApplyResult {
 	return UnknownApplyResult
 }
 
+// GetObject returns the applied object as returned by the server
+func (t *changeTracker) GetObject() runtime.Object {
+	if len(t.objects) == 1 {
+		return t.objects[0]
+	}
+	return nil
+}
+
 func (t *changeTracker) ToPrinter(name string) (printers.ResourcePrinter, error) {
 	var f func()
 	switch name {
@@ -150,5 +191,6 @@ func (t *changeTracker) ToPrinter(name string) (printers.ResourcePrinter, error)
 	return &trackerPrinter{
 		internalPrinter: p,
 		setResult:       f,
+		setObject:       func(o runtime.Object) { t.objects = append(t.objects, o) },
 	}, nil
 }
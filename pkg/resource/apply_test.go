@@ -0,0 +1,71 @@
+package resource
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+// TestChangeTracker tests the tracking of the result and object printed by the apply command, which Apply and
+// DryRunApply return
+func TestChangeTracker(t *testing.T) {
+	cases := []struct {
+		name           string
+		printed        []string
+		expectedResult ApplyResult
+		expectObject   bool
+	}{
+		{
+			name:           "created",
+			printed:        []string{"created"},
+			expectedResult: CreatedApplyResult,
+			expectObject:   true,
+		},
+		{
+			name:           "configured",
+			printed:        []string{"configured"},
+			expectedResult: ConfiguredApplyResult,
+			expectObject:   true,
+		},
+		{
+			name:           "unchanged",
+			printed:        []string{"unchanged"},
+			expectedResult: UnchangedApplyResult,
+			expectObject:   true,
+		},
+		{
+			name:           "nothing printed",
+			expectedResult: UnknownApplyResult,
+		},
+		{
+			name:           "multiple objects printed",
+			printed:        []string{"created", "configured"},
+			expectedResult: UnknownApplyResult,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tracker := &changeTracker{
+				internalToPrinter: func(string) (printers.ResourcePrinter, error) { return &printers.JSONPrinter{}, nil },
+			}
+			obj, err := decodeUnstructured([]byte(testConfigMap))
+			require.NoError(t, err, "unexpected error decoding test object")
+			for _, operation := range tc.printed {
+				printer, err := tracker.ToPrinter(operation)
+				require.NoError(t, err, "unexpected error getting printer")
+				require.NoError(t, printer.PrintObj(obj, &bytes.Buffer{}), "unexpected error printing object")
+			}
+			assert.Equal(t, tc.expectedResult, tracker.GetResult(), "unexpected result")
+			if tc.expectObject {
+				assert.Equal(t, runtime.Object(obj), tracker.GetObject(), "expected printed object to be returned")
+			} else {
+				assert.Nil(t, tracker.GetObject(), "unexpected object returned")
+			}
+		})
+	}
+}
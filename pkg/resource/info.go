@@ -14,6 +14,7 @@ type Info struct {
 	APIVersion string
 	Kind       string
 	Resource   string
+	Namespaced bool
 }
 
 // Info determines the name/namespace and type of the passed in resource bytes
@@ -29,6 +30,10 @@ func (r *Helper) Info(obj []byte) (*Info, error) {
 	return resourceInfo, err
 }
 
+// ResetRESTMapper does nothing, as every call to Info builds a REST mapper that discovers the resources of the cluster
+// again when a kind is not found in the discovery cache
+func (r *Helper) ResetRESTMapper() {}
+
 func (r *Helper) getResourceInfo(f cmdutil.Factory, obj []byte) (*Info, error) {
 	builder := f.NewBuilder()
 	infos, err := builder.Unstructured().Stream(bytes.NewBuffer(obj), "object").Flatten().Do().Infos()
@@ -46,5 +51,6 @@ func (r *Helper) getResourceInfo(f cmdutil.Factory, obj []byte) (*Info, error) {
 		Kind:       infos[0].ResourceMapping().GroupVersionKind.Kind,
 		APIVersion: infos[0].ResourceMapping().GroupVersionKind.GroupVersion().String(),
 		Resource:   infos[0].ResourceMapping().Resource.Resource,
+		Namespaced: infos[0].Namespaced(),
 	}, nil
 }
@@ -0,0 +1,247 @@
+package resource
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+const (
+	// DefaultFieldManager is the field manager used for server-side apply when none is configured
+	DefaultFieldManager = "hive"
+
+	defaultNamespace = "default"
+)
+
+// ServerSideApplyOptions configures a ServerSideApplier
+type ServerSideApplyOptions struct {
+	// FieldManager is the name of the field manager that owns the applied fields. Defaults to DefaultFieldManager.
+	FieldManager string
+
+	// ForceConflicts takes ownership of fields owned by other field managers instead of failing the apply.
+	ForceConflicts bool
+
+	// ControllerName is the controller the metrics of the requests to the cluster are tracked for.
+	ControllerName string
+}
+
+// ServerSideApplier applies objects using server-side apply through a dynamic client. It supports the same
+// operations as Helper without writing temp files or running kubectl commands.
+type ServerSideApplier struct {
+	logger        log.FieldLogger
+	options       ServerSideApplyOptions
+	dynamicClient dynamic.Interface
+	mapper        meta.RESTMapper
+}
+
+// NewServerSideApplier returns a new object that allows server-side apply and patch operations against the cluster
+// specified by kubeconfig. The dynamic client and RESTMapper of the cluster are obtained from
+// controllerutils.RemoteClusters, so that connections and API discovery are shared across reconciles.
+func NewServerSideApplier(kubeconfig []byte, options ServerSideApplyOptions, logger log.FieldLogger) (*ServerSideApplier, error) {
+	dynamicClient, err := controllerutils.RemoteClusters.DynamicClient(string(kubeconfig), options.ControllerName)
+	if err != nil {
+		logger.WithError(err).Error("cannot build dynamic client")
+		return nil, err
+	}
+	mapper, err := controllerutils.RemoteClusters.RESTMapper(string(kubeconfig))
+	if err != nil {
+		logger.WithError(err).Error("cannot build REST mapper")
+		return nil, err
+	}
+	return newServerSideApplier(dynamicClient, mapper, options, logger), nil
+}
+
+func newServerSideApplier(dynamicClient dynamic.Interface, mapper meta.RESTMapper, options ServerSideApplyOptions, logger log.FieldLogger) *ServerSideApplier {
+	if options.FieldManager == "" {
+		options.FieldManager = DefaultFieldManager
+	}
+	return &ServerSideApplier{
+		logger:        logger,
+		options:       options,
+		dynamicClient: dynamicClient,
+		mapper:        mapper,
+	}
+}
+
+// Apply applies the given resource bytes to the target cluster using server-side apply
+func (a *ServerSideApplier) Apply(obj []byte) (ApplyResult, error) {
+	result, _, err := a.apply(obj, false)
+	return result, err
+}
+
+// DryRunApply performs a server-side dry run of applying the given resource bytes to the target cluster. Nothing is
+// persisted. It returns the result the apply would have and the resulting object as it would be persisted.
+func (a *ServerSideApplier) DryRunApply(obj []byte) (ApplyResult, runtime.Object, error) {
+	return a.apply(obj, true)
+}
+
+// ApplyRuntimeObject serializes an object and applies it to the target cluster using server-side apply
+func (a *ServerSideApplier) ApplyRuntimeObject(obj runtime.Object, scheme *runtime.Scheme) (ApplyResult, error) {
+	data, err := serialize(obj, scheme)
+	if err != nil {
+		a.logger.WithError(err).Errorf("cannot serialize runtime object of type %T", obj)
+		return "", err
+	}
+	return a.Apply(data)
+}
+
+// Info determines the name/namespace and type of the passed in resource bytes
+func (a *ServerSideApplier) Info(obj []byte) (*Info, error) {
+	u, err := decodeUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	mapping, err := a.restMapping(u.GroupVersionKind())
+	if err != nil {
+		return nil, fmt.Errorf("could not get info from passed resource: %v", err)
+	}
+	return &Info{
+		Name:       u.GetName(),
+		Namespace:  u.GetNamespace(),
+		Kind:       mapping.GroupVersionKind.Kind,
+		APIVersion: mapping.GroupVersionKind.GroupVersion().String(),
+		Resource:   mapping.Resource.Resource,
+		Namespaced: mapping.Scope.Name() == meta.RESTScopeNameNamespace,
+	}, nil
+}
+
+// ResetRESTMapper lets kinds that were added to the cluster since its resources were last discovered, e.g. by a
+// CustomResourceDefinition that was just applied, be found right away
+func (a *ServerSideApplier) ResetRESTMapper() {
+	if mapper, ok := a.mapper.(controllerutils.ResettableRESTMapper); ok {
+		mapper.Reset()
+	}
+}
+
+// Patch patches the given object with the given patch and patch type
+func (a *ServerSideApplier) Patch(name types.NamespacedName, kind, apiVersion string, patch []byte, patchType string) error {
+	pt, ok := patchTypes[patchType]
+	if !ok {
+		return fmt.Errorf("invalid patch type %q, must be one of json, merge or strategic", patchType)
+	}
+	patchJSON, err := yaml.YAMLToJSON(patch)
+	if err != nil {
+		return fmt.Errorf("cannot parse patch: %v", err)
+	}
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		a.logger.WithError(err).WithField("groupVersion", apiVersion).Error("cannot parse group version")
+		return err
+	}
+	resourceClient, _, err := a.resourceClient(gv.WithKind(kind), name.Namespace)
+	if err != nil {
+		return err
+	}
+	_, err = resourceClient.Patch(name.Name, pt, patchJSON, metav1.PatchOptions{})
+	if err != nil {
+		a.logger.WithError(err).WithField("object", name).Error("patch failed")
+	}
+	return err
+}
+
+func (a *ServerSideApplier) apply(obj []byte, dryRun bool) (ApplyResult, runtime.Object, error) {
+	u, err := decodeUnstructured(obj)
+	if err != nil {
+		return "", nil, err
+	}
+	resourceClient, namespace, err := a.resourceClient(u.GroupVersionKind(), u.GetNamespace())
+	if err != nil {
+		return "", nil, err
+	}
+	u.SetNamespace(namespace)
+	data, err := u.MarshalJSON()
+	if err != nil {
+		return "", nil, err
+	}
+
+	existing, err := resourceClient.Get(u.GetName(), metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		a.logger.WithError(err).Error("cannot get existing object")
+		return "", nil, err
+	}
+	if errors.IsNotFound(err) {
+		existing = nil
+	}
+
+	options := metav1.PatchOptions{
+		FieldManager: a.options.FieldManager,
+		Force:        &a.options.ForceConflicts,
+	}
+	if dryRun {
+		options.DryRun = []string{metav1.DryRunAll}
+	}
+	applied, err := resourceClient.Patch(u.GetName(), types.ApplyPatchType, data, options)
+	if err != nil {
+		if errors.IsConflict(err) && !a.options.ForceConflicts {
+			a.logger.WithError(err).Warn("server-side apply conflicts with fields owned by another field manager")
+		} else {
+			a.logger.WithError(err).Error("server-side apply failed")
+		}
+		return "", nil, err
+	}
+
+	switch {
+	case existing == nil:
+		return CreatedApplyResult, applied, nil
+	case sameObject(existing, applied):
+		return UnchangedApplyResult, applied, nil
+	default:
+		return ConfiguredApplyResult, applied, nil
+	}
+}
+
+// resourceClient returns a dynamic client for the resource of the given kind, and the namespace the object lives in
+func (a *ServerSideApplier) resourceClient(gvk schema.GroupVersionKind, namespace string) (dynamic.ResourceInterface, string, error) {
+	mapping, err := a.restMapping(gvk)
+	if err != nil {
+		return nil, "", err
+	}
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return a.dynamicClient.Resource(mapping.Resource), "", nil
+	}
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	return a.dynamicClient.Resource(mapping.Resource).Namespace(namespace), namespace, nil
+}
+
+// restMapping returns the RESTMapping for a kind. The RESTMapper of the cluster discovers its resources again if the
+// kind is not known, as it may have been added by a CustomResourceDefinition.
+func (a *ServerSideApplier) restMapping(gvk schema.GroupVersionKind) (*meta.RESTMapping, error) {
+	return a.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+}
+
+func decodeUnstructured(obj []byte) (*unstructured.Unstructured, error) {
+	data, err := yaml.YAMLToJSON(obj)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse resource: %v", err)
+	}
+	u := &unstructured.Unstructured{}
+	if err := u.UnmarshalJSON(data); err != nil {
+		return nil, fmt.Errorf("cannot decode resource: %v", err)
+	}
+	return u, nil
+}
+
+// sameObject returns true if two versions of an object only differ in the metadata maintained by the server
+func sameObject(a, b *unstructured.Unstructured) bool {
+	a, b = a.DeepCopy(), b.DeepCopy()
+	for _, u := range []*unstructured.Unstructured{a, b} {
+		u.SetResourceVersion("")
+		unstructured.RemoveNestedField(u.Object, "metadata", "managedFields")
+	}
+	return equality.Semantic.DeepEqual(a.Object, b.Object)
+}
@@ -0,0 +1,286 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	testConfigMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: foo
+  namespace: bar
+data:
+  key: value
+`
+	testNamespace = `apiVersion: v1
+kind: Namespace
+metadata:
+  name: bar
+`
+)
+
+func TestServerSideApply(t *testing.T) {
+	cases := []struct {
+		name           string
+		existing       *unstructured.Unstructured
+		obj            string
+		dryRun         bool
+		forceConflicts bool
+		expectedResult ApplyResult
+		expectedKey    string
+		expectStored   bool
+	}{
+		{
+			name:           "created",
+			obj:            testConfigMap,
+			expectedResult: CreatedApplyResult,
+			expectedKey:    "configmaps/bar/foo",
+			expectStored:   true,
+		},
+		{
+			name:           "unchanged",
+			existing:       testObject(t, testConfigMap, "1"),
+			obj:            testConfigMap,
+			expectedResult: UnchangedApplyResult,
+			expectedKey:    "configmaps/bar/foo",
+			expectStored:   true,
+		},
+		{
+			name:           "configured",
+			existing:       testObject(t, testConfigMap, "1"),
+			obj:            testConfigMap + "  other: value\n",
+			expectedResult: ConfiguredApplyResult,
+			expectedKey:    "configmaps/bar/foo",
+			expectStored:   true,
+		},
+		{
+			name:           "dry run does not persist",
+			obj:            testConfigMap,
+			dryRun:         true,
+			expectedResult: CreatedApplyResult,
+			expectedKey:    "configmaps/bar/foo",
+		},
+		{
+			name: "namespaced object defaults to default namespace",
+			obj: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: foo
+`,
+			expectedResult: CreatedApplyResult,
+			expectedKey:    "configmaps/default/foo",
+			expectStored:   true,
+		},
+		{
+			name:           "cluster scoped object",
+			obj:            testNamespace,
+			forceConflicts: true,
+			expectedResult: CreatedApplyResult,
+			expectedKey:    "namespaces//bar",
+			expectStored:   true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newFakeApplyClient()
+			if tc.existing != nil {
+				client.objects[tc.expectedKey] = tc.existing
+			}
+			applier := newServerSideApplier(client, testRESTMapper(), ServerSideApplyOptions{ForceConflicts: tc.forceConflicts}, log.StandardLogger())
+
+			var (
+				result ApplyResult
+				err    error
+			)
+			if tc.dryRun {
+				result, _, err = applier.DryRunApply([]byte(tc.obj))
+			} else {
+				result, err = applier.Apply([]byte(tc.obj))
+			}
+			require.NoError(t, err, "unexpected error applying object")
+			assert.Equal(t, tc.expectedResult, result, "unexpected apply result")
+
+			require.Len(t, client.patches, 1, "expected a single apply patch")
+			patch := client.patches[0]
+			assert.Equal(t, tc.expectedKey, patch.key, "unexpected object applied")
+			assert.Equal(t, DefaultFieldManager, patch.options.FieldManager, "expected default field manager")
+			require.NotNil(t, patch.options.Force, "expected force option")
+			assert.Equal(t, tc.forceConflicts, *patch.options.Force, "unexpected force option")
+			if tc.dryRun {
+				assert.Equal(t, []string{metav1.DryRunAll}, patch.options.DryRun, "expected dry run")
+			} else {
+				assert.Empty(t, patch.options.DryRun, "unexpected dry run")
+			}
+			_, stored := client.objects[tc.expectedKey]
+			assert.Equal(t, tc.expectStored, stored, "unexpected stored object")
+		})
+	}
+}
+
+func TestServerSideApplyUnknownKind(t *testing.T) {
+	applier := newServerSideApplier(newFakeApplyClient(), testRESTMapper(), ServerSideApplyOptions{}, log.StandardLogger())
+	_, err := applier.Apply([]byte(`apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: foo
+`))
+	assert.True(t, meta.IsNoMatchError(err), "expected no match error for unknown kind, got %v", err)
+}
+
+func TestServerSideInfo(t *testing.T) {
+	applier := newServerSideApplier(newFakeApplyClient(), testRESTMapper(), ServerSideApplyOptions{}, log.StandardLogger())
+	info, err := applier.Info([]byte(testConfigMap))
+	require.NoError(t, err, "unexpected error getting info")
+	assert.Equal(t, &Info{
+		Name:       "foo",
+		Namespace:  "bar",
+		Kind:       "ConfigMap",
+		APIVersion: "v1",
+		Resource:   "configmaps",
+		Namespaced: true,
+	}, info, "unexpected info")
+}
+
+func TestServerSideResetRESTMapper(t *testing.T) {
+	mapper := &fakeResettableRESTMapper{RESTMapper: testRESTMapper()}
+	applier := newServerSideApplier(newFakeApplyClient(), mapper, ServerSideApplyOptions{}, log.StandardLogger())
+	applier.ResetRESTMapper()
+	assert.Equal(t, 1, mapper.resets, "expected REST mapper to be reset")
+}
+
+func TestServerSidePatch(t *testing.T) {
+	client := newFakeApplyClient()
+	applier := newServerSideApplier(client, testRESTMapper(), ServerSideApplyOptions{}, log.StandardLogger())
+
+	err := applier.Patch(types.NamespacedName{Namespace: "bar", Name: "foo"}, "ConfigMap", "v1", []byte("data:\n  key: value\n"), "merge")
+	require.NoError(t, err, "unexpected error patching")
+	require.Len(t, client.patches, 1, "expected a single patch")
+	assert.Equal(t, types.MergePatchType, client.patches[0].patchType, "unexpected patch type")
+	assert.JSONEq(t, `{"data":{"key":"value"}}`, string(client.patches[0].data), "expected patch converted to JSON")
+
+	err = applier.Patch(types.NamespacedName{Namespace: "bar", Name: "foo"}, "ConfigMap", "v1", []byte("{}"), "unknown")
+	assert.Error(t, err, "expected error for unknown patch type")
+}
+
+func TestSameObject(t *testing.T) {
+	a := testObject(t, testConfigMap, "1")
+	a.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "hive"}})
+
+	b := testObject(t, testConfigMap, "2")
+	b.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "hive"}, {Manager: "other"}})
+	assert.True(t, sameObject(a, b), "expected objects that only differ in server maintained metadata to be the same")
+	assert.Equal(t, "1", a.GetResourceVersion(), "expected compared object to be left unmodified")
+
+	b.SetLabels(map[string]string{"changed": "true"})
+	assert.False(t, sameObject(a, b), "expected objects with different labels to differ")
+
+	c := testObject(t, testConfigMap+"  other: value\n", "1")
+	assert.False(t, sameObject(a, c), "expected objects with different content to differ")
+}
+
+func testObject(t *testing.T, obj, resourceVersion string) *unstructured.Unstructured {
+	u, err := decodeUnstructured([]byte(obj))
+	require.NoError(t, err, "unexpected error decoding test object")
+	u.SetResourceVersion(resourceVersion)
+	return u
+}
+
+func testRESTMapper() meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+	return mapper
+}
+
+type fakeResettableRESTMapper struct {
+	meta.RESTMapper
+	resets int
+}
+
+func (m *fakeResettableRESTMapper) Reset() {
+	m.resets++
+}
+
+type fakePatch struct {
+	key       string
+	patchType types.PatchType
+	data      []byte
+	options   metav1.PatchOptions
+}
+
+// fakeApplyClient is a dynamic client that stores objects by resource, namespace and name. An apply patch replaces
+// the stored object and bumps its resource version if its content changed, like the API server does.
+type fakeApplyClient struct {
+	objects map[string]*unstructured.Unstructured
+	patches []fakePatch
+}
+
+func newFakeApplyClient() *fakeApplyClient {
+	return &fakeApplyClient{objects: map[string]*unstructured.Unstructured{}}
+}
+
+func (c *fakeApplyClient) Resource(resource schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return &fakeApplyResourceClient{client: c, resource: resource}
+}
+
+// fakeApplyResourceClient implements the operations used by ServerSideApplier, other operations panic
+type fakeApplyResourceClient struct {
+	dynamic.ResourceInterface
+	client    *fakeApplyClient
+	resource  schema.GroupVersionResource
+	namespace string
+}
+
+func (c *fakeApplyResourceClient) Namespace(namespace string) dynamic.ResourceInterface {
+	return &fakeApplyResourceClient{client: c.client, resource: c.resource, namespace: namespace}
+}
+
+func (c *fakeApplyResourceClient) key(name string) string {
+	return fmt.Sprintf("%s/%s/%s", c.resource.Resource, c.namespace, name)
+}
+
+func (c *fakeApplyResourceClient) Get(name string, options metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	obj, ok := c.client.objects[c.key(name)]
+	if !ok {
+		return nil, errors.NewNotFound(c.resource.GroupResource(), name)
+	}
+	return obj.DeepCopy(), nil
+}
+
+func (c *fakeApplyResourceClient) Patch(name string, pt types.PatchType, data []byte, options metav1.PatchOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	c.client.patches = append(c.client.patches, fakePatch{key: c.key(name), patchType: pt, data: data, options: options})
+	if pt != types.ApplyPatchType {
+		return &unstructured.Unstructured{}, nil
+	}
+	applied := &unstructured.Unstructured{}
+	if err := json.Unmarshal(data, &applied.Object); err != nil {
+		return nil, err
+	}
+	applied.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: options.FieldManager}})
+	applied.SetResourceVersion("1")
+	if existing, ok := c.client.objects[c.key(name)]; ok {
+		applied.SetResourceVersion(existing.GetResourceVersion())
+		if !sameObject(existing, applied) {
+			applied.SetResourceVersion(existing.GetResourceVersion() + "1")
+		}
+	}
+	if len(options.DryRun) == 0 {
+		c.client.objects[c.key(name)] = applied.DeepCopy()
+	}
+	return applied, nil
+}
@@ -0,0 +1,67 @@
+package syncsetmatch
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/syncsettemplate"
+)
+
+// Platforms are the platform names that can be matched by a SelectorSyncSet.
+var Platforms = []string{"aws", "azure", "gcp", "baremetal"}
+
+// Matches returns true if the SelectorSyncSet applies to the ClusterDeployment, i.e. the ClusterDeployment labels
+// match the ClusterDeploymentSelector and the cluster meets the ClusterMatch conditions.
+func Matches(selectorSyncSet *hivev1.SelectorSyncSet, cd *hivev1.ClusterDeployment) (bool, error) {
+	labelSelector, err := metav1.LabelSelectorAsSelector(&selectorSyncSet.Spec.ClusterDeploymentSelector)
+	if err != nil {
+		return false, err
+	}
+	if !labelSelector.Matches(labels.Set(cd.Labels)) {
+		return false, nil
+	}
+	return MatchesCluster(selectorSyncSet.Spec.ClusterMatch, cd)
+}
+
+// MatchesCluster returns true if the cluster of the ClusterDeployment meets all conditions of match. A nil match
+// matches all clusters.
+func MatchesCluster(match *hivev1.SelectorSyncSetClusterMatch, cd *hivev1.ClusterDeployment) (bool, error) {
+	if match == nil {
+		return true, nil
+	}
+	data := syncsettemplate.DataForClusterDeployment(cd)
+	if len(match.Platforms) > 0 && !contains(match.Platforms, data.Platform) {
+		return false, nil
+	}
+	if len(match.Regions) > 0 && !contains(match.Regions, data.Region) {
+		return false, nil
+	}
+	if match.VersionRange != "" {
+		versionRange, err := ParseVersionRange(match.VersionRange)
+		if err != nil {
+			return false, err
+		}
+		version, err := ParseVersion(cd.Status.ClusterVersionStatus.Desired.Version)
+		if err != nil {
+			// The version of the cluster is not known yet
+			return false, nil
+		}
+		return versionRange(version), nil
+	}
+	return true, nil
+}
+
+// IsSupportedPlatform returns true if platform is one of the platforms that can be matched.
+func IsSupportedPlatform(platform string) bool {
+	return contains(Platforms, platform)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
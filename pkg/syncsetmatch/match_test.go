@@ -0,0 +1,155 @@
+package syncsetmatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	openshiftapiv1 "github.com/openshift/api/config/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	hivev1aws "github.com/openshift/hive/pkg/apis/hive/v1/aws"
+)
+
+func testClusterDeployment(version string) *hivev1.ClusterDeployment {
+	return &hivev1.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: "bar",
+			Labels:    map[string]string{"env": "prod"},
+		},
+		Spec: hivev1.ClusterDeploymentSpec{
+			Platform: hivev1.Platform{
+				AWS: &hivev1aws.Platform{
+					Region: "us-east-1",
+				},
+			},
+		},
+		Status: hivev1.ClusterDeploymentStatus{
+			ClusterVersionStatus: openshiftapiv1.ClusterVersionStatus{
+				Desired: openshiftapiv1.Update{Version: version},
+			},
+		},
+	}
+}
+
+func TestParseVersionRange(t *testing.T) {
+	cases := []struct {
+		versionRange string
+		version      string
+		expected     bool
+		expectErr    bool
+	}{
+		{versionRange: ">=4.2.0 <4.4.0", version: "4.3.1", expected: true},
+		{versionRange: ">=4.2.0 <4.4.0", version: "4.4.0", expected: false},
+		{versionRange: ">=4.2.0 <4.4.0", version: "4.1.20", expected: false},
+		{versionRange: ">=4.2", version: "4.2.0", expected: true},
+		{versionRange: ">4.2", version: "4.2.0", expected: false},
+		{versionRange: "<=4.2.5", version: "4.2.5", expected: true},
+		{versionRange: "4.3.1", version: "4.3.1", expected: true},
+		{versionRange: "=4.3.1", version: "4.3.2", expected: false},
+		{versionRange: "!=4.3.1", version: "4.3.2", expected: true},
+		{versionRange: "<4.2 || >=4.4", version: "4.5.0", expected: true},
+		{versionRange: "<4.2 || >=4.4", version: "4.3.0", expected: false},
+		{versionRange: ">=4.4.0", version: "4.4.0-rc.1", expected: false},
+		{versionRange: ">=4.4.0-rc.2", version: "4.4.0-rc.10", expected: true},
+		{versionRange: ">=4.4.0-rc.1", version: "4.4.0-fc.1", expected: false},
+		{versionRange: "<4.4.0", version: "4.3.5+build.1", expected: true},
+		{versionRange: ">=4.x", expectErr: true},
+		{versionRange: ">=4.2 ||", expectErr: true},
+		{versionRange: "4.2.1.0", expectErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.versionRange+" "+tc.version, func(t *testing.T) {
+			versionRange, err := ParseVersionRange(tc.versionRange)
+			if tc.expectErr {
+				assert.Error(t, err, "expected invalid version range")
+				return
+			}
+			require.NoError(t, err, "unexpected error parsing version range")
+			version, err := ParseVersion(tc.version)
+			require.NoError(t, err, "unexpected error parsing version")
+			assert.Equal(t, tc.expected, versionRange(version), "unexpected match result")
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	cases := []struct {
+		name      string
+		selector  metav1.LabelSelector
+		match     *hivev1.SelectorSyncSetClusterMatch
+		version   string
+		expected  bool
+		expectErr bool
+	}{
+		{
+			name:     "no cluster match",
+			selector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+			expected: true,
+		},
+		{
+			name:     "labels do not match",
+			selector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "dev"}},
+			match:    &hivev1.SelectorSyncSetClusterMatch{Platforms: []string{"aws"}},
+			expected: false,
+		},
+		{
+			name:     "platform and region match",
+			match:    &hivev1.SelectorSyncSetClusterMatch{Platforms: []string{"gcp", "aws"}, Regions: []string{"us-east-1"}},
+			expected: true,
+		},
+		{
+			name:     "platform does not match",
+			match:    &hivev1.SelectorSyncSetClusterMatch{Platforms: []string{"azure"}},
+			expected: false,
+		},
+		{
+			name:     "region does not match",
+			match:    &hivev1.SelectorSyncSetClusterMatch{Regions: []string{"us-west-2"}},
+			expected: false,
+		},
+		{
+			name:     "version in range",
+			match:    &hivev1.SelectorSyncSetClusterMatch{VersionRange: ">=4.2 <4.4"},
+			version:  "4.3.8",
+			expected: true,
+		},
+		{
+			name:     "version out of range",
+			match:    &hivev1.SelectorSyncSetClusterMatch{VersionRange: ">=4.2 <4.4"},
+			version:  "4.4.2",
+			expected: false,
+		},
+		{
+			name:     "version not known",
+			match:    &hivev1.SelectorSyncSetClusterMatch{VersionRange: ">=4.2"},
+			expected: false,
+		},
+		{
+			name:      "invalid version range",
+			match:     &hivev1.SelectorSyncSetClusterMatch{VersionRange: "latest"},
+			version:   "4.3.8",
+			expectErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			sss := &hivev1.SelectorSyncSet{
+				Spec: hivev1.SelectorSyncSetSpec{
+					ClusterDeploymentSelector: tc.selector,
+					ClusterMatch:              tc.match,
+				},
+			}
+			matches, err := Matches(sss, testClusterDeployment(tc.version))
+			if tc.expectErr {
+				assert.Error(t, err, "expected error")
+				return
+			}
+			require.NoError(t, err, "unexpected error")
+			assert.Equal(t, tc.expected, matches, "unexpected match result")
+		})
+	}
+}
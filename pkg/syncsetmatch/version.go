@@ -0,0 +1,158 @@
+package syncsetmatch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version, e.g. 4.3.1 or 4.4.0-rc.2. Missing minor and patch numbers are 0.
+type Version struct {
+	numbers    [3]uint64
+	prerelease []string
+}
+
+// VersionRange returns true if a version is in the range.
+type VersionRange func(Version) bool
+
+// ParseVersion parses a semantic version. Build metadata is ignored.
+func ParseVersion(s string) (Version, error) {
+	v := Version{}
+	original := s
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.Index(s, "+"); i >= 0 {
+		s = s[:i]
+	}
+	if i := strings.Index(s, "-"); i >= 0 {
+		v.prerelease = strings.Split(s[i+1:], ".")
+		s = s[:i]
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) > len(v.numbers) {
+		return v, fmt.Errorf("invalid version %q", original)
+	}
+	for i, part := range parts {
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return v, fmt.Errorf("invalid version %q", original)
+		}
+		v.numbers[i] = n
+	}
+	return v, nil
+}
+
+// Compare returns -1, 0 or 1 if v is lower than, equal to or greater than other. Pre-release versions are lower than
+// the release they precede.
+func (v Version) Compare(other Version) int {
+	for i := range v.numbers {
+		if c := compareUint(v.numbers[i], other.numbers[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(v.prerelease) == 0 && len(other.prerelease) == 0:
+		return 0
+	case len(v.prerelease) == 0:
+		return 1
+	case len(other.prerelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(v.prerelease) && i < len(other.prerelease); i++ {
+		if c := comparePrereleaseIdentifier(v.prerelease[i], other.prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return compareUint(uint64(len(v.prerelease)), uint64(len(other.prerelease)))
+}
+
+// comparePrereleaseIdentifier compares numeric identifiers numerically and others lexically. Numeric identifiers are
+// lower than others.
+func comparePrereleaseIdentifier(a, b string) int {
+	an, aErr := strconv.ParseUint(a, 10, 64)
+	bn, bErr := strconv.ParseUint(b, 10, 64)
+	switch {
+	case aErr == nil && bErr == nil:
+		return compareUint(an, bn)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// versionOperators maps the operators of version constraints to the comparison results they accept. Longer
+// operators are listed first so that they are matched before their prefixes.
+var versionOperators = []struct {
+	operator string
+	accepts  func(int) bool
+}{
+	{">=", func(c int) bool { return c >= 0 }},
+	{"<=", func(c int) bool { return c <= 0 }},
+	{"!=", func(c int) bool { return c != 0 }},
+	{">", func(c int) bool { return c > 0 }},
+	{"<", func(c int) bool { return c < 0 }},
+	{"=", func(c int) bool { return c == 0 }},
+}
+
+// ParseVersionRange parses a version range made of alternatives separated by "||", each of which is a space separated
+// list of constraints that must all be met, e.g. ">=4.2.0 <4.4.0 || >=4.5". A constraint without operator requires
+// an equal version.
+func ParseVersionRange(s string) (VersionRange, error) {
+	var alternatives [][]VersionRange
+	for _, alternative := range strings.Split(s, "||") {
+		var constraints []VersionRange
+		fields := strings.Fields(alternative)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("invalid version range %q: empty constraint", s)
+		}
+		for _, field := range fields {
+			constraint, err := parseVersionConstraint(field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version range %q: %v", s, err)
+			}
+			constraints = append(constraints, constraint)
+		}
+		alternatives = append(alternatives, constraints)
+	}
+	return func(v Version) bool {
+		for _, constraints := range alternatives {
+			met := true
+			for _, constraint := range constraints {
+				met = met && constraint(v)
+			}
+			if met {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+func parseVersionConstraint(s string) (VersionRange, error) {
+	accepts := func(c int) bool { return c == 0 }
+	for _, op := range versionOperators {
+		if strings.HasPrefix(s, op.operator) {
+			accepts = op.accepts
+			s = strings.TrimPrefix(s, op.operator)
+			break
+		}
+	}
+	version, err := ParseVersion(s)
+	if err != nil {
+		return nil, err
+	}
+	return func(v Version) bool {
+		return accepts(v.Compare(version))
+	}, nil
+}
@@ -0,0 +1,29 @@
+package syncsetreadiness
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// ParseCheck splits the readiness check expression of a SyncSet resource into its JSONPath template and the value
+// the template is expected to evaluate to. The expected value is optional and follows the template after an "=".
+func ParseCheck(expression string) (*jsonpath.JSONPath, string, bool, error) {
+	end := strings.LastIndex(expression, "}")
+	if end == -1 {
+		return nil, "", false, fmt.Errorf("readiness check %q does not contain a JSONPath template", expression)
+	}
+	path := jsonpath.New("readiness").AllowMissingKeys(true)
+	if err := path.Parse(expression[:end+1]); err != nil {
+		return nil, "", false, fmt.Errorf("cannot parse readiness check %q: %v", expression, err)
+	}
+	rest := expression[end+1:]
+	if rest == "" {
+		return path, "", false, nil
+	}
+	if !strings.HasPrefix(rest, "=") {
+		return nil, "", false, fmt.Errorf("readiness check %q must be a JSONPath template optionally followed by =value", expression)
+	}
+	return path, rest[1:], true, nil
+}
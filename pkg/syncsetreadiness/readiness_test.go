@@ -0,0 +1,61 @@
+package syncsetreadiness
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCheck(t *testing.T) {
+	tests := []struct {
+		name                string
+		expression          string
+		expectedValue       string
+		expectedHasExpected bool
+		expectErr           bool
+	}{
+		{
+			name:       "template only",
+			expression: "{.status.readyReplicas}",
+		},
+		{
+			name:                "template with expected value",
+			expression:          "{.status.phase}=Active",
+			expectedValue:       "Active",
+			expectedHasExpected: true,
+		},
+		{
+			name:                "template with empty expected value",
+			expression:          "{.status.phase}=",
+			expectedHasExpected: true,
+		},
+		{
+			name:       "no template",
+			expression: "status.phase=Active",
+			expectErr:  true,
+		},
+		{
+			name:       "text after template",
+			expression: "{.status.phase}Active",
+			expectErr:  true,
+		},
+		{
+			name:       "invalid template",
+			expression: "{.status[}",
+			expectErr:  true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path, value, hasExpected, err := ParseCheck(test.expression)
+			if test.expectErr {
+				assert.Error(t, err, "expected error")
+				return
+			}
+			assert.NoError(t, err, "unexpected error")
+			assert.NotNil(t, path, "expected a JSONPath template")
+			assert.Equal(t, test.expectedValue, value, "unexpected expected value")
+			assert.Equal(t, test.expectedHasExpected, hasExpected, "unexpected hasExpected")
+		})
+	}
+}
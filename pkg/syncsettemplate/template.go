@@ -0,0 +1,212 @@
+package syncsettemplate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+)
+
+const templateMarker = "{{"
+
+// Data contains the ClusterDeployment fields that SyncSet templates may reference.
+type Data struct {
+	ClusterDeploymentName      string
+	ClusterDeploymentNamespace string
+	ClusterName                string
+	BaseDomain                 string
+	InfraID                    string
+	ClusterID                  string
+	Platform                   string
+	Region                     string
+	Labels                     map[string]string
+}
+
+// funcs is the restricted set of functions available to templates in addition to the
+// text/template builtins.
+var funcs = template.FuncMap{
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+	"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+	"replace":    func(old, new, s string) string { return strings.Replace(s, old, new, -1) },
+	"default": func(def string, value interface{}) string {
+		if s, ok := value.(string); ok && s != "" {
+			return s
+		}
+		return def
+	},
+}
+
+// DataForClusterDeployment returns the template Data for the given ClusterDeployment.
+func DataForClusterDeployment(cd *hivev1.ClusterDeployment) *Data {
+	data := &Data{
+		ClusterDeploymentName:      cd.Name,
+		ClusterDeploymentNamespace: cd.Namespace,
+		ClusterName:                cd.Spec.ClusterName,
+		BaseDomain:                 cd.Spec.BaseDomain,
+		Labels:                     map[string]string{},
+	}
+	for k, v := range cd.Labels {
+		data.Labels[k] = v
+	}
+	if cd.Spec.ClusterMetadata != nil {
+		data.InfraID = cd.Spec.ClusterMetadata.InfraID
+		data.ClusterID = cd.Spec.ClusterMetadata.ClusterID
+	}
+	switch {
+	case cd.Spec.Platform.AWS != nil:
+		data.Platform = "aws"
+		data.Region = cd.Spec.Platform.AWS.Region
+	case cd.Spec.Platform.Azure != nil:
+		data.Platform = "azure"
+		data.Region = cd.Spec.Platform.Azure.Region
+	case cd.Spec.Platform.GCP != nil:
+		data.Platform = "gcp"
+		data.Region = cd.Spec.Platform.GCP.Region
+	case cd.Spec.Platform.BareMetal != nil:
+		data.Platform = "baremetal"
+	}
+	return data
+}
+
+// Expand returns a copy of spec with all templates in resources and patches expanded for the given
+// ClusterDeployment. If templates are not enabled for the spec, the spec is returned unchanged.
+func Expand(spec *hivev1.SyncSetCommonSpec, cd *hivev1.ClusterDeployment) (*hivev1.SyncSetCommonSpec, error) {
+	if !spec.EnableResourceTemplates {
+		return spec, nil
+	}
+	return expand(spec, func(s string) (string, error) {
+		return execute(s, DataForClusterDeployment(cd))
+	})
+}
+
+// Validate checks that all templates in resources and patches of spec can be parsed. It does nothing
+// if templates are not enabled for the spec.
+func Validate(spec *hivev1.SyncSetCommonSpec) error {
+	if !spec.EnableResourceTemplates {
+		return nil
+	}
+	_, err := expand(spec, func(s string) (string, error) {
+		_, err := parse(s)
+		return s, err
+	})
+	return err
+}
+
+func expand(spec *hivev1.SyncSetCommonSpec, expandString func(string) (string, error)) (*hivev1.SyncSetCommonSpec, error) {
+	result := spec.DeepCopy()
+	for i, resource := range result.Resources {
+		raw, err := rawBytes(resource)
+		if err != nil {
+			return nil, fmt.Errorf("cannot serialize resource at index %d: %v", i, err)
+		}
+		raw, err = expandDocument(raw, expandString)
+		if err != nil {
+			return nil, fmt.Errorf("cannot expand templates in resource at index %d: %v", i, err)
+		}
+		result.Resources[i] = runtime.RawExtension{Raw: raw}
+	}
+	for i, patch := range result.Patches {
+		var err error
+		if result.Patches[i].Name, err = expandString(patch.Name); err != nil {
+			return nil, fmt.Errorf("cannot expand template in name of patch at index %d: %v", i, err)
+		}
+		if result.Patches[i].Namespace, err = expandString(patch.Namespace); err != nil {
+			return nil, fmt.Errorf("cannot expand template in namespace of patch at index %d: %v", i, err)
+		}
+		if !strings.Contains(patch.Patch, templateMarker) {
+			continue
+		}
+		patchJSON, err := yaml.YAMLToJSON([]byte(patch.Patch))
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse patch at index %d: %v", i, err)
+		}
+		patchJSON, err = expandDocument(patchJSON, expandString)
+		if err != nil {
+			return nil, fmt.Errorf("cannot expand templates in patch at index %d: %v", i, err)
+		}
+		result.Patches[i].Patch = string(patchJSON)
+	}
+	return result, nil
+}
+
+// expandDocument expands templates found in the keys and string values of a JSON document. Only string
+// values are templated so that expanded values are always correctly escaped in the resulting document.
+func expandDocument(doc []byte, expandString func(string) (string, error)) ([]byte, error) {
+	if !bytes.Contains(doc, []byte(templateMarker)) {
+		return doc, nil
+	}
+	var obj interface{}
+	if err := json.Unmarshal(doc, &obj); err != nil {
+		return nil, err
+	}
+	obj, err := expandValue(obj, expandString)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(obj)
+}
+
+func expandValue(value interface{}, expandString func(string) (string, error)) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return expandString(v)
+	case []interface{}:
+		for i := range v {
+			expanded, err := expandValue(v[i], expandString)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = expanded
+		}
+		return v, nil
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			expandedKey, err := expandString(key)
+			if err != nil {
+				return nil, err
+			}
+			expandedItem, err := expandValue(item, expandString)
+			if err != nil {
+				return nil, err
+			}
+			result[expandedKey] = expandedItem
+		}
+		return result, nil
+	}
+	return value, nil
+}
+
+func rawBytes(resource runtime.RawExtension) ([]byte, error) {
+	if len(resource.Raw) > 0 || resource.Object == nil {
+		return resource.Raw, nil
+	}
+	return json.Marshal(resource.Object)
+}
+
+func parse(s string) (*template.Template, error) {
+	return template.New("").Funcs(funcs).Option("missingkey=error").Parse(s)
+}
+
+func execute(s string, data *Data) (string, error) {
+	if !strings.Contains(s, templateMarker) {
+		return s, nil
+	}
+	t, err := parse(s)
+	if err != nil {
+		return "", err
+	}
+	buf := &bytes.Buffer{}
+	if err := t.Execute(buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,189 @@
+package syncsettemplate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	hivev1aws "github.com/openshift/hive/pkg/apis/hive/v1/aws"
+)
+
+func testClusterDeployment() *hivev1.ClusterDeployment {
+	return &hivev1.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: "bar",
+			Labels: map[string]string{
+				"hive.openshift.io/cluster-type": "managed",
+				"env":                            "prod",
+			},
+		},
+		Spec: hivev1.ClusterDeploymentSpec{
+			ClusterName: "foo-cluster",
+			BaseDomain:  "example.com",
+			Platform: hivev1.Platform{
+				AWS: &hivev1aws.Platform{
+					Region: "us-east-1",
+				},
+			},
+			ClusterMetadata: &hivev1.ClusterMetadata{
+				InfraID:   "foo-cluster-abcde",
+				ClusterID: "0123-4567",
+			},
+		},
+	}
+}
+
+func TestExpand(t *testing.T) {
+	cases := []struct {
+		name              string
+		disabled          bool
+		resources         []string
+		patches           []hivev1.SyncObjectPatch
+		expectedResources []string
+		expectedPatches   []hivev1.SyncObjectPatch
+		expectErr         bool
+	}{
+		{
+			name:              "no templates",
+			resources:         []string{`{"apiVersion":"v1","kind":"ConfigMap","data":{"a":"b"}}`},
+			expectedResources: []string{`{"apiVersion":"v1","kind":"ConfigMap","data":{"a":"b"}}`},
+		},
+		{
+			name:              "cluster fields",
+			resources:         []string{`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"{{ .ClusterName }}"},"data":{"domain":"apps.{{ .ClusterName }}.{{ .BaseDomain }}","infra":"{{ .InfraID }}","region":"{{ .Region }}","platform":"{{ .Platform }}"}}`},
+			expectedResources: []string{`{"apiVersion":"v1","data":{"domain":"apps.foo-cluster.example.com","infra":"foo-cluster-abcde","platform":"aws","region":"us-east-1"},"kind":"ConfigMap","metadata":{"name":"foo-cluster"}}`},
+		},
+		{
+			name:              "labels and functions",
+			resources:         []string{`{"kind":"ConfigMap","data":{"{{ .Labels.env }}":"{{ index .Labels \"hive.openshift.io/cluster-type\" | upper }}","missing":"{{ default \"none\" (index .Labels \"missing\") }}"}}`},
+			expectedResources: []string{`{"data":{"missing":"none","prod":"MANAGED"},"kind":"ConfigMap"}`},
+		},
+		{
+			name:              "values are escaped",
+			resources:         []string{`{"kind":"ConfigMap","data":{"q":"{{ printf \"%q\" .ClusterName }}"}}`},
+			expectedResources: []string{`{"data":{"q":"\"foo-cluster\""},"kind":"ConfigMap"}`},
+		},
+		{
+			name:              "templates disabled",
+			disabled:          true,
+			resources:         []string{`{"kind":"ConfigMap","data":{"a":"{{ .ClusterName }}"}}`},
+			expectedResources: []string{`{"kind":"ConfigMap","data":{"a":"{{ .ClusterName }}"}}`},
+		},
+		{
+			name:      "missing label",
+			resources: []string{`{"kind":"ConfigMap","data":{"a":"{{ .Labels.missing }}"}}`},
+			expectErr: true,
+		},
+		{
+			name:      "unknown field",
+			resources: []string{`{"kind":"ConfigMap","data":{"a":"{{ .Unknown }}"}}`},
+			expectErr: true,
+		},
+		{
+			name: "patches",
+			patches: []hivev1.SyncObjectPatch{
+				{
+					Name:      "{{ .ClusterDeploymentName }}-config",
+					Namespace: "openshift-config",
+					Patch:     "data:\n  region: '{{ .Region }}'\n",
+					PatchType: "merge",
+				},
+				{
+					Name:      "plain",
+					Patch:     `{"data":{"a":"b"}}`,
+					PatchType: "merge",
+				},
+			},
+			expectedPatches: []hivev1.SyncObjectPatch{
+				{
+					Name:      "foo-config",
+					Namespace: "openshift-config",
+					Patch:     `{"data":{"region":"us-east-1"}}`,
+					PatchType: "merge",
+				},
+				{
+					Name:      "plain",
+					Patch:     `{"data":{"a":"b"}}`,
+					PatchType: "merge",
+				},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := &hivev1.SyncSetCommonSpec{
+				EnableResourceTemplates: !tc.disabled,
+				Patches:                 tc.patches,
+			}
+			for _, r := range tc.resources {
+				spec.Resources = append(spec.Resources, runtime.RawExtension{Raw: []byte(r)})
+			}
+			original := spec.DeepCopy()
+			result, err := Expand(spec, testClusterDeployment())
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, original, spec, "source spec must not be modified")
+			actualResources := []string{}
+			for _, r := range result.Resources {
+				actualResources = append(actualResources, string(r.Raw))
+			}
+			if tc.expectedResources == nil {
+				tc.expectedResources = []string{}
+			}
+			assert.Equal(t, tc.expectedResources, actualResources)
+			assert.Equal(t, tc.expectedPatches, result.Patches)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name      string
+		disabled  bool
+		resource  string
+		expectErr bool
+	}{
+		{
+			name:     "valid template",
+			resource: `{"data":{"a":"{{ .ClusterName | lower }}"}}`,
+		},
+		{
+			name:      "unterminated action",
+			resource:  `{"data":{"a":"{{ .ClusterName "}}`,
+			expectErr: true,
+		},
+		{
+			name:      "unknown function",
+			resource:  `{"data":{"a":"{{ env \"HOME\" }}"}}`,
+			expectErr: true,
+		},
+		{
+			name:     "templates disabled",
+			disabled: true,
+			resource: `{"data":{"a":"{{ env \"HOME\" }}"}}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := &hivev1.SyncSetCommonSpec{
+				EnableResourceTemplates: !tc.disabled,
+				Resources:               []runtime.RawExtension{{Raw: []byte(tc.resource)}},
+			}
+			err := Validate(spec)
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
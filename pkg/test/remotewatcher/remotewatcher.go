@@ -0,0 +1,84 @@
+package remotewatcher
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+)
+
+// Fake is a RemoteWatcher for tests that returns the given objects for every watched cluster.
+type Fake struct {
+	// Objects are the watched objects of every cluster
+	Objects []*unstructured.Unstructured
+
+	// Synced indicates whether the objects have been listed
+	Synced bool
+
+	// WatchErr is returned from Watch if set
+	WatchErr error
+
+	// Watched and Stopped record the clusters that Watch and Stop were called for
+	Watched []types.NamespacedName
+	Stopped []types.NamespacedName
+}
+
+// New returns a Fake that has listed the given objects.
+func New(objects ...runtime.Object) *Fake {
+	fake := &Fake{Synced: true}
+	for _, obj := range objects {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			panic(err)
+		}
+		fake.Objects = append(fake.Objects, &unstructured.Unstructured{Object: content})
+	}
+	return fake
+}
+
+// Source returns a source without events.
+func (f *Fake) Source() source.Source {
+	return &source.Channel{Source: make(chan event.GenericEvent)}
+}
+
+// Watch records that the cluster is watched.
+func (f *Fake) Watch(cd *hivev1.ClusterDeployment, kubeconfig string) error {
+	if f.WatchErr != nil {
+		return f.WatchErr
+	}
+	f.Watched = append(f.Watched, types.NamespacedName{Namespace: cd.Namespace, Name: cd.Name})
+	return nil
+}
+
+// List returns the objects if the cluster is watched.
+func (f *Fake) List(cd types.NamespacedName) ([]*unstructured.Unstructured, bool) {
+	for _, watched := range f.Watched {
+		if watched == cd {
+			if !f.Synced {
+				return nil, false
+			}
+			objects := make([]*unstructured.Unstructured, len(f.Objects))
+			for i, obj := range f.Objects {
+				objects[i] = obj.DeepCopy()
+			}
+			return objects, true
+		}
+	}
+	return nil, false
+}
+
+// Stop records that the cluster is no longer watched.
+func (f *Fake) Stop(cd types.NamespacedName) {
+	f.Stopped = append(f.Stopped, cd)
+	watched := f.Watched[:0]
+	for _, w := range f.Watched {
+		if w != cd {
+			watched = append(watched, w)
+		}
+	}
+	f.Watched = watched
+}